@@ -22,11 +22,14 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/klaytn/klaytn/accounts/abi"
 	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/types/account"
 	"github.com/klaytn/klaytn/blockchain/types/accountkey"
@@ -229,13 +232,9 @@ type CallArgs struct {
 	Data     hexutil.Bytes   `json:"data"`
 }
 
-func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, uint64, bool, error) {
-	defer func(start time.Time) { logger.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
-
-	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
-	if state == nil || err != nil {
-		return nil, 0, 0, false, err
-	}
+// ToMessage converts the arguments to the message of a call. The first account
+// of the wallets is used as the sender if it is not specified.
+func (args *CallArgs) ToMessage(b Backend, globalGasCap *big.Int) (*types.Transaction, error) {
 	// Set sender address or use a default if none specified
 	addr := args.From
 	if addr == (common.Address{}) {
@@ -260,11 +259,31 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 
 	intrinsicGas, err := types.IntrinsicGas(args.Data, args.To == nil, true)
 	if err != nil {
-		return nil, 0, 0, false, err
+		return nil, err
 	}
 
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false, intrinsicGas)
+	return types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false, intrinsicGas), nil
+}
+
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, uint64, bool, error) {
+	defer func(start time.Time) { logger.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, 0, 0, false, err
+	}
+	// Override the accounts on a copy of the state not to affect the original one
+	if overrides != nil {
+		state = state.Copy()
+		if err := overrides.Apply(state); err != nil {
+			return nil, 0, 0, false, err
+		}
+	}
+	msg, err := args.ToMessage(b, globalGasCap)
+	if err != nil {
+		return nil, 0, 0, false, err
+	}
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -310,22 +329,29 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, _, err := DoCall(ctx, s.b, args, blockNr, vm.Config{}, localTxExecutionTime, s.b.RPCGasCap())
+// The optional overrides are applied to the accounts before the execution.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, _, _, _, err := DoCall(ctx, s.b, args, blockNr, overrides, vm.Config{}, localTxExecutionTime, s.b.RPCGasCap())
 	return (hexutil.Bytes)(result), err
 }
 
 func (s *PublicBlockChainAPI) EstimateComputationCost(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
-	_, _, computationCost, _, err := DoCall(ctx, s.b, args, blockNr, vm.Config{UseOpcodeComputationCost: true}, localTxExecutionTime, s.b.RPCGasCap())
+	_, _, computationCost, _, err := DoCall(ctx, s.b, args, blockNr, nil, vm.Config{UseOpcodeComputationCost: true}, localTxExecutionTime, s.b.RPCGasCap())
 	return (hexutil.Uint64)(computationCost), err
 }
 
-// EstimateGas returns an estimate of the amount of gas needed to execute the given transaction against the latest block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
-	return s.DoEstimateGas(ctx, s.b, args, s.b.RPCGasCap())
+// EstimateGas returns an estimate of the amount of gas needed to execute the given transaction against the given block.
+// The latest block is used if the block number is not given, and the optional overrides are applied to the accounts
+// before the execution.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, blockNr *rpc.BlockNumber, overrides *StateOverride) (hexutil.Uint64, error) {
+	number := rpc.LatestBlockNumber
+	if blockNr != nil {
+		number = *blockNr
+	}
+	return s.DoEstimateGas(ctx, s.b, args, number, overrides, s.b.RPCGasCap())
 }
 
-func (s *PublicBlockChainAPI) DoEstimateGas(ctx context.Context, b Backend, args CallArgs, gasCap *big.Int) (hexutil.Uint64, error) {
+func (s *PublicBlockChainAPI) DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, gasCap *big.Int) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -348,7 +374,7 @@ func (s *PublicBlockChainAPI) DoEstimateGas(ctx context.Context, b Backend, args
 	executable := func(gas uint64) bool {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, _, failed, err := DoCall(ctx, b, args, rpc.LatestBlockNumber, vm.Config{UseOpcodeComputationCost: true}, localTxExecutionTime, gasCap)
+		_, _, _, failed, err := DoCall(ctx, b, args, blockNr, overrides, vm.Config{UseOpcodeComputationCost: true}, localTxExecutionTime, gasCap)
 		if err != nil || failed {
 			return false
 		}
@@ -372,6 +398,186 @@ func (s *PublicBlockChainAPI) DoEstimateGas(ctx context.Context, b Backend, args
 	return hexutil.Uint64(hi), nil
 }
 
+// BundleTxResult is the result of a transaction executed in a bundle.
+type BundleTxResult struct {
+	TxHash       *common.Hash    `json:"txHash,omitempty"` // only for signed raw transactions
+	From         common.Address  `json:"from"`
+	FeePayer     *common.Address `json:"feePayer,omitempty"`
+	To           *common.Address `json:"to"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Status       hexutil.Uint    `json:"status"`
+	ReturnValue  hexutil.Bytes   `json:"returnValue"`
+	Logs         []*types.Log    `json:"logs"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+}
+
+// BalanceChange is the balance of an account changed by a bundle.
+type BalanceChange struct {
+	Before *hexutil.Big `json:"before"`
+	After  *hexutil.Big `json:"after"`
+	Delta  *hexutil.Big `json:"delta"`
+}
+
+// BundleResult is the result of the transactions executed sequentially in a bundle.
+type BundleResult struct {
+	BlockNumber    hexutil.Uint64                    `json:"blockNumber"`
+	GasUsed        hexutil.Uint64                    `json:"gasUsed"`
+	Results        []*BundleTxResult                 `json:"results"`
+	BalanceChanges map[common.Address]*BalanceChange `json:"balanceChanges"`
+}
+
+// CallBundle executes the given transactions sequentially on top of the state
+// of the given block number, so that a transaction sees the changes made by the
+// previous ones. Each transaction is either the arguments of a call or a signed
+// raw transaction of any transaction type, including the fee-delegated ones.
+// It doesn't make any changes in the state/blockchain, and returns the result
+// of each transaction as well as the balances changed by the bundle.
+// The optional overrides are applied to the accounts before the execution.
+func (s *PublicBlockChainAPI) CallBundle(ctx context.Context, txs []BundleTxArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (*BundleResult, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("bundle missing transactions")
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// Execute the bundle on a copy of the state not to affect the original one
+	state = state.Copy()
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	state.Finalise(true, false)
+	base := state.Copy()
+
+	ctx, cancel := context.WithTimeout(ctx, localTxExecutionTime)
+	defer cancel()
+
+	signer := types.MakeSigner(s.b.ChainConfig(), header.Number)
+	result := &BundleResult{
+		BlockNumber:    hexutil.Uint64(header.Number.Uint64()),
+		Results:        make([]*BundleTxResult, 0, len(txs)),
+		BalanceChanges: make(map[common.Address]*BalanceChange),
+	}
+	for i, args := range txs {
+		var msg *types.Transaction
+		if args.Call != nil {
+			if msg, err = args.Call.ToMessage(s.b, s.b.RPCGasCap()); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+		} else {
+			tx := new(types.Transaction)
+			if err := rlp.DecodeBytes(args.Raw, tx); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			if msg, err = tx.AsMessageWithAccountKeyPicker(signer, state, header.Number.Uint64()); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+		}
+		txResult, err := s.applyBundleTx(ctx, msg, args.Call == nil, i, state, header)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %v", i, err)
+		}
+		result.Results = append(result.Results, txResult)
+		result.GasUsed += txResult.GasUsed
+	}
+	// Collect the balances changed by the bundle
+	for _, addr := range state.DirtyAddresses() {
+		before, after := base.GetBalance(addr), state.GetBalance(addr)
+		if before.Cmp(after) != 0 {
+			result.BalanceChanges[addr] = &BalanceChange{
+				Before: (*hexutil.Big)(before),
+				After:  (*hexutil.Big)(after),
+				Delta:  (*hexutil.Big)(new(big.Int).Sub(after, before)),
+			}
+		}
+	}
+	return result, nil
+}
+
+// applyBundleTx executes a transaction of a bundle on the given state.
+// A signed raw transaction is executed the same as it is in a block, while the
+// arguments of a call are executed the same as klay_call, whose gas is not paid
+// by the sender. An invalid transaction doesn't change the state.
+func (s *PublicBlockChainAPI) applyBundleTx(ctx context.Context, msg *types.Transaction, signed bool, index int, state *state.StateDB, header *types.Header) (*BundleTxResult, error) {
+	sender := msg.ValidatedSender()
+	balance := state.GetBalance(sender)
+	snapshot := state.Snapshot()
+
+	state.Prepare(msg.Hash(), common.Hash{}, index)
+	seenLogs := len(state.GetLogs(msg.Hash()))
+
+	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// The backend may pay the gas of the sender in advance, which is taken back
+	// before a signed transaction is executed.
+	subsidy := new(big.Int).Sub(state.GetBalance(sender), balance)
+	if signed && subsidy.Sign() > 0 {
+		state.SubBalance(sender, subsidy)
+	}
+	// Cancel the EVM when the bundle is timed out
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel(vm.CancelByCtxDone)
+		case <-done:
+		}
+	}()
+
+	ret, gasUsed, kerr := blockchain.ApplyMessage(evm, msg)
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if evm.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", localTxExecutionTime)
+	}
+	if kerr.ErrTxInvalid != nil {
+		state.RevertToSnapshot(snapshot)
+		kerr.Status = types.ReceiptStatusFailed
+	} else if !signed && subsidy.Sign() > 0 {
+		// The gas of a call is paid by the subsidy, so the rest of the subsidy is
+		// taken back from the sender and the fee is taken back from the rewardbase.
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), msg.GasPrice())
+		state.SubBalance(sender, new(big.Int).Sub(subsidy, fee))
+		if config := s.b.ChainConfig(); config.Governance == nil || !config.Governance.DeferredTxFee() {
+			state.SubBalance(evm.Coinbase, fee)
+		}
+	}
+	state.Finalise(true, false)
+
+	result := &BundleTxResult{
+		From:        sender,
+		To:          msg.To(),
+		GasUsed:     hexutil.Uint64(gasUsed),
+		Status:      hexutil.Uint(kerr.Status),
+		ReturnValue: ret,
+		Logs:        append([]*types.Log{}, state.GetLogs(msg.Hash())[seenLogs:]...),
+	}
+	if signed {
+		hash := msg.Hash()
+		result.TxHash = &hash
+	}
+	if msg.IsFeeDelegatedTransaction() {
+		feePayer := msg.ValidatedFeePayer()
+		result.FeePayer = &feePayer
+	}
+	if kerr.ErrTxInvalid != nil {
+		result.Error = kerr.ErrTxInvalid.Error()
+	} else if err := blockchain.GetVMerrFromReceiptStatus(kerr.Status); err != nil {
+		result.Error = err.Error()
+		if kerr.Status == types.ReceiptStatusErrExecutionReverted {
+			if reason, err := abi.UnpackRevert(ret); err == nil {
+				result.RevertReason = reason
+			}
+		}
+	}
+	return result, nil
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
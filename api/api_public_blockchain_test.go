@@ -0,0 +1,203 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_api "github.com/klaytn/klaytn/api/mocks"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCallTestBackend returns a mock backend which executes calls on the given
+// state without any deployed contracts.
+func newCallTestBackend(t *testing.T, statedb *state.StateDB) (*gomock.Controller, *mock_api.MockBackend) {
+	mockCtrl := gomock.NewController(t)
+	mockBackend := mock_api.NewMockBackend(mockCtrl)
+
+	header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), BlockScore: big.NewInt(1)}
+	mockBackend.EXPECT().RPCGasCap().Return(nil).AnyTimes()
+	mockBackend.EXPECT().StateAndHeaderByNumber(gomock.Any(), rpc.LatestBlockNumber).Return(statedb, header, nil).AnyTimes()
+	mockBackend.EXPECT().GetEVM(gomock.Any(), gomock.Any(), gomock.Any(), header, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, msg blockchain.Message, statedb *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+			statedb.AddBalance(msg.ValidatedSender(), new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.GasPrice()))
+			vmctx := vm.Context{
+				CanTransfer: blockchain.CanTransfer,
+				Transfer:    blockchain.Transfer,
+				Origin:      msg.ValidatedSender(),
+				BlockNumber: header.Number,
+				Time:        header.Time,
+				BlockScore:  header.BlockScore,
+				GasPrice:    msg.GasPrice(),
+			}
+			return vm.NewEVM(vmctx, statedb, params.TestChainConfig, &vmCfg), func() error { return nil }, nil
+		}).AnyTimes()
+	return mockCtrl, mockBackend
+}
+
+// TestPublicBlockChainAPI_CallWithOverrides tests that klay_call and
+// klay_estimateGas are executed with the overridden accounts, and the
+// original state is not affected by the overrides.
+func TestPublicBlockChainAPI_CallWithOverrides(t *testing.T) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+
+	mockCtrl, mockBackend := newCallTestBackend(t, statedb)
+	defer mockCtrl.Finish()
+	api := NewPublicBlockChainAPI(mockBackend)
+
+	// The contract stores 1 to the slot 1, and returns the value of the slot 0
+	var (
+		from     = common.HexToAddress("0x1")
+		contract = common.HexToAddress("0x1000")
+		code     = hexutil.Bytes(common.FromHex("0x600160015560005460005260206000f3"))
+		storage  = map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(42))}
+		args     = CallArgs{From: from, To: &contract}
+	)
+	overrides := &StateOverride{contract: {Code: &code, StateDiff: &storage}}
+
+	// Without overrides, there is no contract to call
+	ret, err := api.Call(context.Background(), args, rpc.LatestBlockNumber, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	ret, err = api.Call(context.Background(), args, rpc.LatestBlockNumber, overrides)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(42)).Bytes(), []byte(ret))
+
+	// The gas of the storage write is estimated with the contract not deployed
+	plainGas, err := api.EstimateGas(context.Background(), args, nil, nil)
+	assert.NoError(t, err)
+	gas, err := api.EstimateGas(context.Background(), args, nil, overrides)
+	assert.NoError(t, err)
+	assert.True(t, uint64(gas) >= uint64(plainGas)+params.SstoreSetGas, "gas %d, plain gas %d", gas, plainGas)
+
+	// The original state is not modified
+	assert.Empty(t, statedb.GetCode(contract))
+	assert.Equal(t, common.Hash{}, statedb.GetState(contract, common.Hash{}))
+}
+
+// TestPublicBlockChainAPI_CallBundle tests that the transactions of a bundle
+// are executed sequentially, and their results and the changed balances are
+// returned.
+func TestPublicBlockChainAPI_CallBundle(t *testing.T) {
+	var (
+		sender    = crypto.PubkeyToAddress(senderPrvKey.PublicKey)
+		feePayer  = crypto.PubkeyToAddress(feePayerPrvKey.PublicKey)
+		recipient = common.HexToAddress("0x2000")
+		contract  = common.HexToAddress("0x1000")
+		gasPrice  = big.NewInt(1)
+		value     = big.NewInt(1000)
+		signer    = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+	statedb.SetBalance(feePayer, big.NewInt(params.KLAY))
+
+	mockCtrl, mockBackend := newCallTestBackend(t, statedb)
+	defer mockCtrl.Finish()
+	mockBackend.EXPECT().ChainConfig().Return(params.TestChainConfig).AnyTimes()
+	api := NewPublicBlockChainAPI(mockBackend)
+
+	// The fee payer sends some KLAY to the sender which doesn't have any balance
+	transfer := types.NewTransaction(0, sender, value, 100000, gasPrice, nil)
+	assert.NoError(t, transfer.SignWithKeys(signer, []*ecdsa.PrivateKey{feePayerPrvKey}))
+
+	// The sender sends the received KLAY with a fee-delegated transaction
+	feeDelegated, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     sender,
+		types.TxValueKeyTo:       recipient,
+		types.TxValueKeyAmount:   value,
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: gasPrice,
+		types.TxValueKeyFeePayer: feePayer,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, feeDelegated.SignWithKeys(signer, []*ecdsa.PrivateKey{senderPrvKey}))
+	assert.NoError(t, feeDelegated.SignFeePayerWithKeys(signer, []*ecdsa.PrivateKey{feePayerPrvKey}))
+
+	// The contract reverts with the reason "nope"
+	code := hexutil.Bytes(common.FromHex("0x6064600c60003960646000fd" +
+		"08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000"))
+	overrides := &StateOverride{contract: {Code: &code}}
+
+	var txs []BundleTxArgs
+	for _, tx := range []*types.Transaction{transfer, feeDelegated} {
+		raw, err := rlp.EncodeToBytes(tx)
+		assert.NoError(t, err)
+		txs = append(txs, BundleTxArgs{Raw: raw})
+	}
+	txs = append(txs, BundleTxArgs{Call: &CallArgs{From: common.HexToAddress("0x3000"), To: &contract}})
+
+	result, err := api.CallBundle(context.Background(), txs, rpc.LatestBlockNumber, overrides)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(result.Results))
+
+	assert.Equal(t, transfer.Hash(), *result.Results[0].TxHash)
+	assert.Equal(t, feePayer, result.Results[0].From)
+	assert.Equal(t, hexutil.Uint(types.ReceiptStatusSuccessful), result.Results[0].Status)
+
+	assert.Equal(t, feeDelegated.Hash(), *result.Results[1].TxHash)
+	assert.Equal(t, sender, result.Results[1].From)
+	assert.Equal(t, feePayer, *result.Results[1].FeePayer)
+	assert.Equal(t, hexutil.Uint(types.ReceiptStatusSuccessful), result.Results[1].Status)
+
+	assert.Nil(t, result.Results[2].TxHash)
+	assert.Equal(t, hexutil.Uint(types.ReceiptStatusErrExecutionReverted), result.Results[2].Status)
+	assert.Equal(t, "nope", result.Results[2].RevertReason)
+	assert.NotEmpty(t, result.Results[2].Error)
+
+	// The fee payer pays the value and the fees of both transactions, and the
+	// sender has nothing changed at the end. The call doesn't pay any fee.
+	fees := new(big.Int).SetUint64(uint64(result.Results[0].GasUsed + result.Results[1].GasUsed))
+	delta := new(big.Int).Neg(new(big.Int).Add(value, fees))
+	assert.Equal(t, 3, len(result.BalanceChanges))
+	assert.Equal(t, delta, result.BalanceChanges[feePayer].Delta.ToInt())
+	assert.Equal(t, value, result.BalanceChanges[recipient].Delta.ToInt())
+	assert.Equal(t, fees, result.BalanceChanges[common.Address{}].Delta.ToInt())
+	assert.NotContains(t, result.BalanceChanges, sender)
+
+	// The original state is not modified
+	assert.Equal(t, big.NewInt(params.KLAY), statedb.GetBalance(feePayer))
+	assert.Equal(t, uint64(0), statedb.GetNonce(feePayer))
+
+	// An invalid transaction doesn't change the state without stopping the bundle
+	result, err = api.CallBundle(context.Background(), txs[1:2], rpc.LatestBlockNumber, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint(types.ReceiptStatusFailed), result.Results[0].Status)
+	assert.NotEmpty(t, result.Results[0].Error)
+	assert.Empty(t, result.BalanceChanges)
+}
@@ -23,12 +23,16 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 
+	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/blockchain/vm"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/params"
@@ -376,3 +380,118 @@ func (args *AccountUpdateTxArgs) toTransaction() (*types.Transaction, error) {
 
 	return tx, nil
 }
+
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of a message call.
+// Note that state and stateDiff can't be specified at the same time. If state
+// is set, the message execution only uses the given storage of the account.
+// If stateDiff is set, the given storage slots are overridden and the others
+// are left as they are.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   **hexutil.Big                `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of the overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the specified accounts in the given state.
+func (diff *StateOverride) Apply(statedb *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		// An EOA can't have code and storage, so the account is replaced with a
+		// smart contract account carrying over the balance, nonce and code. The
+		// replaced account has an empty storage which is needed to override
+		// the whole storage as well.
+		if account.State != nil || (account.Code != nil && statedb.Exist(addr) && !statedb.IsProgramAccount(addr)) {
+			nonce, code := statedb.GetNonce(addr), statedb.GetCode(addr)
+			statedb.CreateSmartContractAccount(addr, params.CodeFormatEVM)
+			statedb.SetNonce(addr, nonce)
+			if len(code) > 0 {
+				if err := statedb.SetCode(addr, code); err != nil {
+					return err
+				}
+			}
+		}
+		// Override account nonce.
+		if account.Nonce != nil {
+			statedb.SetNonce(addr, uint64(*account.Nonce))
+		}
+		// Override account (contract) code.
+		if account.Code != nil {
+			if err := statedb.SetCode(addr, *account.Code); err != nil {
+				return fmt.Errorf("failed to override the code of %s: %v", addr.Hex(), err)
+			}
+		}
+		// Override account balance.
+		if account.Balance != nil {
+			statedb.SetBalance(addr, (*big.Int)(*account.Balance))
+		}
+		// Override the storage, which was emptied above if the whole storage
+		// is given.
+		if account.State != nil {
+			for key, value := range *account.State {
+				statedb.SetState(addr, key, value)
+			}
+		}
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				statedb.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// BlockOverrides is a set of header fields to override during the execution
+// of a message call.
+type BlockOverrides struct {
+	Number     *hexutil.Big    `json:"number"`
+	Time       *hexutil.Big    `json:"timestamp"`
+	BlockScore *hexutil.Big    `json:"blockScore"`
+	Rewardbase *common.Address `json:"reward"`
+}
+
+// Apply overrides the block information of the given EVM context.
+func (o *BlockOverrides) Apply(vmctx *vm.Context) {
+	if o == nil {
+		return
+	}
+	if o.Number != nil {
+		vmctx.BlockNumber = new(big.Int).Set(o.Number.ToInt())
+	}
+	if o.Time != nil {
+		vmctx.Time = new(big.Int).Set(o.Time.ToInt())
+	}
+	if o.BlockScore != nil {
+		vmctx.BlockScore = new(big.Int).Set(o.BlockScore.ToInt())
+	}
+	if o.Rewardbase != nil {
+		vmctx.Coinbase = *o.Rewardbase
+	}
+}
+
+// BundleTxArgs represents a transaction of a bundle, which is either the
+// arguments of a call or a signed raw transaction given as a hex string.
+type BundleTxArgs struct {
+	Call *CallArgs
+	Raw  hexutil.Bytes
+}
+
+// UnmarshalJSON unmarshals the arguments of a call from a JSON object, or a
+// signed raw transaction from a JSON string.
+func (args *BundleTxArgs) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		return json.Unmarshal(input, &args.Raw)
+	}
+	args.Call = new(CallArgs)
+	return json.Unmarshal(input, args.Call)
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStateOverride_Apply tests that the overrides are applied to both of
+// externally owned accounts and smart contract accounts.
+func TestStateOverride_Apply(t *testing.T) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+
+	var (
+		eoa      = common.HexToAddress("0x1")
+		contract = common.HexToAddress("0x2")
+		key1     = common.HexToHash("0x1")
+		key2     = common.HexToHash("0x2")
+	)
+	statedb.SetNonce(eoa, 3)
+	statedb.SetBalance(eoa, big.NewInt(100))
+	assert.NoError(t, statedb.SetCode(contract, []byte{0x1}))
+	statedb.SetState(contract, key1, common.HexToHash("0x11"))
+	statedb.SetState(contract, key2, common.HexToHash("0x22"))
+
+	code := hexutil.Bytes{0x60, 0x00}
+	balance := (*hexutil.Big)(big.NewInt(200))
+	nonce := hexutil.Uint64(7)
+	storage := map[common.Hash]common.Hash{key2: common.HexToHash("0x33")}
+	override := &StateOverride{
+		eoa:      {Code: &code, Balance: &balance},
+		contract: {Nonce: &nonce, State: &storage},
+	}
+	assert.NoError(t, override.Apply(statedb))
+
+	// The externally owned account is replaced with a smart contract account
+	assert.True(t, statedb.IsProgramAccount(eoa))
+	assert.Equal(t, []byte(code), statedb.GetCode(eoa))
+	assert.Equal(t, uint64(3), statedb.GetNonce(eoa))
+	assert.Equal(t, big.NewInt(200), statedb.GetBalance(eoa))
+
+	// The whole storage of the smart contract account is replaced
+	assert.Equal(t, []byte{0x1}, statedb.GetCode(contract))
+	assert.Equal(t, uint64(7), statedb.GetNonce(contract))
+	assert.Equal(t, common.Hash{}, statedb.GetState(contract, key1))
+	assert.Equal(t, common.HexToHash("0x33"), statedb.GetState(contract, key2))
+
+	// State and stateDiff can't be given at the same time
+	override = &StateOverride{contract: {State: &storage, StateDiff: &storage}}
+	assert.Error(t, override.Apply(statedb))
+}
+
+// TestBlockOverrides_Apply tests that only the given fields are overridden.
+func TestBlockOverrides_Apply(t *testing.T) {
+	vmctx := vm.Context{
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(2),
+		BlockScore:  big.NewInt(3),
+	}
+	rewardbase := common.HexToAddress("0x1")
+	overrides := &BlockOverrides{
+		Number:     (*hexutil.Big)(big.NewInt(10)),
+		Rewardbase: &rewardbase,
+	}
+	overrides.Apply(&vmctx)
+
+	assert.Equal(t, big.NewInt(10), vmctx.BlockNumber)
+	assert.Equal(t, big.NewInt(2), vmctx.Time)
+	assert.Equal(t, big.NewInt(3), vmctx.BlockScore)
+	assert.Equal(t, rewardbase, vmctx.Coinbase)
+
+	// Nil overrides have no effect
+	(*BlockOverrides)(nil).Apply(&vmctx)
+	assert.Equal(t, big.NewInt(10), vmctx.BlockNumber)
+}
+
+// TestBundleTxArgs_UnmarshalJSON tests that a transaction of a bundle is
+// unmarshalled as either the arguments of a call or a signed raw transaction.
+func TestBundleTxArgs_UnmarshalJSON(t *testing.T) {
+	var txs []BundleTxArgs
+	input := `[{"from": "0x0000000000000000000000000000000000000001", "data": "0x01"}, "0xf801"]`
+	assert.NoError(t, json.Unmarshal([]byte(input), &txs))
+	assert.Equal(t, 2, len(txs))
+
+	assert.NotNil(t, txs[0].Call)
+	assert.Equal(t, common.HexToAddress("0x1"), txs[0].Call.From)
+	assert.Equal(t, hexutil.Bytes{0x1}, txs[0].Call.Data)
+	assert.Nil(t, txs[0].Raw)
+
+	assert.Nil(t, txs[1].Call)
+	assert.Equal(t, hexutil.Bytes{0xf8, 0x1}, txs[1].Raw)
+
+	assert.Error(t, json.Unmarshal([]byte(`["0xzz"]`), &txs))
+}
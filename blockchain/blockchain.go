@@ -48,6 +48,7 @@ import (
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
 	"github.com/rcrowley/go-metrics"
@@ -82,6 +83,7 @@ var (
 	ErrNotExistNode         = errors.New("the node does not exist in cached node")
 	ErrQuitBySignal         = errors.New("quit by signal")
 	ErrNotInWarmUp          = errors.New("not in warm up")
+	ErrSnapshotDisabled     = errors.New("snapshot is disabled")
 	logger                  = log.NewModuleLogger(log.Blockchain)
 	kesCachePrefixBlockLogs = []byte("blockLogs")
 )
@@ -104,6 +106,8 @@ const (
 	BlockChainVersion    = 3
 	DefaultBlockInterval = 128
 	MaxPrefetchTxs       = 20000
+
+	DefaultSnapshotLayers = 128 // Maximum number of snapshot diff layers kept in the memory
 )
 
 // CacheConfig contains the configuration values for the 1) stateDB caching and
@@ -116,6 +120,11 @@ type CacheConfig struct {
 	TriesInMemory        uint64                       // Maximum number of recent state tries according to its block number
 	SenderTxHashIndexing bool                         // Enables saving senderTxHash to txHash mapping information to database and cache
 	TrieNodeCacheConfig  *statedb.TrieNodeCacheConfig // Configures trie node cache
+	SnapshotCacheSize    int                          // Memory allowance (MB) to use for caching snapshot entries in memory
+	SnapshotAsyncGen     bool                         // Enables snapshot data to be generated asynchronously
+
+	StatePruning          bool   // Enables pruning the stale state trie nodes from the disk
+	StatePruningRetention uint64 // Number of recent blocks whose committed state tries are kept by the pruning
 }
 
 // gcBlock is used for priority queue for GC.
@@ -165,6 +174,7 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache   state.Database // State database to reuse between imports (contains state cache)
+	snaps        *snapshot.Tree // Snapshot tree for fast trie leaf access
 	futureBlocks *lru.Cache     // future blocks are blocks added for later processing
 
 	quit    chan struct{} // blockchain quit channel
@@ -192,6 +202,9 @@ type BlockChain struct {
 	progress              float64
 	migrationErr          error
 
+	// State pruning
+	statePruning int32 // 1 while the state is being pruned, must be accessed atomically
+
 	// Warm up
 	lastCommittedBlock uint64
 	quitWarmUp         chan struct{}
@@ -224,6 +237,20 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 		cacheConfig.TrieNodeCacheConfig = statedb.GetEmptyTrieNodeCacheConfig()
 	}
 
+	if cacheConfig.StatePruning {
+		if cacheConfig.ArchiveMode {
+			logger.Warn("State pruning is disabled in the archive mode")
+			cacheConfig.StatePruning = false
+		} else if !isStatePruningSupported(db) {
+			return nil, ErrStatePruningNotSupported
+		}
+		if cacheConfig.StatePruningRetention < cacheConfig.TriesInMemory {
+			logger.Warn("State pruning retention is smaller than the tries in memory", "retention", cacheConfig.StatePruningRetention,
+				"updated", cacheConfig.TriesInMemory)
+			cacheConfig.StatePruningRetention = cacheConfig.TriesInMemory
+		}
+	}
+
 	state.EnabledExpensive = db.GetDBConfig().EnableDBPerfMetrics
 
 	// Initialize DeriveSha implementation
@@ -270,6 +297,11 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 	if err := bc.loadLastState(); err != nil {
 		return nil, err
 	}
+	// Load any existing snapshot, regenerating it if loading failed
+	if bc.cacheConfig.SnapshotCacheSize > 0 {
+		head := bc.CurrentBlock()
+		bc.snaps, _ = snapshot.New(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.SnapshotCacheSize, head.Root(), bc.cacheConfig.SnapshotAsyncGen, true)
+	}
 	// Check the current state of the block hashes and make sure that we do not have any of the bad blocks in our chain
 	for hash := range BadHashes {
 		if header := bc.GetHeaderByHash(hash); header != nil {
@@ -295,6 +327,12 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 	bc.gcCachedNodeLoop()
 	bc.restartStateMigration()
 
+	if bc.cacheConfig.StatePruning {
+		logger.Info("State pruning is enabled", "retention", bc.cacheConfig.StatePruningRetention)
+		bc.wg.Add(1)
+		go bc.statePruningLoop()
+	}
+
 	if cacheConfig.TrieNodeCacheConfig.DumpPeriodically() {
 		logger.Info("LocalCache is used for trie node cache, start saving cache to file periodically",
 			"dir", bc.cacheConfig.TrieNodeCacheConfig.FastCacheFileDir,
@@ -317,7 +355,7 @@ func (bc *BlockChain) prefetchTxWorker(index int) {
 
 	logger.Debug("prefetchTxWorker is started", "index", index)
 	for followup := range bc.prefetchTxCh {
-		stateDB, err := state.New(bc.CurrentBlock().Root(), bc.stateCache)
+		stateDB, err := state.New(bc.CurrentBlock().Root(), bc.stateCache, nil)
 		if err != nil {
 			logger.Debug("failed to retrieve stateDB for prefetchTxWorker", "err", err)
 			continue
@@ -398,7 +436,7 @@ func (bc *BlockChain) loadLastState() error {
 		return bc.Reset()
 	}
 	// Make sure the state associated with the block is available
-	if _, err := state.New(currentBlock.Root(), bc.stateCache); err != nil {
+	if _, err := state.New(currentBlock.Root(), bc.stateCache, nil); err != nil {
 		// Dangling block without a state associated, init from scratch
 		logger.Error("Head state missing, repairing chain",
 			"number", currentBlock.NumberU64(), "hash", currentBlock.Hash().String())
@@ -467,7 +505,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 		bc.currentBlock.Store(bc.GetBlock(currentHeader.Hash(), currentHeader.Number.Uint64()))
 	}
 	if currentBlock := bc.CurrentBlock(); currentBlock != nil {
-		if _, err := state.New(currentBlock.Root(), bc.stateCache); err != nil {
+		if _, err := state.New(currentBlock.Root(), bc.stateCache, nil); err != nil {
 			// Rewound state missing, rolled back to before pivot, reset to genesis
 			bc.currentBlock.Store(bc.genesisBlock)
 		}
@@ -489,6 +527,10 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.db.WriteHeadBlockHash(currentBlock.Hash())
 	bc.db.WriteHeadFastBlockHash(currentFastBlock.Hash())
 
+	// Rebuild the snapshot if the rewound head is not covered by it anymore
+	if bc.snaps != nil && bc.snaps.Snapshot(currentBlock.Root()) == nil {
+		bc.snaps.Rebuild(currentBlock.Root())
+	}
 	return bc.loadLastState()
 }
 
@@ -542,7 +584,7 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache)
+	return state.New(root, bc.stateCache, bc.snaps)
 }
 
 // StateAtWithPersistent returns a new mutable state based on a particular point in time with persistent trie nodes.
@@ -551,7 +593,7 @@ func (bc *BlockChain) StateAtWithPersistent(root common.Hash) (*state.StateDB, e
 	if !exist {
 		return nil, ErrNotExistNode
 	}
-	return state.New(root, bc.stateCache)
+	return state.New(root, bc.stateCache, nil)
 }
 
 // StateAtWithGCLock returns a new mutable state based on a particular point in time with read lock of the state nodes.
@@ -564,7 +606,7 @@ func (bc *BlockChain) StateAtWithGCLock(root common.Hash) (*state.StateDB, error
 		return nil, ErrNotExistNode
 	}
 
-	stateDB, err := state.New(root, bc.stateCache)
+	stateDB, err := state.New(root, bc.stateCache, nil)
 	if err != nil {
 		bc.RUnlockGCCachedNode()
 		return nil, err
@@ -616,7 +658,7 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *types.Block) error {
 func (bc *BlockChain) repair(head **types.Block) error {
 	for {
 		// Abort if we've rewound to a head block that does have associated state
-		if _, err := state.New((*head).Root(), bc.stateCache); err == nil {
+		if _, err := state.New((*head).Root(), bc.stateCache, nil); err == nil {
 			logger.Info("Rewound blockchain to past state", "number", (*head).Number(), "hash", (*head).Hash())
 			return nil
 		} else {
@@ -857,6 +899,15 @@ func (bc *BlockChain) Stop() {
 
 	bc.wg.Wait()
 
+	// Ensure that the entirety of the state snapshot is journalled to disk.
+	var snapBase common.Hash
+	if bc.snaps != nil {
+		var err error
+		if snapBase, err = bc.snaps.Journal(bc.CurrentBlock().Root()); err != nil {
+			logger.Error("Failed to journal state snapshot", "err", err)
+		}
+	}
+
 	triedb := bc.stateCache.TrieDB()
 	if !bc.isArchiveMode() {
 		number := bc.CurrentBlock().NumberU64()
@@ -870,6 +921,14 @@ func (bc *BlockChain) Stop() {
 		if err := triedb.Commit(recent.Root(), true, number); err != nil {
 			logger.Error("Failed to commit recent state trie", "err", err)
 		}
+		// The snapshot generator resumes on the disk layer root after restart,
+		// so its trie should be persisted as well.
+		if snapBase != (common.Hash{}) && snapBase != recent.Root() {
+			logger.Info("Writing snapshot state to disk", "root", snapBase)
+			if err := triedb.Commit(snapBase, true, number); err != nil {
+				logger.Error("Failed to commit snapshot state trie", "err", err)
+			}
+		}
 
 		for !bc.triegc.Empty() {
 			triedb.Dereference(bc.triegc.PopItem().(common.Hash))
@@ -1115,6 +1174,11 @@ func (bc *BlockChain) writeStateTrie(block *types.Block, state *state.StateDB) e
 	if err != nil {
 		return err
 	}
+	if bc.snaps != nil {
+		if err := bc.snaps.Cap(root, bc.snapshotLayers()); err != nil {
+			logger.Warn("Failed to cap snapshot tree", "root", root, "layers", bc.snapshotLayers(), "err", err)
+		}
+	}
 	trieDB := bc.stateCache.TrieDB()
 	trieDB.UpdateMetricNodes()
 
@@ -1179,6 +1243,51 @@ func (bc *BlockChain) triesInMemory() uint64 {
 	return bc.cacheConfig.TriesInMemory
 }
 
+// snapshotLayers returns the number of snapshot diff layers kept in the memory.
+// The disk layer is iterated by the snapshot generator, so the state trie of it
+// should not be garbage collected from the trie database before being flattened.
+func (bc *BlockChain) snapshotLayers() int {
+	if bc.isArchiveMode() {
+		return DefaultSnapshotLayers
+	}
+	if bc.triesInMemory() < 2 {
+		return 0
+	}
+	if layers := bc.triesInMemory() - 2; layers < DefaultSnapshotLayers {
+		return int(layers)
+	}
+	return DefaultSnapshotLayers
+}
+
+// Snapshots returns the snapshot tree of the blockchain. It returns nil if the
+// snapshot is disabled.
+func (bc *BlockChain) Snapshots() *snapshot.Tree {
+	return bc.snaps
+}
+
+// SnapshotStatus returns the status of the snapshot including the progress of
+// the background generation.
+func (bc *BlockChain) SnapshotStatus() (*snapshot.Status, error) {
+	if bc.snaps == nil {
+		return nil, ErrSnapshotDisabled
+	}
+	return bc.snaps.Status()
+}
+
+// RebuildSnapshot discards the current snapshot and starts to regenerate it
+// from the state of the current head block.
+func (bc *BlockChain) RebuildSnapshot() error {
+	if bc.snaps == nil {
+		return ErrSnapshotDisabled
+	}
+	// Make sure no block is written while the snapshot is replaced
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.snaps.Rebuild(bc.CurrentBlock().Root())
+	return nil
+}
+
 // gcCachedNodeLoop runs a loop to gc.
 func (bc *BlockChain) gcCachedNodeLoop() {
 	trieDB := bc.stateCache.TrieDB()
@@ -1228,7 +1337,7 @@ func isCommitTrieRequired(bc *BlockChain, blockNum uint64) bool {
 	}
 
 	if bc.chainConfig.Istanbul != nil {
-		return bc.ProposerPolicy() == params.WeightedRandom &&
+		return params.IsWeightedRandomPolicy(bc.ProposerPolicy()) &&
 			params.IsStakingUpdateInterval(blockNum)
 	}
 	return false
@@ -1691,7 +1800,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 				// current block is not the last one, so prefetch the right next block
 				followup := chain[i+1]
 				go func(start time.Time) {
-					throwaway, _ := state.New(parent.Root(), bc.stateCache)
+					throwaway, _ := state.New(parent.Root(), bc.stateCache, nil)
 					bc.prefetcher.Prefetch(followup, throwaway, bc.vmConfig, &followupInterrupt)
 
 					blockPrefetchExecuteTimer.Update(time.Since(start))
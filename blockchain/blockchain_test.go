@@ -158,7 +158,7 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 			}
 			return err
 		}
-		statedb, err := state.New(blockchain.GetBlockByHash(block.ParentHash()).Root(), blockchain.stateCache)
+		statedb, err := state.New(blockchain.GetBlockByHash(block.ParentHash()).Root(), blockchain.stateCache, nil)
 		if err != nil {
 			return err
 		}
@@ -1626,3 +1626,73 @@ func TestBlockChain_SetCanonicalBlock(t *testing.T) {
 	assert.Equal(t, targetBlock.Hash(), newHeadBlock.Hash())
 	assert.EqualValues(t, targetBlock, newHeadBlock)
 }
+
+// TestBlockChain_Snapshot tests that the snapshot follows the inserted blocks
+// and that it is restored from the journal after restarting the blockchain.
+func TestBlockChain_Snapshot(t *testing.T) {
+	var (
+		gendb       = database.NewMemoryDBManager()
+		key, _      = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address     = crypto.PubkeyToAddress(key.PublicKey)
+		receiver    = common.HexToAddress("0x1234")
+		funds       = big.NewInt(100000000000000000)
+		testGenesis = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: funds}},
+		}
+		genesis = testGenesis.MustCommit(gendb)
+		signer  = types.NewEIP155Signer(testGenesis.Config.ChainID)
+	)
+	db := database.NewMemoryDBManager()
+	testGenesis.MustCommit(db)
+
+	cacheConfig := &CacheConfig{
+		CacheSize:           512,
+		BlockInterval:       DefaultBlockInterval,
+		TriesInMemory:       DefaultTriesInMemory,
+		TrieNodeCacheConfig: statedb.GetEmptyTrieNodeCacheConfig(),
+		SnapshotCacheSize:   16,
+	}
+	blockchain, err := NewBlockChain(db, cacheConfig, testGenesis.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks, _ := GenerateChain(testGenesis.Config, genesis, gxhash.NewFaker(), gendb, 20, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), receiver, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	if n, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+
+	head := blockchain.CurrentBlock()
+	assert.NotNil(t, blockchain.Snapshots().Snapshot(head.Root()))
+
+	status, err := blockchain.SnapshotStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain.snapshotLayers()+1, status.DiffLayers)
+
+	stateDB, err := blockchain.State()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(20*1000), stateDB.GetBalance(receiver))
+
+	// Restart the blockchain, the diff layers should be loaded from the journal
+	blockchain.Stop()
+	blockchain, err = NewBlockChain(db, cacheConfig, testGenesis.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+
+	assert.Equal(t, head.Hash(), blockchain.CurrentBlock().Hash())
+	assert.NotNil(t, blockchain.Snapshots().Snapshot(head.Root()))
+
+	status, err = blockchain.SnapshotStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.Generating)
+	assert.Equal(t, blockchain.snapshotLayers()+1, status.DiffLayers)
+}
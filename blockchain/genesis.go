@@ -145,7 +145,7 @@ func findBlockWithState(db database.DBManager) *types.Block {
 	}
 
 	startBlock := headBlock
-	for _, err := state.New(headBlock.Root(), state.NewDatabase(db)); err != nil; {
+	for _, err := state.New(headBlock.Root(), state.NewDatabase(db), nil); err != nil; {
 		if headBlock.NumberU64() == 0 {
 			logger.Crit("failed to find state from the head block to the genesis block",
 				"headBlockNum", headBlock.NumberU64(),
@@ -179,6 +179,11 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	if genesis != nil && genesis.Config == nil {
 		return params.AllGxhashProtocolChanges, common.Hash{}, errGenesisNoConfig
 	}
+	if genesis != nil {
+		if err := genesis.Config.CheckConfigForkOrder(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+	}
 
 	// Just commit the new block if there is no stored genesis block.
 	stored := db.ReadCanonicalHash(0)
@@ -281,7 +286,7 @@ func (g *Genesis) ToBlock(baseStateRoot common.Hash, db database.DBManager) *typ
 	if db == nil {
 		db = database.NewMemoryDBManager()
 	}
-	stateDB, _ := state.New(baseStateRoot, state.NewDatabase(db))
+	stateDB, _ := state.New(baseStateRoot, state.NewDatabase(db), nil)
 	for addr, account := range g.Alloc {
 		if len(account.Code) != 0 {
 			originalCode := stateDB.GetCode(addr)
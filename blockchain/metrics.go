@@ -37,4 +37,6 @@ var (
 
 	txPoolPendingGauge = metrics.NewRegisteredGauge("tx/pool/pending/gauge", nil)
 	txPoolQueueGauge   = metrics.NewRegisteredGauge("tx/pool/queue/gauge", nil)
+
+	statePruningDeletedMeter = metrics.NewRegisteredMeter("blockchain/state/pruning/deleted", nil)
 )
@@ -75,9 +75,10 @@ func (self Storage) Copy() Storage {
 // Account values can be accessed and modified through the object.
 // Finally, call CommitStorageTrie to write the modified storage trie into a database.
 type stateObject struct {
-	address common.Address
-	account account.Account
-	db      *StateDB
+	address  common.Address
+	addrHash common.Hash // hash of the address, the key of the account in the trie and the snapshot
+	account  account.Account
+	db       *StateDB
 
 	// DB error.
 	// State objects are used by the consensus core and VM which are
@@ -120,6 +121,7 @@ func newObject(db *StateDB, address common.Address, data account.Account) *state
 	return &stateObject{
 		db:            db,
 		address:       address,
+		addrHash:      crypto.Keccak256Hash(address[:]),
 		account:       data,
 		cachedStorage: make(Storage),
 		dirtyStorage:  make(Storage),
@@ -182,10 +184,26 @@ func (self *stateObject) GetState(db Database, key common.Hash) common.Hash {
 	if EnabledExpensive {
 		defer func(start time.Time) { self.db.StorageReads += time.Since(start) }(time.Now())
 	}
-	enc, err := self.getStorageTrie(db).TryGet(key[:])
-	if err != nil {
-		self.setError(err)
-		return common.Hash{}
+	var (
+		enc []byte
+		err error
+	)
+	// If the snapshot is available and the object wasn't destructed in this
+	// block, read the slot from the flat state instead of the storage trie.
+	if self.db.snap != nil {
+		if _, destructed := self.db.snapDestructs[self.addrHash]; destructed {
+			self.cachedStorage[key] = value
+			return value
+		}
+		enc, err = self.db.snap.Storage(self.addrHash, crypto.Keccak256Hash(key[:]))
+	}
+	// If snapshot unavailable or reading from it failed, load from the database
+	if self.db.snap == nil || err != nil {
+		enc, err = self.getStorageTrie(db).TryGet(key[:])
+		if err != nil {
+			self.setError(err)
+			return common.Hash{}
+		}
 	}
 	if len(enc) > 0 {
 		_, content, _, err := rlp.Split(enc)
@@ -254,15 +272,30 @@ func (self *stateObject) updateStorageTrie(db Database) Trie {
 		defer func(start time.Time) { self.db.StorageUpdates += time.Since(start) }(time.Now())
 	}
 	tr := self.getStorageTrie(db)
+
+	// Retrieve the snapshot storage map for the object
+	var storage map[common.Hash][]byte
+	if self.db.snap != nil {
+		if storage = self.db.snapStorage[self.addrHash]; storage == nil {
+			storage = make(map[common.Hash][]byte)
+			self.db.snapStorage[self.addrHash] = storage
+		}
+	}
 	for key, value := range self.dirtyStorage {
 		delete(self.dirtyStorage, key)
+
+		var v []byte
 		if (value == common.Hash{}) {
 			self.setError(tr.TryDelete(key[:]))
-			continue
+		} else {
+			// Encoding []byte cannot fail, ok to ignore the error.
+			v, _ = rlp.EncodeToBytes(bytes.TrimLeft(value[:], "\x00"))
+			self.setError(tr.TryUpdate(key[:], v))
+		}
+		// If state snapshotting is active, cache the data til commit
+		if storage != nil {
+			storage[crypto.Keccak256Hash(key[:])] = v // v will be nil if value is 0x00
 		}
-		// Encoding []byte cannot fail, ok to ignore the error.
-		v, _ := rlp.EncodeToBytes(bytes.TrimLeft(value[:], "\x00"))
-		self.setError(tr.TryUpdate(key[:], v))
 	}
 	return tr
 }
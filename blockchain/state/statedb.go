@@ -35,6 +35,7 @@ import (
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/statedb"
 )
 
@@ -62,6 +63,12 @@ type StateDB struct {
 	db   Database
 	trie Trie
 
+	snaps         *snapshot.Tree
+	snap          snapshot.Snapshot
+	snapDestructs map[common.Hash]struct{}
+	snapAccounts  map[common.Hash][]byte
+	snapStorage   map[common.Hash]map[common.Hash][]byte
+
 	// This map holds 'live' objects, which will get modified while processing a state transition.
 	stateObjects             map[common.Address]*stateObject
 	stateObjectsDirty        map[common.Address]struct{}
@@ -84,6 +91,9 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Per-transaction access list
+	accessList *accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -102,21 +112,41 @@ type StateDB struct {
 }
 
 // Create a new state from a given trie.
-func New(root common.Hash, db Database) (*StateDB, error) {
+// If snaps is given, the flat state snapshot of the root is used to accelerate
+// the account and storage reads and is updated on Commit.
+func New(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error) {
 	tr, err := db.OpenTrie(root)
 	if err != nil {
 		return nil, err
 	}
-	return &StateDB{
+	sdb := &StateDB{
 		db:                       db,
 		trie:                     tr,
+		snaps:                    snaps,
 		stateObjects:             make(map[common.Address]*stateObject),
 		stateObjectsDirtyStorage: make(map[common.Address]struct{}),
 		stateObjectsDirty:        make(map[common.Address]struct{}),
 		logs:                     make(map[common.Hash][]*types.Log),
 		preimages:                make(map[common.Hash][]byte),
 		journal:                  newJournal(),
-	}, nil
+		accessList:               newAccessList(),
+	}
+	sdb.openSnapshot(root)
+	return sdb, nil
+}
+
+// openSnapshot looks up the snapshot of the given root and prepares the maps
+// collecting the flat state changes. The snapshot is not used if it is missing.
+func (self *StateDB) openSnapshot(root common.Hash) {
+	self.snap, self.snapDestructs, self.snapAccounts, self.snapStorage = nil, nil, nil, nil
+	if self.snaps == nil {
+		return
+	}
+	if self.snap = self.snaps.Snapshot(root); self.snap != nil {
+		self.snapDestructs = make(map[common.Hash]struct{})
+		self.snapAccounts = make(map[common.Hash][]byte)
+		self.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	}
 }
 
 // RLockGCCachedNode locks the GC lock of CachedNode.
@@ -156,7 +186,9 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.logs = make(map[common.Hash][]*types.Log)
 	self.logSize = 0
 	self.preimages = make(map[common.Hash][]byte)
+	self.accessList = newAccessList()
 	self.clearJournalAndRefund()
+	self.openSnapshot(root)
 	return nil
 }
 
@@ -466,12 +498,18 @@ func (self *StateDB) updateStateObject(stateObject *stateObject) {
 		self.setError(self.trie.TryUpdateWithKeys(addr[:],
 			encodedData.trieHashKey, encodedData.trieHexKey, encodedData.data))
 		stateObject.encoded = atomic.Value{}
+		if self.snap != nil {
+			self.snapAccounts[stateObject.addrHash] = encodedData.data
+		}
 	} else {
 		data, err := rlp.EncodeToBytes(stateObject)
 		if err != nil {
 			panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 		}
 		self.setError(self.trie.TryUpdate(addr[:], data))
+		if self.snap != nil {
+			self.snapAccounts[stateObject.addrHash] = data
+		}
 	}
 }
 
@@ -484,6 +522,13 @@ func (self *StateDB) deleteStateObject(stateObject *stateObject) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 	self.setError(self.trie.TryDelete(addr[:]))
+
+	// The account and its storage are gone from the flat state as well.
+	if self.snap != nil {
+		self.snapDestructs[stateObject.addrHash] = struct{}{}
+		delete(self.snapAccounts, stateObject.addrHash)
+		delete(self.snapStorage, stateObject.addrHash)
+	}
 }
 
 // Retrieve a state object given by the address. Returns nil if not found.
@@ -500,8 +545,18 @@ func (self *StateDB) getStateObject(addr common.Address) *stateObject {
 		defer func(start time.Time) { self.AccountReads += time.Since(start) }(time.Now())
 	}
 	// Second, the object for given address is not cached.
-	// Load the object from the database.
-	enc, err := self.trie.TryGet(addr[:])
+	// Load the object from the snapshot if available, otherwise from the database.
+	var (
+		enc []byte
+		err error
+	)
+	if self.snap != nil {
+		enc, err = self.snap.AccountRLP(crypto.Keccak256Hash(addr[:]))
+	}
+	// If snapshot unavailable or reading from it failed, load from the database
+	if self.snap == nil || err != nil {
+		enc, err = self.trie.TryGet(addr[:])
+	}
 	if len(enc) == 0 {
 		self.setError(err)
 		return nil
@@ -558,12 +613,25 @@ func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObjec
 	if prev == nil {
 		self.journal.append(createObjectChange{account: &addr})
 	} else {
-		self.journal.append(resetObjectChange{prev: prev})
+		self.journal.append(resetObjectChange{prev: prev, prevdestruct: self.destructSnapshot(prev)})
 	}
 	self.setStateObject(newobj)
 	return newobj, prev
 }
 
+// destructSnapshot marks the storage of the overwritten object as deleted in the
+// flat state. It returns whether the object had already been marked.
+func (self *StateDB) destructSnapshot(prev *stateObject) bool {
+	if self.snap == nil {
+		return false
+	}
+	_, prevdestruct := self.snapDestructs[prev.addrHash]
+	if !prevdestruct {
+		self.snapDestructs[prev.addrHash] = struct{}{}
+	}
+	return prevdestruct
+}
+
 // createObjectWithMap creates a new state object with the given parameters (accountType and values).
 // If there is an existing account with the given address, it is overwritten and
 // returned as the second return value.
@@ -579,7 +647,7 @@ func (self *StateDB) createObjectWithMap(addr common.Address, accountType accoun
 	if prev == nil {
 		self.journal.append(createObjectChange{account: &addr})
 	} else {
-		self.journal.append(resetObjectChange{prev: prev})
+		self.journal.append(resetObjectChange{prev: prev, prevdestruct: self.destructSnapshot(prev)})
 	}
 	self.setStateObject(newobj)
 	return newobj, prev
@@ -656,15 +724,18 @@ func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value common
 func (self *StateDB) Copy() *StateDB {
 	// Copy all the basic fields, initialize the memory ones
 	state := &StateDB{
-		db:                self.db,
-		trie:              self.db.CopyTrie(self.trie),
-		stateObjects:      make(map[common.Address]*stateObject, len(self.journal.dirties)),
-		stateObjectsDirty: make(map[common.Address]struct{}, len(self.journal.dirties)),
-		refund:            self.refund,
-		logs:              make(map[common.Hash][]*types.Log, len(self.logs)),
-		logSize:           self.logSize,
-		preimages:         make(map[common.Hash][]byte),
-		journal:           newJournal(),
+		db:                       self.db,
+		trie:                     self.db.CopyTrie(self.trie),
+		snaps:                    self.snaps,
+		snap:                     self.snap,
+		stateObjects:             make(map[common.Address]*stateObject, len(self.journal.dirties)),
+		stateObjectsDirty:        make(map[common.Address]struct{}, len(self.journal.dirties)),
+		stateObjectsDirtyStorage: make(map[common.Address]struct{}, len(self.stateObjectsDirtyStorage)),
+		refund:                   self.refund,
+		logs:                     make(map[common.Hash][]*types.Log, len(self.logs)),
+		logSize:                  self.logSize,
+		preimages:                make(map[common.Hash][]byte),
+		journal:                  newJournal(),
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.journal.dirties {
@@ -687,12 +758,45 @@ func (self *StateDB) Copy() *StateDB {
 		}
 	}
 
+	for addr := range self.stateObjectsDirtyStorage {
+		state.stateObjectsDirtyStorage[addr] = struct{}{}
+	}
+
 	deepCopyLogs(self, state)
 
 	for hash, preimage := range self.preimages {
 		state.preimages[hash] = preimage
 	}
 
+	// Do we need to copy the access list? In practice: No. At the start of a
+	// transaction, the access list is empty. In practice, we only ever copy state
+	// _between_ transactions/blocks, never in the middle of a transaction.
+	// However, it doesn't cost us much to copy an empty list, so we do it anyway
+	// to not blow up if we ever decide copy it in the middle of a transaction
+	state.accessList = self.accessList.Copy()
+
+	if self.snap != nil {
+		// In order for the miner to be able to use and make additions
+		// to the snapshot tree, we need to copy that aswell.
+		// Otherwise, any block mined by ourselves will cause gaps in the tree,
+		// and force the miner to operate trie-backed only
+		state.snapDestructs = make(map[common.Hash]struct{}, len(self.snapDestructs))
+		for k, v := range self.snapDestructs {
+			state.snapDestructs[k] = v
+		}
+		state.snapAccounts = make(map[common.Hash][]byte, len(self.snapAccounts))
+		for k, v := range self.snapAccounts {
+			state.snapAccounts[k] = v
+		}
+		state.snapStorage = make(map[common.Hash]map[common.Hash][]byte, len(self.snapStorage))
+		for k, v := range self.snapStorage {
+			temp := make(map[common.Hash][]byte, len(v))
+			for kk, vv := range v {
+				temp[kk] = vv
+			}
+			state.snapStorage[k] = temp
+		}
+	}
 	return state
 }
 
@@ -776,6 +880,16 @@ func (stateDB *StateDB) Finalise(deleteEmptyObjects bool, setStorageRoot bool) {
 	}
 }
 
+// DirtyAddresses returns the addresses of the accounts which are finalised by
+// Finalise but not committed yet.
+func (stateDB *StateDB) DirtyAddresses() []common.Address {
+	addrs := make([]common.Address, 0, len(stateDB.stateObjectsDirty))
+	for addr := range stateDB.stateObjectsDirty {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 // IntermediateRoot computes the current root hash of the state statedb.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
@@ -794,6 +908,55 @@ func (self *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	self.thash = thash
 	self.bhash = bhash
 	self.txIndex = ti
+	self.accessList = newAccessList()
+}
+
+// PrepareAccessList handles the preparatory steps for executing a state transition
+// after the berlin compatible block. The sender, the destination and the
+// precompiled contracts are added to the access list.
+func (self *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address) {
+	self.AddAddressToAccessList(sender)
+	if dst != nil {
+		self.AddAddressToAccessList(*dst)
+	}
+	for _, addr := range precompiles {
+		self.AddAddressToAccessList(addr)
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (self *StateDB) AddAddressToAccessList(addr common.Address) {
+	if self.accessList.AddAddress(addr) {
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot)-tuple to the access list
+func (self *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := self.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		self.journal.append(accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (self *StateDB) AddressInAccessList(addr common.Address) bool {
+	return self.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (self *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return self.accessList.Contains(addr, slot)
 }
 
 func (s *StateDB) clearJournalAndRefund() {
@@ -865,6 +1028,19 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (root common.Hash, err error)
 		}
 		return nil
 	})
+	if err != nil {
+		return root, err
+	}
+	// If snapshotting is enabled, update the snapshot tree with this new version
+	if s.snap != nil {
+		// Only update if there's a state transition
+		if parent := s.snap.Root(); parent != root {
+			if err := s.snaps.Update(root, parent, s.snapDestructs, s.snapAccounts, s.snapStorage); err != nil {
+				logger.Warn("Failed to update snapshot tree", "from", parent, "to", root, "err", err)
+			}
+		}
+		s.snap, s.snapDestructs, s.snapAccounts, s.snapStorage = nil, nil, nil, nil
+	}
 	return root, err
 }
 
@@ -34,7 +34,9 @@ import (
 
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
 	"github.com/stretchr/testify/assert"
@@ -46,7 +48,7 @@ func TestUpdateLeaks(t *testing.T) {
 	// Create an empty state database
 	memDBManager := database.NewMemoryDBManager()
 	db := memDBManager.GetMemDB()
-	state, _ := New(common.Hash{}, NewDatabase(memDBManager))
+	state, _ := New(common.Hash{}, NewDatabase(memDBManager), nil)
 
 	// Update it with some accounts
 	for i := byte(0); i < 255; i++ {
@@ -79,8 +81,8 @@ func TestIntermediateLeaks(t *testing.T) {
 	transDb := transDBManager.GetMemDB()
 	finalDb := finalDBManager.GetMemDB()
 
-	transState, _ := New(common.Hash{}, NewDatabase(transDBManager))
-	finalState, _ := New(common.Hash{}, NewDatabase(finalDBManager))
+	transState, _ := New(common.Hash{}, NewDatabase(transDBManager), nil)
+	finalState, _ := New(common.Hash{}, NewDatabase(finalDBManager), nil)
 
 	modify := func(state *StateDB, addr common.Address, i, tweak byte) {
 		if i%2 == 0 {
@@ -133,7 +135,7 @@ func TestIntermediateLeaks(t *testing.T) {
 // https://github.com/ethereum/go-ethereum/pull/15549.
 func TestCopy(t *testing.T) {
 	// Create a random state test to copy and modify "independently"
-	orig, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()))
+	orig, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()), nil)
 
 	for i := byte(0); i < 255; i++ {
 		obj := orig.GetOrNewStateObject(common.BytesToAddress([]byte{i}))
@@ -195,7 +197,7 @@ func TestSnapshotRandom(t *testing.T) {
 // TestStateObjects tests basic functional operations of StateObjects.
 // It will be updated by StateDB.Commit() with state objects in StateDB.stateObjects.
 func TestStateObjects(t *testing.T) {
-	stateDB, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()))
+	stateDB, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()), nil)
 
 	// Update each account, it will update StateDB.stateObjects.
 	for i := byte(0); i < 128; i++ {
@@ -364,7 +366,7 @@ func (test *snapshotTest) String() string {
 func (test *snapshotTest) run() bool {
 	// Run all actions and create snapshots.
 	var (
-		state, _     = New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()))
+		state, _     = New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()), nil)
 		snapshotRevs = make([]int, len(test.snapshots))
 		sindex       = 0
 	)
@@ -378,7 +380,7 @@ func (test *snapshotTest) run() bool {
 	// Revert all snapshots in reverse order. Each revert must yield a state
 	// that is equivalent to fresh state with all actions up the snapshot applied.
 	for sindex--; sindex >= 0; sindex-- {
-		checkstate, _ := New(common.Hash{}, state.Database())
+		checkstate, _ := New(common.Hash{}, state.Database(), nil)
 		for _, action := range test.actions[:test.snapshots[sindex]] {
 			action.fn(action, checkstate)
 		}
@@ -457,7 +459,7 @@ func (s *StateSuite) TestSnapshotWithJournalDirties(c *check.C) {
 // TestCopyOfCopy tests that modified objects are carried over to the copy, and the copy of the copy.
 // See https://github.com/ethereum/go-ethereum/pull/15225#issuecomment-380191512
 func TestCopyOfCopy(t *testing.T) {
-	sdb, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()))
+	sdb, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()), nil)
 	addr := common.HexToAddress("aaaa")
 	sdb.SetBalance(addr, big.NewInt(42))
 
@@ -484,3 +486,97 @@ func TestZeroHashNode(t *testing.T) {
 		t.Fatalf("node should return nil value for zero hash")
 	}
 }
+
+// Tests that the state is read from and written to the snapshot tree if it is
+// given to the StateDB.
+func TestStateDBWithSnapshot(t *testing.T) {
+	var (
+		db       = database.NewMemoryDBManager()
+		sdb      = NewDatabase(db)
+		eoa      = common.HexToAddress("0x1111")
+		contract = common.HexToAddress("0x2222")
+		slot     = common.HexToHash("0x01")
+	)
+	genesis, _ := New(common.Hash{}, sdb, nil)
+	genesis.AddBalance(eoa, big.NewInt(100))
+	genesis.CreateSmartContractAccount(contract, params.CodeFormatEVM)
+	genesis.SetState(contract, slot, common.HexToHash("0xaa"))
+	root, err := genesis.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Generate the snapshot synchronously from the committed state
+	snaps, err := snapshot.New(db, sdb.TrieDB(), 16, root, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateDB, _ := New(root, sdb, snaps)
+	assert.Equal(t, big.NewInt(100), stateDB.GetBalance(eoa))
+	assert.Equal(t, common.HexToHash("0xaa"), stateDB.GetState(contract, slot))
+
+	// Commit a new state on top of the snapshot, which should create a diff layer
+	stateDB.AddBalance(eoa, big.NewInt(50))
+	stateDB.SetState(contract, slot, common.Hash{})
+	newRoot, err := stateDB.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := snaps.Snapshot(newRoot)
+	if snap == nil {
+		t.Fatal("snapshot of the new state is missing")
+	}
+	blob, err := snap.AccountRLP(crypto.Keccak256Hash(eoa[:]))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, blob)
+
+	blob, err = snap.Storage(crypto.Keccak256Hash(contract[:]), crypto.Keccak256Hash(slot[:]))
+	assert.NoError(t, err)
+	assert.Empty(t, blob)
+
+	stateDB, _ = New(newRoot, sdb, snaps)
+	assert.Equal(t, big.NewInt(150), stateDB.GetBalance(eoa))
+	assert.Equal(t, common.Hash{}, stateDB.GetState(contract, slot))
+
+	// The snapshot of the old state should not be affected
+	stateDB, _ = New(root, sdb, snaps)
+	assert.Equal(t, big.NewInt(100), stateDB.GetBalance(eoa))
+	assert.Equal(t, common.HexToHash("0xaa"), stateDB.GetState(contract, slot))
+}
+
+// Tests that the access list is reverted with the journal and copied with the state.
+func TestStateDBAccessList(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x01")
+		addr2 = common.HexToAddress("0x02")
+		slot1 = common.HexToHash("0x01")
+		slot2 = common.HexToHash("0x02")
+	)
+	stateDB, _ := New(common.Hash{}, NewDatabase(database.NewMemoryDBManager()), nil)
+
+	stateDB.PrepareAccessList(addr1, nil, nil)
+	assert.True(t, stateDB.AddressInAccessList(addr1))
+	assert.False(t, stateDB.AddressInAccessList(addr2))
+
+	snapshot := stateDB.Snapshot()
+	stateDB.AddSlotToAccessList(addr1, slot1)
+	stateDB.AddSlotToAccessList(addr2, slot2)
+	addrOk, slotOk := stateDB.SlotInAccessList(addr2, slot2)
+	assert.True(t, addrOk)
+	assert.True(t, slotOk)
+
+	cpy := stateDB.Copy()
+	stateDB.RevertToSnapshot(snapshot)
+	assert.True(t, stateDB.AddressInAccessList(addr1))
+	assert.False(t, stateDB.AddressInAccessList(addr2))
+	_, slotOk = stateDB.SlotInAccessList(addr1, slot1)
+	assert.False(t, slotOk)
+
+	// The copy is not affected by the revert
+	_, slotOk = cpy.SlotInAccessList(addr1, slot1)
+	assert.True(t, slotOk)
+	assert.True(t, cpy.AddressInAccessList(addr2))
+
+	// The access list is cleared for a new transaction
+	stateDB.Prepare(common.Hash{}, common.Hash{}, 1)
+	assert.False(t, stateDB.AddressInAccessList(addr1))
+}
@@ -0,0 +1,458 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/steakknife/bloomfilter"
+)
+
+const (
+	// DefaultStatePruningRetention is the default number of recent blocks whose
+	// committed state tries are kept by the state pruning.
+	DefaultStatePruningRetention = 2 * DefaultBlockInterval
+
+	statePruningCheckInterval = time.Minute // Interval to check if the state should be pruned
+	statePruningSweepBatch    = 10000       // Number of keys swept between the pauses of the pruning
+	statePruningMarkBatch     = 10000       // Number of nodes marked between the pauses of the pruning
+)
+
+var (
+	// statePruningBloomSize is the size of the bloom filter (in MiB) tracking
+	// the nodes to be kept. A false positive only leaves a stale node on disk.
+	statePruningBloomSize uint64 = 256
+
+	emptyStorageRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCodeHash    = crypto.Keccak256Hash(nil)
+
+	ErrStatePruningNotSupported = errors.New("state pruning is not supported by the database")
+	ErrStatePruningInProgress   = errors.New("state pruning is in progress")
+)
+
+// statePruningProgress is the progress of the state pruning stored in the
+// database. An interrupted pruning is resumed from the cursor after a restart.
+type statePruningProgress struct {
+	Number uint64 // Number of the head block when the pruning started
+	Cursor []byte // Key of the state trie database to resume the sweep from
+	Done   bool   // Whether the pruning of the block has been completed
+}
+
+// pruningBloomHasher is a wrapper around a trie node hash to satisfy the
+// interface API requirements of the bloom library.
+type pruningBloomHasher []byte
+
+func (f pruningBloomHasher) Write(p []byte) (n int, err error) { panic("not implemented") }
+func (f pruningBloomHasher) Sum(b []byte) []byte               { panic("not implemented") }
+func (f pruningBloomHasher) Reset()                            { panic("not implemented") }
+func (f pruningBloomHasher) BlockSize() int                    { panic("not implemented") }
+func (f pruningBloomHasher) Size() int                         { return 8 }
+func (f pruningBloomHasher) Sum64() uint64                     { return binary.BigEndian.Uint64(f) }
+
+// statePruner deletes the state trie nodes which are not reachable from the
+// state tries of the recent blocks. The nodes to be kept are marked in a bloom
+// filter, and the other nodes of the state trie database are swept.
+//
+// The nodes flushed to disk while pruning are marked as well. The sweep checks
+// the marks and deletes the nodes holding the lock, so a node which is written
+// again by a new block is never deleted after being written.
+type statePruner struct {
+	bc *BlockChain
+
+	lock     sync.Mutex               // Lock between marking flushed nodes and sweeping
+	marks    *bloomfilter.Filter      // Nodes to be kept
+	storages map[common.Hash]struct{} // Storage trie roots completely marked
+
+	marked  uint64 // Number of nodes marked by the traversal
+	swept   uint64 // Number of keys checked by the sweep
+	deleted uint64 // Number of nodes deleted by the sweep
+
+	start   time.Time // Time when the pruning started
+	resumed time.Time // Time when the pruning resumed from the last pause
+	logged  time.Time // Time when the progress was reported last
+}
+
+func newStatePruner(bc *BlockChain) (*statePruner, error) {
+	marks, err := bloomfilter.New(statePruningBloomSize*1024*1024*8, 4)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &statePruner{
+		bc:       bc,
+		marks:    marks,
+		storages: make(map[common.Hash]struct{}),
+		start:    now,
+		resumed:  now,
+		logged:   now,
+	}, nil
+}
+
+// markFlushed marks a node flushed to disk by the trie database.
+func (p *statePruner) markFlushed(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.marks.Add(pruningBloomHasher(hash[:]))
+}
+
+// mark marks a node reached by the traversal.
+func (p *statePruner) mark(hash common.Hash) error {
+	p.marks.Add(pruningBloomHasher(hash[:]))
+	p.marked++
+
+	if p.marked%statePruningMarkBatch == 0 {
+		p.report("mark")
+		return p.throttle()
+	}
+	return nil
+}
+
+// throttle pauses the pruning as long as it has been running since the last
+// pause, limiting the load on the database to a half. ErrQuitBySignal is
+// returned if the blockchain is stopped.
+func (p *statePruner) throttle() error {
+	select {
+	case <-time.After(time.Since(p.resumed)):
+	case <-p.bc.quit:
+		return ErrQuitBySignal
+	}
+	p.resumed = time.Now()
+	return nil
+}
+
+// report logs the progress of the pruning periodically.
+func (p *statePruner) report(phase string) {
+	if time.Since(p.logged) < log.StatsReportLimit {
+		return
+	}
+	logger.Info("Pruning state", "phase", phase, "marked", p.marked, "swept", p.swept, "deleted", p.deleted,
+		"elapsed", common.PrettyDuration(time.Since(p.start)))
+	p.logged = time.Now()
+}
+
+// markState marks all the nodes of the state trie of the given root including
+// the storage tries and the contract codes.
+func (p *statePruner) markState(root common.Hash) error {
+	db := p.bc.stateCache
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			if err := p.mark(hash); err != nil {
+				return err
+			}
+		}
+		if !it.Leaf() {
+			continue
+		}
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.LeafBlob(), serializer); err != nil {
+			return err
+		}
+		pa := account.GetProgramAccount(serializer.GetAccount())
+		if pa == nil {
+			continue
+		}
+		if err := p.markStorage(pa.GetStorageRoot()); err != nil {
+			return err
+		}
+		if codeHash := common.BytesToHash(pa.GetCodeHash()); codeHash != emptyCodeHash {
+			if err := p.mark(codeHash); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+// markStorage marks all the nodes of the storage trie of the given root. The
+// storage tries shared by the state tries are traversed only once.
+func (p *statePruner) markStorage(root common.Hash) error {
+	if root == emptyStorageRoot {
+		return nil
+	}
+	if _, ok := p.storages[root]; ok {
+		return nil
+	}
+	tr, err := p.bc.stateCache.OpenStorageTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			if err := p.mark(hash); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	p.storages[root] = struct{}{}
+	return nil
+}
+
+// markRetained marks the state tries to be kept. Those are the state tries of
+// the blocks from the retention boundary up to the current head, which are
+// either committed to disk or still referenced in memory, and the state trie
+// of the snapshot disk layer.
+func (p *statePruner) markRetained(number uint64) error {
+	var (
+		bc       = p.bc
+		triedb   = bc.stateCache.TrieDB()
+		head     = bc.CurrentBlock().NumberU64()
+		boundary = uint64(0)
+		roots    []common.Hash
+		seen     = make(map[common.Hash]struct{})
+	)
+	if number > bc.cacheConfig.StatePruningRetention {
+		boundary = number - bc.cacheConfig.StatePruningRetention
+	}
+	// The head is read after setting the pruning marker, so the nodes of the
+	// following blocks are either reachable from the head or marked when flushed.
+	for n := head; ; n-- {
+		if header := bc.GetHeaderByNumber(n); header != nil {
+			roots = append(roots, header.Root)
+		}
+		if n <= boundary {
+			break
+		}
+	}
+	if bc.snaps != nil {
+		roots = append(roots, bc.snaps.DiskRoot())
+	}
+	for _, root := range roots {
+		if _, ok := seen[root]; ok {
+			continue
+		}
+		seen[root] = struct{}{}
+
+		if !triedb.DoesExistCachedNode(root) && !triedb.DoesExistNodeInPersistent(root) {
+			continue
+		}
+		if err := p.markState(root); err != nil {
+			// The state trie only residing in the memory can be garbage collected
+			// while being marked, but the other failures should stop the sweep.
+			if err != ErrQuitBySignal && !triedb.DoesExistCachedNode(root) && !triedb.DoesExistNodeInPersistent(root) {
+				continue
+			}
+			return fmt.Errorf("failed to mark state %x: %v", root, err)
+		}
+	}
+	return nil
+}
+
+// sweep deletes the nodes of the state trie database which are not marked,
+// starting from the given cursor. The cursor is stored periodically to resume
+// the sweep after a restart.
+func (p *statePruner) sweep(number uint64, cursor []byte) error {
+	var (
+		db      = p.bc.db
+		it      = db.NewStateTrieDBIterator(cursor)
+		batch   = db.NewBatch(database.StateTrieDB)
+		swap    = time.Now()
+		scanned int
+	)
+	defer func() { it.Release() }()
+
+	p.lock.Lock()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		p.swept++
+		scanned++
+		if !p.marks.Contains(pruningBloomHasher(key)) {
+			if err := batch.Delete(common.CopyBytes(key)); err != nil {
+				p.lock.Unlock()
+				return err
+			}
+			p.deleted++
+		}
+		if scanned < statePruningSweepBatch {
+			continue
+		}
+		// Flush the deletions holding the lock and store the progress
+		if err := batch.Write(); err != nil {
+			p.lock.Unlock()
+			return err
+		}
+		p.lock.Unlock()
+
+		batch.Reset()
+		scanned = 0
+		cursor = common.CopyBytes(key)
+		p.bc.writeStatePruningProgress(&statePruningProgress{Number: number, Cursor: cursor})
+		p.report("sweep")
+
+		// Restart the iterator now and again not to hold a database snapshot
+		if time.Since(swap) > log.StatsReportLimit {
+			it.Release()
+			it = db.NewStateTrieDBIterator(cursor)
+			swap = time.Now()
+		}
+		if err := p.throttle(); err != nil {
+			return err
+		}
+		p.lock.Lock()
+	}
+	defer p.lock.Unlock()
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// readStatePruningProgress retrieves the progress of the state pruning from
+// the database. Nil is returned if the state has never been pruned.
+func (bc *BlockChain) readStatePruningProgress() *statePruningProgress {
+	blob := bc.db.ReadStatePruningProgress()
+	if len(blob) == 0 {
+		return nil
+	}
+	progress := new(statePruningProgress)
+	if err := rlp.DecodeBytes(blob, progress); err != nil {
+		logger.Error("Failed to decode state pruning progress", "err", err)
+		return nil
+	}
+	return progress
+}
+
+// writeStatePruningProgress stores the progress of the state pruning.
+func (bc *BlockChain) writeStatePruningProgress(progress *statePruningProgress) {
+	blob, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		logger.Crit("Failed to encode state pruning progress", "err", err)
+	}
+	bc.db.WriteStatePruningProgress(blob)
+}
+
+// isStatePruningSupported returns whether the state trie database can be
+// iterated to sweep the stale nodes.
+func isStatePruningSupported(db database.DBManager) bool {
+	dbType := db.GetDBConfig().DBType
+	return dbType == database.LevelDB || dbType == database.MemoryDB
+}
+
+// statePruningTarget returns the number of the head block if the state should
+// be pruned. The pruning is resumed if it has been interrupted, and otherwise
+// starts again every retention blocks. It doesn't run while the state is being
+// migrated or downloaded.
+func (bc *BlockChain) statePruningTarget() (uint64, bool) {
+	if bc.db.InMigration() {
+		return 0, false
+	}
+	head := bc.CurrentBlock().NumberU64()
+	if fast := bc.CurrentFastBlock(); fast != nil && fast.NumberU64() > head {
+		return 0, false
+	}
+	retention := bc.cacheConfig.StatePruningRetention
+	if head <= retention {
+		return 0, false
+	}
+	progress := bc.readStatePruningProgress()
+	if progress != nil && progress.Done && head < progress.Number+retention {
+		return 0, false
+	}
+	return head, true
+}
+
+// statePruningLoop checks periodically if the state should be pruned and prunes
+// it in the background.
+func (bc *BlockChain) statePruningLoop() {
+	defer bc.wg.Done()
+
+	ticker := time.NewTicker(statePruningCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if number, ok := bc.statePruningTarget(); ok {
+			if err := bc.pruneState(number); err != nil {
+				if err == ErrQuitBySignal {
+					logger.Info("State pruning stopped by quit signal; should continue on node restart")
+					return
+				}
+				logger.Error("Failed to prune state", "number", number, "err", err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
+// pruneState deletes the state trie nodes which are not reachable from the
+// state tries of the recent blocks of the given head block number.
+func (bc *BlockChain) pruneState(number uint64) error {
+	if !atomic.CompareAndSwapInt32(&bc.statePruning, 0, 1) {
+		return ErrStatePruningInProgress
+	}
+	defer atomic.StoreInt32(&bc.statePruning, 0)
+
+	if bc.db.InMigration() {
+		return errors.New("state migration is in progress")
+	}
+	var cursor []byte
+	if progress := bc.readStatePruningProgress(); progress != nil && !progress.Done {
+		cursor = progress.Cursor
+		logger.Info("Resuming state pruning", "number", number, "cursor", common.Bytes2Hex(cursor))
+	} else {
+		logger.Info("Starting state pruning", "number", number)
+	}
+	pruner, err := newStatePruner(bc)
+	if err != nil {
+		return err
+	}
+	bc.writeStatePruningProgress(&statePruningProgress{Number: number, Cursor: cursor})
+
+	triedb := bc.stateCache.TrieDB()
+	triedb.SetPruningMarker(pruner.markFlushed)
+	defer triedb.SetPruningMarker(nil)
+
+	if err := pruner.markRetained(number); err != nil {
+		return err
+	}
+	if err := pruner.sweep(number, cursor); err != nil {
+		return err
+	}
+	bc.writeStatePruningProgress(&statePruningProgress{Number: number, Done: true})
+
+	statePruningDeletedMeter.Mark(int64(pruner.deleted))
+	logger.Info("State pruning is completed", "number", number, "marked", pruner.marked,
+		"swept", pruner.swept, "deleted", pruner.deleted, "elapsed", common.PrettyDuration(time.Since(pruner.start)))
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPruningInterval  = 4
+	testPruningRetention = 8
+)
+
+// newPruningTestChain creates a blockchain pruning its state, and the blocks
+// sending values to the different receivers to leave stale state trie nodes.
+func newPruningTestChain(t *testing.T, n int) (*BlockChain, []*types.Block) {
+	var (
+		gendb       = database.NewMemoryDBManager()
+		key, _      = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address     = crypto.PubkeyToAddress(key.PublicKey)
+		funds       = big.NewInt(100000000000000000)
+		testGenesis = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: funds}},
+		}
+		genesis = testGenesis.MustCommit(gendb)
+		signer  = types.NewEIP155Signer(testGenesis.Config.ChainID)
+	)
+	db := database.NewMemoryDBManager()
+	testGenesis.MustCommit(db)
+
+	cacheConfig := &CacheConfig{
+		CacheSize:             512,
+		BlockInterval:         testPruningInterval,
+		TriesInMemory:         DefaultTriesInMemory,
+		TrieNodeCacheConfig:   statedb.GetEmptyTrieNodeCacheConfig(),
+		StatePruning:          true,
+		StatePruningRetention: testPruningRetention,
+	}
+	bc, err := NewBlockChain(db, cacheConfig, testGenesis.Config, gxhash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, _ := GenerateChain(testGenesis.Config, genesis, gxhash.NewFaker(), gendb, n, func(i int, block *BlockGen) {
+		receiver := common.BigToAddress(big.NewInt(int64(0x1000 + i%5)))
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), receiver, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	return bc, blocks
+}
+
+// stateTrieKeys returns the sorted keys of the trie nodes in the state trie database.
+func stateTrieKeys(db database.DBManager) [][]byte {
+	var keys [][]byte
+	it := db.NewStateTrieDBIterator(nil)
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) == common.HashLength {
+			keys = append(keys, common.CopyBytes(it.Key()))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	return keys
+}
+
+// assertCompleteState checks that the whole state trie of the given block can
+// be iterated.
+func assertCompleteState(t *testing.T, bc *BlockChain, number uint64) {
+	stateDB, err := bc.StateAt(bc.GetHeaderByNumber(number).Root)
+	if err != nil {
+		t.Fatalf("state of block %d is not available: %v", number, err)
+	}
+	it := state.NewNodeIterator(stateDB)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("state of block %d is incomplete: %v", number, it.Error)
+	}
+}
+
+// Tests that the stale state trie nodes are deleted while the state tries of
+// the recent blocks are kept, and that the blocks can be inserted after pruning.
+func TestStatePruning(t *testing.T) {
+	// A small bloom filter is enough for the test chain
+	defer func(size uint64) { statePruningBloomSize = size }(statePruningBloomSize)
+	statePruningBloomSize = 1
+
+	bc, blocks := newPruningTestChain(t, 60)
+	defer bc.Stop()
+
+	if n, err := bc.InsertChain(blocks[:40]); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	number, ok := bc.statePruningTarget()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(40), number)
+
+	before := len(stateTrieKeys(bc.db))
+	assert.NoError(t, bc.pruneState(number))
+	assert.True(t, len(stateTrieKeys(bc.db)) < before)
+
+	// The committed state tries before the retention are deleted
+	for _, n := range []uint64{4, 16, 28} {
+		exist, _ := bc.db.HasStateTrieNode(bc.GetHeaderByNumber(n).Root[:])
+		assert.False(t, exist, "state of block %d is not pruned", n)
+	}
+	// The committed state tries within the retention and the tries in memory are kept
+	for _, n := range []uint64{32, 36, 37, 38, 39, 40} {
+		assertCompleteState(t, bc, n)
+	}
+	progress := bc.readStatePruningProgress()
+	assert.Equal(t, &statePruningProgress{Number: 40, Cursor: []byte{}, Done: true}, progress)
+
+	// The state is not pruned again until the head passes the retention
+	_, ok = bc.statePruningTarget()
+	assert.False(t, ok)
+
+	if n, err := bc.InsertChain(blocks[40:]); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	assertCompleteState(t, bc, 60)
+
+	number, ok = bc.statePruningTarget()
+	assert.True(t, ok)
+	assert.NoError(t, bc.pruneState(number))
+	assertCompleteState(t, bc, 60)
+	assertCompleteState(t, bc, 52)
+}
+
+// Tests that an interrupted pruning is resumed from the stored cursor.
+func TestStatePruningResume(t *testing.T) {
+	// A small bloom filter is enough for the test chain
+	defer func(size uint64) { statePruningBloomSize = size }(statePruningBloomSize)
+	statePruningBloomSize = 1
+
+	bc, blocks := newPruningTestChain(t, 40)
+	defer bc.Stop()
+
+	if n, err := bc.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	keys := stateTrieKeys(bc.db)
+	cursor := keys[len(keys)/2]
+	bc.writeStatePruningProgress(&statePruningProgress{Number: 20, Cursor: cursor})
+
+	number, ok := bc.statePruningTarget()
+	assert.True(t, ok)
+	assert.NoError(t, bc.pruneState(number))
+
+	// The keys before the cursor have been swept before the interruption
+	pruned := stateTrieKeys(bc.db)
+	assert.Equal(t, keys[:len(keys)/2], pruned[:len(keys)/2])
+	assert.True(t, len(pruned) < len(keys))
+	assert.True(t, bc.readStatePruningProgress().Done)
+
+	for _, n := range []uint64{32, 36, 40} {
+		assertCompleteState(t, bc, n)
+	}
+}
+
+// Tests that the nodes flushed to disk while pruning are not deleted.
+func TestStatePruningKeepsFlushedNodes(t *testing.T) {
+	// A small bloom filter is enough for the test chain
+	defer func(size uint64) { statePruningBloomSize = size }(statePruningBloomSize)
+	statePruningBloomSize = 1
+
+	bc, blocks := newPruningTestChain(t, 40)
+	defer bc.Stop()
+
+	if n, err := bc.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	var (
+		flushed = bc.GetHeaderByNumber(4).Root
+		stale   = bc.GetHeaderByNumber(8).Root
+	)
+	pruner, err := newStatePruner(bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, pruner.markRetained(40))
+
+	pruner.markFlushed(flushed)
+	assert.NoError(t, pruner.sweep(40, nil))
+
+	exist, _ := bc.db.HasStateTrieNode(flushed[:])
+	assert.True(t, exist)
+	exist, _ = bc.db.HasStateTrieNode(stale[:])
+	assert.False(t, exist)
+}
@@ -245,6 +245,11 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, kerr kerr
 		errTxFailed error
 	)
 
+	// The sender, the recipient and the pre-compiled contracts are warm from the start of the transaction.
+	if rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber); rules.IsBerlin {
+		st.state.PrepareAccessList(msg.ValidatedSender(), msg.To(), vm.ActivePrecompiles(rules))
+	}
+
 	ret, st.gas, errTxFailed = msg.Execute(st.evm, st.state, st.evm.BlockNumber.Uint64(), st.gas, st.value)
 
 	if errTxFailed != nil {
@@ -21,18 +21,27 @@
 package blockchain
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"os"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/rlp"
 )
 
-// errNoActiveJournal is returned if a transaction is attempted to be inserted
-// into the journal, but no such file is currently open.
-var errNoActiveJournal = errors.New("no active journal")
+var (
+	// errNoActiveJournal is returned if a transaction is attempted to be inserted
+	// into the journal, but no such file is currently open.
+	errNoActiveJournal = errors.New("no active journal")
+
+	// errJournalFull is returned if a transaction is attempted to be inserted
+	// into the journal, but the journal already has the maximum number of
+	// transactions until the next rotation.
+	errJournalFull = errors.New("journal is full")
+)
 
 // devNull is a WriteCloser that just discards anything written into it. Its
 // goal is to allow the transaction journal to write into a fake journal when
@@ -44,16 +53,35 @@ func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
 func (*devNull) Close() error                      { return nil }
 
 // txJournal is a rotating log of transactions with the aim of storing locally
-// created transactions to allow non-executed ones to survive node restarts.
+// created or remotely received transactions to allow non-executed ones to
+// survive node restarts.
 type txJournal struct {
 	path   string         // Filesystem path to store the transactions at
 	writer io.WriteCloser // Output stream to write new transactions into
+	kind   string         // Kind of the journaled transactions, used in the logs
+	limit  int            // Maximum number of transactions in the journal (0 = unlimited)
+	count  int            // Number of transactions in the journal
+
+	lastRotation time.Time // Time when the journal was regenerated last
 }
 
-// newTxJournal creates a new transaction journal to
-func newTxJournal(path string) *txJournal {
+// TxJournalStats is the status of a transaction journal.
+type TxJournalStats struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	Transactions int       `json:"transactions"`
+	Limit        int       `json:"limit"`
+	LastRotation time.Time `json:"lastRotation"`
+}
+
+// newTxJournal creates a new transaction journal to store the transactions of
+// the given kind. If limit is not zero, the journal keeps at most limit
+// transactions.
+func newTxJournal(path string, kind string, limit int) *txJournal {
 	return &txJournal{
-		path: path,
+		path:  path,
+		kind:  kind,
+		limit: limit,
 	}
 }
 
@@ -76,6 +104,16 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 	defer func() { journal.writer = nil }()
 
 	// Inject all transactions from the journal into the pool
+	total, dropped, failure := loadTransactions(input, add)
+	logger.Info("Loaded transaction journal", "kind", journal.kind, "transactions", total, "dropped", dropped)
+
+	return failure
+}
+
+// loadTransactions decodes the RLP encoded transactions from the input, and adds
+// them to the pool in batches. It returns the number of the decoded transactions
+// and the number of the transactions failed to be added.
+func loadTransactions(input io.Reader, add func([]*types.Transaction) []error) (int, int, error) {
 	stream := rlp.NewStream(input, 0)
 	total, dropped := 0, 0
 
@@ -97,7 +135,7 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 	for {
 		// Parse the next transaction and terminate on error
 		tx := new(types.Transaction)
-		if err = stream.Decode(tx); err != nil {
+		if err := stream.Decode(tx); err != nil {
 			if err != io.EOF {
 				failure = err
 			}
@@ -114,24 +152,45 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 			batch = batch[:0]
 		}
 	}
-	logger.Info("Loaded local transaction journal", "transactions", total, "dropped", dropped)
-
-	return failure
+	return total, dropped, failure
 }
 
 // insert adds the specified transaction to the local disk journal.
 func (journal *txJournal) insert(tx *types.Transaction) error {
+	return journal.insertAll([]*types.Transaction{tx})
+}
+
+// insertAll adds the specified transactions to the local disk journal with a
+// single write. If the journal has a limit, the transactions exceeding the
+// limit are not added and errJournalFull is returned.
+func (journal *txJournal) insertAll(txs []*types.Transaction) error {
 	if journal.writer == nil {
 		return errNoActiveJournal
 	}
-	if err := rlp.Encode(journal.writer, tx); err != nil {
+	full := false
+	if journal.limit > 0 && journal.count+len(txs) > journal.limit {
+		txs = txs[:journal.limit-journal.count]
+		full = true
+	}
+	var buf bytes.Buffer
+	for _, tx := range txs {
+		if err := rlp.Encode(&buf, tx); err != nil {
+			return err
+		}
+	}
+	if _, err := journal.writer.Write(buf.Bytes()); err != nil {
 		return err
 	}
+	journal.count += len(txs)
+	if full {
+		return errJournalFull
+	}
 	return nil
 }
 
 // rotate regenerates the transaction journal based on the current contents of
-// the transaction pool.
+// the transaction pool. If the journal has a limit, the transactions exceeding
+// the limit are not journaled.
 func (journal *txJournal) rotate(all map[common.Address]types.Transactions) error {
 	// Close the current journal (if any is open)
 	if journal.writer != nil {
@@ -147,6 +206,9 @@ func (journal *txJournal) rotate(all map[common.Address]types.Transactions) erro
 	}
 	journaled := 0
 	for _, txs := range all {
+		if journal.limit > 0 && journaled+len(txs) > journal.limit {
+			txs = txs[:journal.limit-journaled]
+		}
 		for _, tx := range txs {
 			if err = rlp.Encode(replacement, tx); err != nil {
 				replacement.Close()
@@ -166,11 +228,27 @@ func (journal *txJournal) rotate(all map[common.Address]types.Transactions) erro
 		return err
 	}
 	journal.writer = sink
-	logger.Info("Regenerated local transaction journal", "transactions", journaled, "accounts", len(all))
+	journal.count = journaled
+	journal.lastRotation = time.Now()
+	logger.Info("Regenerated transaction journal", "kind", journal.kind, "transactions", journaled, "accounts", len(all))
 
 	return nil
 }
 
+// stats returns the status of the transaction journal.
+func (journal *txJournal) stats() *TxJournalStats {
+	stats := &TxJournalStats{
+		Path:         journal.path,
+		Transactions: journal.count,
+		Limit:        journal.limit,
+		LastRotation: journal.lastRotation,
+	}
+	if info, err := os.Stat(journal.path); err == nil {
+		stats.Size = info.Size()
+	}
+	return stats
+}
+
 // close flushes the transaction journal contents to disk and closes the file.
 func (journal *txJournal) close() error {
 	var err error
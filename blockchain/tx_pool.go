@@ -23,6 +23,7 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"sort"
@@ -36,6 +37,7 @@ import (
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/kerrors"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
 	"github.com/rcrowley/go-metrics"
 )
 
@@ -106,6 +108,9 @@ type TxPoolConfig struct {
 	Journal            string        // Journal of local transactions to survive node restarts
 	JournalInterval    time.Duration // Time interval to regenerate the local transaction journal
 
+	RemoteJournal      string // Journal of remote transactions to survive node restarts (empty = disabled)
+	RemoteJournalLimit uint64 // Maximum number of transactions in the remote transaction journal
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -118,6 +123,12 @@ type TxPoolConfig struct {
 	Lifetime   time.Duration // Maximum amount of time non-executable transaction are queued
 
 	NoAccountCreation bool // Whether account creation transactions should be disabled
+
+	SenderRateLimit float64          // Number of transactions per second admitted from a sender (0 = unlimited)
+	SenderRateBurst uint64           // Maximum number of transactions admitted from a sender at once
+	FeePayerQuota   uint64           // Maximum number of fee-delegated transactions in the pool paid by a fee payer (0 = unlimited)
+	DenyList        []common.Address // Senders and fee payers whose transactions are rejected
+	AllowList       []common.Address // Senders whose transactions are only admitted, if not empty
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -126,6 +137,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:         "transactions.rlp",
 	JournalInterval: time.Hour,
 
+	RemoteJournalLimit: 5120,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -136,6 +149,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 
 	KeepLocals: false,
 	Lifetime:   5 * time.Minute,
+
+	SenderRateBurst: 64,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -154,6 +169,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		logger.Error("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if conf.SenderRateLimit < 0 {
+		logger.Error("Sanitizing invalid txpool sender rate limit", "provided", conf.SenderRateLimit, "updated", 0)
+		conf.SenderRateLimit = 0
+	}
+	if conf.SenderRateLimit > 0 && conf.SenderRateBurst < 1 {
+		logger.Error("Sanitizing invalid txpool sender rate burst", "provided", conf.SenderRateBurst, "updated", DefaultTxPoolConfig.SenderRateBurst)
+		conf.SenderRateBurst = DefaultTxPoolConfig.SenderRateBurst
+	}
 	return conf
 }
 
@@ -180,8 +203,9 @@ type TxPool struct {
 	currentState       *state.StateDB            // Current state in the blockchain head
 	pendingNonce       map[common.Address]uint64 // Pending nonce tracking virtual nonces
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *txJournal  // Journal of local transaction to back up to disk
+	locals        *accountSet // Set of local transaction to exempt from eviction rules
+	journal       *txJournal  // Journal of local transaction to back up to disk
+	remoteJournal *txJournal  // Journal of remote transaction to back up to disk
 
 	//TODO-Klaytn
 	txMu sync.RWMutex
@@ -192,6 +216,11 @@ type TxPool struct {
 	all     map[common.Hash]*types.Transaction // All transactions to allow lookups
 	priced  *txPricedList                      // All transactions sorted by price
 
+	feePayerTxs map[common.Address]int // Number of fee-delegated transactions in the pool by fee payer
+
+	addressList *AddressListPolicy  // Deny and allow lists of the addresses
+	policies    []TxAdmissionPolicy // Admission policies checked before adding a transaction
+
 	wg sync.WaitGroup // for shutdown sync
 
 	txMsgCh chan types.Transactions
@@ -213,6 +242,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:        make(map[common.Address]*txList),
 		beats:        make(map[common.Address]time.Time),
 		all:          make(map[common.Hash]*types.Transaction),
+		feePayerTxs:  make(map[common.Address]int),
 		pendingNonce: make(map[common.Address]uint64),
 		chainHeadCh:  make(chan ChainHeadEvent, chainHeadChanSize),
 		// TODO-Klaytn We use ChainConfig.UnitPrice to initialize TxPool.gasPrice,
@@ -222,11 +252,18 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	}
 	pool.locals = newAccountSet(pool.signer)
 	pool.priced = newTxPricedList(&pool.all)
+
+	// Set up the admission policies enabled by the configuration
+	pool.addressList = NewAddressListPolicy(config.DenyList, config.AllowList)
+	pool.policies = []TxAdmissionPolicy{pool.addressList}
+	if config.FeePayerQuota > 0 {
+		pool.policies = append(pool.policies, NewFeePayerQuotaPolicy(config.FeePayerQuota, pool.countFeeDelegatedTxs))
+	}
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// If local transactions and journaling is enabled, load from disk
 	if !config.NoLocals && config.Journal != "" {
-		pool.journal = newTxJournal(config.Journal)
+		pool.journal = newTxJournal(config.Journal, "local", 0)
 
 		if err := pool.journal.load(pool.AddLocals); err != nil {
 			logger.Error("Failed to load transaction journal", "err", err)
@@ -235,6 +272,22 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 			logger.Error("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If remote transaction journaling is enabled, load from disk
+	if config.RemoteJournal != "" {
+		pool.remoteJournal = newTxJournal(config.RemoteJournal, "remote", int(config.RemoteJournalLimit))
+
+		if err := pool.remoteJournal.load(pool.AddRemotes); err != nil {
+			logger.Error("Failed to load remote transaction journal", "err", err)
+		}
+		if err := pool.remoteJournal.rotate(pool.remote()); err != nil {
+			logger.Error("Failed to rotate remote transaction journal", "err", err)
+		}
+	}
+	// The sender rate limit is set up after loading the journals, since the
+	// journaled transactions were already admitted before the restart.
+	if config.SenderRateLimit > 0 {
+		pool.policies = append(pool.policies, NewSenderRateLimitPolicy(config.SenderRateLimit, config.SenderRateBurst))
+	}
 	// Subscribe events from blockchain
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
 
@@ -326,7 +379,7 @@ func (pool *TxPool) loop() {
 			}
 			pool.mu.Unlock()
 
-			// Handle local transaction journal rotation
+			// Handle local and remote transaction journal rotation
 		case <-journal.C:
 			if pool.journal != nil {
 				pool.mu.Lock()
@@ -335,6 +388,13 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.remoteJournal != nil {
+				pool.mu.Lock()
+				if err := pool.remoteJournal.rotate(pool.remote()); err != nil {
+					logger.Error("Failed to rotate remote tx journal", "err", err)
+				}
+				pool.mu.Unlock()
+			}
 		}
 	}
 }
@@ -418,7 +478,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	//pool.mu.Lock()
 	//defer pool.mu.Unlock()
 
-	pool.addTxsLocked(reinject, false)
+	pool.addTxsLocked(reinject, false, false)
 
 	// validate the pool of pending transactions, this will remove
 	// any transactions that have been included in the block or
@@ -453,6 +513,9 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.remoteJournal != nil {
+		pool.remoteJournal.close()
+	}
 	logger.Info("Transaction pool stopped")
 }
 
@@ -482,6 +545,7 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 		pool.queue = make(map[common.Address]*txList)
 		pool.beats = make(map[common.Address]time.Time)
 		pool.all = make(map[common.Hash]*types.Transaction)
+		pool.feePayerTxs = make(map[common.Address]int)
 		pool.pendingNonce = make(map[common.Address]uint64)
 		pool.locals = newAccountSet(pool.signer)
 		pool.priced = newTxPricedList(&pool.all)
@@ -606,6 +670,24 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// remote retrieves all currently known remote transactions, grouped by origin
+// account and sorted by nonce. The returned transaction set is a copy and can be
+// freely modified by calling code.
+func (pool *TxPool) remote() map[common.Address]types.Transactions {
+	txs := make(map[common.Address]types.Transactions)
+	for addr, pending := range pool.pending {
+		if !pool.locals.contains(addr) {
+			txs[addr] = append(txs[addr], pending.Flatten()...)
+		}
+	}
+	for addr, queued := range pool.queue {
+		if !pool.locals.contains(addr) {
+			txs[addr] = append(txs[addr], queued.Flatten()...)
+		}
+	}
+	return txs
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction) error {
@@ -740,7 +822,11 @@ func (pool *TxPool) getMaxTxFromQueueWhenNonceIsMissing(tx *types.Transaction, f
 // If a newly added transaction is marked as local, its sending account will be
 // whitelisted, preventing any associated transaction from being dropped out of
 // the pool due to pricing constraints.
-func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
+//
+// If a transaction is imported, it is not throttled by the admission policies.
+// The added transactions are not journaled here, since the callers journal
+// them at once with journalTxs.
+func (pool *TxPool) add(tx *types.Transaction, local, imported bool) (bool, error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all[hash] != nil {
@@ -753,6 +839,11 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxCounter.Inc(1)
 		return false, err
 	}
+	// If the transaction is rejected by an admission policy, discard it
+	if err := pool.admit(tx, local, imported); err != nil {
+		logger.Trace("Discarding transaction rejected by policy", "hash", hash, "err", err)
+		return false, err
+	}
 
 	// If the transaction pool is full and new Tx is valid,
 	// (1) discard a new Tx if there is no room for the account of the Tx
@@ -806,13 +897,12 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 		// New transaction is better, replace old one
 		if old != nil {
-			delete(pool.all, old.Hash())
+			pool.removeFromAll(old.Hash())
 			pool.priced.Removed()
 			pendingReplaceCounter.Inc(1)
 		}
-		pool.all[tx.Hash()] = tx
+		pool.addToAll(tx)
 		pool.priced.Put(tx)
-		pool.journalTx(from, tx)
 
 		logger.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
@@ -826,16 +916,103 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	// Mark local addresses and journal local transactions
+	// Mark local addresses
 	if local {
 		pool.locals.add(from)
 	}
-	pool.journalTx(from, tx)
 
 	logger.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replace, nil
 }
 
+// admit checks the transaction against the admission policies in order, and
+// returns the error of the first policy rejecting it. The throttling policies
+// are not checked for an imported transaction.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) admit(tx *types.Transaction, local, imported bool) error {
+	from := tx.ValidatedSender()
+	for _, policy := range pool.policies {
+		if _, throttling := policy.(txThrottlingPolicy); throttling && imported {
+			continue
+		}
+		if err := policy.Admit(tx, from, local); err != nil {
+			markPolicyRejection(policy, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// countFeeDelegatedTxs returns the number of the fee-delegated transactions
+// paid by the given fee payer in the pool.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) countFeeDelegatedTxs(feePayer common.Address) int {
+	return pool.feePayerTxs[feePayer]
+}
+
+// addToAll adds a transaction to the lookup of all transactions, and counts
+// it for its fee payer if it is fee-delegated.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) addToAll(tx *types.Transaction) {
+	hash := tx.Hash()
+	if pool.all[hash] != nil {
+		return
+	}
+	pool.all[hash] = tx
+	if tx.IsFeeDelegatedTransaction() {
+		pool.feePayerTxs[tx.ValidatedFeePayer()]++
+	}
+}
+
+// removeFromAll removes a transaction from the lookup of all transactions,
+// and uncounts it for its fee payer if it is fee-delegated.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) removeFromAll(hash common.Hash) {
+	tx := pool.all[hash]
+	if tx == nil {
+		return
+	}
+	delete(pool.all, hash)
+	if tx.IsFeeDelegatedTransaction() {
+		feePayer := tx.ValidatedFeePayer()
+		if pool.feePayerTxs[feePayer] <= 1 {
+			delete(pool.feePayerTxs, feePayer)
+		} else {
+			pool.feePayerTxs[feePayer]--
+		}
+	}
+}
+
+// AddAdmissionPolicy appends an admission policy checked before adding a transaction.
+func (pool *TxPool) AddAdmissionPolicy(policy TxAdmissionPolicy) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.policies = append(pool.policies, policy)
+}
+
+// AdmissionPolicies returns the names of the admission policies in order.
+func (pool *TxPool) AdmissionPolicies() []string {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	names := make([]string, len(pool.policies))
+	for i, policy := range pool.policies {
+		names[i] = policy.Name()
+	}
+	return names
+}
+
+// AddressList returns the policy of the deny and allow lists of the addresses,
+// which can be updated while the pool is running.
+func (pool *TxPool) AddressList() *AddressListPolicy {
+	return pool.addressList
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -853,12 +1030,12 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.removeFromAll(old.Hash())
 		pool.priced.Removed()
 		queuedReplaceCounter.Inc(1)
 	}
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.addToAll(tx)
 		pool.priced.Put(tx)
 	}
 
@@ -866,18 +1043,86 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	return old != nil, nil
 }
 
-// journalTx adds the specified transaction to the local disk journal if it is
-// deemed to have been sent from a local account.
-func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
-	// Only journal if it's enabled and the transaction is local
-	if pool.journal == nil || !pool.locals.contains(from) {
-		return
+// journalTxs adds the specified transactions to the local disk journal if they
+// are deemed to have been sent from a local account, or to the remote disk
+// journal otherwise. Each journal is written at once to keep the disk writes
+// out of the per transaction path.
+func (pool *TxPool) journalTxs(txs []*types.Transaction) {
+	var locals, remotes []*types.Transaction
+	for _, tx := range txs {
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		if pool.locals.contains(from) {
+			locals = append(locals, tx)
+		} else {
+			remotes = append(remotes, tx)
+		}
+	}
+	if pool.journal != nil && len(locals) > 0 {
+		if err := pool.journal.insertAll(locals); err != nil {
+			logger.Error("Failed to journal local transactions", "err", err)
+		}
 	}
-	if err := pool.journal.insert(tx); err != nil {
-		logger.Error("Failed to journal local transaction", "err", err)
+	// The transactions exceeding the limit will be journaled on the next rotation
+	// if they are still in the pool, so the full journal is not an error.
+	if pool.remoteJournal != nil && len(remotes) > 0 {
+		if err := pool.remoteJournal.insertAll(remotes); err != nil && err != errJournalFull {
+			logger.Error("Failed to journal remote transactions", "err", err)
+		}
 	}
 }
 
+// JournalStats returns the status of the local and the remote transaction
+// journals, keyed by the kind. Disabled journals are not included.
+func (pool *TxPool) JournalStats() map[string]*TxJournalStats {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	stats := make(map[string]*TxJournalStats)
+	for _, journal := range []*txJournal{pool.journal, pool.remoteJournal} {
+		if journal != nil {
+			stats[journal.kind] = journal.stats()
+		}
+	}
+	return stats
+}
+
+// ExportPending writes the RLP encoded pending transactions to the writer in
+// the journal format, and returns the number of the written transactions.
+func (pool *TxPool) ExportPending(w io.Writer) (int, error) {
+	pending, err := pool.Pending()
+	if err != nil {
+		return 0, err
+	}
+	exported := 0
+	for _, txs := range pending {
+		for _, tx := range txs {
+			if err := rlp.Encode(w, tx); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+	}
+	return exported, nil
+}
+
+// ImportPending adds the RLP encoded transactions read from the reader to the
+// pool as remote transactions. It returns the number of the read transactions
+// and the number of the transactions failed to be added.
+func (pool *TxPool) ImportPending(r io.Reader) (int, int, error) {
+	return loadTransactions(r, pool.importTxs)
+}
+
+// importTxs adds a batch of imported transactions to the pool as remote ones.
+// They are not throttled by the admission policies.
+func (pool *TxPool) importTxs(txs []*types.Transaction) []error {
+	senderCacher.recover(pool.signer, txs)
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.addTxsLocked(txs, false, true)
+}
+
 // promoteTx adds a transaction to the pending (processable) list of transactions
 // and returns whether it was inserted or an older was better.
 //
@@ -892,7 +1137,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
-		delete(pool.all, hash)
+		pool.removeFromAll(hash)
 		pool.priced.Removed()
 
 		pendingDiscardCounter.Inc(1)
@@ -900,14 +1145,14 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.removeFromAll(old.Hash())
 		pool.priced.Removed()
 
 		pendingReplaceCounter.Inc(1)
 	}
 	// Failsafe to work around direct pending inserts (tests)
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.addToAll(tx)
 		pool.priced.Put(tx)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
@@ -1005,10 +1250,11 @@ func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
 	defer pool.mu.Unlock()
 
 	// Try to inject the transaction and update any state
-	replace, err := pool.add(tx, local)
+	replace, err := pool.add(tx, local, false)
 	if err != nil {
 		return err
 	}
+	pool.journalTxs([]*types.Transaction{tx})
 	// If we added a new transaction, run promotion checks and return
 	if !replace {
 		from, _ := types.Sender(pool.signer, tx) // already validated
@@ -1024,25 +1270,29 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	return pool.addTxsLocked(txs, local)
+	return pool.addTxsLocked(txs, local, false)
 }
 
 // addTxsLocked attempts to queue a batch of transactions if they are valid,
 // whilst assuming the transaction pool lock is already held.
-func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local bool) []error {
+func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local, imported bool) []error {
 	// Add the batch of transaction, tracking the accepted ones
 	dirty := make(map[common.Address]struct{})
 	errs := make([]error, len(txs))
+	added := make([]*types.Transaction, 0, len(txs))
 
 	for i, tx := range txs {
 		var replace bool
-		if replace, errs[i] = pool.add(tx, local); errs[i] == nil {
+		if replace, errs[i] = pool.add(tx, local, imported); errs[i] == nil {
+			added = append(added, tx)
 			if !replace {
 				from, _ := types.Sender(pool.signer, tx) // already validated
 				dirty[from] = struct{}{}
 			}
 		}
 	}
+	// Journal the accepted transactions with a single write per journal
+	pool.journalTxs(added)
 
 	// Only reprocess the internal state if something was actually added
 	if len(dirty) > 0 {
@@ -1104,7 +1354,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 	addr, _ := types.Sender(pool.signer, tx) // already validated during insertion
 
 	// Remove it from the list of known transactions
-	delete(pool.all, hash)
+	pool.removeFromAll(hash)
 	if outofbound {
 		pool.priced.Removed()
 	}
@@ -1159,7 +1409,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range list.Forward(pool.getNonce(addr)) {
 			hash := tx.Hash()
 			logger.Trace("Removed old queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.Removed()
 		}
 		// Drop all transactions that are too costly (low balance)
@@ -1167,7 +1417,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			logger.Trace("Removed unpayable queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.Removed()
 			queuedNofundsCounter.Inc(1)
 		}
@@ -1184,7 +1434,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		if !pool.locals.contains(addr) {
 			for _, tx := range list.Cap(int(pool.config.NonExecSlotsAccount)) {
 				hash := tx.Hash()
-				delete(pool.all, hash)
+				pool.removeFromAll(hash)
 				pool.priced.Removed()
 				queuedRateLimitCounter.Inc(1)
 				logger.Trace("Removed cap-exceeding queued transaction", "hash", hash)
@@ -1234,7 +1484,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						for _, tx := range list.Cap(list.Len() - 1) {
 							// Drop the transaction from the global pools too
 							hash := tx.Hash()
-							delete(pool.all, hash)
+							pool.removeFromAll(hash)
 							pool.priced.Removed()
 
 							// Update the account nonce to the dropped transaction
@@ -1254,7 +1504,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 					for _, tx := range list.Cap(list.Len() - 1) {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
-						delete(pool.all, hash)
+						pool.removeFromAll(hash)
 						pool.priced.Removed()
 
 						// Update the account nonce to the dropped transaction
@@ -1328,7 +1578,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range list.Forward(nonce) {
 			hash := tx.Hash()
 			logger.Trace("Removed old pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.Removed()
 		}
 
@@ -1345,7 +1595,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			logger.Trace("Removed unexecutable pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.Removed()
 			pendingNofundsCounter.Inc(1)
 		}
@@ -0,0 +1,274 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/rcrowley/go-metrics"
+)
+
+// Reasons of the rejections by the admission policies.
+const (
+	RejectDeniedSender     = "denied-sender"
+	RejectDeniedFeePayer   = "denied-feepayer"
+	RejectNotAllowedSender = "not-allowed-sender"
+	RejectRateLimited      = "rate-limited"
+	RejectQuotaExceeded    = "quota-exceeded"
+)
+
+const (
+	// senderRateLimitPruneInterval is the time interval to remove the rate limit
+	// states of the senders which have not sent any transaction recently.
+	senderRateLimitPruneInterval = time.Minute
+)
+
+// TxAdmissionPolicy decides whether a transaction can be added to the pool.
+// The policies are checked in order after the transaction is validated and
+// before it is added, with the pool lock held.
+type TxAdmissionPolicy interface {
+	// Name returns the name of the policy used in the metrics and the logs.
+	Name() string
+
+	// Admit returns an error if the transaction should be rejected. The error
+	// should be a *TxPolicyError to be counted by the reason in the metrics.
+	Admit(tx *types.Transaction, from common.Address, local bool) error
+}
+
+// txThrottlingPolicy is implemented by the admission policies limiting the rate
+// of the transactions. They are not checked for the imported transactions,
+// which have been admitted before they are exported.
+type txThrottlingPolicy interface {
+	TxAdmissionPolicy
+	throttling()
+}
+
+// TxPolicyError is returned when a transaction is rejected by an admission policy.
+type TxPolicyError struct {
+	Policy string // Name of the policy rejecting the transaction
+	Reason string // Reason of the rejection
+}
+
+func (e *TxPolicyError) Error() string {
+	return fmt.Sprintf("transaction rejected by %s policy: %s", e.Policy, e.Reason)
+}
+
+// markPolicyRejection increases the rejection counter of the policy and the reason.
+func markPolicyRejection(policy TxAdmissionPolicy, err error) {
+	reason := "unknown"
+	if perr, ok := err.(*TxPolicyError); ok {
+		reason = perr.Reason
+	}
+	metrics.GetOrRegisterCounter(fmt.Sprintf("txpool/policy/%s/%s", policy.Name(), reason), nil).Inc(1)
+}
+
+// AddressListPolicy rejects the transactions sent or paid by the addresses in
+// the deny list. If the allow list is not empty, only the transactions sent by
+// the addresses in the allow list are admitted.
+type AddressListPolicy struct {
+	deny  map[common.Address]struct{}
+	allow map[common.Address]struct{}
+	mu    sync.RWMutex
+}
+
+// NewAddressListPolicy creates an address list policy with the given lists.
+func NewAddressListPolicy(deny, allow []common.Address) *AddressListPolicy {
+	p := &AddressListPolicy{}
+	p.SetDenyList(deny)
+	p.SetAllowList(allow)
+	return p
+}
+
+func (p *AddressListPolicy) Name() string { return "addresslist" }
+
+func (p *AddressListPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if _, ok := p.deny[from]; ok {
+		return &TxPolicyError{Policy: p.Name(), Reason: RejectDeniedSender}
+	}
+	if tx.IsFeeDelegatedTransaction() {
+		if _, ok := p.deny[tx.ValidatedFeePayer()]; ok {
+			return &TxPolicyError{Policy: p.Name(), Reason: RejectDeniedFeePayer}
+		}
+	}
+	if len(p.allow) > 0 {
+		if _, ok := p.allow[from]; !ok {
+			return &TxPolicyError{Policy: p.Name(), Reason: RejectNotAllowedSender}
+		}
+	}
+	return nil
+}
+
+// SetDenyList replaces the deny list with the given addresses.
+func (p *AddressListPolicy) SetDenyList(addrs []common.Address) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deny = toAddressSet(addrs)
+}
+
+// SetAllowList replaces the allow list with the given addresses.
+// An empty allow list admits the transactions from any sender.
+func (p *AddressListPolicy) SetAllowList(addrs []common.Address) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allow = toAddressSet(addrs)
+}
+
+// DenyList returns the addresses in the deny list in ascending order.
+func (p *AddressListPolicy) DenyList() []common.Address {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return fromAddressSet(p.deny)
+}
+
+// AllowList returns the addresses in the allow list in ascending order.
+func (p *AddressListPolicy) AllowList() []common.Address {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return fromAddressSet(p.allow)
+}
+
+func toAddressSet(addrs []common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+func fromAddressSet(set map[common.Address]struct{}) []common.Address {
+	addrs := make([]common.Address, 0, len(set))
+	for addr := range set {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs
+}
+
+// senderBucket is the token bucket of a sender.
+type senderBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// SenderRateLimitPolicy limits the number of transactions admitted from a sender
+// with a token bucket per sender. A sender can send up to burst transactions at
+// once, and the bucket is refilled by rate transactions per second.
+type SenderRateLimitPolicy struct {
+	rate  float64
+	burst float64
+
+	buckets   map[common.Address]*senderBucket
+	lastPrune time.Time
+	now       func() time.Time // Returns the current time, replaced in tests
+	mu        sync.Mutex
+}
+
+// NewSenderRateLimitPolicy creates a sender rate limit policy.
+func NewSenderRateLimitPolicy(rate float64, burst uint64) *SenderRateLimitPolicy {
+	if burst == 0 {
+		burst = 1
+	}
+	return &SenderRateLimitPolicy{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[common.Address]*senderBucket),
+		lastPrune: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (p *SenderRateLimitPolicy) Name() string { return "senderratelimit" }
+
+func (p *SenderRateLimitPolicy) throttling() {}
+
+func (p *SenderRateLimitPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if now.Sub(p.lastPrune) > senderRateLimitPruneInterval {
+		p.prune(now)
+	}
+
+	bucket := p.buckets[from]
+	if bucket == nil {
+		bucket = &senderBucket{tokens: p.burst, last: now}
+		p.buckets[from] = bucket
+	}
+	p.refill(bucket, now)
+	if bucket.tokens < 1 {
+		return &TxPolicyError{Policy: p.Name(), Reason: RejectRateLimited}
+	}
+	bucket.tokens--
+	return nil
+}
+
+// refill adds the tokens accumulated since the last refill to the bucket.
+func (p *SenderRateLimitPolicy) refill(bucket *senderBucket, now time.Time) {
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * p.rate
+		if bucket.tokens > p.burst {
+			bucket.tokens = p.burst
+		}
+		bucket.last = now
+	}
+}
+
+// prune removes the buckets which are full, since they are the same as new ones.
+func (p *SenderRateLimitPolicy) prune(now time.Time) {
+	for addr, bucket := range p.buckets {
+		p.refill(bucket, now)
+		if bucket.tokens >= p.burst {
+			delete(p.buckets, addr)
+		}
+	}
+	p.lastPrune = now
+}
+
+// FeePayerQuotaPolicy limits the number of fee-delegated transactions paid by a
+// fee payer in the pool.
+type FeePayerQuotaPolicy struct {
+	quota int
+	count func(feePayer common.Address) int // Returns the number of transactions paid by the fee payer in the pool
+}
+
+// NewFeePayerQuotaPolicy creates a fee payer quota policy. count should return
+// the number of the fee-delegated transactions paid by the fee payer in the pool.
+func NewFeePayerQuotaPolicy(quota uint64, count func(common.Address) int) *FeePayerQuotaPolicy {
+	return &FeePayerQuotaPolicy{quota: int(quota), count: count}
+}
+
+func (p *FeePayerQuotaPolicy) Name() string { return "feepayerquota" }
+
+func (p *FeePayerQuotaPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	if !tx.IsFeeDelegatedTransaction() {
+		return nil
+	}
+	if p.count(tx.ValidatedFeePayer()) >= p.quota {
+		return &TxPolicyError{Policy: p.Name(), Reason: RejectQuotaExceeded}
+	}
+	return nil
+}
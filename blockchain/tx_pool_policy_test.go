@@ -0,0 +1,166 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func feeDelegatedTransaction(nonce uint64, key, feePayerKey *ecdsa.PrivateKey) *types.Transaction {
+	values := map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    nonce,
+		types.TxValueKeyFrom:     crypto.PubkeyToAddress(key.PublicKey),
+		types.TxValueKeyTo:       common.HexToAddress("0xAAAA"),
+		types.TxValueKeyAmount:   big.NewInt(100),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(1),
+		types.TxValueKeyFeePayer: crypto.PubkeyToAddress(feePayerKey.PublicKey),
+	}
+	tx, _ := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, values)
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	tx.Sign(signer, key)
+	tx.SignFeePayer(signer, feePayerKey)
+	return tx
+}
+
+func policyRejections(policy, reason string) int64 {
+	return metrics.GetOrRegisterCounter("txpool/policy/"+policy+"/"+reason, nil).Count()
+}
+
+// Tests that the deny and allow lists reject the transactions, and can be replaced at runtime.
+func TestTxPoolAddressListPolicy(t *testing.T) {
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000))
+
+	// Denied senders are rejected and counted in the metrics
+	denied := policyRejections("addresslist", RejectDeniedSender)
+	pool.AddressList().SetDenyList([]common.Address{from})
+	err := pool.AddRemote(transaction(0, 100000, key))
+	assert.Equal(t, &TxPolicyError{Policy: "addresslist", Reason: RejectDeniedSender}, err)
+	assert.Equal(t, denied+1, policyRejections("addresslist", RejectDeniedSender))
+
+	// Senders not in a non-empty allow list are rejected
+	pool.AddressList().SetDenyList(nil)
+	pool.AddressList().SetAllowList([]common.Address{common.HexToAddress("0x1234")})
+	err = pool.AddRemote(transaction(0, 100000, key))
+	assert.Equal(t, &TxPolicyError{Policy: "addresslist", Reason: RejectNotAllowedSender}, err)
+
+	pool.AddressList().SetAllowList([]common.Address{from})
+	assert.NoError(t, pool.AddRemote(transaction(0, 100000, key)))
+	assert.Equal(t, []common.Address{from}, pool.AddressList().AllowList())
+
+	// Denied fee payers are rejected
+	payerKey, _ := crypto.GenerateKey()
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	pool.currentState.AddBalance(payer, big.NewInt(1000000))
+	pool.AddressList().SetDenyList([]common.Address{payer})
+	err = pool.AddRemote(feeDelegatedTransaction(1, key, payerKey))
+	assert.Equal(t, &TxPolicyError{Policy: "addresslist", Reason: RejectDeniedFeePayer}, err)
+}
+
+// Tests that the transactions from a sender are rate limited by a token bucket.
+func TestTxPoolSenderRateLimitPolicy(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.SenderRateLimit = 1
+	config.SenderRateBurst = 2
+
+	pool, key := setupTxPool()
+	pool.Stop()
+	pool = NewTxPool(config, params.TestChainConfig, pool.chain)
+	defer pool.Stop()
+
+	assert.Equal(t, []string{"addresslist", "senderratelimit"}, pool.AdmissionPolicies())
+
+	now := time.Now()
+	limiter := pool.policies[1].(*SenderRateLimitPolicy)
+	limiter.now = func() time.Time { return now }
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000))
+
+	assert.NoError(t, pool.AddRemote(transaction(0, 100000, key)))
+	assert.NoError(t, pool.AddRemote(transaction(1, 100000, key)))
+	err := pool.AddRemote(transaction(2, 100000, key))
+	assert.Equal(t, &TxPolicyError{Policy: "senderratelimit", Reason: RejectRateLimited}, err)
+
+	// Other senders have their own buckets
+	other, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000))
+	assert.NoError(t, pool.AddRemote(transaction(0, 100000, other)))
+
+	// A token is refilled after a second
+	now = now.Add(time.Second)
+	assert.NoError(t, pool.AddRemote(transaction(2, 100000, key)))
+	err = pool.AddRemote(transaction(3, 100000, key))
+	assert.Equal(t, &TxPolicyError{Policy: "senderratelimit", Reason: RejectRateLimited}, err)
+
+	// Full buckets are pruned
+	now = now.Add(senderRateLimitPruneInterval + time.Second)
+	assert.NoError(t, pool.AddRemote(transaction(3, 100000, key)))
+	assert.Len(t, limiter.buckets, 1)
+}
+
+// Tests that the fee-delegated transactions paid by a fee payer are limited by the quota.
+func TestTxPoolFeePayerQuotaPolicy(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.FeePayerQuota = 2
+
+	pool, key := setupTxPool()
+	pool.Stop()
+	pool = NewTxPool(config, params.TestChainConfig, pool.chain)
+	defer pool.Stop()
+
+	payerKey, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(payerKey.PublicKey), big.NewInt(1000000))
+
+	last := feeDelegatedTransaction(1, key, payerKey)
+	assert.NoError(t, pool.AddRemote(feeDelegatedTransaction(0, key, payerKey)))
+	assert.NoError(t, pool.AddRemote(last))
+	err := pool.AddRemote(feeDelegatedTransaction(2, key, payerKey))
+	assert.Equal(t, &TxPolicyError{Policy: "feepayerquota", Reason: RejectQuotaExceeded}, err)
+
+	// The quota is freed when a transaction of the fee payer is removed
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	pool.mu.Lock()
+	assert.Equal(t, 2, pool.countFeeDelegatedTxs(payer))
+	pool.removeTx(last.Hash(), true)
+	assert.Equal(t, 1, pool.countFeeDelegatedTxs(payer))
+	pool.mu.Unlock()
+	assert.NoError(t, pool.AddRemote(last))
+
+	// Transactions not fee-delegated are not limited
+	assert.NoError(t, pool.AddRemote(transaction(2, 100000, key)))
+}
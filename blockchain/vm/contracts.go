@@ -76,7 +76,41 @@ var PrecompiledContractsCypress = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{11}): &validateSender{},
 }
 
-// TODO-IncompatibleChange: define PrecompiledContracts set for first incompatible change
+// PrecompiledContractsIstanbul contains the default set of pre-compiled Klaytn
+// contracts used after the istanbul compatible block. The bn256 contracts are
+// repriced as in EIP-1108.
+var PrecompiledContractsIstanbul = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}):  &ecrecover{},
+	common.BytesToAddress([]byte{2}):  &sha256hash{},
+	common.BytesToAddress([]byte{3}):  &ripemd160hash{},
+	common.BytesToAddress([]byte{4}):  &dataCopy{},
+	common.BytesToAddress([]byte{5}):  &bigModExp{},
+	common.BytesToAddress([]byte{6}):  &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}):  &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}):  &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}):  &vmLog{},
+	common.BytesToAddress([]byte{10}): &feePayer{},
+	common.BytesToAddress([]byte{11}): &validateSender{},
+}
+
+// precompiledContracts returns the pre-compiled contracts of the given chain rules.
+func precompiledContracts(rules params.Rules) map[common.Address]PrecompiledContract {
+	if rules.IsIstanbul {
+		return PrecompiledContractsIstanbul
+	}
+	return PrecompiledContractsCypress
+}
+
+// ActivePrecompiles returns the addresses of the pre-compiled contracts
+// enabled with the given chain rules.
+func ActivePrecompiles(rules params.Rules) []common.Address {
+	precompiles := precompiledContracts(rules)
+	addrs := make([]common.Address, 0, len(precompiles))
+	for addr := range precompiles {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract, evm *EVM) (ret []byte, computationCost uint64, err error) {
@@ -308,6 +342,22 @@ func newTwistPoint(blob []byte) (*bn256.G2, error) {
 	return p, nil
 }
 
+// runBn256Add implements the bn256Add precompile, referenced by both
+// the Cypress and the istanbul compatible contract sets.
+func runBn256Add(input []byte) ([]byte, error) {
+	x, err := newCurvePoint(getData(input, 0, 64))
+	if err != nil {
+		return nil, err
+	}
+	y, err := newCurvePoint(getData(input, 64, 64))
+	if err != nil {
+		return nil, err
+	}
+	res := new(bn256.G1)
+	res.Add(x, y)
+	return res.Marshal(), nil
+}
+
 // bn256Add implements a native elliptic curve point addition.
 type bn256Add struct{}
 
@@ -318,16 +368,31 @@ func (c *bn256Add) GetRequiredGasAndComputationCost(input []byte) (uint64, uint6
 }
 
 func (c *bn256Add) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
-	x, err := newCurvePoint(getData(input, 0, 64))
-	if err != nil {
-		return nil, err
-	}
-	y, err := newCurvePoint(getData(input, 64, 64))
+	return runBn256Add(input)
+}
+
+// bn256AddIstanbul implements a native elliptic curve point addition repriced by EIP-1108.
+type bn256AddIstanbul struct{}
+
+// GetRequiredGasAndComputationCost returns the gas required to execute the pre-compiled contract
+// and the computation cost of the precompiled contract.
+func (c *bn256AddIstanbul) GetRequiredGasAndComputationCost(input []byte) (uint64, uint64) {
+	return params.Bn256AddGasIstanbul, params.Bn256AddComputationCost
+}
+
+func (c *bn256AddIstanbul) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+	return runBn256Add(input)
+}
+
+// runBn256ScalarMul implements the bn256ScalarMul precompile, referenced by
+// both the Cypress and the istanbul compatible contract sets.
+func runBn256ScalarMul(input []byte) ([]byte, error) {
+	p, err := newCurvePoint(getData(input, 0, 64))
 	if err != nil {
 		return nil, err
 	}
 	res := new(bn256.G1)
-	res.Add(x, y)
+	res.ScalarMult(p, new(big.Int).SetBytes(getData(input, 64, 32)))
 	return res.Marshal(), nil
 }
 
@@ -341,13 +406,20 @@ func (c *bn256ScalarMul) GetRequiredGasAndComputationCost(input []byte) (uint64,
 }
 
 func (c *bn256ScalarMul) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
-	p, err := newCurvePoint(getData(input, 0, 64))
-	if err != nil {
-		return nil, err
-	}
-	res := new(bn256.G1)
-	res.ScalarMult(p, new(big.Int).SetBytes(getData(input, 64, 32)))
-	return res.Marshal(), nil
+	return runBn256ScalarMul(input)
+}
+
+// bn256ScalarMulIstanbul implements a native elliptic curve scalar multiplication repriced by EIP-1108.
+type bn256ScalarMulIstanbul struct{}
+
+// GetRequiredGasAndComputationCost returns the gas required to execute the pre-compiled contract
+// and the computation cost of the precompiled contract.
+func (c *bn256ScalarMulIstanbul) GetRequiredGasAndComputationCost(input []byte) (uint64, uint64) {
+	return params.Bn256ScalarMulGasIstanbul, params.Bn256ScalarMulComputationCost
+}
+
+func (c *bn256ScalarMulIstanbul) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+	return runBn256ScalarMul(input)
 }
 
 var (
@@ -361,18 +433,9 @@ var (
 	errBadPairingInput = errors.New("bad elliptic curve pairing size")
 )
 
-// bn256Pairing implements a pairing pre-compile for the bn256 curve
-type bn256Pairing struct{}
-
-// GetRequiredGasAndComputationCost returns the gas required to execute the pre-compiled contract
-// and the computation cost of the precompiled contract.
-func (c *bn256Pairing) GetRequiredGasAndComputationCost(input []byte) (uint64, uint64) {
-	numParings := uint64(len(input) / 192)
-	return params.Bn256PairingBaseGas + numParings*params.Bn256PairingPerPointGas,
-		params.Bn256ParingBaseComputationCost + numParings*params.Bn256ParingPerPointComputationCost
-}
-
-func (c *bn256Pairing) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+// runBn256Pairing implements the bn256Pairing precompile, referenced by both
+// the Cypress and the istanbul compatible contract sets.
+func runBn256Pairing(input []byte) ([]byte, error) {
 	// Handle some corner cases cheaply
 	if len(input)%192 > 0 {
 		return nil, errBadPairingInput
@@ -401,6 +464,36 @@ func (c *bn256Pairing) Run(input []byte, contract *Contract, evm *EVM) ([]byte,
 	return false32Byte, nil
 }
 
+// bn256Pairing implements a pairing pre-compile for the bn256 curve
+type bn256Pairing struct{}
+
+// GetRequiredGasAndComputationCost returns the gas required to execute the pre-compiled contract
+// and the computation cost of the precompiled contract.
+func (c *bn256Pairing) GetRequiredGasAndComputationCost(input []byte) (uint64, uint64) {
+	numParings := uint64(len(input) / 192)
+	return params.Bn256PairingBaseGas + numParings*params.Bn256PairingPerPointGas,
+		params.Bn256ParingBaseComputationCost + numParings*params.Bn256ParingPerPointComputationCost
+}
+
+func (c *bn256Pairing) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+	return runBn256Pairing(input)
+}
+
+// bn256PairingIstanbul implements a pairing pre-compile for the bn256 curve repriced by EIP-1108.
+type bn256PairingIstanbul struct{}
+
+// GetRequiredGasAndComputationCost returns the gas required to execute the pre-compiled contract
+// and the computation cost of the precompiled contract.
+func (c *bn256PairingIstanbul) GetRequiredGasAndComputationCost(input []byte) (uint64, uint64) {
+	numParings := uint64(len(input) / 192)
+	return params.Bn256PairingBaseGasIstanbul + numParings*params.Bn256PairingPerPointGasIstanbul,
+		params.Bn256ParingBaseComputationCost + numParings*params.Bn256ParingPerPointComputationCost
+}
+
+func (c *bn256PairingIstanbul) Run(input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+	return runBn256Pairing(input)
+}
+
 // vmLog implemented as a native contract.
 type vmLog struct{}
 
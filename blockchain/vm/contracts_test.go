@@ -54,7 +54,7 @@ func prepare(reqGas uint64) (*Contract, *EVM, error) {
 		nil, new(big.Int), reqGas)
 
 	// Generate EVM
-	stateDb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+	stateDb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
 	txhash := common.HexToHash("0xc6a37e155d3fa480faea012a68ad35fd53c8cc3cd8263a434c697755985a6577")
 	stateDb.Prepare(txhash, common.Hash{}, 0)
 	evm := NewEVM(Context{}, stateDb, params.TestChainConfig, &Config{})
@@ -173,6 +173,53 @@ func BenchmarkPrecompiledBn256ScalarMul(b *testing.B) { benchJson("bn256ScalarMu
 func TestPrecompiledBn256Pairing(t *testing.T)      { testJson("bn256Pairing", "08", t) }
 func BenchmarkPrecompiledBn256Pairing(b *testing.B) { benchJson("bn256Pairing", "08", b) }
 
+// Tests that the bn256 contracts after the istanbul compatible block return the
+// same outputs with the gas repriced by EIP-1108.
+func TestPrecompiledBn256Istanbul(t *testing.T) {
+	config := *params.TestChainConfig
+	config.IstanbulCompatibleBlock = big.NewInt(10)
+	stateDb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	before := NewEVM(Context{BlockNumber: big.NewInt(9)}, stateDb, &config, &Config{})
+	after := NewEVM(Context{BlockNumber: big.NewInt(10)}, stateDb, &config, &Config{})
+
+	for _, tc := range []struct {
+		name, addr string
+		gas        func(input []byte) uint64
+	}{
+		{"bn256Add", "06", func([]byte) uint64 { return params.Bn256AddGasIstanbul }},
+		{"bn256ScalarMul", "07", func([]byte) uint64 { return params.Bn256ScalarMulGasIstanbul }},
+		{"bn256Pairing", "08", func(input []byte) uint64 {
+			return params.Bn256PairingBaseGasIstanbul + uint64(len(input)/192)*params.Bn256PairingPerPointGasIstanbul
+		}},
+	} {
+		tests, err := loadJson(tc.name)
+		require.NoError(t, err)
+		addr := common.HexToAddress(tc.addr)
+		p, ok := after.precompile(addr)
+		require.True(t, ok)
+		cypress, _ := before.precompile(addr)
+		require.Equal(t, PrecompiledContractsCypress[addr], cypress)
+
+		for _, test := range tests {
+			in := common.Hex2Bytes(test.Input)
+			gas, computationCost := p.GetRequiredGasAndComputationCost(in)
+			_, cypressComputationCost := cypress.GetRequiredGasAndComputationCost(in)
+			require.Equal(t, tc.gas(in), gas, test.Name)
+			require.Equal(t, cypressComputationCost, computationCost, test.Name)
+
+			contract, evm, err := prepare(gas)
+			require.NoError(t, err)
+			res, _, err := RunPrecompiledContract(p, in, contract, evm)
+			require.NoError(t, err, test.Name)
+			require.Equal(t, test.Expected, common.Bytes2Hex(res), test.Name)
+		}
+	}
+
+	// The jump table is also selected by the block number
+	require.True(t, before.interpreter.jumpTable == &ConstantinopleInstructionSet)
+	require.True(t, after.interpreter.jumpTable == &IstanbulInstructionSet)
+}
+
 // Tests the sample inputs of the vmLog
 func TestPrecompiledVmLog(t *testing.T)      { testJson("vmLog", "09", t) }
 func BenchmarkPrecompiledVmLog(b *testing.B) { benchJson("vmLog", "09", b) }
@@ -55,16 +55,15 @@ type (
 // isProgramAccount returns true if the address is one of the following:
 // - an address of precompiled contracts
 // - an address of program accounts
-func isProgramAccount(addr common.Address, db StateDB) bool {
-	_, exists := PrecompiledContractsCypress[addr]
+func isProgramAccount(evm *EVM, addr common.Address, db StateDB) bool {
+	_, exists := evm.precompile(addr)
 	return exists || db.IsProgramAccount(addr)
 }
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContractsCypress
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p, _ := evm.precompile(*contract.CodeAddr); p != nil {
 			///////////////////////////////////////////////////////
 			// OpcodeComputationCostLimit: The below code is commented and will be usd for debugging purposes.
 			//var startTime time.Time
@@ -177,6 +176,12 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 	return evm
 }
 
+// precompile returns the precompiled contract of the given address active at the current block.
+func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
+	p, ok := precompiledContracts(evm.chainRules)[addr]
+	return p, ok
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel(reason int32) {
@@ -219,8 +224,7 @@ func (evm *EVM) Call(caller types.ContractRef, addr common.Address, input []byte
 
 	// Filter out invalid precompiled address calls, and create a precompiled contract object if it is not exist.
 	if common.IsPrecompiledContractAddress(addr) {
-		precompiles := PrecompiledContractsCypress
-		if precompiles[addr] == nil || value.Sign() != 0 {
+		if p, _ := evm.precompile(addr); p == nil || value.Sign() != 0 {
 			// Return an error if an enabled precompiled address is called or a value is transferred to a precompiled address.
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -250,7 +254,7 @@ func (evm *EVM) Call(caller types.ContractRef, addr common.Address, input []byte
 	}
 	evm.Transfer(evm.StateDB, caller.Address(), to.Address(), value)
 
-	if !isProgramAccount(addr, evm.StateDB) {
+	if !isProgramAccount(evm, addr, evm.StateDB) {
 		return ret, gas, nil
 	}
 
@@ -304,7 +308,7 @@ func (evm *EVM) CallCode(caller types.ContractRef, addr common.Address, input []
 		return nil, gas, ErrInsufficientBalance // TODO-Klaytn-Issue615
 	}
 
-	if !isProgramAccount(addr, evm.StateDB) {
+	if !isProgramAccount(evm, addr, evm.StateDB) {
 		logger.Info("Returning since the addr is not a program account", "addr", addr)
 		return nil, gas, nil
 	}
@@ -342,7 +346,7 @@ func (evm *EVM) DelegateCall(caller types.ContractRef, addr common.Address, inpu
 		return nil, gas, ErrDepth // TODO-Klaytn-Issue615
 	}
 
-	if !isProgramAccount(addr, evm.StateDB) {
+	if !isProgramAccount(evm, addr, evm.StateDB) {
 		logger.Info("Returning since the addr is not a program account", "addr", addr)
 		return nil, gas, nil
 	}
@@ -386,7 +390,7 @@ func (evm *EVM) StaticCall(caller types.ContractRef, addr common.Address, input
 		defer func() { evm.interpreter.readOnly = false }()
 	}
 
-	if !isProgramAccount(addr, evm.StateDB) {
+	if !isProgramAccount(evm, addr, evm.StateDB) {
 		logger.Info("Returning since the addr is not a program account", "addr", addr)
 		return nil, gas, nil
 	}
@@ -440,6 +444,12 @@ func (evm *EVM) create(caller types.ContractRef, codeAndHash *codeAndHash, gas u
 	// Increasing nonce since a failed tx with one of following error will be loaded on a block.
 	evm.StateDB.IncNonce(caller.Address())
 
+	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
+	// the access-list change should not be rolled back
+	if evm.chainRules.IsBerlin {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
+
 	if evm.StateDB.Exist(address) {
 		return nil, common.Address{}, 0, ErrContractAddressCollision // TODO-Klaytn-Issue615
 	}
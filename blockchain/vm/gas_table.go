@@ -23,6 +23,7 @@ package vm
 import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/kerrors"
 	"github.com/klaytn/klaytn/params"
 )
 
@@ -457,3 +458,141 @@ func gasStaticCall(gt params.GasTable, evm *EVM, contract *Contract, stack *Stac
 	}
 	return gas, nil
 }
+
+// The gas functions below charge the state access opcodes as in EIP-2929.
+// The gas table after the berlin compatible block holds the warm access cost,
+// and the difference to the cold access cost is charged on the first access
+// to an account or a storage slot in a transaction.
+
+// gasSLoadEIP2929 calculates dynamic gas for SLOAD according to EIP-2929
+func gasSLoadEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	slot := common.BigToHash(stack.Back(0))
+	// If the caller cannot afford the cost, this change will be rolled back
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+		return params.ColdSloadCostEIP2929, nil
+	}
+	return gt.SLoad, nil
+}
+
+// gasSStoreEIP2929 calculates dynamic gas for SSTORE. The cold storage access
+// cost is added to the gas of SSTORE if the slot is accessed first in the transaction.
+func gasSStoreEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	var (
+		slot = common.BigToHash(stack.Back(0))
+		cost = uint64(0)
+	)
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		cost = params.ColdSloadCostEIP2929
+		// If the caller cannot afford the cost, this change will be rolled back
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+	}
+	gas, err := gasSStore(gt, evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	var overflow bool
+	if gas, overflow = math.SafeAdd(gas, cost); overflow {
+		return 0, errGasUintOverflow
+	}
+	return gas, nil
+}
+
+// makeAccountCheckGasEIP2929 creates a gas function charging the cold account access
+// cost on top of the given gas function if the account at the top of the stack is
+// accessed first in the transaction.
+func makeAccountCheckGasEIP2929(oldCalculator gasFunc) gasFunc {
+	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.BigToAddress(stack.Back(0))
+		gas, err := oldCalculator(gt, evm, contract, stack, mem, memorySize)
+		if err != nil {
+			return 0, err
+		}
+		if !evm.StateDB.AddressInAccessList(addr) {
+			// If the caller cannot afford the cost, this change will be rolled back
+			evm.StateDB.AddAddressToAccessList(addr)
+			var overflow bool
+			if gas, overflow = math.SafeAdd(gas, params.ColdAccountAccessCostEIP2929-params.WarmStorageReadCostEIP2929); overflow {
+				return 0, errGasUintOverflow
+			}
+		}
+		return gas, nil
+	}
+}
+
+// makeCallVariantGasCallEIP2929 creates a gas function for the call opcodes
+// charging the cold account access cost of the callee. The cost is charged
+// before the call gas is calculated, since it affects the gas available to the callee.
+func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
+	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.BigToAddress(stack.Back(1))
+		// Check slot presence in the access list
+		warmAccess := evm.StateDB.AddressInAccessList(addr)
+		// The WarmStorageReadCostEIP2929 (100) is already deducted in the form of a constant cost, so
+		// the cost to charge for cold access, if any, is Cold - Warm
+		coldCost := params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+		if !warmAccess {
+			evm.StateDB.AddAddressToAccessList(addr)
+			// Charge the remaining difference here already, to correctly calculate available
+			// gas for call
+			if !contract.UseGas(coldCost) {
+				return 0, kerrors.ErrOutOfGas
+			}
+		}
+		// Now call the old calculator, which takes into account
+		// - create new account
+		// - transfer value
+		// - memory expansion
+		// - 63/64ths rule
+		gas, err := oldCalculator(gt, evm, contract, stack, mem, memorySize)
+		if warmAccess || err != nil {
+			return gas, err
+		}
+		// In case of a cold access, we temporarily add the cold charge back, and also
+		// add it to the returned gas. By adding it to the return, it will be charged
+		// outside of this function, as part of the dynamic gas, and that will make it
+		// also become correctly reported to tracers.
+		contract.Gas += coldCost
+
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, coldCost); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+// gasSelfdestructEIP2929 calculates dynamic gas for SELFDESTRUCT. The cold account
+// access cost is added if the beneficiary is accessed first in the transaction.
+func gasSelfdestructEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	var (
+		gas     uint64
+		address = common.BigToAddress(stack.Back(0))
+	)
+	if !evm.StateDB.AddressInAccessList(address) {
+		// If the caller cannot afford the cost, this change will be rolled back
+		evm.StateDB.AddAddressToAccessList(address)
+		gas = params.ColdAccountAccessCostEIP2929
+	}
+	suicideGas, err := gasSuicide(gt, evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	var overflow bool
+	if gas, overflow = math.SafeAdd(gas, suicideGas); overflow {
+		return 0, errGasUintOverflow
+	}
+	return gas, nil
+}
+
+var (
+	gasBalanceEIP2929     = makeAccountCheckGasEIP2929(gasBalance)
+	gasExtCodeSizeEIP2929 = makeAccountCheckGasEIP2929(gasExtCodeSize)
+	gasExtCodeCopyEIP2929 = makeAccountCheckGasEIP2929(gasExtCodeCopy)
+	gasExtCodeHashEIP2929 = makeAccountCheckGasEIP2929(gasExtCodeHash)
+
+	gasCallEIP2929         = makeCallVariantGasCallEIP2929(gasCall)
+	gasCallCodeEIP2929     = makeCallVariantGasCallEIP2929(gasCallCode)
+	gasDelegateCallEIP2929 = makeCallVariantGasCallEIP2929(gasDelegateCall)
+	gasStaticCallEIP2929   = makeCallVariantGasCallEIP2929(gasStaticCall)
+)
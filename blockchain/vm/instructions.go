@@ -589,6 +589,20 @@ func opGasLimit(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack
 	return nil, nil
 }
 
+// opChainID implements CHAINID opcode
+func opChainID(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	chainId := evm.interpreter.intPool.get().Set(evm.chainConfig.ChainID)
+	stack.push(chainId)
+	return nil, nil
+}
+
+// opSelfBalance implements SELFBALANCE opcode
+func opSelfBalance(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	balance := evm.interpreter.intPool.get().Set(evm.StateDB.GetBalance(contract.Address()))
+	stack.push(balance)
+	return nil, nil
+}
+
 func opPop(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	evm.interpreter.intPool.put(stack.pop())
 	return nil, nil
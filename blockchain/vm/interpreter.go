@@ -37,7 +37,7 @@ type Config struct {
 	NoRecursion             bool   // Disables call, callcode, delegate call and create
 	EnablePreimageRecording bool   // Enables recording of SHA3/keccak preimages
 
-	JumpTable [256]operation // EVM instruction table, automatically populated if unset
+	JumpTable [256]operation // EVM instruction table, selected by the chain rules if unset
 
 	// RunningEVM is to indicate the running EVM and used to stop the EVM.
 	RunningEVM chan *EVM
@@ -62,9 +62,10 @@ type keccakState interface {
 // The Interpreter will run the byte code VM based on the passed
 // configuration.
 type Interpreter struct {
-	evm      *EVM
-	cfg      *Config
-	gasTable params.GasTable
+	evm       *EVM
+	cfg       *Config
+	gasTable  params.GasTable
+	jumpTable *JumpTable
 
 	intPool *intPool
 
@@ -79,15 +80,25 @@ type Interpreter struct {
 func NewEVMInterpreter(evm *EVM, cfg *Config) *Interpreter {
 	// We use the STOP instruction whether to see
 	// the jump table was initialised. If it was not
-	// we'll set the default jump table.
-	if !cfg.JumpTable[STOP].valid {
-		cfg.JumpTable = ConstantinopleInstructionSet
+	// we'll use the jump table of the current fork.
+	// The config is not updated since it can be shared by EVMs of different blocks.
+	var jumpTable *JumpTable
+	switch {
+	case cfg.JumpTable[STOP].valid:
+		jumpTable = (*JumpTable)(&cfg.JumpTable)
+	case evm.chainRules.IsBerlin:
+		jumpTable = &BerlinInstructionSet
+	case evm.chainRules.IsIstanbul:
+		jumpTable = &IstanbulInstructionSet
+	default:
+		jumpTable = &ConstantinopleInstructionSet
 	}
 
 	return &Interpreter{
-		evm:      evm,
-		cfg:      cfg,
-		gasTable: evm.ChainConfig().GasTable(evm.BlockNumber),
+		evm:       evm,
+		cfg:       cfg,
+		gasTable:  evm.ChainConfig().GasTable(evm.BlockNumber),
+		jumpTable: jumpTable,
 	}
 }
 
@@ -209,7 +220,7 @@ func (in *Interpreter) Run(contract *Contract, input []byte) (ret []byte, err er
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(pc)
-		operation := in.cfg.JumpTable[op]
+		operation := in.jumpTable[op]
 		if !operation.valid {
 			return nil, fmt.Errorf("invalid opcode 0x%x", int(op)) // TODO-Klaytn-Issue615
 		}
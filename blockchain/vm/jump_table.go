@@ -60,14 +60,57 @@ type operation struct {
 	returns bool // determines whether the operations sets the return data content
 }
 
-// TODO-IncompatibleChange: define new InstructionSet for first incompatible change
 var (
 	ConstantinopleInstructionSet = newConstantinopleInstructionSet()
+	IstanbulInstructionSet       = newIstanbulInstructionSet()
+	BerlinInstructionSet         = newBerlinInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]operation
 
+// newBerlinInstructionSet returns the instructions available after the berlin
+// compatible block. The state access opcodes are charged by the access list of
+// EIP-2929 on top of the istanbul instructions.
+func newBerlinInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+	instructionSet[SLOAD].dynamicGas = gasSLoadEIP2929
+	instructionSet[SSTORE].dynamicGas = gasSStoreEIP2929
+	instructionSet[BALANCE].dynamicGas = gasBalanceEIP2929
+	instructionSet[EXTCODESIZE].dynamicGas = gasExtCodeSizeEIP2929
+	instructionSet[EXTCODECOPY].dynamicGas = gasExtCodeCopyEIP2929
+	instructionSet[EXTCODEHASH].dynamicGas = gasExtCodeHashEIP2929
+	instructionSet[CALL].dynamicGas = gasCallEIP2929
+	instructionSet[CALLCODE].dynamicGas = gasCallCodeEIP2929
+	instructionSet[DELEGATECALL].dynamicGas = gasDelegateCallEIP2929
+	instructionSet[STATICCALL].dynamicGas = gasStaticCallEIP2929
+	instructionSet[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP2929
+	return instructionSet
+}
+
+// newIstanbulInstructionSet returns the instructions available after the
+// istanbul compatible block on top of the constantinople instructions.
+func newIstanbulInstructionSet() JumpTable {
+	instructionSet := newConstantinopleInstructionSet()
+	instructionSet[CHAINID] = operation{
+		execute:         opChainID,
+		constantGas:     GasQuickStep,
+		minStack:        minStack(0, 1),
+		maxStack:        maxStack(0, 1),
+		valid:           true,
+		computationCost: params.ChainIDComputationCost,
+	}
+	instructionSet[SELFBALANCE] = operation{
+		execute:         opSelfBalance,
+		constantGas:     GasFastStep,
+		minStack:        minStack(0, 1),
+		maxStack:        maxStack(0, 1),
+		valid:           true,
+		computationCost: params.SelfBalanceComputationCost,
+	}
+	return instructionSet
+}
+
 // newConstantinopleInstructionSet returns the frontier, homestead
 // byzantium and constantinople instructions.
 func newConstantinopleInstructionSet() JumpTable {
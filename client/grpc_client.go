@@ -0,0 +1,403 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/event"
+	kgrpc "github.com/klaytn/klaytn/networks/grpc"
+	"github.com/klaytn/klaytn/rlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCClient defines typed wrappers for the typed gRPC API of Klaytn.
+type GRPCClient struct {
+	conn *grpc.ClientConn
+	c    kgrpc.KlaytnAPIClient
+}
+
+// DialGRPC connects a client to the gRPC endpoint of the given address. The
+// connection is insecure unless a transport security option is given.
+func DialGRPC(ctx context.Context, addr string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	conn, err := grpc.DialContext(ctx, addr, append([]grpc.DialOption{grpc.WithInsecure()}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return NewGRPCClient(conn), nil
+}
+
+// NewGRPCClient creates a client that uses the given gRPC connection.
+func NewGRPCClient(conn *grpc.ClientConn) *GRPCClient {
+	return &GRPCClient{conn, kgrpc.NewKlaytnAPIClient(conn)}
+}
+
+// WithBearerToken returns a dial option sending the bearer token with each
+// request to the endpoint requiring the authentication.
+func WithBearerToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(bearerToken(token))
+}
+
+// bearerToken implements credentials.PerRPCCredentials with a bearer token.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+func (gc *GRPCClient) Close() {
+	gc.conn.Close()
+}
+
+// toBlockRequest converts a block number into a request, and nil means the latest block.
+func toBlockRequest(number *big.Int) *kgrpc.BlockRequest {
+	if number == nil {
+		return &kgrpc.BlockRequest{Block: &kgrpc.BlockRequest_Tag{Tag: kgrpc.BlockTag_LATEST}}
+	}
+	return &kgrpc.BlockRequest{Block: &kgrpc.BlockRequest_Number{Number: number.Uint64()}}
+}
+
+// notFound converts the NotFound error into klaytn.NotFound.
+func notFound(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return klaytn.NotFound
+	}
+	return err
+}
+
+// BlockNumber returns the current block number.
+func (gc *GRPCClient) BlockNumber(ctx context.Context) (*big.Int, error) {
+	number, err := gc.c.GetBlockNumber(ctx, &kgrpc.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(number.GetNumber()), nil
+}
+
+// HeaderByHash returns the block header with the given hash.
+func (gc *GRPCClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := gc.c.GetHeader(ctx, &kgrpc.BlockRequest{Block: &kgrpc.BlockRequest_Hash{Hash: hash.Bytes()}})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return toHeader(header), nil
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If number is
+// nil, the latest known header is returned.
+func (gc *GRPCClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := gc.c.GetHeader(ctx, toBlockRequest(number))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return toHeader(header), nil
+}
+
+// BlockByHash returns the given full block.
+func (gc *GRPCClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return gc.getBlock(ctx, &kgrpc.BlockRequest{Block: &kgrpc.BlockRequest_Hash{Hash: hash.Bytes()}, FullTransactions: true})
+}
+
+// BlockByNumber returns a block from the current canonical chain. If number is nil, the
+// latest known block is returned.
+func (gc *GRPCClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	req := toBlockRequest(number)
+	req.FullTransactions = true
+	return gc.getBlock(ctx, req)
+}
+
+func (gc *GRPCClient) getBlock(ctx context.Context, req *kgrpc.BlockRequest) (*types.Block, error) {
+	block, err := gc.c.GetBlock(ctx, req)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	header := toHeader(block.GetHeader())
+	txs := make([]*types.Transaction, len(block.GetTransactions()))
+	for i, tx := range block.GetTransactions() {
+		if txs[i], err = toTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+	return types.NewBlockWithHeader(header).WithBody(txs), nil
+}
+
+// BlockReceipts returns the receipts of the transactions in the given block.
+func (gc *GRPCClient) BlockReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	receipts, err := gc.c.GetBlockReceipts(ctx, &kgrpc.BlockRequest{Block: &kgrpc.BlockRequest_Hash{Hash: hash.Bytes()}})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	result := make(types.Receipts, len(receipts.GetReceipts()))
+	for i, receipt := range receipts.GetReceipts() {
+		result[i] = toReceipt(receipt)
+	}
+	return result, nil
+}
+
+// TransactionByHash returns the transaction with the given hash.
+func (gc *GRPCClient) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	result, err := gc.c.GetTransaction(ctx, &kgrpc.TransactionRequest{Hash: hash.Bytes()})
+	if err != nil {
+		return nil, false, notFound(err)
+	}
+	if tx, err = toTransaction(result); err != nil {
+		return nil, false, err
+	}
+	return tx, len(result.GetBlockHash()) == 0, nil
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+// Note that the receipt is not available for pending transactions.
+func (gc *GRPCClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := gc.c.GetTransactionReceipt(ctx, &kgrpc.TransactionRequest{Hash: txHash.Bytes()})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return toReceipt(receipt), nil
+}
+
+// SendTransaction injects a signed transaction into the pending pool for execution.
+func (gc *GRPCClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	_, err = gc.c.SendRawTransaction(ctx, &kgrpc.RawTransaction{Raw: raw})
+	return err
+}
+
+// BalanceAt returns the peb balance of the given account.
+// The block number can be nil, in which case the balance is taken from the latest known block.
+func (gc *GRPCClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	result, err := gc.c.GetAccount(ctx, &kgrpc.AccountRequest{Address: account.Bytes(), Block: toBlockRequest(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(result.GetBalance()), nil
+}
+
+// NonceAt returns the account nonce of the given account.
+// The block number can be nil, in which case the nonce is taken from the latest known block.
+func (gc *GRPCClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	result, err := gc.c.GetAccount(ctx, &kgrpc.AccountRequest{Address: account.Bytes(), Block: toBlockRequest(blockNumber)})
+	if err != nil {
+		return 0, err
+	}
+	return result.GetNonce(), nil
+}
+
+// CodeAt returns the contract code of the given account.
+// The block number can be nil, in which case the code is taken from the latest known block.
+func (gc *GRPCClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	result, err := gc.c.GetCode(ctx, &kgrpc.AccountRequest{Address: account.Bytes(), Block: toBlockRequest(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+	return result.GetCode(), nil
+}
+
+// StorageAt returns the value of key in the contract storage of the given account.
+// The block number can be nil, in which case the value is taken from the latest known block.
+func (gc *GRPCClient) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	result, err := gc.c.GetStorageAt(ctx, &kgrpc.StorageRequest{Address: account.Bytes(), Key: key.Bytes(), Block: toBlockRequest(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+	return result.GetValue(), nil
+}
+
+// FilterLogs executes a filter query.
+func (gc *GRPCClient) FilterLogs(ctx context.Context, q klaytn.FilterQuery) ([]types.Log, error) {
+	from := q.FromBlock
+	if from == nil {
+		from = common.Big0
+	}
+	filter := toLogFilter(q)
+	filter.FromBlock, filter.ToBlock = toBlockRequest(from), toBlockRequest(q.ToBlock)
+
+	logs, err := gc.c.GetLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]types.Log, len(logs.GetLogs()))
+	for i, log := range logs.GetLogs() {
+		result[i] = *toLog(log)
+	}
+	return result, nil
+}
+
+// SubscribeNewHead subscribes to notifications about the current blockchain head
+// on the given channel.
+func (gc *GRPCClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (klaytn.Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := gc.c.SubscribeNewHeads(ctx, &kgrpc.Empty{})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer cancel()
+		return receive(quit, func() error {
+			result, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			select {
+			case ch <- toHeader(result):
+			case <-quit:
+			}
+			return nil
+		})
+	}), nil
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query.
+// The block range of the query is ignored.
+func (gc *GRPCClient) SubscribeFilterLogs(ctx context.Context, q klaytn.FilterQuery, ch chan<- types.Log) (klaytn.Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := gc.c.SubscribeLogs(ctx, toLogFilter(q))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer cancel()
+		return receive(quit, func() error {
+			result, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			select {
+			case ch <- *toLog(result):
+			case <-quit:
+			}
+			return nil
+		})
+	}), nil
+}
+
+// receive calls recv until it fails or the subscription is unsubscribed.
+func receive(quit <-chan struct{}, recv func() error) error {
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			if err := recv(); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-quit:
+		return nil
+	}
+}
+
+func toLogFilter(q klaytn.FilterQuery) *kgrpc.LogFilter {
+	filter := &kgrpc.LogFilter{
+		Addresses: make([][]byte, len(q.Addresses)),
+		Topics:    make([]*kgrpc.Topics, len(q.Topics)),
+	}
+	for i, addr := range q.Addresses {
+		filter.Addresses[i] = addr.Bytes()
+	}
+	for i, topics := range q.Topics {
+		filter.Topics[i] = &kgrpc.Topics{}
+		for _, topic := range topics {
+			filter.Topics[i].Hashes = append(filter.Topics[i].Hashes, topic.Bytes())
+		}
+	}
+	return filter
+}
+
+func toHeader(header *kgrpc.Header) *types.Header {
+	result := &types.Header{
+		ParentHash:  common.BytesToHash(header.GetParentHash()),
+		Rewardbase:  common.BytesToAddress(header.GetRewardbase()),
+		Root:        common.BytesToHash(header.GetStateRoot()),
+		TxHash:      common.BytesToHash(header.GetTransactionsRoot()),
+		ReceiptHash: common.BytesToHash(header.GetReceiptsRoot()),
+		Bloom:       types.BytesToBloom(header.GetLogsBloom()),
+		BlockScore:  new(big.Int).SetBytes(header.GetBlockScore()),
+		Number:      new(big.Int).SetUint64(header.GetNumber()),
+		GasUsed:     header.GetGasUsed(),
+		Time:        new(big.Int).SetUint64(header.GetTime()),
+		TimeFoS:     uint8(header.GetTimeFos()),
+		Extra:       header.GetExtraData(),
+		Governance:  header.GetGovernanceData(),
+		Vote:        header.GetVoteData(),
+	}
+	return result
+}
+
+// toTransaction decodes the transaction and checks if it has the given hash.
+func toTransaction(tx *kgrpc.Transaction) (*types.Transaction, error) {
+	result := new(types.Transaction)
+	if err := rlp.DecodeBytes(tx.GetRaw(), result); err != nil {
+		return nil, err
+	}
+	if hash := common.BytesToHash(tx.GetHash()); result.Hash() != hash {
+		return nil, fmt.Errorf("transaction hash mismatch: have %x, want %x", result.Hash(), hash)
+	}
+	return result, nil
+}
+
+func toReceipt(receipt *kgrpc.Receipt) *types.Receipt {
+	result := &types.Receipt{
+		Status:          uint(receipt.GetStatus()),
+		Bloom:           types.BytesToBloom(receipt.GetLogsBloom()),
+		Logs:            make([]*types.Log, len(receipt.GetLogs())),
+		TxHash:          common.BytesToHash(receipt.GetTransactionHash()),
+		ContractAddress: common.BytesToAddress(receipt.GetContractAddress()),
+		GasUsed:         receipt.GetGasUsed(),
+	}
+	for i, log := range receipt.GetLogs() {
+		result.Logs[i] = toLog(log)
+	}
+	return result
+}
+
+func toLog(log *kgrpc.Log) *types.Log {
+	result := &types.Log{
+		Address:     common.BytesToAddress(log.GetAddress()),
+		Topics:      make([]common.Hash, len(log.GetTopics())),
+		Data:        log.GetData(),
+		BlockNumber: log.GetBlockNumber(),
+		TxHash:      common.BytesToHash(log.GetTransactionHash()),
+		TxIndex:     uint(log.GetTransactionIndex()),
+		BlockHash:   common.BytesToHash(log.GetBlockHash()),
+		Index:       uint(log.GetLogIndex()),
+		Removed:     log.GetRemoved(),
+	}
+	for i, topic := range log.GetTopics() {
+		result.Topics[i] = common.BytesToHash(topic)
+	}
+	return result
+}
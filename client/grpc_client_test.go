@@ -0,0 +1,217 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn"
+	mock_api "github.com/klaytn/klaytn/api/mocks"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	kgrpc "github.com/klaytn/klaytn/networks/grpc"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/node/cn/filters"
+	mock_filters "github.com/klaytn/klaytn/node/cn/filters/mock"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ = klaytn.ChainStateReader(&GRPCClient{})
+	_ = klaytn.LogFilterer(&GRPCClient{})
+	_ = klaytn.TransactionSender(&GRPCClient{})
+)
+
+// TestGRPCClient tests the typed gRPC API with the client.
+func TestGRPCClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	backend := mock_api.NewMockBackend(mockCtrl)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tx, err := types.SignTx(types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil), types.NewEIP155Signer(big.NewInt(1)), key)
+	assert.NoError(t, err)
+
+	var (
+		addr    = common.HexToAddress("0x1")
+		topic   = common.HexToHash("0x2")
+		receipt = &types.Receipt{
+			Status:  types.ReceiptStatusSuccessful,
+			TxHash:  tx.Hash(),
+			GasUsed: 21000,
+			Logs:    []*types.Log{{Address: addr, Topics: []common.Hash{topic}, Data: []byte{1}}},
+		}
+		header = &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), BlockScore: big.NewInt(1), GasUsed: 21000}
+		feed   event.Feed
+	)
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	header.Bloom = receipt.Bloom
+	block := types.NewBlockWithHeader(header).WithBody(types.Transactions{tx})
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+	statedb.AddBalance(addr, big.NewInt(100))
+	statedb.SetNonce(addr, 3)
+
+	backend.EXPECT().HeaderByNumber(gomock.Any(), rpc.LatestBlockNumber).Return(header, nil).AnyTimes()
+	backend.EXPECT().HeaderByNumber(gomock.Any(), rpc.BlockNumber(1)).Return(header, nil).AnyTimes()
+	backend.EXPECT().HeaderByNumber(gomock.Any(), rpc.BlockNumber(0)).Return(&types.Header{Number: big.NewInt(0)}, nil).AnyTimes()
+	backend.EXPECT().BlockByNumber(gomock.Any(), rpc.BlockNumber(1)).Return(block, nil).AnyTimes()
+	backend.EXPECT().GetBlock(gomock.Any(), block.Hash()).Return(block, nil).AnyTimes()
+	backend.EXPECT().GetBlock(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	backend.EXPECT().GetBlockReceipts(gomock.Any(), block.Hash()).Return(types.Receipts{receipt}).AnyTimes()
+	backend.EXPECT().GetTxAndLookupInfo(tx.Hash()).Return(tx, block.Hash(), uint64(1), uint64(0)).AnyTimes()
+	backend.EXPECT().GetTxLookupInfoAndReceipt(gomock.Any(), tx.Hash()).Return(tx, block.Hash(), uint64(1), uint64(0), receipt).AnyTimes()
+	backend.EXPECT().StateAndHeaderByNumber(gomock.Any(), rpc.LatestBlockNumber).Return(statedb, header, nil).AnyTimes()
+	backend.EXPECT().SendTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	backend.EXPECT().SubscribeChainHeadEvent(gomock.Any()).DoAndReturn(func(ch chan<- blockchain.ChainHeadEvent) event.Subscription {
+		return feed.Subscribe(ch)
+	}).AnyTimes()
+
+	// The logs are queried with the filter API, which reads the stored logs
+	// having their locations
+	storedLog := &types.Log{Address: addr, Topics: []common.Hash{topic}, Data: []byte{1}, BlockNumber: 1, TxHash: tx.Hash(), BlockHash: block.Hash()}
+	emptySub := event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+	filterBackend := mock_filters.NewMockBackend(mockCtrl)
+	filterBackend.EXPECT().EventMux().Return(new(event.TypeMux)).AnyTimes()
+	filterBackend.EXPECT().ChainDB().Return(nil).AnyTimes()
+	filterBackend.EXPECT().SubscribeNewTxsEvent(gomock.Any()).Return(emptySub).AnyTimes()
+	filterBackend.EXPECT().SubscribeLogsEvent(gomock.Any()).Return(emptySub).AnyTimes()
+	filterBackend.EXPECT().SubscribeRemovedLogsEvent(gomock.Any()).Return(emptySub).AnyTimes()
+	filterBackend.EXPECT().SubscribeChainEvent(gomock.Any()).Return(emptySub).AnyTimes()
+	filterBackend.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(header, nil).AnyTimes()
+	filterBackend.EXPECT().LogIndexStatus().Return(uint64(0), uint64(0)).AnyTimes()
+	filterBackend.EXPECT().BloomStatus().Return(uint64(0), uint64(0)).AnyTimes()
+	filterBackend.EXPECT().GetLogs(gomock.Any(), block.Hash()).Return([][]*types.Log{{storedLog}}, nil).AnyTimes()
+
+	// Start the gRPC endpoint serving the typed API with the backend
+	ln, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	listener := &kgrpc.Listener{Addr: ln.Addr().String()}
+	ln.Close()
+	listener.SetRPCServer(rpc.NewServer())
+	listener.SetAPIBackend(backend)
+	listener.SetFilterAPI(filters.NewPublicFilterAPI(filterBackend, false, &filters.Config{MaxBlockRange: 1}))
+	go listener.Start()
+	defer listener.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := DialGRPC(ctx, listener.Addr, grpc.WithBlock())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	number, err := client.BlockNumber(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), number)
+
+	result, err := client.HeaderByNumber(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash(), result.Hash())
+	_, err = client.HeaderByHash(ctx, common.HexToHash("0x3"))
+	assert.Equal(t, klaytn.NotFound, err)
+
+	resultBlock, err := client.BlockByNumber(ctx, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, block.Hash(), resultBlock.Hash())
+	assert.Equal(t, tx.Hash(), resultBlock.Transactions()[0].Hash())
+
+	resultTx, isPending, err := client.TransactionByHash(ctx, tx.Hash())
+	assert.NoError(t, err)
+	assert.False(t, isPending)
+	assert.Equal(t, tx.Hash(), resultTx.Hash())
+
+	resultReceipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, receipt.Bloom, resultReceipt.Bloom)
+	assert.Equal(t, block.Hash(), resultReceipt.Logs[0].BlockHash)
+	assert.Equal(t, uint64(1), resultReceipt.Logs[0].BlockNumber)
+
+	logs, err := client.FilterLogs(ctx, klaytn.FilterQuery{FromBlock: big.NewInt(1), Topics: [][]common.Hash{{topic}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, addr, logs[0].Address)
+	assert.Equal(t, block.Hash(), logs[0].BlockHash)
+	logs, err = client.FilterLogs(ctx, klaytn.FilterQuery{FromBlock: big.NewInt(1), Addresses: []common.Address{common.HexToAddress("0x3")}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(logs))
+
+	// The log queries are limited by the config of the filter API
+	_, err = client.FilterLogs(ctx, klaytn.FilterQuery{FromBlock: big.NewInt(0)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	balance, err := client.BalanceAt(ctx, addr, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), balance)
+	nonce, err := client.NonceAt(ctx, addr, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	heads := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+	for feed.Send(blockchain.ChainHeadEvent{Block: block}) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case head := <-heads:
+		assert.Equal(t, header.Hash(), head.Hash())
+	case <-ctx.Done():
+		t.Fatal("timeout to receive a new head")
+	}
+
+	// The typed API requires the authorization of the klay namespace
+	auth, err := rpc.NewAuthenticator(map[string][]string{"klay": {"klay"}, "net": {"net"}}, nil)
+	assert.NoError(t, err)
+	rpc.SetAuthenticator(auth)
+	defer rpc.SetAuthenticator(nil)
+
+	_, err = client.BlockNumber(ctx)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	netClient, err := DialGRPC(ctx, listener.Addr, WithBearerToken("net"))
+	assert.NoError(t, err)
+	defer netClient.Close()
+	_, err = netClient.BlockNumber(ctx)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	klayClient, err := DialGRPC(ctx, listener.Addr, WithBearerToken("klay"))
+	assert.NoError(t, err)
+	defer klayClient.Close()
+	_, err = klayClient.BlockNumber(ctx)
+	assert.NoError(t, err)
+}
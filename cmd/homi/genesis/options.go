@@ -163,6 +163,18 @@ func Clique(config *params.CliqueConfig) Option {
 	}
 }
 
+func IstanbulCompatible(num *big.Int) Option {
+	return func(genesis *blockchain.Genesis) {
+		genesis.Config.IstanbulCompatibleBlock = num
+	}
+}
+
+func BerlinCompatible(num *big.Int) Option {
+	return func(genesis *blockchain.Genesis) {
+		genesis.Config.BerlinCompatibleBlock = num
+	}
+}
+
 func StakingInterval(interval uint64) Option {
 	return func(genesis *blockchain.Genesis) {
 		genesis.Config.Governance.Reward.StakingUpdateInterval = interval
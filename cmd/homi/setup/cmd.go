@@ -124,6 +124,8 @@ Args :
 			istSubGroupFlag,
 			cliqueEpochFlag,
 			cliquePeriodFlag,
+			istanbulCompatibleBlockNumberFlag,
+			berlinCompatibleBlockNumberFlag,
 		},
 		ArgsUsage: "type",
 	}
@@ -250,6 +252,7 @@ func genIstanbulGenesis(ctx *cli.Context, nodeAddrs, testAddrs []common.Address,
 		options = append(options, genesis.Governance(config))
 	}
 	options = append(options, genesis.Istanbul(genIstanbulConfig(ctx)))
+	options = append(options, genForkOptions(ctx)...)
 
 	return genesis.New(options...)
 }
@@ -263,16 +266,39 @@ func genCliqueGenesis(ctx *cli.Context, nodeAddrs, testAddrs []common.Address, c
 		log.Fatalf("Currently, governance is not supported for clique consensus", "--governance", ok)
 	}
 
-	genesisJson := genesis.NewClique(
+	options := []genesis.Option{
 		genesis.ValidatorsOfClique(nodeAddrs...),
 		genesis.Alloc(append(nodeAddrs, testAddrs...), new(big.Int).Exp(big.NewInt(10), big.NewInt(50), nil)),
 		genesis.UnitPrice(unitPrice),
 		genesis.ChainID(chainID),
 		genesis.Clique(config),
-	)
+	}
+	options = append(options, genForkOptions(ctx)...)
+
+	genesisJson := genesis.NewClique(options...)
 	return genesisJson
 }
 
+// genForkOptions returns the options scheduling the hard forks given by the flags.
+func genForkOptions(ctx *cli.Context) []genesis.Option {
+	var options []genesis.Option
+	if ctx.IsSet(istanbulCompatibleBlockNumberFlag.Name) {
+		num := ctx.Int64(istanbulCompatibleBlockNumberFlag.Name)
+		if num < 0 {
+			log.Fatalf("Invalid istanbul compatible block number: %d", num)
+		}
+		options = append(options, genesis.IstanbulCompatible(big.NewInt(num)))
+	}
+	if ctx.IsSet(berlinCompatibleBlockNumberFlag.Name) {
+		num := ctx.Int64(berlinCompatibleBlockNumberFlag.Name)
+		if num < 0 {
+			log.Fatalf("Invalid berlin compatible block number: %d", num)
+		}
+		options = append(options, genesis.BerlinCompatible(big.NewInt(num)))
+	}
+	return options
+}
+
 func genValidatorKeystore(privKeys []*ecdsa.PrivateKey) {
 	path := path.Join(outputPath, DirKeys)
 	ks := keystore.NewKeyStore(path, keystore.StandardScryptN, keystore.StandardScryptP)
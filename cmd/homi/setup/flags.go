@@ -302,7 +302,7 @@ var (
 
 	istProposerPolicyFlag = cli.Uint64Flag{
 		Name:  "ist-proposer-policy",
-		Usage: "governance proposer policy (0: RoundRobin, 1: Sticky, 2: WeightedRandom) [default: 0]",
+		Usage: "governance proposer policy (0: RoundRobin, 1: Sticky, 2: WeightedRandom, 3: StakeCappedWeightedRandom) [default: 0]",
 		Value: params.DefaultProposerPolicy,
 	}
 
@@ -323,4 +323,14 @@ var (
 		Usage: "clique period",
 		Value: params.DefaultPeriod,
 	}
+
+	istanbulCompatibleBlockNumberFlag = cli.Int64Flag{
+		Name:  "istanbul-compatible-blocknumber",
+		Usage: "istanbulCompatible blockNumber [default: not scheduled]",
+	}
+
+	berlinCompatibleBlockNumberFlag = cli.Int64Flag{
+		Name:  "berlin-compatible-blocknumber",
+		Usage: "berlinCompatible blockNumber [default: not scheduled]",
+	}
 )
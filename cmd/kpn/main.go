@@ -57,6 +57,11 @@ func init() {
 	app.Commands = []cli.Command{
 		// See utils/nodecmd/chaincmd.go:
 		nodecmd.InitCommand,
+		nodecmd.ImportCommand,
+		nodecmd.ExportCommand,
+		nodecmd.ImportPreimagesCommand,
+		nodecmd.ExportPreimagesCommand,
+		nodecmd.DumpCommand,
 
 		// See utils/nodecmd/accountcmd.go
 		nodecmd.AccountCommand,
@@ -73,6 +78,9 @@ func init() {
 
 		// See utils/nodecmd/db_migration.go:
 		nodecmd.MigrationCommand,
+
+		// See utils/nodecmd/governancecmd.go:
+		nodecmd.GovernanceCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
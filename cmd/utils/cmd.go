@@ -28,12 +28,16 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/node"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
 )
 
 const (
@@ -105,6 +109,7 @@ func ImportChain(chain *blockchain.BlockChain, fn string) error {
 	// Run actual the import.
 	blocks := make(types.Blocks, importBatchSize)
 	n := 0
+	start := time.Now()
 	for batch := 0; ; batch++ {
 		// Load a batch of RLP blocks.
 		if checkInterrupt() {
@@ -141,6 +146,8 @@ func ImportChain(chain *blockchain.BlockChain, fn string) error {
 		if _, err := chain.InsertChain(missing); err != nil {
 			return fmt.Errorf("invalid block %d: %v", n, err)
 		}
+		logger.Info("Imported batch of blocks", "batch", batch, "imported", n,
+			"head", chain.CurrentBlock().NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
 	}
 	return nil
 }
@@ -214,77 +221,81 @@ func ExportAppendChain(blockchain *blockchain.BlockChain, fn string, first uint6
 	return nil
 }
 
-// TODO-Klaytn Commented out due to mismatched interface.
-//// ImportPreimages imports a batch of exported hash preimages into the database.
-//func ImportPreimages(db *database.LevelDB, fn string) error {
-//	logger.Info("Importing preimages", "file", fn)
-//
-//	// Open the file handle and potentially unwrap the gzip stream
-//	fh, err := os.Open(fn)
-//	if err != nil {
-//		return err
-//	}
-//	defer fh.Close()
-//
-//	var reader io.Reader = fh
-//	if strings.HasSuffix(fn, ".gz") {
-//		if reader, err = gzip.NewReader(reader); err != nil {
-//			return err
-//		}
-//	}
-//	stream := rlp.NewStream(reader, 0)
-//
-//	// Import the preimages in batches to prevent disk trashing
-//	preimages := make(map[common.Hash][]byte)
-//
-//	for {
-//		// Read the next entry and ensure it's not junk
-//		var blob []byte
-//
-//		if err := stream.Decode(&blob); err != nil {
-//			if err == io.EOF {
-//				break
-//			}
-//			return err
-//		}
-//		// Accumulate the preimages and flush when enough ws gathered
-//		preimages[crypto.Keccak256Hash(blob)] = common.CopyBytes(blob)
-//		if len(preimages) > 1024 {
-//			rawdb.WritePreimages(db, 0, preimages)
-//			preimages = make(map[common.Hash][]byte)
-//		}
-//	}
-//	// Flush the last batch preimage data
-//	if len(preimages) > 0 {
-//		rawdb.WritePreimages(db, 0, preimages)
-//	}
-//	return nil
-//}
-//
-//// ExportPreimages exports all known hash preimages into the specified file,
-//// truncating any data already present in the file.
-//func ExportPreimages(db *database.LevelDB, fn string) error {
-//	logger.Info("Exporting preimages", "file", fn)
-//
-//	// Open the file handle and potentially wrap with a gzip stream
-//	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
-//	if err != nil {
-//		return err
-//	}
-//	defer fh.Close()
-//
-//	var writer io.Writer = fh
-//	if strings.HasSuffix(fn, ".gz") {
-//		writer = gzip.NewWriter(writer)
-//		defer writer.(*gzip.Writer).Close()
-//	}
-//	// Iterate over the preimages and export them
-//	it := db.NewIteratorWithPrefix([]byte("secure-key-"))
-//	for it.Next() {
-//		if err := rlp.Encode(writer, it.Value()); err != nil {
-//			return err
-//		}
-//	}
-//	logger.Info("Exported preimages", "file", fn)
-//	return nil
-//}
+// ImportPreimages imports a batch of exported hash preimages into the database.
+func ImportPreimages(db database.DBManager, fn string) error {
+	logger.Info("Importing preimages", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	// Import the preimages in batches to prevent disk trashing
+	preimages := make(map[common.Hash][]byte)
+
+	for {
+		// Read the next entry and ensure it's not junk
+		var blob []byte
+
+		if err := stream.Decode(&blob); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		// Accumulate the preimages and flush when enough ws gathered
+		preimages[crypto.Keccak256Hash(blob)] = common.CopyBytes(blob)
+		if len(preimages) > 1024 {
+			db.WritePreimages(0, preimages)
+			preimages = make(map[common.Hash][]byte)
+		}
+	}
+	// Flush the last batch preimage data
+	if len(preimages) > 0 {
+		db.WritePreimages(0, preimages)
+	}
+	return nil
+}
+
+// ExportPreimages exports all known hash preimages into the specified file,
+// truncating any data already present in the file.
+func ExportPreimages(db database.DBManager, fn string) error {
+	logger.Info("Exporting preimages", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the preimages and export them
+	it := db.GetStateTrieDB().NewIterator([]byte("secure-key-"), nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := rlp.Encode(writer, it.Value()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	logger.Info("Exported preimages", "file", fn)
+	return nil
+}
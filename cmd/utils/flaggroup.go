@@ -65,6 +65,8 @@ var FlagGroups = []FlagGroup{
 			TxPoolAllowLocalAnchorTxFlag,
 			TxPoolJournalFlag,
 			TxPoolJournalIntervalFlag,
+			TxPoolRemoteJournalFlag,
+			TxPoolRemoteJournalLimitFlag,
 			TxPoolPriceLimitFlag,
 			TxPoolPriceBumpFlag,
 			TxPoolExecSlotsAccountFlag,
@@ -73,6 +75,11 @@ var FlagGroups = []FlagGroup{
 			TxPoolNonExecSlotsAllFlag,
 			TxPoolLifetimeFlag,
 			TxPoolKeepLocalsFlag,
+			TxPoolSenderRateLimitFlag,
+			TxPoolSenderRateBurstFlag,
+			TxPoolFeePayerQuotaFlag,
+			TxPoolDenyListFlag,
+			TxPoolAllowListFlag,
 			TxResendIntervalFlag,
 			TxResendCountFlag,
 			TxResendUseLegacyFlag,
@@ -93,6 +100,10 @@ var FlagGroups = []FlagGroup{
 			DynamoDBWriteCapacityFlag,
 			NoParallelDBWriteFlag,
 			SenderTxHashIndexingFlag,
+			RewardDetailsFlag,
+			ValidatorStatsFlag,
+			ValidatorStatsFromFlag,
+			LogIndexingFlag,
 			DBNoPerformanceMetricsFlag,
 		},
 	},
@@ -167,6 +178,11 @@ var FlagGroups = []FlagGroup{
 			TrieMemoryCacheSizeFlag,
 			TrieBlockIntervalFlag,
 			TriesInMemoryFlag,
+			StatePruningFlag,
+			StatePruningRetentionFlag,
+			SnapshotFlag,
+			SnapshotCacheSizeFlag,
+			SnapshotSyncGenFlag,
 		},
 	},
 	{
@@ -241,6 +257,19 @@ var FlagGroups = []FlagGroup{
 			RPCVirtualHostsFlag,
 			RPCApiFlag,
 			RPCGlobalGasCap,
+			RPCLogQueryBlockRangeFlag,
+			RPCLogQueryResultLimitFlag,
+			RPCMethodAllowFlag,
+			RPCMethodDenyFlag,
+			RPCMethodConcurrencyFlag,
+			RPCSlowRequestThresholdFlag,
+			RPCAuthTokenFileFlag,
+			RPCAuthJWTSecretFlag,
+			RPCRateLimitFlag,
+			RPCRateLimitBurstFlag,
+			RPCRateLimitCostFlag,
+			RPCBatchLimitFlag,
+			RPCResponseLimitFlag,
 			IPCDisabledFlag,
 			IPCPathFlag,
 			WSEnabledFlag,
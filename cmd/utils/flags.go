@@ -122,7 +122,7 @@ var (
 	defaultSyncMode = cn.GetDefaultConfig().SyncMode
 	SyncModeFlag    = TextMarshalerFlag{
 		Name:  "syncmode",
-		Usage: `Blockchain sync mode (only "full" is supported)`,
+		Usage: `Blockchain sync mode ("full" or "snap")`,
 		Value: &defaultSyncMode,
 	}
 	GCModeFlag = cli.StringFlag{
@@ -161,6 +161,16 @@ var (
 		Usage: "Time interval to regenerate the local transaction journal",
 		Value: blockchain.DefaultTxPoolConfig.JournalInterval,
 	}
+	TxPoolRemoteJournalFlag = cli.StringFlag{
+		Name:  "txpool.remote-journal",
+		Usage: "Disk journal for remote transaction to survive node restarts (empty = disabled)",
+		Value: blockchain.DefaultTxPoolConfig.RemoteJournal,
+	}
+	TxPoolRemoteJournalLimitFlag = cli.Uint64Flag{
+		Name:  "txpool.remote-journal-limit",
+		Usage: "Maximum number of transactions in the remote transaction journal",
+		Value: blockchain.DefaultTxPoolConfig.RemoteJournalLimit,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -200,6 +210,29 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: cn.GetDefaultConfig().TxPool.Lifetime,
 	}
+	TxPoolSenderRateLimitFlag = cli.Float64Flag{
+		Name:  "txpool.sender-ratelimit",
+		Usage: "Number of transactions per second admitted from a sender (0 = unlimited)",
+		Value: cn.GetDefaultConfig().TxPool.SenderRateLimit,
+	}
+	TxPoolSenderRateBurstFlag = cli.Uint64Flag{
+		Name:  "txpool.sender-burst",
+		Usage: "Maximum number of transactions admitted from a sender at once",
+		Value: cn.GetDefaultConfig().TxPool.SenderRateBurst,
+	}
+	TxPoolFeePayerQuotaFlag = cli.Uint64Flag{
+		Name:  "txpool.feepayer-quota",
+		Usage: "Maximum number of fee-delegated transactions in the pool paid by a fee payer (0 = unlimited)",
+		Value: cn.GetDefaultConfig().TxPool.FeePayerQuota,
+	}
+	TxPoolDenyListFlag = cli.StringFlag{
+		Name:  "txpool.denylist",
+		Usage: "Comma separated addresses whose transactions are rejected as a sender or a fee payer",
+	}
+	TxPoolAllowListFlag = cli.StringFlag{
+		Name:  "txpool.allowlist",
+		Usage: "Comma separated addresses whose transactions are only admitted (empty = any sender)",
+	}
 	// KES
 	KESNodeTypeServiceFlag = cli.BoolFlag{
 		Name:  "kes.nodetype.service",
@@ -279,6 +312,28 @@ var (
 		Usage: "The number of recent state tries residing in the memory",
 		Value: blockchain.DefaultTriesInMemory,
 	}
+	StatePruningFlag = cli.BoolFlag{
+		Name:  "state.pruning",
+		Usage: "Enables pruning the state tries older than the retention from the disk (not supported in the archive mode)",
+	}
+	StatePruningRetentionFlag = cli.Uint64Flag{
+		Name:  "state.pruning-retention",
+		Usage: "The number of recent blocks whose committed state tries are kept by the state pruning",
+		Value: blockchain.DefaultStatePruningRetention,
+	}
+	SnapshotFlag = cli.BoolFlag{
+		Name:  "snapshot",
+		Usage: "Enables the state snapshot for the fast access to the accounts and storage slots",
+	}
+	SnapshotCacheSizeFlag = cli.IntFlag{
+		Name:  "snapshot.cache-size",
+		Usage: "Size of in-memory cache of the state snapshot (in MiB)",
+		Value: 512,
+	}
+	SnapshotSyncGenFlag = cli.BoolFlag{
+		Name:  "snapshot.sync-gen",
+		Usage: "Generates the state snapshot synchronously while starting up the node",
+	}
 	CacheTypeFlag = cli.IntFlag{
 		Name:  "cache.type",
 		Usage: "Cache Type: 0=LRUCache, 1=LRUShardCache, 2=FIFOCache",
@@ -337,6 +392,22 @@ var (
 		Name:  "sendertxhashindexing",
 		Usage: "Enables storing mapping information of senderTxHash to txHash",
 	}
+	LogIndexingFlag = cli.BoolFlag{
+		Name:  "logindexing",
+		Usage: "Enables the log index of addresses and topics to accelerate highly selective klay_getLogs queries",
+	}
+	RewardDetailsFlag = cli.BoolFlag{
+		Name:  "rewarddetails",
+		Usage: "Enables storing the details of the block rewards served by klay_getRewards",
+	}
+	ValidatorStatsFlag = cli.BoolFlag{
+		Name:  "validatorstats",
+		Usage: "Enables counting the proposals and the committed seals of validators served by istanbul_getValidatorStats",
+	}
+	ValidatorStatsFromFlag = cli.Uint64Flag{
+		Name:  "validatorstats.from",
+		Usage: "The first block number counted in the validator stats, if no block has been counted yet",
+	}
 	ChildChainIndexingFlag = cli.BoolFlag{
 		Name:  "childchainindexing",
 		Usage: "Enables storing transaction hash of child chain transaction for fast access to child chain data",
@@ -450,6 +521,64 @@ var (
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in klay_call/estimateGas",
 	}
+	RPCMethodAllowFlag = cli.StringFlag{
+		Name:  "rpc.method.allow",
+		Usage: "Comma separated list of the RPC methods allowed on all the endpoints, e.g. klay_*,net_version (empty = all methods)",
+	}
+	RPCMethodDenyFlag = cli.StringFlag{
+		Name:  "rpc.method.deny",
+		Usage: "Comma separated list of the RPC methods denied on all the endpoints, e.g. debug_*,klay_getLogs",
+	}
+	RPCMethodConcurrencyFlag = cli.StringFlag{
+		Name:  "rpc.method.concurrency",
+		Usage: "Comma separated list of the concurrency limits of the RPC methods, e.g. klay_getLogs=8,debug_*=2",
+	}
+	RPCSlowRequestThresholdFlag = cli.DurationFlag{
+		Name:  "rpc.slowrequest",
+		Usage: "Logs the method and the truncated params of the RPC requests taking longer than the given duration, redacting the params of the personal and signing methods (0 = disabled)",
+		Value: rpc.SlowRequestThreshold,
+	}
+	RPCAuthTokenFileFlag = cli.StringFlag{
+		Name:  "rpc.auth.tokens",
+		Usage: "File of the bearer tokens required on the HTTP, WS and gRPC endpoints, each line having a token and its comma separated namespaces, e.g. \"mytoken admin,debug\" (* = all namespaces)",
+	}
+	RPCAuthJWTSecretFlag = cli.StringFlag{
+		Name:  "rpc.auth.jwtsecret",
+		Usage: "File of the hex encoded secret verifying the HS256 JWT bearer tokens on the HTTP, WS and gRPC endpoints, which should have the exp or the iat claim",
+	}
+	RPCRateLimitFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit",
+		Usage: "Maximum number of RPC requests per second of each client identified by its bearer token or the IP address of its connection on the HTTP, WS and gRPC endpoints (0 = no limit)",
+	}
+	RPCRateLimitBurstFlag = cli.IntFlag{
+		Name:  "rpc.ratelimit.burst",
+		Usage: "Maximum number of RPC requests of each client at once under the rate limit",
+		Value: 100,
+	}
+	RPCRateLimitCostFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit.cost",
+		Usage: "Comma separated list of the number of requests counted by the rate limit for the RPC methods, e.g. klay_getLogs=10,debug_*=50 (default = 1)",
+	}
+	RPCBatchLimitFlag = cli.IntFlag{
+		Name:  "rpc.batchlimit",
+		Usage: "Maximum number of requests in an RPC batch request (0 = no limit)",
+		Value: rpc.MaxBatchRequests,
+	}
+	RPCResponseLimitFlag = cli.IntFlag{
+		Name:  "rpc.responselimit",
+		Usage: "Maximum size in bytes of the result of an RPC request (0 = no limit)",
+		Value: rpc.MaxResponseSize,
+	}
+	RPCLogQueryBlockRangeFlag = cli.Uint64Flag{
+		Name:  "rpc.logquery.blockrange",
+		Usage: "Sets the maximum number of blocks scanned by klay_getLogs and a page of klay_getLogsPage (0 = no limit)",
+		Value: cn.GetDefaultConfig().RPCLogQueryBlockRange,
+	}
+	RPCLogQueryResultLimitFlag = cli.IntFlag{
+		Name:  "rpc.logquery.limit",
+		Usage: "Sets the maximum number of logs returned by klay_getLogs and a page of klay_getLogsPage (0 = no limit)",
+		Value: cn.GetDefaultConfig().RPCLogQueryResultLimit,
+	}
 	WSEnabledFlag = cli.BoolFlag{
 		Name:  "ws",
 		Usage: "Enable the WS-RPC server",
@@ -476,7 +605,7 @@ var (
 	}
 	WSMaxSubscriptionPerConn = cli.IntFlag{
 		Name:  "wsmaxsubscriptionperconn",
-		Usage: "Allowed maximum subscription number per a websocket connection",
+		Usage: "Allowed maximum subscription number per a websocket connection or a gRPC connection of the typed API",
 		Value: 5,
 	}
 	WSReadDeadLine = cli.Int64Flag{
@@ -1180,6 +1309,70 @@ func setgRPC(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setRPCMethodPolicy sets the access control, the concurrency limits and the
+// slow request logging of the RPC methods from the set command line flags.
+func setRPCMethodPolicy(ctx *cli.Context) {
+	var allow, deny []string
+	if ctx.GlobalIsSet(RPCMethodAllowFlag.Name) {
+		allow = splitAndTrim(ctx.GlobalString(RPCMethodAllowFlag.Name))
+	}
+	if ctx.GlobalIsSet(RPCMethodDenyFlag.Name) {
+		deny = splitAndTrim(ctx.GlobalString(RPCMethodDenyFlag.Name))
+	}
+	limits := parseMethodValues(ctx, RPCMethodConcurrencyFlag)
+	if err := rpc.SetMethodPolicy(allow, deny, limits); err != nil {
+		log.Fatalf("Invalid RPC method policy: %v", err)
+	}
+	rpc.SlowRequestThreshold = ctx.GlobalDuration(RPCSlowRequestThresholdFlag.Name)
+}
+
+// setRPCRateLimit sets the rate limit of the clients and the size limits of
+// the batch requests and the responses from the set command line flags.
+func setRPCRateLimit(ctx *cli.Context) {
+	costs := parseMethodValues(ctx, RPCRateLimitCostFlag)
+	if err := rpc.SetRateLimit(ctx.GlobalFloat64(RPCRateLimitFlag.Name), ctx.GlobalInt(RPCRateLimitBurstFlag.Name), costs); err != nil {
+		log.Fatalf("Invalid RPC rate limit: %v", err)
+	}
+	rpc.MaxBatchRequests = ctx.GlobalInt(RPCBatchLimitFlag.Name)
+	rpc.MaxResponseSize = ctx.GlobalInt(RPCResponseLimitFlag.Name)
+}
+
+// parseMethodValues parses the comma separated list of the RPC method patterns
+// and their values, e.g. klay_getLogs=8,debug_*=2.
+func parseMethodValues(ctx *cli.Context, flag cli.StringFlag) map[string]int {
+	values := make(map[string]int)
+	if !ctx.GlobalIsSet(flag.Name) {
+		return values
+	}
+	for _, entry := range splitAndTrim(ctx.GlobalString(flag.Name)) {
+		elems := strings.Split(entry, "=")
+		if len(elems) != 2 {
+			log.Fatalf("Option %q: invalid value %q", flag.Name, entry)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(elems[1]))
+		if err != nil {
+			log.Fatalf("Option %q: invalid value %q", flag.Name, entry)
+		}
+		values[strings.TrimSpace(elems[0])] = value
+	}
+	return values
+}
+
+// setRPCAuth sets the authenticator of the bearer tokens required on the HTTP,
+// WS and gRPC endpoints.
+func setRPCAuth(ctx *cli.Context) {
+	tokenFile, jwtSecretFile := ctx.GlobalString(RPCAuthTokenFileFlag.Name), ctx.GlobalString(RPCAuthJWTSecretFlag.Name)
+	if tokenFile == "" && jwtSecretFile == "" {
+		rpc.SetAuthenticator(nil)
+		return
+	}
+	auth, err := rpc.LoadAuthenticator(tokenFile, jwtSecretFile)
+	if err != nil {
+		log.Fatalf("Invalid RPC authentication: %v", err)
+	}
+	rpc.SetAuthenticator(auth)
+}
+
 // MakeAddress converts an account specified directly as a hex encoded string or
 // a key index in the key store to an internal account representation.
 func MakeAddress(ks *keystore.KeyStore, account string) (accounts.Account, error) {
@@ -1318,6 +1511,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
 	setgRPC(ctx, cfg)
+	setRPCMethodPolicy(ctx)
+	setRPCAuth(ctx)
+	setRPCRateLimit(ctx)
 	setNodeUserIdent(ctx, cfg)
 
 	if dbtype := database.DBType(ctx.GlobalString(DbTypeFlag.Name)).ToValid(); len(dbtype) != 0 {
@@ -1348,6 +1544,12 @@ func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolJournalIntervalFlag.Name) {
 		cfg.JournalInterval = ctx.GlobalDuration(TxPoolJournalIntervalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolRemoteJournalFlag.Name) {
+		cfg.RemoteJournal = ctx.GlobalString(TxPoolRemoteJournalFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolRemoteJournalLimitFlag.Name) {
+		cfg.RemoteJournalLimit = ctx.GlobalUint64(TxPoolRemoteJournalLimitFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1372,6 +1574,36 @@ func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSenderRateLimitFlag.Name) {
+		cfg.SenderRateLimit = ctx.GlobalFloat64(TxPoolSenderRateLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolSenderRateBurstFlag.Name) {
+		cfg.SenderRateBurst = ctx.GlobalUint64(TxPoolSenderRateBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolFeePayerQuotaFlag.Name) {
+		cfg.FeePayerQuota = ctx.GlobalUint64(TxPoolFeePayerQuotaFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolDenyListFlag.Name) {
+		cfg.DenyList = parseAddressList(ctx.GlobalString(TxPoolDenyListFlag.Name), TxPoolDenyListFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolAllowListFlag.Name) {
+		cfg.AllowList = parseAddressList(ctx.GlobalString(TxPoolAllowListFlag.Name), TxPoolAllowListFlag.Name)
+	}
+}
+
+// parseAddressList parses comma separated addresses given by the flag.
+func parseAddressList(list string, flagName string) []common.Address {
+	var addrs []common.Address
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		if !common.IsHexAddress(addr) {
+			log.Fatalf("Invalid address in %s: %s", flagName, addr)
+		}
+		addrs = append(addrs, common.HexToAddress(addr))
+	}
+	return addrs
 }
 
 // CheckExclusive verifies that only a single instance of the provided flags was
@@ -1440,8 +1672,8 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
-		if cfg.SyncMode != downloader.FullSync {
-			log.Fatalf("only syncmode=full can be used for syncmode!")
+		if cfg.SyncMode != downloader.FullSync && cfg.SyncMode != downloader.SnapSync {
+			log.Fatalf("only syncmode=full or syncmode=snap can be used for syncmode!")
 		}
 	}
 
@@ -1492,6 +1724,20 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	common.DefaultCacheType = common.CacheType(ctx.GlobalInt(CacheTypeFlag.Name))
 	cfg.TrieBlockInterval = ctx.GlobalUint(TrieBlockIntervalFlag.Name)
 	cfg.TriesInMemory = ctx.GlobalUint64(TriesInMemoryFlag.Name)
+	if ctx.GlobalIsSet(StatePruningFlag.Name) {
+		if cfg.NoPruning {
+			log.Fatalf("--%s can't be used with --%s=archive", StatePruningFlag.Name, GCModeFlag.Name)
+		}
+		cfg.StatePruning = true
+	}
+	cfg.StatePruningRetention = ctx.GlobalUint64(StatePruningRetentionFlag.Name)
+	if ctx.GlobalIsSet(SnapshotFlag.Name) {
+		cfg.SnapshotCacheSize = ctx.GlobalInt(SnapshotCacheSizeFlag.Name)
+		if cfg.SnapshotCacheSize <= 0 {
+			log.Fatalf("--%s must be positive if --%s is set", SnapshotCacheSizeFlag.Name, SnapshotFlag.Name)
+		}
+	}
+	cfg.SnapshotAsyncGen = !ctx.GlobalIsSet(SnapshotSyncGenFlag.Name)
 
 	if ctx.GlobalIsSet(CacheScaleFlag.Name) {
 		common.CacheScale = ctx.GlobalInt(CacheScaleFlag.Name)
@@ -1520,6 +1766,10 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	}
 
 	cfg.SenderTxHashIndexing = ctx.GlobalIsSet(SenderTxHashIndexingFlag.Name)
+	cfg.Istanbul.StoreRewardDetails = ctx.GlobalIsSet(RewardDetailsFlag.Name)
+	cfg.Istanbul.ValidatorStats = ctx.GlobalIsSet(ValidatorStatsFlag.Name)
+	cfg.Istanbul.ValidatorStatsFrom = ctx.GlobalUint64(ValidatorStatsFromFlag.Name)
+	cfg.LogIndexing = ctx.GlobalIsSet(LogIndexingFlag.Name)
 	cfg.ParallelDBWrite = !ctx.GlobalIsSet(NoParallelDBWriteFlag.Name)
 	cfg.TrieNodeCacheConfig = statedb.TrieNodeCacheConfig{
 		CacheType: statedb.TrieNodeCacheType(ctx.GlobalString(TrieNodeCacheTypeFlag.
@@ -1552,6 +1802,8 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	if ctx.GlobalIsSet(RPCGlobalGasCap.Name) {
 		cfg.RPCGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(RPCGlobalGasCap.Name))
 	}
+	cfg.RPCLogQueryBlockRange = ctx.GlobalUint64(RPCLogQueryBlockRangeFlag.Name)
+	cfg.RPCLogQueryResultLimit = ctx.GlobalInt(RPCLogQueryResultLimitFlag.Name)
 
 	// Override any default configs for hard coded network.
 	// TODO-Klaytn-Bootnode: Discuss and add `baobab` test network's genesis block
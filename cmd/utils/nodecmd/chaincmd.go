@@ -23,15 +23,26 @@ package nodecmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
 	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	istanbulBackend "github.com/klaytn/klaytn/consensus/istanbul/backend"
 	"github.com/klaytn/klaytn/governance"
 	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/node/cn"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/reward"
 	"github.com/klaytn/klaytn/rlp"
 	"github.com/klaytn/klaytn/storage/database"
 	"gopkg.in/urfave/cli.v1"
@@ -67,8 +78,92 @@ participating.
 
 It expects the genesis file as argument.`,
 	}
+	ImportCommand = cli.Command{
+		Action:    utils.MigrateFlags(importChain),
+		Name:      "import",
+		Usage:     "Import a blockchain file",
+		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
+		Flags:     chainDataFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The import command imports blocks from an RLP-encoded form. The form can be one file
+with several RLP-encoded blocks, or several files can be used.
+
+If only one file is used, import error will result in failure. If several files are used,
+processing will proceed even if an individual RLP-file import failure occurs.
+Files ending with .gz are decompressed on the fly.`,
+	}
+	ExportCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportChain),
+		Name:      "export",
+		Usage:     "Export blockchain into file",
+		ArgsUsage: "<filename> [<blockNumFirst> <blockNumLast>]",
+		Flags:     chainDataFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last block to write. In this mode, the file will be appended
+if already existing. If the file ends with .gz, the output will
+be gzipped.`,
+	}
+	ImportPreimagesCommand = cli.Command{
+		Action:    utils.MigrateFlags(importPreimages),
+		Name:      "import-preimages",
+		Usage:     "Import the preimage database from an RLP stream",
+		ArgsUsage: "<datafile>",
+		Flags:     chainDataFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-preimages command imports hash preimages from an RLP encoded stream.`,
+	}
+	ExportPreimagesCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportPreimages),
+		Name:      "export-preimages",
+		Usage:     "Export the preimage database into an RLP stream",
+		ArgsUsage: "<dumpfile>",
+		Flags:     chainDataFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-preimages command exports hash preimages to an RLP encoded stream.`,
+	}
+	DumpCommand = cli.Command{
+		Action:    utils.MigrateFlags(dump),
+		Name:      "dump",
+		Usage:     "Dump a specific block from storage",
+		ArgsUsage: "[<blockHash> | <blockNum>]...",
+		Flags:     chainDataFlags,
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The arguments are interpreted as block numbers or hashes.
+Use "debug.dumpBlock" to dump the state of a block on a running node.`,
+	}
 )
 
+// chainDataFlags are the flags used by the commands which open the chain
+// database directly without starting the node.
+var chainDataFlags = []cli.Flag{
+	utils.DataDirFlag,
+	utils.ConfigFileFlag,
+	utils.NetworkIdFlag,
+	utils.DbTypeFlag,
+	utils.SingleDBFlag,
+	utils.NumStateTrieShardsFlag,
+	utils.DynamoDBTableNameFlag,
+	utils.DynamoDBRegionFlag,
+	utils.DynamoDBIsProvisionedFlag,
+	utils.DynamoDBReadCapacityFlag,
+	utils.DynamoDBWriteCapacityFlag,
+	utils.DynamoDBReadOnlyFlag,
+	utils.LevelDBCacheSizeFlag,
+	utils.LevelDBCompressionTypeFlag,
+	utils.NoParallelDBWriteFlag,
+	utils.GCModeFlag,
+	utils.TrieMemoryCacheSizeFlag,
+	utils.TrieBlockIntervalFlag,
+	utils.TriesInMemoryFlag,
+}
+
 // initGenesis will initialise the given JSON format genesis file and writes it as
 // the zero'd block (i.e. genesis) or will fail hard if it can't succeed.
 func initGenesis(ctx *cli.Context) error {
@@ -212,3 +307,217 @@ func ValidateGenesisConfig(g *blockchain.Genesis) error {
 	}
 	return nil
 }
+
+// makeChainDatabase opens the chain database described by the given CN config.
+func makeChainDatabase(stack *node.Node, cfg *cn.Config) database.DBManager {
+	dbc := &database.DBConfig{Dir: "chaindata", DBType: cfg.DBType, ParallelDBWrite: cfg.ParallelDBWrite,
+		SingleDB: cfg.SingleDB, NumStateTrieShards: cfg.NumStateTrieShards,
+		LevelDBCacheSize: cfg.LevelDBCacheSize, OpenFilesLimit: database.GetOpenFilesLimit(),
+		LevelDBCompression: cfg.LevelDBCompression, LevelDBBufferPool: cfg.LevelDBBufferPool,
+		EnableDBPerfMetrics: cfg.EnableDBPerfMetrics, DynamoDBConfig: &cfg.DynamoDBConfig}
+	return stack.OpenDatabase(dbc)
+}
+
+// makeChain creates a BlockChain on top of the chain database without
+// starting the p2p stack or any other node service.
+func makeChain(ctx *cli.Context) (*blockchain.BlockChain, database.DBManager) {
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := makeChainDatabase(stack, &cfg.CN)
+
+	chainConfig, _, err := blockchain.SetupGenesisBlock(chainDB, cfg.CN.Genesis, cfg.CN.NetworkId, cfg.CN.IsPrivate, false)
+	if _, ok := err.(*params.ConfigCompatError); err != nil && !ok {
+		logger.Crit("Failed to setup genesis block", "err", err)
+	}
+	if chainConfig.Clique != nil {
+		types.EngineType = types.Engine_Clique
+	}
+	if chainConfig.Istanbul != nil {
+		types.EngineType = types.Engine_IBFT
+	}
+	if chainConfig.Governance == nil {
+		chainConfig.Governance = params.GetDefaultGovernanceConfig(params.UseIstanbul)
+	}
+
+	gov := governance.NewGovernanceInitialize(chainConfig, chainDB)
+	engine := istanbulBackend.New(cfg.CN.Rewardbase, &cfg.CN.Istanbul, cfg.Node.NodeKey(), chainDB, gov,
+		cfg.Node.P2P.ConnectionType)
+
+	cacheConfig := &blockchain.CacheConfig{ArchiveMode: cfg.CN.NoPruning, CacheSize: cfg.CN.TrieCacheSize,
+		BlockInterval: cfg.CN.TrieBlockInterval, TriesInMemory: cfg.CN.TriesInMemory,
+		TrieNodeCacheConfig: &cfg.CN.TrieNodeCacheConfig, SenderTxHashIndexing: cfg.CN.SenderTxHashIndexing}
+
+	chain, err := blockchain.NewBlockChain(chainDB, cacheConfig, chainConfig, engine, vm.Config{})
+	if err != nil {
+		logger.Crit("Can't create BlockChain", "err", err)
+	}
+	gov.SetBlockchain(chain)
+	if chain.Config().Istanbul != nil {
+		chain.Config().Istanbul.ProposerPolicy = gov.ProposerPolicy()
+	}
+	if chain.Config().Governance.Reward != nil {
+		chain.Config().Governance.Reward.UseGiniCoeff = gov.UseGiniCoeff()
+	}
+	if params.IsWeightedRandomPolicy(gov.ProposerPolicy()) {
+		reward.NewStakingManager(chain, gov, chainDB)
+	}
+	return chain, chainDB
+}
+
+func importChain(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		log.Fatalf("This command requires an argument.")
+	}
+	chain, db := makeChain(ctx)
+	defer db.Close()
+
+	// Start periodically gathering memory profiles
+	var peakMemAlloc, peakMemSys uint64
+	go func() {
+		stats := new(runtime.MemStats)
+		for {
+			runtime.ReadMemStats(stats)
+			if atomic.LoadUint64(&peakMemAlloc) < stats.Alloc {
+				atomic.StoreUint64(&peakMemAlloc, stats.Alloc)
+			}
+			if atomic.LoadUint64(&peakMemSys) < stats.Sys {
+				atomic.StoreUint64(&peakMemSys, stats.Sys)
+			}
+			time.Sleep(5 * time.Second)
+		}
+	}()
+	// Import the chain
+	start := time.Now()
+
+	var importErr error
+	if len(ctx.Args()) == 1 {
+		if err := utils.ImportChain(chain, ctx.Args().First()); err != nil {
+			importErr = fmt.Errorf("import error: %v", err)
+		}
+	} else {
+		for _, arg := range ctx.Args() {
+			if err := utils.ImportChain(chain, arg); err != nil {
+				logger.Error("Import error", "file", arg, "err", err)
+			}
+		}
+	}
+	chain.Stop()
+	if importErr != nil {
+		return importErr
+	}
+	fmt.Printf("Import done in %v.\n\n", time.Since(start))
+
+	// Print the memory statistics used by the importing
+	mem := new(runtime.MemStats)
+	runtime.ReadMemStats(mem)
+
+	fmt.Printf("Object memory: %.3f MB current, %.3f MB peak\n", float64(mem.Alloc)/1024/1024, float64(atomic.LoadUint64(&peakMemAlloc))/1024/1024)
+	fmt.Printf("System memory: %.3f MB current, %.3f MB peak\n", float64(mem.Sys)/1024/1024, float64(atomic.LoadUint64(&peakMemSys))/1024/1024)
+	fmt.Printf("Allocations:   %.3f million\n", float64(mem.Mallocs)/1000000)
+	fmt.Printf("GC pause:      %v\n\n", time.Duration(mem.PauseTotalNs))
+
+	return nil
+}
+
+func exportChain(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		log.Fatalf("This command requires an argument.")
+	}
+	chain, db := makeChain(ctx)
+	defer db.Close()
+	defer chain.Stop()
+
+	start := time.Now()
+
+	var err error
+	fp := ctx.Args().First()
+	if len(ctx.Args()) < 3 {
+		err = utils.ExportChain(chain, fp)
+	} else {
+		// This can be improved to allow for numbers larger than 9223372036854775807
+		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		if ferr != nil || lerr != nil {
+			log.Fatalf("Export error in parsing parameters: block number not an integer\n")
+		}
+		if first < 0 || last < 0 {
+			log.Fatalf("Export error: block number must be greater than 0\n")
+		}
+		if head := chain.CurrentBlock().NumberU64(); uint64(last) > head {
+			log.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head)
+		}
+		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+	}
+
+	if err != nil {
+		log.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+// importPreimages imports preimage data from the specified file.
+func importPreimages(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		log.Fatalf("This command requires an argument.")
+	}
+	stack, cfg := makeConfigNode(ctx)
+	db := makeChainDatabase(stack, &cfg.CN)
+	defer db.Close()
+
+	start := time.Now()
+	if err := utils.ImportPreimages(db, ctx.Args().First()); err != nil {
+		log.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+// exportPreimages dumps the preimage data to specified json file in streaming way.
+func exportPreimages(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		log.Fatalf("This command requires an argument.")
+	}
+	stack, cfg := makeConfigNode(ctx)
+	db := makeChainDatabase(stack, &cfg.CN)
+	defer db.Close()
+
+	start := time.Now()
+	if err := utils.ExportPreimages(db, ctx.Args().First()); err != nil {
+		log.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func dump(ctx *cli.Context) error {
+	chain, db := makeChain(ctx)
+	defer db.Close()
+	defer chain.Stop()
+
+	for _, arg := range ctx.Args() {
+		var block *types.Block
+		if hashish(arg) {
+			block = chain.GetBlockByHash(common.HexToHash(arg))
+		} else {
+			num, _ := strconv.Atoi(arg)
+			block = chain.GetBlockByNumber(uint64(num))
+		}
+		if block == nil {
+			fmt.Println("{}")
+			log.Fatalf("block not found")
+		} else {
+			stateDB, err := chain.StateAt(block.Root())
+			if err != nil {
+				log.Fatalf("could not create new state: %v", err)
+			}
+			fmt.Printf("%s\n", stateDB.Dump())
+		}
+	}
+	return nil
+}
+
+// hashish returns true for strings that look like hashes.
+func hashish(x string) bool {
+	_, err := strconv.Atoi(x)
+	return err != nil
+}
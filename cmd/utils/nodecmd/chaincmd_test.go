@@ -0,0 +1,81 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Tests that the chain of a data directory can be exported, imported into a
+// fresh data directory and dumped without starting a node.
+func TestExportImportDump(t *testing.T) {
+	genesis := customGenesisTests[4].genesis
+
+	srcDir := tmpdir(t)
+	defer os.RemoveAll(srcDir)
+	dstDir := tmpdir(t)
+	defer os.RemoveAll(dstDir)
+
+	json := filepath.Join(srcDir, "genesis.json")
+	if err := ioutil.WriteFile(json, []byte(genesis), 0600); err != nil {
+		t.Fatalf("failed to write genesis file: %v", err)
+	}
+	runKlay(t, "klay-test", "--datadir", srcDir, "--verbosity", "0", "init", json).WaitExit()
+	runKlay(t, "klay-test", "--datadir", dstDir, "--verbosity", "0", "init", json).WaitExit()
+
+	// Export the whole chain and a range of it, the latter with gzip
+	exported := filepath.Join(srcDir, "chain.rlp")
+	runKlay(t, "klay-test", "--datadir", srcDir, "--verbosity", "0", "export", exported).WaitExit()
+	if fi, err := os.Stat(exported); err != nil || fi.Size() == 0 {
+		t.Fatalf("exported chain is missing or empty: %v", err)
+	}
+	gzipped := filepath.Join(srcDir, "chain.rlp.gz")
+	runKlay(t, "klay-test", "--datadir", srcDir, "--verbosity", "0", "export", gzipped, "0", "0").WaitExit()
+	if fi, err := os.Stat(gzipped); err != nil || fi.Size() == 0 {
+		t.Fatalf("exported chain range is missing or empty: %v", err)
+	}
+
+	// Import the exported chain into another data directory
+	klay := runKlay(t, "klay-test", "--datadir", dstDir, "--verbosity", "0", "import", exported)
+	klay.ExpectRegexp("Import done in")
+	klay.WaitExit()
+
+	// The import of a single file fails on an error
+	klay = runKlay(t, "klay-test", "--datadir", dstDir, "--verbosity", "0", "import", filepath.Join(srcDir, "missing.rlp"))
+	klay.WaitExit()
+	if stderr := klay.StderrText(); !strings.Contains(stderr, "import error") {
+		t.Fatalf("import error is not reported: %q", stderr)
+	}
+
+	// Export and re-import the preimages
+	preimages := filepath.Join(srcDir, "preimages.rlp")
+	klay = runKlay(t, "klay-test", "--datadir", srcDir, "--verbosity", "0", "export-preimages", preimages)
+	klay.ExpectRegexp("Export done in")
+	klay.WaitExit()
+	klay = runKlay(t, "klay-test", "--datadir", dstDir, "--verbosity", "0", "import-preimages", preimages)
+	klay.ExpectRegexp("Import done in")
+	klay.WaitExit()
+
+	// Dump the genesis state of the imported chain
+	klay = runKlay(t, "klay-test", "--datadir", dstDir, "--verbosity", "0", "dump", "0")
+	klay.ExpectRegexp(`"dddfb991127b43e209c2f8ed08b8b3d0b5843d36"`)
+	klay.WaitExit()
+}
@@ -109,7 +109,7 @@ var flagsWithValues = []struct {
 	{
 		flag:        "--syncmode",
 		flagType:    FlagTypeArgument,
-		values:      []string{"full"}, //[]string{"fast", "full"},
+		values:      []string{"full", "snap"}, //[]string{"fast", "full"},
 		wrongValues: commonThreeErrors,
 		errors:      []int{ErrorInvalidValue, ErrorInvalidValue, ErrorInvalidValue},
 	},
@@ -284,6 +284,10 @@ var flagsWithValues = []struct {
 		flag:     "--sendertxhashindexing",
 		flagType: FlagTypeBoolean,
 	},
+	{
+		flag:     "--rewarddetails",
+		flagType: FlagTypeBoolean,
+	},
 	{
 		flag:     "--childchainindexing",
 		flagType: FlagTypeBoolean,
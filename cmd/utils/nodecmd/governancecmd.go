@@ -0,0 +1,93 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var GovernanceCommand = cli.Command{
+	Name:     "governance",
+	Usage:    "Inspect the governance data stored in the database",
+	Category: "GOVERNANCE COMMANDS",
+	Subcommands: []cli.Command{
+		{
+			Name:      "history",
+			Usage:     "Show every change of a governance item",
+			ArgsUsage: "<key> [<blockNumFirst> <blockNumLast>]",
+			Flags:     chainDataFlags,
+			Action:    utils.MigrateFlags(governanceHistory),
+			Description: `
+The history command walks the governance index in the database and prints every
+change of the given governance item (e.g. governance.unitprice) as JSON.
+Each change contains the block number, the old and new values and the voters.
+Optional second and third arguments limit the range of block numbers.
+Do not use this command while a node is executing.`,
+		},
+	},
+}
+
+func governanceHistory(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 && len(ctx.Args()) != 3 {
+		return errors.New("this command requires a key and an optional block range")
+	}
+	from, to := uint64(0), uint64(math.MaxUint64)
+	if len(ctx.Args()) == 3 {
+		var err error
+		if from, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid first block number: %v", err)
+		}
+		if to, err = strconv.ParseUint(ctx.Args().Get(2), 10, 64); err != nil {
+			return fmt.Errorf("invalid last block number: %v", err)
+		}
+		if from > to {
+			return errors.New("the first block number is larger than the last one")
+		}
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	chainDB := makeChainDatabase(stack, &cfg.CN)
+	defer chainDB.Close()
+
+	chainConfig := chainDB.ReadChainConfig(chainDB.ReadCanonicalHash(0))
+	if chainConfig == nil {
+		return errors.New("no chain config found in the database, the genesis block should be initialized first")
+	}
+	if chainConfig.Governance == nil {
+		chainConfig.Governance = params.GetDefaultGovernanceConfig(params.UseIstanbul)
+	}
+
+	changes, err := governance.NewGovernance(chainConfig, chainDB).ItemHistory(ctx.Args().First(), from, to)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -42,6 +42,8 @@ var CommonNodeFlags = []cli.Flag{
 	utils.TxPoolAllowLocalAnchorTxFlag,
 	utils.TxPoolJournalFlag,
 	utils.TxPoolJournalIntervalFlag,
+	utils.TxPoolRemoteJournalFlag,
+	utils.TxPoolRemoteJournalLimitFlag,
 	utils.TxPoolPriceLimitFlag,
 	utils.TxPoolPriceBumpFlag,
 	utils.TxPoolExecSlotsAccountFlag,
@@ -50,6 +52,11 @@ var CommonNodeFlags = []cli.Flag{
 	utils.TxPoolNonExecSlotsAllFlag,
 	utils.TxPoolLifetimeFlag,
 	utils.TxPoolKeepLocalsFlag,
+	utils.TxPoolSenderRateLimitFlag,
+	utils.TxPoolSenderRateBurstFlag,
+	utils.TxPoolFeePayerQuotaFlag,
+	utils.TxPoolDenyListFlag,
+	utils.TxPoolAllowListFlag,
 	utils.SyncModeFlag,
 	utils.GCModeFlag,
 	utils.LightKDFFlag,
@@ -67,9 +74,18 @@ var CommonNodeFlags = []cli.Flag{
 	utils.LevelDBCacheSizeFlag,
 	utils.NoParallelDBWriteFlag,
 	utils.SenderTxHashIndexingFlag,
+	utils.RewardDetailsFlag,
+	utils.ValidatorStatsFlag,
+	utils.ValidatorStatsFromFlag,
+	utils.LogIndexingFlag,
 	utils.TrieMemoryCacheSizeFlag,
 	utils.TrieBlockIntervalFlag,
 	utils.TriesInMemoryFlag,
+	utils.StatePruningFlag,
+	utils.StatePruningRetentionFlag,
+	utils.SnapshotFlag,
+	utils.SnapshotCacheSizeFlag,
+	utils.SnapshotSyncGenFlag,
 	utils.CacheTypeFlag,
 	utils.CacheScaleFlag,
 	utils.CacheUsageLevelFlag,
@@ -120,6 +136,19 @@ var CommonRPCFlags = []cli.Flag{
 	utils.RPCPortFlag,
 	utils.RPCApiFlag,
 	utils.RPCGlobalGasCap,
+	utils.RPCLogQueryBlockRangeFlag,
+	utils.RPCLogQueryResultLimitFlag,
+	utils.RPCMethodAllowFlag,
+	utils.RPCMethodDenyFlag,
+	utils.RPCMethodConcurrencyFlag,
+	utils.RPCSlowRequestThresholdFlag,
+	utils.RPCAuthTokenFileFlag,
+	utils.RPCAuthJWTSecretFlag,
+	utils.RPCRateLimitFlag,
+	utils.RPCRateLimitBurstFlag,
+	utils.RPCRateLimitCostFlag,
+	utils.RPCBatchLimitFlag,
+	utils.RPCResponseLimitFlag,
 	utils.WSEnabledFlag,
 	utils.WSListenAddrFlag,
 	utils.WSPortFlag,
@@ -88,6 +88,11 @@ func init() {
 	app.Commands = []cli.Command{
 		// See chaincmd.go:
 		InitCommand,
+		ImportCommand,
+		ExportCommand,
+		ImportPreimagesCommand,
+		ExportPreimagesCommand,
+		DumpCommand,
 
 		// See accountcmd.go
 		AccountCommand,
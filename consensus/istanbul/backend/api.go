@@ -27,8 +27,10 @@ import (
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/consensus"
 	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/validator"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"math/big"
 	"reflect"
@@ -128,6 +130,77 @@ func (api *API) Discard(address common.Address) {
 	delete(api.istanbul.candidates, address)
 }
 
+// GetValidatorStats returns the proposals and the committed seals of the given
+// validator counted in the epochs overlapping the blocks from `from` to `to`.
+// If `from` is not given, it counts from the genesis block, and if `to` is not
+// given, it counts to the latest block.
+func (api *API) GetValidatorStats(address common.Address, from, to *rpc.BlockNumber) (*ValidatorStatsResult, error) {
+	if !api.istanbul.validatorStats.enabled() {
+		return nil, errValidatorStatsDisabled
+	}
+	current := api.chain.CurrentHeader().Number.Uint64()
+	start, end := uint64(0), current
+	if from != nil {
+		if *from == rpc.PendingBlockNumber {
+			return nil, errPendingNotAllowed
+		}
+		if *from != rpc.LatestBlockNumber {
+			start = uint64(from.Int64())
+		} else {
+			start = current
+		}
+	}
+	if to != nil {
+		if *to == rpc.PendingBlockNumber {
+			return nil, errPendingNotAllowed
+		}
+		if *to != rpc.LatestBlockNumber {
+			end = uint64(to.Int64())
+		}
+	}
+	if start > end {
+		return nil, errStartLargerThanEnd
+	}
+	if end > current {
+		return nil, errEndLargetThanLatest
+	}
+
+	tracker := api.istanbul.validatorStats
+	head, err := api.istanbul.db.ReadValidatorStatsHead()
+	if err != nil {
+		return nil, err
+	}
+	if head < end {
+		if end-head <= validatorStatsSyncLimit {
+			if err := tracker.count(api.chain, end); err != nil {
+				return nil, err
+			}
+		} else {
+			// Count the blocks in background and return the counted stats only
+			tracker.update(api.chain)
+		}
+	}
+
+	header := api.chain.GetHeaderByNumber(start)
+	if header == nil {
+		return nil, errNoBlockExist
+	}
+	snap, err := api.istanbul.snapshot(api.chain, start, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	epochs, err := tracker.stats(address, start, end, snap.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	result := &ValidatorStatsResult{Address: address, Epochs: epochs}
+	for _, epoch := range epochs {
+		result.Total.add(&epoch.ValidatorStats)
+	}
+	result.LastCountedBlock, err = api.istanbul.db.ReadValidatorStatsHead()
+	return result, err
+}
+
 // API extended by Klaytn developers
 type APIExtension struct {
 	chain    consensus.ChainReader
@@ -145,6 +218,8 @@ var (
 	errExtractIstanbulExtra    = errors.New("extract Istanbul Extra from block header of the given block number")
 	errNoBlockExist            = errors.New("block with the given block number is not existed")
 	errNoBlockNumber           = errors.New("block number is not assigned")
+	errNoRewardDetail          = errors.New("reward details of the block are not stored")
+	errValidatorStatsDisabled  = errors.New("validator stats are not counted, enable them with --validatorstats")
 )
 
 // GetCouncil retrieves the list of authorized validators at the specified block.
@@ -250,7 +325,7 @@ func (api *APIExtension) getConsensusInfo(block *types.Block) (ConsensusInfo, er
 	originProposer := common.Address{}
 	lastProposer := common.Address{}
 	// TODO-Klaytn add the logic to get the last proposer for other policies. Weighted Random doesn't need it.
-	if istanbul.ProposerPolicy(snap.Policy) == istanbul.WeightedRandom {
+	if validator.IsWeightedPolicy(istanbul.ProposerPolicy(snap.Policy)) {
 		newValSet := snap.ValSet.Copy()
 		newValSet.CalcProposer(lastProposer, 0)
 		originProposer = newValSet.GetProposer().Address()
@@ -455,6 +530,45 @@ func (api *APIExtension) GetBlockWithConsensusInfoByHash(blockHash common.Hash)
 	return api.makeRPCBlockOutput(block, cInfo, block.Transactions(), receipts), nil
 }
 
+// GetRewards returns the details of the reward distributed in the given block.
+// The details are available only if they were stored when the block was processed.
+func (api *APIExtension) GetRewards(number *rpc.BlockNumber) (map[string]interface{}, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else if *number == rpc.PendingBlockNumber {
+		return nil, errPendingNotAllowed
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errNoBlockExist
+	}
+
+	detail, err := api.istanbul.readRewardDetail(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	rewards := make(map[common.Address]*hexutil.Big, len(detail.Rewards))
+	for addr, amount := range detail.Rewards {
+		rewards[addr] = (*hexutil.Big)(amount)
+	}
+	return map[string]interface{}{
+		"blockNumber":        (*hexutil.Big)(header.Number),
+		"minted":             (*hexutil.Big)(detail.Minted),
+		"totalFee":           (*hexutil.Big)(detail.TotalFee),
+		"deferredTxFee":      detail.DeferredTxFee,
+		"ratio":              detail.Ratio,
+		"useGini":            detail.UseGini,
+		"gini":               detail.Gini,
+		"stakingBlockNumber": hexutil.Uint64(detail.StakingBlockNumber),
+		"proposer":           detail.Proposer,
+		"poc":                detail.PoC,
+		"kir":                detail.KIR,
+		"rewards":            rewards,
+	}, nil
+}
+
 func (api *API) GetTimeout() uint64 {
 	return istanbul.DefaultConfig.Timeout
 }
@@ -54,6 +54,7 @@ func New(rewardbase common.Address, config *istanbul.Config, privateKey *ecdsa.P
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	recentMessages, _ := lru.NewARC(inmemoryPeers)
 	knownMessages, _ := lru.NewARC(inmemoryMessages)
+	pendingRewardDetails, _ := lru.NewARC(inmemoryPendingRewardDetails)
 	backend := &backend{
 		config:            config,
 		istanbulEventMux:  new(event.TypeMux),
@@ -71,8 +72,11 @@ func New(rewardbase common.Address, config *istanbul.Config, privateKey *ecdsa.P
 		governance:        governance,
 		nodetype:          nodetype,
 		rewardDistributor: reward.NewRewardDistributor(governance),
+
+		pendingRewardDetails: pendingRewardDetails,
 	}
 	backend.currentView.Store(&istanbul.View{Sequence: big.NewInt(0), Round: big.NewInt(0)})
+	backend.validatorStats = newValidatorStatsTracker(backend, db)
 	backend.core = istanbulCore.New(backend, backend.config)
 	return backend
 }
@@ -121,6 +125,12 @@ type backend struct {
 
 	rewardDistributor *reward.RewardDistributor
 
+	// Counters of proposals and committed seals of validators
+	validatorStats *validatorStatsTracker
+
+	// The reward details of the blocks being mined, indexed by their signature hashes
+	pendingRewardDetails *lru.ARCCache
+
 	// Node type
 	nodetype common.ConnType
 }
@@ -300,6 +310,7 @@ func (sb *backend) Commit(proposal istanbul.Proposal, seals [][]byte) error {
 	//    the next block and the previous Seal() will be stopped.
 	// -- otherwise, a error will be returned and a round change event will be fired.
 	if sb.proposedBlockHash == block.Hash() {
+		sb.commitRewardDetail(block.Header())
 		// feed block hash to Seal() and wait the Seal() result
 		sb.commitCh <- &types.Result{Block: block, Round: round}
 		return nil
@@ -55,6 +55,8 @@ const (
 	inmemoryPeers      = 200
 	inmemoryMessages   = 4096
 
+	inmemoryPendingRewardDetails = 16 // Number of the reward details of the blocks being mined
+
 	allowedFutureBlockTime = 1 * time.Second // Max time from current time allowed for blocks, before they're considered future blocks
 )
 
@@ -349,8 +351,10 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	receipts []*types.Receipt) (*types.Block, error) {
 
+	var rewardDetail *reward.RewardDetail
+
 	// If sb.chain is nil, it means backend is not initialized yet.
-	if sb.chain != nil && sb.governance.ProposerPolicy() == uint64(istanbul.WeightedRandom) {
+	if sb.chain != nil && params.IsWeightedRandomPolicy(sb.governance.ProposerPolicy()) {
 		// TODO-Klaytn Let's redesign below logic and remove dependency between block reward and istanbul consensus.
 
 		pocAddr := common.Address{}
@@ -375,24 +379,34 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 			logger.Trace(logMsg, "header.Number", header.Number.Uint64(), "node address", sb.address, "rewardbase", header.Rewardbase)
 		}
 
-		if stakingInfo := reward.GetStakingInfo(header.Number.Uint64()); stakingInfo != nil {
+		stakingInfo := reward.GetStakingInfo(header.Number.Uint64())
+		if stakingInfo != nil {
 			kirAddr = stakingInfo.KIRAddr
 			pocAddr = stakingInfo.PoCAddr
 		}
 
-		if err := sb.rewardDistributor.DistributeBlockReward(state, header, pocAddr, kirAddr); err != nil {
+		detail, err := sb.rewardDistributor.DistributeBlockReward(state, header, pocAddr, kirAddr)
+		if err != nil {
 			return nil, err
 		}
+		detail.SetStakingInfo(stakingInfo)
+		rewardDetail = detail
 	} else {
-		if err := sb.rewardDistributor.MintKLAY(state, header); err != nil {
+		detail, err := sb.rewardDistributor.MintKLAY(state, header)
+		if err != nil {
 			return nil, err
 		}
+		rewardDetail = detail
 	}
 
+	// A block being mined doesn't have its state root yet
+	mining := common.EmptyHash(header.Root)
 	header.Root = state.IntermediateRoot(true)
 
 	// Assemble and return the final block for sealing
-	return types.NewBlock(header, txs, receipts), nil
+	block := types.NewBlock(header, txs, receipts)
+	sb.recordRewardDetail(block.Header(), rewardDetail, mining)
+	return block, nil
 }
 
 // Seal generates a new block for the given input block with the local miner's
@@ -633,7 +647,7 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	if err != nil {
 		return nil, err
 	}
-	if sb.governance.ProposerPolicy() == uint64(istanbul.WeightedRandom) {
+	if params.IsWeightedRandomPolicy(sb.governance.ProposerPolicy()) {
 		// Snapshot of block N (Snapshot_N) should contain proposers for N+1 and following blocks.
 		// And proposers for Block N+1 can be calculated from the nearest previous proposersUpdateInterval block.
 		// Let's refresh proposers in Snapshot_N using previous proposersUpdateInterval block for N+1, if not updated yet.
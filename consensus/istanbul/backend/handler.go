@@ -42,8 +42,8 @@ var (
 	// TODO-Klaytn-Istanbul: define Versions and Lengths with correct values.
 	istanbulProtocol = consensus.Protocol{
 		Name:     "istanbul",
-		Versions: []uint{64},
-		Lengths:  []uint64{21},
+		Versions: []uint{66, 65, 64},
+		Lengths:  []uint64{30, 27, 21},
 	}
 )
 
@@ -134,5 +134,6 @@ func (sb *backend) NewChainHead() error {
 	}
 
 	go sb.istanbulEventMux.Post(istanbul.FinalCommittedEvent{})
+	sb.validatorStats.update(sb.chain)
 	return nil
 }
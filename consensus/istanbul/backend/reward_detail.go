@@ -0,0 +1,73 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/reward"
+)
+
+// recordRewardDetail stores the reward details of a finalized block if enabled.
+// The hash of a block being mined is not determined until it is committed,
+// so its details are kept in memory until the block is committed.
+func (sb *backend) recordRewardDetail(header *types.Header, detail *reward.RewardDetail, mining bool) {
+	if !sb.config.StoreRewardDetails || detail == nil {
+		return
+	}
+	if mining {
+		sb.pendingRewardDetails.Add(sigHash(header), detail)
+		return
+	}
+	sb.writeRewardDetail(header.Hash(), detail)
+}
+
+// commitRewardDetail stores the reward details of the committed block mined by this node.
+func (sb *backend) commitRewardDetail(header *types.Header) {
+	if !sb.config.StoreRewardDetails {
+		return
+	}
+	if detail, ok := sb.pendingRewardDetails.Get(sigHash(header)); ok {
+		sb.writeRewardDetail(header.Hash(), detail.(*reward.RewardDetail))
+	}
+}
+
+func (sb *backend) writeRewardDetail(hash common.Hash, detail *reward.RewardDetail) {
+	b, err := json.Marshal(detail)
+	if err != nil {
+		logger.Error("Failed to marshal reward details", "number", detail.BlockNumber, "err", err)
+		return
+	}
+	if err := sb.db.WriteRewardDetail(hash, b); err != nil {
+		logger.Error("Failed to write reward details", "number", detail.BlockNumber, "err", err)
+	}
+}
+
+// readRewardDetail retrieves the stored reward details of the block of the given hash.
+func (sb *backend) readRewardDetail(hash common.Hash) (*reward.RewardDetail, error) {
+	b, err := sb.db.ReadRewardDetail(hash)
+	if err != nil || len(b) == 0 {
+		return nil, errNoRewardDetail
+	}
+	detail := new(reward.RewardDetail)
+	if err := json.Unmarshal(b, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests that the reward details of a block mined by the node are stored when
+// the block is committed, and those of a block mined by others are stored when
+// the block is processed.
+func TestRewardDetail(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	defer engine.Stop()
+
+	config := *engine.config
+	config.StoreRewardDetails = true
+	engine.config = &config
+
+	block := makeBlock(chain, engine, chain.Genesis())
+	_, err := engine.readRewardDetail(block.Hash())
+	assert.NoError(t, err)
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatal(err)
+	}
+	minted, _ := new(big.Int).SetString(engine.governance.MintingAmount(), 10)
+
+	api := &APIExtension{chain: chain, istanbul: engine}
+	number := rpc.BlockNumber(1)
+	rewards, err := api.GetRewards(&number)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, (*hexutil.Big)(big.NewInt(1)), rewards["blockNumber"])
+	assert.Equal(t, (*hexutil.Big)(minted), rewards["minted"])
+	assert.Equal(t, "", rewards["ratio"])
+	assert.Equal(t, map[common.Address]*hexutil.Big{block.Rewardbase(): (*hexutil.Big)(minted)}, rewards["rewards"])
+
+	// The details of a block being mined are not stored until it is committed
+	block = makeBlockWithoutSeal(chain, engine, block)
+	_, err = engine.readRewardDetail(block.Hash())
+	assert.Equal(t, errNoRewardDetail, err)
+
+	// The details of a block whose state root is determined are stored when it is processed
+	state, _ := chain.StateAt(chain.CurrentBlock().Root())
+	if _, err := engine.Finalize(chain, block.Header(), state, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	detail, err := engine.readRewardDetail(block.Hash())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(2), detail.BlockNumber)
+	assert.Equal(t, minted, detail.Minted)
+	assert.Equal(t, map[common.Address]*big.Int{block.Rewardbase(): minted}, detail.Rewards)
+
+	// The details are not stored if disabled
+	config.StoreRewardDetails = false
+	header := block.Header()
+	header.Time = new(big.Int).Add(header.Time, common.Big1)
+	state, _ = chain.StateAt(chain.CurrentBlock().Root())
+	if _, err := engine.Finalize(chain, header, state, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, err = engine.readRewardDetail(header.Hash())
+	assert.Equal(t, errNoRewardDetail, err)
+
+	pending := rpc.PendingBlockNumber
+	_, err = api.GetRewards(&pending)
+	assert.Equal(t, errPendingNotAllowed, err)
+}
@@ -184,16 +184,17 @@ func (s *Snapshot) apply(headers []*types.Header, gov *governance.Governance, ad
 			gov.UpdateCurrentGovernance(number)
 			gov.ClearVotes(number)
 
+			// Carry over the votes of the proposals in their voting windows
+			snap.Votes, snap.Tally = gov.ExpireVotes(number, snap.Epoch, snap.ValSet, snap.Votes, snap.Tally)
+
 			// Reload governance values because epoch changed
 			snap.Epoch, snap.Policy, snap.CommitteeSize = getGovernanceValue(gov, number)
-			snap.Votes = make([]governance.GovernanceVote, 0)
-			snap.Tally = make([]governance.GovernanceTallyItem, 0)
 		}
 	}
 	snap.Number += uint64(len(headers))
 	snap.Hash = headers[len(headers)-1].Hash()
 
-	if snap.ValSet.Policy() == istanbul.WeightedRandom {
+	if validator.IsWeightedPolicy(snap.ValSet.Policy()) {
 		// TODO-Klaytn-Issue1166 We have to update block number of ValSet too.
 		snap.ValSet.SetBlockNum(snap.Number)
 	}
@@ -273,7 +274,7 @@ func (s *Snapshot) toJSONStruct() *snapshotJSON {
 	var validators []common.Address
 
 	// TODO-Klaytn-Issue1166 For weightedCouncil
-	if s.ValSet.Policy() == istanbul.WeightedRandom {
+	if validator.IsWeightedPolicy(s.ValSet.Policy()) {
 		validators, rewardAddrs, votingPowers, weights, proposers, proposersBlockNum = validator.GetWeightedCouncilData(s.ValSet)
 	} else {
 		validators = s.validators()
@@ -310,7 +311,7 @@ func (s *Snapshot) UnmarshalJSON(b []byte) error {
 	s.Tally = j.Tally
 
 	// TODO-Klaytn-Issue1166 For weightedCouncil
-	if j.Policy == istanbul.WeightedRandom {
+	if validator.IsWeightedPolicy(j.Policy) {
 		s.ValSet = validator.NewWeightedCouncil(j.Validators, j.RewardAddrs, j.VotingPowers, j.Weights, j.Policy, j.SubGroupSize, j.Number, j.ProposersBlockNum, nil)
 		validator.RecoverWeightedCouncilProposer(s.ValSet, j.Proposers)
 	} else {
@@ -0,0 +1,280 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	istanbulCore "github.com/klaytn/klaytn/consensus/istanbul/core"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+const (
+	// validatorStatsSyncLimit is the maximum number of blocks counted while
+	// serving an API call. If more blocks are left, they are counted in background.
+	validatorStatsSyncLimit = 128
+
+	// validatorStatsFlushInterval is the number of blocks counted before the
+	// stats of the current epoch are written to the database.
+	validatorStatsFlushInterval = 1024
+)
+
+// ValidatorStats is the reliability counters of a validator.
+type ValidatorStats struct {
+	Proposed       uint64 `json:"proposed"`       // Number of blocks proposed by the validator
+	MissedProposal uint64 `json:"missedProposal"` // Number of rounds the validator failed to propose in
+	Committee      uint64 `json:"committee"`      // Number of blocks the validator was a committee member of
+	Signed         uint64 `json:"signed"`         // Number of committed seals signed by the validator
+	MissedSeal     uint64 `json:"missedSeal"`     // Number of blocks the validator didn't sign as a committee member
+}
+
+func (s *ValidatorStats) add(o *ValidatorStats) {
+	s.Proposed += o.Proposed
+	s.MissedProposal += o.MissedProposal
+	s.Committee += o.Committee
+	s.Signed += o.Signed
+	s.MissedSeal += o.MissedSeal
+}
+
+// ValidatorEpochStats is the reliability counters of a validator in an epoch.
+type ValidatorEpochStats struct {
+	Start uint64 `json:"start"` // The first block number of the epoch
+	End   uint64 `json:"end"`   // The last block number counted in the epoch
+	ValidatorStats
+}
+
+// ValidatorStatsResult is the result of istanbul_getValidatorStats.
+type ValidatorStatsResult struct {
+	Address          common.Address         `json:"address"`
+	LastCountedBlock uint64                 `json:"lastCountedBlock"`
+	Total            ValidatorStats         `json:"total"`
+	Epochs           []*ValidatorEpochStats `json:"epochs"`
+}
+
+// validatorStatsEpoch is the counters of all validators in an epoch stored in the database.
+type validatorStatsEpoch struct {
+	Start uint64                             `json:"start"`
+	End   uint64                             `json:"end"`
+	Stats map[common.Address]*ValidatorStats `json:"stats"`
+}
+
+func (e *validatorStatsEpoch) get(addr common.Address) *ValidatorStats {
+	s, ok := e.Stats[addr]
+	if !ok {
+		s = new(ValidatorStats)
+		e.Stats[addr] = s
+	}
+	return s
+}
+
+// validatorStatsTracker counts the proposals and the committed seals of the
+// validators from the headers of the canonical chain. Since the blocks of
+// Istanbul are final, a counted block is never counted again.
+type validatorStatsTracker struct {
+	sb      *backend
+	db      database.DBManager
+	mu      sync.Mutex // Serializes counting
+	running int32      // Whether counting in background or not
+}
+
+func newValidatorStatsTracker(sb *backend, db database.DBManager) *validatorStatsTracker {
+	return &validatorStatsTracker{sb: sb, db: db}
+}
+
+// enabled returns true if counting the validator stats is enabled.
+func (t *validatorStatsTracker) enabled() bool {
+	return t.sb.config.ValidatorStats
+}
+
+// update counts the blocks up to the current block in background.
+// It returns immediately if the tracker is disabled or already counting in background.
+func (t *validatorStatsTracker) update(chain consensus.ChainReader) {
+	if chain == nil || !t.enabled() || !atomic.CompareAndSwapInt32(&t.running, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&t.running, 0)
+		if err := t.count(chain, chain.CurrentHeader().Number.Uint64()); err != nil {
+			logger.Warn("Failed to count validator stats", "err", err)
+		}
+	}()
+}
+
+// count counts the blocks from the next block of the last counted one to the given block number.
+// The blocks before the configured first block are not counted.
+func (t *validatorStatsTracker) count(chain consensus.ChainReader, to uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	head, err := t.db.ReadValidatorStatsHead()
+	if err != nil {
+		return err
+	}
+	if from := t.sb.config.ValidatorStatsFrom; head+1 < from {
+		head = from - 1
+	}
+	var epoch *validatorStatsEpoch
+	flush := func() error {
+		if epoch == nil {
+			return nil
+		}
+		b, err := json.Marshal(epoch)
+		if err != nil {
+			return err
+		}
+		if err := t.db.WriteValidatorStats(epoch.Start, b); err != nil {
+			return err
+		}
+		return t.db.WriteValidatorStatsHead(epoch.End)
+	}
+
+	for number := head + 1; number <= to; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		snap, err := t.sb.snapshot(chain, number-1, header.ParentHash, nil)
+		if err != nil {
+			flush()
+			return err
+		}
+		start := number - number%snap.Epoch
+		if epoch == nil || epoch.Start != start {
+			if err := flush(); err != nil {
+				return err
+			}
+			if epoch, err = t.readEpoch(start); err != nil {
+				return err
+			}
+		}
+		if err := t.countBlock(chain, snap, header, epoch); err != nil {
+			flush()
+			return err
+		}
+		epoch.End = number
+
+		if number%validatorStatsFlushInterval == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// countBlock adds the proposal and the committed seals of the given header to
+// the counters of the epoch. The snapshot should be the one of the parent block.
+func (t *validatorStatsTracker) countBlock(chain consensus.ChainReader, snap *Snapshot, header *types.Header, epoch *validatorStatsEpoch) error {
+	number := header.Number.Uint64()
+	proposer, err := ecrecover(header)
+	if err != nil {
+		return err
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	epoch.get(proposer).Proposed++
+
+	// The proposers of the previous rounds failed to propose this block
+	var lastProposer common.Address
+	if number > 1 {
+		if parent := chain.GetHeader(header.ParentHash, number-1); parent != nil {
+			if lastProposer, err = ecrecover(parent); err != nil {
+				return err
+			}
+		}
+	}
+	round := header.Round()
+	valSet := snap.ValSet.Copy()
+	for r := uint64(0); r < uint64(round); r++ {
+		valSet.CalcProposer(lastProposer, r)
+		if missed := valSet.GetProposer(); missed != nil && missed.Address() != proposer {
+			epoch.get(missed.Address()).MissedProposal++
+		}
+	}
+
+	signers := make(map[common.Address]bool)
+	proposalSeal := istanbulCore.PrepareCommittedSeal(header.Hash())
+	for _, seal := range extra.CommittedSeal {
+		addr, err := istanbul.GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return err
+		}
+		if !signers[addr] {
+			signers[addr] = true
+			epoch.get(addr).Signed++
+		}
+	}
+
+	view := &istanbul.View{
+		Sequence: new(big.Int).SetUint64(number),
+		Round:    new(big.Int).SetUint64(uint64(round)),
+	}
+	for _, val := range snap.ValSet.SubListWithProposer(header.ParentHash, proposer, view) {
+		s := epoch.get(val.Address())
+		s.Committee++
+		if !signers[val.Address()] {
+			s.MissedSeal++
+		}
+	}
+	return nil
+}
+
+func (t *validatorStatsTracker) readEpoch(start uint64) (*validatorStatsEpoch, error) {
+	epoch := &validatorStatsEpoch{Start: start, Stats: make(map[common.Address]*ValidatorStats)}
+	b, err := t.db.ReadValidatorStats(start)
+	if err != nil || len(b) == 0 {
+		// The epoch is not counted yet
+		return epoch, nil
+	}
+	if err := json.Unmarshal(b, epoch); err != nil {
+		return nil, err
+	}
+	if epoch.Stats == nil {
+		epoch.Stats = make(map[common.Address]*ValidatorStats)
+	}
+	return epoch, nil
+}
+
+// stats returns the counters of the given validator in the epochs overlapping
+// the blocks from `from` to `to`, both inclusive.
+func (t *validatorStatsTracker) stats(addr common.Address, from, to, epochLength uint64) ([]*ValidatorEpochStats, error) {
+	ret := []*ValidatorEpochStats{}
+	for _, b := range t.db.ReadValidatorStatsRange(from-from%epochLength, to) {
+		epoch := new(validatorStatsEpoch)
+		if err := json.Unmarshal(b, epoch); err != nil {
+			return nil, err
+		}
+		if epoch.End < from {
+			continue
+		}
+		s := &ValidatorEpochStats{Start: epoch.Start, End: epoch.End}
+		if v, ok := epoch.Stats[addr]; ok {
+			s.ValidatorStats = *v
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
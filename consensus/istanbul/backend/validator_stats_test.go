@@ -0,0 +1,320 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	istanbulCore "github.com/klaytn/klaytn/consensus/istanbul/core"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+const testValidatorStatsEpoch = 5
+
+// testHeaderChain serves the headers sealed by the test instead of the blocks
+// inserted to the blockchain.
+type testHeaderChain struct {
+	*blockchain.BlockChain
+	headers []*types.Header
+}
+
+func (c *testHeaderChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+
+func (c *testHeaderChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *testHeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if h := c.GetHeaderByNumber(number); h != nil && h.Hash() == hash {
+		return h
+	}
+	return nil
+}
+
+func (c *testHeaderChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, h := range c.headers {
+		if h.Hash() == hash {
+			return h
+		}
+	}
+	return nil
+}
+
+func newValidatorStatsTestChain(t *testing.T, n int, from uint64) (*testHeaderChain, *backend, map[common.Address]*ecdsa.PrivateKey, []common.Address) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	addrs := make([]common.Address, n)
+	for i := 0; i < n; i++ {
+		key, _ := crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+		keys[addrs[i]] = key
+	}
+	sortValidatorArray(addrs)
+
+	config := *getTestConfig()
+	config.Istanbul = &params.IstanbulConfig{
+		Epoch:          testValidatorStatsEpoch,
+		ProposerPolicy: uint64(istanbul.RoundRobin),
+		SubGroupSize:   params.DefaultSubGroupSize,
+	}
+	dbm := database.NewDBManager(&database.DBConfig{DBType: database.MemoryDB})
+	gov := governance.NewGovernanceInitialize(&config, dbm)
+	istanbulConfig := *istanbul.DefaultConfig
+	istanbulConfig.ValidatorStats = true
+	istanbulConfig.ValidatorStatsFrom = from
+	engine := New(addrs[0], &istanbulConfig, keys[addrs[0]], dbm, gov, common.CONSENSUSNODE).(*backend)
+
+	genesis := blockchain.DefaultGenesisBlock()
+	genesis.Config = &config
+	genesis.Timestamp = uint64(time.Now().Unix())
+	appendValidators(genesis, addrs)
+	genesis.MustCommit(dbm)
+
+	bc, err := blockchain.NewBlockChain(dbm, nil, genesis.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testHeaderChain{BlockChain: bc, headers: []*types.Header{bc.Genesis().Header()}}, engine, keys, addrs
+}
+
+// addHeader seals a header proposed at the given round, and the validators
+// except the given ones sign its committed seals. It returns the proposers
+// of the rounds before and at the given round.
+func (c *testHeaderChain) addHeader(t *testing.T, engine *backend, keys map[common.Address]*ecdsa.PrivateKey, round int64, nonSigners ...common.Address) []common.Address {
+	parent := c.CurrentHeader()
+	number := parent.Number.Uint64() + 1
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).SetUint64(number),
+		Time:       new(big.Int).Add(parent.Time, common.Big1),
+		BlockScore: defaultBlockScore,
+	}
+	snap, err := engine.snapshot(c, number-1, parent.Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Extra, err = prepareExtra(header, snap.validators()); err != nil {
+		t.Fatal(err)
+	}
+	types.SetRoundToHeader(header, round)
+
+	// Select the proposers in the same way with the Istanbul core
+	var lastProposer common.Address
+	if number > 1 {
+		lastProposer, _ = ecrecover(parent)
+	}
+	var proposers []common.Address
+	valSet := snap.ValSet.Copy()
+	for r := int64(0); r <= round; r++ {
+		valSet.CalcProposer(lastProposer, uint64(r))
+		proposers = append(proposers, valSet.GetProposer().Address())
+	}
+
+	seal, err := crypto.Sign(crypto.Keccak256(sigHash(header).Bytes()), keys[proposers[round]])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeSeal(header, seal); err != nil {
+		t.Fatal(err)
+	}
+
+	var committedSeals [][]byte
+	proposalSeal := istanbulCore.PrepareCommittedSeal(header.Hash())
+	for _, addr := range snap.validators() {
+		if containsAddress(nonSigners, addr) {
+			continue
+		}
+		committedSeal, err := crypto.Sign(crypto.Keccak256(proposalSeal), keys[addr])
+		if err != nil {
+			t.Fatal(err)
+		}
+		committedSeals = append(committedSeals, committedSeal)
+	}
+	if err := writeCommittedSeals(header, committedSeals); err != nil {
+		t.Fatal(err)
+	}
+	c.headers = append(c.headers, header)
+	return proposers
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Tests that the proposals and the committed seals are counted by epoch,
+// and that the stats are the same if the blocks are counted at once or not.
+func TestValidatorStatsTracker(t *testing.T) {
+	chain, engine, keys, addrs := newValidatorStatsTestChain(t, 4, 0)
+	defer chain.Stop()
+
+	var (
+		lazy     = addrs[3]
+		expected = make(map[uint64]map[common.Address]*ValidatorStats)
+		rounds   = map[int]int64{3: 1, 7: 2, 11: 1}
+	)
+	for number := 1; number <= 12; number++ {
+		var nonSigners []common.Address
+		if number%2 == 0 {
+			nonSigners = append(nonSigners, lazy)
+		}
+		proposers := chain.addHeader(t, engine, keys, rounds[number], nonSigners...)
+
+		start := uint64(number - number%testValidatorStatsEpoch)
+		if expected[start] == nil {
+			expected[start] = make(map[common.Address]*ValidatorStats)
+			for _, addr := range addrs {
+				expected[start][addr] = new(ValidatorStats)
+			}
+		}
+		stats := expected[start]
+		stats[proposers[len(proposers)-1]].Proposed++
+		for _, missed := range proposers[:len(proposers)-1] {
+			stats[missed].MissedProposal++
+		}
+		for _, addr := range addrs {
+			stats[addr].Committee++
+			if containsAddress(nonSigners, addr) {
+				stats[addr].MissedSeal++
+			} else {
+				stats[addr].Signed++
+			}
+		}
+	}
+
+	tracker := engine.validatorStats
+	assert.NoError(t, tracker.count(chain, 6))
+	head, _ := engine.db.ReadValidatorStatsHead()
+	assert.Equal(t, uint64(6), head)
+	assert.NoError(t, tracker.count(chain, 12))
+	head, _ = engine.db.ReadValidatorStatsHead()
+	assert.Equal(t, uint64(12), head)
+
+	starts := make([]uint64, 0, len(expected))
+	for start := range expected {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	var totalMissed uint64
+	for _, addr := range addrs {
+		epochs, err := tracker.stats(addr, 0, 12, testValidatorStatsEpoch)
+		assert.NoError(t, err)
+		assert.Equal(t, len(starts), len(epochs))
+		for i, epoch := range epochs {
+			assert.Equal(t, starts[i], epoch.Start)
+			assert.Equal(t, *expected[starts[i]][addr], epoch.ValidatorStats, "validator %v, epoch %d", addr.String(), epoch.Start)
+			totalMissed += epoch.MissedProposal
+		}
+		assert.Equal(t, uint64(12), epochs[len(epochs)-1].End)
+	}
+	assert.Equal(t, uint64(4), totalMissed)
+
+	// Counting the blocks again doesn't change the stats
+	assert.NoError(t, tracker.count(chain, 12))
+	epochs, _ := tracker.stats(lazy, 0, 12, testValidatorStatsEpoch)
+	assert.Equal(t, *expected[0][lazy], epochs[0].ValidatorStats)
+}
+
+// Tests that the blocks before the configured first block are not counted.
+func TestValidatorStatsTracker_From(t *testing.T) {
+	chain, engine, keys, addrs := newValidatorStatsTestChain(t, 4, 6)
+	defer chain.Stop()
+
+	for number := 1; number <= 12; number++ {
+		chain.addHeader(t, engine, keys, 0)
+	}
+	tracker := engine.validatorStats
+	assert.NoError(t, tracker.count(chain, 12))
+
+	epochs, err := tracker.stats(addrs[0], 0, 12, testValidatorStatsEpoch)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(epochs))
+	assert.Equal(t, uint64(5), epochs[0].Start)
+	assert.Equal(t, uint64(4), epochs[0].Committee)
+	assert.Equal(t, uint64(3), epochs[1].Committee)
+
+	// The counted blocks are not counted again from the configured first block
+	engine.config.ValidatorStatsFrom = 1
+	assert.NoError(t, tracker.count(chain, 12))
+	epochs, _ = tracker.stats(addrs[0], 0, 12, testValidatorStatsEpoch)
+	assert.Equal(t, 2, len(epochs))
+}
+
+func TestAPI_GetValidatorStats(t *testing.T) {
+	chain, engine, keys, addrs := newValidatorStatsTestChain(t, 4, 0)
+	defer chain.Stop()
+
+	lazy := addrs[3]
+	for number := 1; number <= 12; number++ {
+		chain.addHeader(t, engine, keys, 0, lazy)
+	}
+	api := &API{chain: chain, istanbul: engine}
+
+	from, to := rpc.BlockNumber(6), rpc.BlockNumber(12)
+	result, err := api.GetValidatorStats(lazy, &from, &to)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), result.LastCountedBlock)
+	assert.Equal(t, 2, len(result.Epochs))
+	assert.Equal(t, uint64(5), result.Epochs[0].Start)
+	assert.Equal(t, uint64(9), result.Epochs[0].End)
+	assert.Equal(t, uint64(10), result.Epochs[1].Start)
+	assert.Equal(t, ValidatorStats{Proposed: 2, Committee: 8, MissedSeal: 8}, result.Total)
+
+	// All blocks are counted if the range is not given
+	result, err = api.GetValidatorStats(addrs[0], nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(result.Epochs))
+	assert.Equal(t, uint64(12), result.Total.Signed)
+
+	// Invalid ranges
+	_, err = api.GetValidatorStats(lazy, &to, &from)
+	assert.Equal(t, errStartLargerThanEnd, err)
+	future := rpc.BlockNumber(13)
+	_, err = api.GetValidatorStats(lazy, &from, &future)
+	assert.Equal(t, errEndLargetThanLatest, err)
+	pending := rpc.PendingBlockNumber
+	_, err = api.GetValidatorStats(lazy, &pending, nil)
+	assert.Equal(t, errPendingNotAllowed, err)
+
+	// The stats are not served if they are not counted
+	engine.config.ValidatorStats = false
+	_, err = api.GetValidatorStats(lazy, nil, nil)
+	assert.Equal(t, errValidatorStatsDisabled, err)
+}
@@ -26,6 +26,7 @@ const (
 	RoundRobin ProposerPolicy = iota
 	Sticky
 	WeightedRandom
+	StakeCappedWeightedRandom
 )
 
 type Config struct {
@@ -34,6 +35,11 @@ type Config struct {
 	ProposerPolicy ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
 	Epoch          uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	SubGroupSize   uint64         `toml:",omitempty"`
+
+	StoreRewardDetails bool `toml:",omitempty"` // Store the details of the reward distributed in each block
+
+	ValidatorStats     bool   `toml:",omitempty"` // Count the proposals and the committed seals of validators
+	ValidatorStatsFrom uint64 `toml:",omitempty"` // The first block number counted in the validator stats
 }
 
 var DefaultConfig = &Config{
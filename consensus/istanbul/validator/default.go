@@ -85,14 +85,20 @@ func newDefaultSet(addrs []common.Address, policy istanbul.ProposerPolicy) *defa
 	if valSet.Size() > 0 {
 		valSet.proposer.Store(valSet.GetByIndex(0))
 	}
-	valSet.selector = roundRobinProposer
-	if policy == istanbul.Sticky {
-		valSet.selector = stickyProposer
-	}
+	valSet.selector = defaultSetSelector(policy)
 
 	return valSet
 }
 
+// defaultSetSelector returns the selector of the given policy if it can be
+// served by defaultSet. Otherwise, it falls back to round robin.
+func defaultSetSelector(policy istanbul.ProposerPolicy) istanbul.ProposalSelector {
+	if p, ok := GetProposerPolicy(policy); ok && !p.Weighted {
+		return p.Selector
+	}
+	return roundRobinProposer
+}
+
 func newDefaultSubSet(addrs []common.Address, policy istanbul.ProposerPolicy, subSize uint64) *defaultSet {
 	valSet := &defaultSet{}
 
@@ -109,10 +115,7 @@ func newDefaultSubSet(addrs []common.Address, policy istanbul.ProposerPolicy, su
 	if valSet.Size() > 0 {
 		valSet.proposer.Store(valSet.GetByIndex(0))
 	}
-	valSet.selector = roundRobinProposer
-	if policy == istanbul.Sticky {
-		valSet.selector = stickyProposer
-	}
+	valSet.selector = defaultSetSelector(policy)
 
 	return valSet
 }
@@ -0,0 +1,147 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/params"
+)
+
+// maxWeightRatioToAverage limits the weight of a validator under the
+// stake-capped weighted random policy to the multiple of the average weight.
+const maxWeightRatioToAverage = 2
+
+// ProposerPolicy defines how a validator set selects proposers under a value
+// of the istanbul.policy governance item.
+type ProposerPolicy struct {
+	// Name is the value of the policy in governance votes, e.g. "roundrobin".
+	Name string
+
+	// Weighted is true if the policy is served by the weighted council, which
+	// refreshes its proposers with staking information.
+	Weighted bool
+
+	// Selector picks a proposer with the last proposer and the round.
+	Selector istanbul.ProposalSelector
+
+	// Weights returns the number of slots of each validator in the proposers
+	// shuffled at refresh. It is only used by weighted policies.
+	Weights func(validators istanbul.Validators) []uint64
+}
+
+var proposerPolicies = make(map[istanbul.ProposerPolicy]*ProposerPolicy)
+
+func init() {
+	RegisterProposerPolicy(istanbul.RoundRobin, &ProposerPolicy{
+		Name:     "roundrobin",
+		Selector: roundRobinProposer,
+	})
+	RegisterProposerPolicy(istanbul.Sticky, &ProposerPolicy{
+		Name:     "sticky",
+		Selector: stickyProposer,
+	})
+	RegisterProposerPolicy(istanbul.WeightedRandom, &ProposerPolicy{
+		Name:     "weightedrandom",
+		Weighted: true,
+		Selector: weightedRandomProposer,
+		Weights:  stakingWeights,
+	})
+	RegisterProposerPolicy(istanbul.StakeCappedWeightedRandom, &ProposerPolicy{
+		Name:     "stakecappedweightedrandom",
+		Weighted: true,
+		Selector: weightedRandomProposer,
+		Weights:  cappedStakingWeights,
+	})
+}
+
+// RegisterProposerPolicy adds a proposer policy to the registry. It is supposed
+// to be called in init functions, and it panics if the policy or its name is
+// already registered or a weighted policy doesn't define its weights.
+// A weighted policy is also registered to params, so that the packages below
+// the validator package can tell if the staking information is needed.
+func RegisterProposerPolicy(policy istanbul.ProposerPolicy, p *ProposerPolicy) {
+	if _, exist := proposerPolicies[policy]; exist {
+		panic(fmt.Sprintf("proposer policy %d is already registered", policy))
+	}
+	if p.Name == "" || p.Selector == nil || (p.Weighted && p.Weights == nil) {
+		panic(fmt.Sprintf("proposer policy %d is incomplete", policy))
+	}
+	for _, registered := range proposerPolicies {
+		if registered.Name == p.Name {
+			panic(fmt.Sprintf("proposer policy name %s is already registered", p.Name))
+		}
+	}
+	proposerPolicies[policy] = p
+	if p.Weighted {
+		params.RegisterWeightedRandomPolicy(uint64(policy))
+	}
+}
+
+// GetProposerPolicy returns the registered proposer policy.
+func GetProposerPolicy(policy istanbul.ProposerPolicy) (*ProposerPolicy, bool) {
+	p, ok := proposerPolicies[policy]
+	return p, ok
+}
+
+// ProposerPolicyNames returns the registered proposer policies by their names.
+func ProposerPolicyNames() map[string]istanbul.ProposerPolicy {
+	names := make(map[string]istanbul.ProposerPolicy, len(proposerPolicies))
+	for policy, p := range proposerPolicies {
+		names[p.Name] = policy
+	}
+	return names
+}
+
+// IsWeightedPolicy returns true if the given proposer policy is served by the weighted council.
+func IsWeightedPolicy(policy istanbul.ProposerPolicy) bool {
+	p, ok := proposerPolicies[policy]
+	return ok && p.Weighted
+}
+
+// stakingWeights returns the weights of validators calculated from staking information.
+func stakingWeights(validators istanbul.Validators) []uint64 {
+	weights := make([]uint64, len(validators))
+	for i, val := range validators {
+		weights[i] = val.Weight()
+	}
+	return weights
+}
+
+// cappedStakingWeights returns the weights of validators calculated from staking
+// information, but a weight larger than maxWeightRatioToAverage times of the
+// average weight is capped. It keeps a few large stakers from proposing most blocks.
+func cappedStakingWeights(validators istanbul.Validators) []uint64 {
+	weights := stakingWeights(validators)
+	if len(weights) == 0 {
+		return weights
+	}
+
+	total := uint64(0)
+	for _, weight := range weights {
+		total += weight
+	}
+	numValidators := uint64(len(weights))
+	limit := (maxWeightRatioToAverage*total + numValidators - 1) / numValidators
+	for i, weight := range weights {
+		if weight > limit {
+			weights[i] = limit
+		}
+	}
+	return weights
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/reward"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProposerPolicy_Registry checks that the registered policies agree with
+// the policy values used by the packages which cannot import the validator package.
+func TestProposerPolicy_Registry(t *testing.T) {
+	for _, policy := range []istanbul.ProposerPolicy{istanbul.RoundRobin, istanbul.Sticky, istanbul.WeightedRandom, istanbul.StakeCappedWeightedRandom} {
+		p, ok := GetProposerPolicy(policy)
+		assert.True(t, ok, "policy %d is not registered", policy)
+		assert.NotNil(t, p.Selector)
+		assert.Equal(t, params.IsWeightedRandomPolicy(uint64(policy)), IsWeightedPolicy(policy), "policy %d", policy)
+	}
+	assert.False(t, IsWeightedPolicy(istanbul.ProposerPolicy(100)))
+	assert.Equal(t, map[string]istanbul.ProposerPolicy{
+		"roundrobin":                istanbul.RoundRobin,
+		"sticky":                    istanbul.Sticky,
+		"weightedrandom":            istanbul.WeightedRandom,
+		"stakecappedweightedrandom": istanbul.StakeCappedWeightedRandom,
+	}, ProposerPolicyNames())
+
+	assert.Panics(t, func() {
+		RegisterProposerPolicy(istanbul.WeightedRandom, &ProposerPolicy{Name: "duplicated", Selector: roundRobinProposer})
+	})
+	assert.Panics(t, func() {
+		RegisterProposerPolicy(istanbul.ProposerPolicy(100), &ProposerPolicy{Name: "incomplete", Weighted: true, Selector: weightedRandomProposer})
+	})
+	assert.Panics(t, func() {
+		RegisterProposerPolicy(istanbul.ProposerPolicy(100), &ProposerPolicy{Name: "sticky", Selector: stickyProposer})
+	})
+}
+
+func TestCappedStakingWeights(t *testing.T) {
+	testCases := []struct {
+		weights  []uint64
+		expected []uint64
+	}{
+		{[]uint64{}, []uint64{}},
+		{[]uint64{0, 0, 0}, []uint64{0, 0, 0}},
+		{[]uint64{25, 25, 25, 25}, []uint64{25, 25, 25, 25}},
+		{[]uint64{60, 15, 10, 8, 5, 2}, []uint64{34, 15, 10, 8, 5, 2}},
+		{[]uint64{97, 1, 1, 1}, []uint64{50, 1, 1, 1}},
+		{[]uint64{100}, []uint64{100}},
+	}
+	for _, tc := range testCases {
+		validators := make(istanbul.Validators, len(tc.weights))
+		for i, weight := range tc.weights {
+			validators[i] = newWeightedValidator(testAddrs[i], testRewardAddrs[i], testVotingPowers[i], weight)
+		}
+		assert.Equal(t, tc.weights, stakingWeights(validators))
+		assert.Equal(t, tc.expected, cappedStakingWeights(validators))
+	}
+}
+
+// TestProposerPolicy_DeterministicReplay replays the recorded staking information
+// on the councils built with validators in different orders, and checks that
+// every council calculates the same proposers for the weighted policies.
+func TestProposerPolicy_DeterministicReplay(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/staking_info.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stakingInfos []*reward.StakingInfo
+	if err := json.Unmarshal(blob, &stakingInfos); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		nodeAddrs = stakingInfos[0].CouncilNodeAddrs[:6]
+		hashes    = []common.Hash{
+			common.HexToHash("0x7c2a8a3b4e8e5bbc8f8eac6b7cb5dd7f0e0c0a99bdfa03b9cbfc2e12c8b1d35a"),
+			common.HexToHash("0x1d0e9c6c24bd0e79fcba10f5fe7b2ab1aa8e9f6a6d4e1dfe6e5bc43b8d7b0f12"),
+		}
+		reversed = make([]common.Address, len(nodeAddrs))
+		rotated  = append(append([]common.Address{}, nodeAddrs[3:]...), nodeAddrs[:3]...)
+
+		// The number of proposer slots of each validator in the order of its address
+		expectedSlots = map[istanbul.ProposerPolicy][][]int{
+			istanbul.WeightedRandom:            {{60, 15, 10, 8, 5, 2}, {64, 11, 9, 7, 5, 5}},
+			istanbul.StakeCappedWeightedRandom: {{34, 15, 10, 8, 5, 2}, {34, 11, 9, 7, 5, 5}},
+		}
+	)
+	for i, addr := range nodeAddrs {
+		reversed[len(nodeAddrs)-1-i] = addr
+	}
+
+	for policy, slots := range expectedSlots {
+		councils := []*weightedCouncil{
+			newTestPolicyCouncil(nodeAddrs, policy),
+			newTestPolicyCouncil(reversed, policy),
+			newTestPolicyCouncil(rotated, policy),
+		}
+		for i, info := range stakingInfos {
+			var expected []common.Address
+			for j, valSet := range councils {
+				assert.NoError(t, valSet.refresh(hashes[i], info.BlockNum, info))
+
+				proposers := make([]common.Address, len(valSet.proposers))
+				for k, p := range valSet.proposers {
+					proposers[k] = p.Address()
+				}
+				if j == 0 {
+					expected = proposers
+					assertProposerSlots(t, valSet, slots[i])
+					continue
+				}
+				assert.Equal(t, expected, proposers, "policy %d, block %d, council %d", policy, info.BlockNum, j)
+			}
+		}
+	}
+}
+
+func newTestPolicyCouncil(nodeAddrs []common.Address, policy istanbul.ProposerPolicy) *weightedCouncil {
+	return NewWeightedCouncil(nodeAddrs, nil, make([]uint64, len(nodeAddrs)), nil, policy, 0, 0, 0, nil)
+}
+
+func assertProposerSlots(t *testing.T, valSet *weightedCouncil, expected []int) {
+	count := make(map[common.Address]int)
+	for _, p := range valSet.proposers {
+		count[p.Address()]++
+	}
+	slots := make([]int, len(valSet.validators))
+	for i, val := range valSet.validators {
+		slots[i] = count[val.Address()]
+	}
+	assert.Equal(t, expected, slots)
+}
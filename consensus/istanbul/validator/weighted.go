@@ -134,7 +134,7 @@ func RecoverWeightedCouncilProposer(valSet istanbul.ValidatorSet, proposerAddrs
 
 func NewWeightedCouncil(addrs []common.Address, rewards []common.Address, votingPowers []uint64, weights []uint64, policy istanbul.ProposerPolicy, committeeSize uint64, blockNum uint64, proposersBlockNum uint64, chain consensus.ChainReader) *weightedCouncil {
 
-	if policy != istanbul.WeightedRandom {
+	if !IsWeightedPolicy(policy) {
 		logger.Error("unsupported proposer policy for weighted council", "policy", policy)
 		return nil
 	}
@@ -201,7 +201,8 @@ func NewWeightedCouncil(addrs []common.Address, rewards []common.Address, voting
 		valSet.proposer.Store(valSet.GetByIndex(0))
 	}
 	valSet.SetSubGroupSize(committeeSize)
-	valSet.selector = weightedRandomProposer
+	p, _ := GetProposerPolicy(policy)
+	valSet.selector = p.Selector
 
 	valSet.blockNum = blockNum
 	valSet.proposers = make([]istanbul.Validator, len(addrs))
@@ -221,7 +222,7 @@ func GetWeightedCouncilData(valSet istanbul.ValidatorSet) (validators []common.A
 		return
 	}
 
-	if weightedCouncil.Policy() == istanbul.WeightedRandom {
+	if IsWeightedPolicy(weightedCouncil.Policy()) {
 		numVals := len(weightedCouncil.validators)
 		validators = make([]common.Address, numVals)
 		rewardAddrs = make([]common.Address, numVals)
@@ -568,7 +569,12 @@ func (valSet *weightedCouncil) Refresh(hash common.Hash, blockNum uint64) error
 		// already refreshed
 		return nil
 	}
+	return valSet.refresh(hash, blockNum, reward.GetStakingInfo(blockNum+1))
+}
 
+// refresh recalculates proposers with the given staking information.
+// The caller should hold validatorMu.
+func (valSet *weightedCouncil) refresh(hash common.Hash, blockNum uint64, newStakingInfo *reward.StakingInfo) error {
 	// Check errors
 	numValidators := len(valSet.validators)
 	if numValidators == 0 {
@@ -584,8 +590,6 @@ func (valSet *weightedCouncil) Refresh(hash common.Hash, blockNum uint64) error
 		return err
 	}
 
-	newStakingInfo := reward.GetStakingInfo(blockNum + 1)
-
 	valSet.stakingInfo = newStakingInfo
 	if valSet.stakingInfo == nil {
 		// Just return without updating proposer
@@ -707,8 +711,8 @@ func calcWeight(weightedValidators []*weightedValidator, stakingAmounts []float6
 func (valSet *weightedCouncil) refreshProposers(seed int64, blockNum uint64) {
 	var candidateValsIdx []int // This is a slice which stores index of validator. it is used for shuffling
 
-	for index, val := range valSet.validators {
-		weight := val.Weight()
+	policy, _ := GetProposerPolicy(valSet.policy)
+	for index, weight := range policy.Weights(valSet.validators) {
 		for i := uint64(0); i < weight; i++ {
 			candidateValsIdx = append(candidateValsIdx, index)
 		}
@@ -30,13 +30,15 @@ import (
 const (
 	Klay62 = 62
 	Klay63 = 63
+	Klay65 = 65
+	Klay66 = 66
 )
 
 var (
 	KlayProtocol = Protocol{
 		Name:     "klay",
-		Versions: []uint{Klay63, Klay62},
-		Lengths:  []uint64{17, 8},
+		Versions: []uint{Klay66, Klay65, Klay63, Klay62},
+		Lengths:  []uint64{30, 27, 17, 8},
 	}
 )
 
@@ -182,6 +182,23 @@ web3._extend({
 			call: 'governance_itemCacheFromDb',
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'itemHistory',
+			call: 'governance_itemHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'proposal',
+			call: 'governance_proposal',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'voteHistory',
+			call: 'governance_voteHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
 		})
 	],
 	properties: [
@@ -224,6 +241,10 @@ web3._extend({
 		new web3._extend.Property({
 			name: 'idxCacheFromDb',
 			getter: 'governance_idxCacheFromDb',
+		}),
+		new web3._extend.Property({
+			name: 'proposals',
+			getter: 'governance_proposals',
 		})
 	]
 });
@@ -295,6 +316,10 @@ web3._extend({
 			name: 'saveTrieNodeCacheToDisk',
 			call: 'admin_saveTrieNodeCacheToDisk',
 		}),
+		new web3._extend.Method({
+			name: 'rebuildSnapshot',
+			call: 'admin_rebuildSnapshot',
+		}),
 		new web3._extend.Method({
 			name: 'setMaxSubscriptionPerWSConn',
 			call: 'admin_setMaxSubscriptionPerWSConn',
@@ -318,6 +343,10 @@ web3._extend({
 			name: 'stateMigrationStatus',
 			getter: 'admin_stateMigrationStatus'
 		}),
+		new web3._extend.Property({
+			name: 'snapshotStatus',
+			getter: 'admin_snapshotStatus'
+		}),
 	]
 });
 `
@@ -569,6 +598,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCall',
+			call: 'debug_traceCall',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -691,6 +726,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'klay_getRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'gasPriceAt',
 			call: 'klay_gasPriceAt',
@@ -745,6 +786,17 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getLogsPage',
+			call: 'klay_getLogsPage',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'klay_callBundle',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'getAccountKey',
 			call: 'klay_getAccountKey',
@@ -926,7 +978,28 @@ web3._extend({
 const TxPool_JS = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'setDenyList',
+			call: 'txpool_setDenyList',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setAllowList',
+			call: 'txpool_setAllowList',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'exportPending',
+			call: 'txpool_exportPending',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importPending',
+			call: 'txpool_importPending',
+			params: 1
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -937,6 +1010,18 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Property({
+			name: 'addressLists',
+			getter: 'txpool_addressLists'
+		}),
+		new web3._extend.Property({
+			name: 'journal',
+			getter: 'txpool_inspectJournal'
+		}),
+		new web3._extend.Property({
+			name: 'admissionPolicies',
+			getter: 'txpool_admissionPolicies'
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
@@ -981,6 +1066,12 @@ web3._extend({
 			name: 'discard',
 			call: 'istanbul_discard',
 			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorStats',
+			call: 'istanbul_getValidatorStats',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
 		})
 	],
 	properties:
@@ -57,7 +57,7 @@ var (
 // BlockchainAPI interface is for testing purpose.
 type BlockchainAPI interface {
 	GetCode(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error)
-	Call(ctx context.Context, args api.CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error)
+	Call(ctx context.Context, args api.CallArgs, blockNr rpc.BlockNumber, overrides *api.StateOverride) (hexutil.Bytes, error)
 }
 
 // contractCaller performs kip13 method `supportsInterface` to detect the deployed contracts are KIP7 or KIP17.
@@ -88,7 +88,7 @@ func (f *contractCaller) CallContract(ctx context.Context, call klaytn.CallMsg,
 		To:   call.To,
 		Data: hexutil.Bytes(call.Data),
 	}
-	return f.blockchainAPI.Call(ctx, callArgs, num)
+	return f.blockchainAPI.Call(ctx, callArgs, num, nil)
 }
 
 func getCallOpts(blockNumber *big.Int, timeout time.Duration) (*bind.CallOpts, context.CancelFunc) {
@@ -48,6 +48,9 @@ var (
 	MaxReceiptFetch = 256 // Amount of transaction receipts to allow fetching per request
 	MaxStateFetch   = 384 // Amount of node state values to allow fetching per request
 
+	MinSnapFetchBytes = 64 * 1024  // Minimum amount of state range bytes to request per retrieval request
+	MaxSnapFetchBytes = 512 * 1024 // Maximum amount of state range bytes to request per retrieval request
+
 	MaxForkAncestry  = 3 * params.EpochDuration // Maximum chain reorganisation
 	rttMinEstimate   = 2 * time.Second          // Minimum round-trip time to target for download requests
 	rttMaxEstimate   = 20 * time.Second         // Maximum round-trip time to target for download requests
@@ -135,6 +138,10 @@ type Downloader struct {
 	stateSyncStart chan *stateSync
 	trackStateReq  chan *stateReq
 	stateCh        chan dataPack // [klay/63] Channel receiving inbound node state data
+	trackSnapReq   chan *snapReq
+	snapCh         chan dataPack // [klay/65] Channel receiving inbound state ranges
+
+	snapSyncer *snapSyncer // Range based state downloader keeping its progress across the pivot movements
 
 	// Cancellation and termination
 	cancelPeer string         // Identifier of the peer currently being used as the master (cancel on drop)
@@ -230,7 +237,10 @@ func New(mode SyncMode, stateDB database.DBManager, stateBloom *statedb.SyncBloo
 			processed: stateDB.ReadFastTrieProgress(),
 		},
 		trackStateReq: make(chan *stateReq),
+		trackSnapReq:  make(chan *snapReq),
+		snapCh:        make(chan dataPack),
 	}
+	dl.snapSyncer = newSnapSyncer(dl)
 	go dl.qosTuner()
 	go dl.stateFetcher()
 	return dl
@@ -253,7 +263,7 @@ func (d *Downloader) Progress() klaytn.SyncProgress {
 	switch mode {
 	case FullSync:
 		current = d.blockchain.CurrentBlock().NumberU64()
-	case FastSync:
+	case FastSync, SnapSync:
 		current = d.blockchain.CurrentFastBlock().NumberU64()
 	case LightSync:
 		current = d.lightchain.CurrentHeader().Number.Uint64()
@@ -459,7 +469,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 
 	// Ensure our origin point is below any fast sync pivot point
 	pivot := uint64(0)
-	if mode == FastSync {
+	if mode == FastSync || mode == SnapSync {
 		if height <= uint64(fsMinFullBlocks) {
 			origin = 0
 		} else {
@@ -470,7 +480,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		}
 	}
 	d.committed = 1
-	if mode == FastSync && pivot != 0 {
+	if (mode == FastSync || mode == SnapSync) && pivot != 0 {
 		d.committed = 0
 	}
 	// Initiate the sync using a concurrent header and content retrieval algorithm
@@ -485,7 +495,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		func() error { return d.fetchReceipts(origin + 1) },        // Receipts are retrieved during fast sync
 		func() error { return d.processHeaders(origin+1, pivot, td) },
 	}
-	if mode == FastSync {
+	if mode == FastSync || mode == SnapSync {
 		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest) })
 	} else if mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
@@ -622,7 +632,7 @@ func (d *Downloader) findAncestor(p *peerConnection, height uint64) (uint64, err
 	mode := d.getMode()
 	if mode == FullSync {
 		ceil = d.blockchain.CurrentBlock().NumberU64()
-	} else if mode == FastSync {
+	} else if mode == FastSync || mode == SnapSync {
 		ceil = d.blockchain.CurrentFastBlock().NumberU64()
 	}
 	if ceil >= MaxForkAncestry {
@@ -1284,7 +1294,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 				// This check cannot be executed "as is" for full imports, since blocks may still be
 				// queued for processing when the header download completes. However, as long as the
 				// peer gave us something useful, we're already happy/progressed (above check).
-				if mode == FastSync || mode == LightSync {
+				if mode == FastSync || mode == SnapSync || mode == LightSync {
 					head := d.lightchain.CurrentHeader()
 					if td.Cmp(d.lightchain.GetTd(head.Hash(), head.Number.Uint64())) > 0 {
 						return errStallingPeer
@@ -1312,7 +1322,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 				chunk := headers[:limit]
 
 				// In case of header only syncing, validate the chunk immediately
-				if mode == FastSync || mode == LightSync {
+				if mode == FastSync || mode == SnapSync || mode == LightSync {
 					// Collect the yet unknown headers to mark them as uncertain
 					unknown := make([]*types.Header, 0, len(headers))
 					for _, header := range chunk {
@@ -1341,7 +1351,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 					}
 				}
 				// Unless we're doing light chains, schedule the headers for associated content retrieval
-				if mode == FullSync || mode == FastSync {
+				if mode == FullSync || mode == FastSync || mode == SnapSync {
 					// If we've reached the allowed number of pending headers, stall a bit
 					for d.queue.PendingBlocks() >= maxQueuedHeaders || d.queue.PendingReceipts() >= maxQueuedHeaders {
 						select {
@@ -1618,6 +1628,21 @@ func (d *Downloader) DeliverNodeData(id string, data [][]byte) (err error) {
 	return d.deliver(id, d.stateCh, &statePack{id, data}, stateInMeter, stateDropMeter)
 }
 
+// DeliverAccountRange injects a new range of accounts received from a remote node.
+func (d *Downloader) DeliverAccountRange(id string, hashes []common.Hash, accounts [][]byte, proof [][]byte) (err error) {
+	return d.deliver(id, d.snapCh, &accountPack{id, hashes, accounts, proof}, accountRangeInMeter, accountRangeDropMeter)
+}
+
+// DeliverStorageRanges injects new ranges of storage slots received from a remote node.
+func (d *Downloader) DeliverStorageRanges(id string, hashes [][]common.Hash, slots [][][]byte, proof [][]byte) (err error) {
+	return d.deliver(id, d.snapCh, &storagePack{id, hashes, slots, proof}, storageRangeInMeter, storageRangeDropMeter)
+}
+
+// DeliverByteCodes injects a new batch of contract codes received from a remote node.
+func (d *Downloader) DeliverByteCodes(id string, codes [][]byte) (err error) {
+	return d.deliver(id, d.snapCh, &codePack{id, codes}, byteCodeInMeter, byteCodeDropMeter)
+}
+
 // deliver injects a new batch of data received from a remote node.
 func (d *Downloader) deliver(id string, destCh chan dataPack, packet dataPack, inMeter, dropMeter metrics.Meter) (err error) {
 	// Update the delivery metrics for both good and failed deliveries
@@ -597,6 +597,51 @@ func (dlp *downloadTesterPeer) RequestNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+// RequestAccountRange constructs a getAccountRange method associated with a
+// particular peer in the download tester. The returned function can be used to
+// retrieve a range of accounts from the particularly requested peer.
+func (dlp *downloadTesterPeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	dlp.waitDelay()
+
+	dlp.dl.lock.RLock()
+	defer dlp.dl.lock.RUnlock()
+
+	hashes, accounts, proof := serveAccountRange(dlp.dl.peerDb, root, origin, limit, bytes)
+	go dlp.dl.downloader.DeliverAccountRange(dlp.id, hashes, accounts, proof)
+
+	return nil
+}
+
+// RequestStorageRanges constructs a getStorageRanges method associated with a
+// particular peer in the download tester. The returned function can be used to
+// retrieve ranges of storage slots from the particularly requested peer.
+func (dlp *downloadTesterPeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	dlp.waitDelay()
+
+	dlp.dl.lock.RLock()
+	defer dlp.dl.lock.RUnlock()
+
+	hashes, slots, proof := serveStorageRanges(dlp.dl.peerDb, root, accounts, origin, limit, bytes)
+	go dlp.dl.downloader.DeliverStorageRanges(dlp.id, hashes, slots, proof)
+
+	return nil
+}
+
+// RequestByteCodes constructs a getByteCodes method associated with a particular
+// peer in the download tester. The returned function can be used to retrieve
+// batches of contract codes from the particularly requested peer.
+func (dlp *downloadTesterPeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	dlp.waitDelay()
+
+	dlp.dl.lock.RLock()
+	defer dlp.dl.lock.RUnlock()
+
+	codes := serveByteCodes(dlp.dl.peerDb, hashes, bytes)
+	go dlp.dl.downloader.DeliverByteCodes(dlp.id, codes)
+
+	return nil
+}
+
 // assertOwnChain checks if the local chain contains the correct number of items
 // of the various chain components.
 func assertOwnChain(t *testing.T, tester *downloadTester, length int) {
@@ -643,7 +688,7 @@ func assertOwnForkedChain(t *testing.T, tester *downloadTester, common int, leng
 			index = len(tester.ownHashes) - lengths[len(lengths)-1] + int(tester.downloader.queue.fastSyncPivot)
 		}
 		if index > 0 {
-			if statedb, err := state.New(tester.ownHeaders[tester.ownHashes[index]].Root, state.NewDatabase(trie.NewDatabase(tester.stateDb))); statedb == nil || err != nil {
+			if statedb, err := state.New(tester.ownHeaders[tester.ownHashes[index]].Root, state.NewDatabase(trie.NewDatabase(tester.stateDb)), nil); statedb == nil || err != nil {
 				t.Fatalf("state reconstruction failed: %v", err)
 			}
 		}
@@ -661,6 +706,7 @@ func TestCanonicalSynchronisation64Fast(t *testing.T) { testCanonicalSynchronisa
 func TestCanonicalSynchronisation64Light(t *testing.T) {
 	testCanonicalSynchronisation(t, 64, LightSync)
 }
+func TestCanonicalSynchronisation65Snap(t *testing.T) { testCanonicalSynchronisation(t, 65, SnapSync) }
 
 func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -770,6 +816,7 @@ func TestForkedSync63Fast(t *testing.T)  { testForkedSync(t, 63, FastSync) }
 func TestForkedSync64Full(t *testing.T)  { testForkedSync(t, 64, FullSync) }
 func TestForkedSync64Fast(t *testing.T)  { testForkedSync(t, 64, FastSync) }
 func TestForkedSync64Light(t *testing.T) { testForkedSync(t, 64, LightSync) }
+func TestForkedSync65Snap(t *testing.T)  { testForkedSync(t, 65, SnapSync) }
 
 func testForkedSync(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -1687,6 +1734,15 @@ func (ftp *floodingTestPeer) RequestReceipts(hashes []common.Hash) error {
 func (ftp *floodingTestPeer) RequestNodeData(hashes []common.Hash) error {
 	return ftp.peer.RequestNodeData(hashes)
 }
+func (ftp *floodingTestPeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	return ftp.peer.RequestAccountRange(root, origin, limit, bytes)
+}
+func (ftp *floodingTestPeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	return ftp.peer.RequestStorageRanges(root, accounts, origin, limit, bytes)
+}
+func (ftp *floodingTestPeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	return ftp.peer.RequestByteCodes(hashes, bytes)
+}
 
 func (ftp *floodingTestPeer) RequestHeadersByNumber(from uint64, count, skip int, reverse bool) error {
 	deliveriesDone := make(chan struct{}, 500)
@@ -41,5 +41,12 @@ var (
 	stateInMeter   = metrics.NewRegisteredMeter("klay/downloader/states/in", nil)
 	stateDropMeter = metrics.NewRegisteredMeter("klay/downloader/states/drop", nil)
 
+	accountRangeInMeter   = metrics.NewRegisteredMeter("klay/downloader/snap/accounts/in", nil)
+	accountRangeDropMeter = metrics.NewRegisteredMeter("klay/downloader/snap/accounts/drop", nil)
+	storageRangeInMeter   = metrics.NewRegisteredMeter("klay/downloader/snap/storages/in", nil)
+	storageRangeDropMeter = metrics.NewRegisteredMeter("klay/downloader/snap/storages/drop", nil)
+	byteCodeInMeter       = metrics.NewRegisteredMeter("klay/downloader/snap/codes/in", nil)
+	byteCodeDropMeter     = metrics.NewRegisteredMeter("klay/downloader/snap/codes/drop", nil)
+
 	throttleCounter = metrics.NewRegisteredCounter("klay/downloader/throttle", nil)
 )
@@ -54,11 +54,13 @@ type peerConnection struct {
 	blockIdle   int32 // Current block activity state of the peer (idle = 0, active = 1)
 	receiptIdle int32 // Current receipt activity state of the peer (idle = 0, active = 1)
 	stateIdle   int32 // Current node data activity state of the peer (idle = 0, active = 1)
+	snapIdle    int32 // Current state range activity state of the peer (idle = 0, active = 1)
 
 	headerThroughput  float64 // Number of headers measured to be retrievable per second
 	blockThroughput   float64 // Number of blocks (bodies) measured to be retrievable per second
 	receiptThroughput float64 // Number of receipts measured to be retrievable per second
 	stateThroughput   float64 // Number of node data pieces measured to be retrievable per second
+	snapThroughput    float64 // Number of state range bytes measured to be retrievable per second
 
 	rtt time.Duration // Request round trip time to track responsiveness (QoS)
 
@@ -66,6 +68,7 @@ type peerConnection struct {
 	blockStarted   time.Time // Time instance when the last block (body) fetch was started
 	receiptStarted time.Time // Time instance when the last receipt fetch was started
 	stateStarted   time.Time // Time instance when the last node data fetch was started
+	snapStarted    time.Time // Time instance when the last state range fetch was started
 
 	lacking map[common.Hash]struct{} // Set of hashes not to request (didn't have previously)
 
@@ -89,6 +92,9 @@ type Peer interface {
 	RequestBodies([]common.Hash) error
 	RequestReceipts([]common.Hash) error
 	RequestNodeData([]common.Hash) error
+	RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error
+	RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error
+	RequestByteCodes(hashes []common.Hash, bytes uint64) error
 }
 
 // lightPeerWrapper wraps a LightPeer struct, stubbing out the Peer-only methods.
@@ -112,6 +118,15 @@ func (w *lightPeerWrapper) RequestReceipts([]common.Hash) error {
 func (w *lightPeerWrapper) RequestNodeData([]common.Hash) error {
 	panic("RequestNodeData not supported in light client mode sync")
 }
+func (w *lightPeerWrapper) RequestAccountRange(common.Hash, common.Hash, common.Hash, uint64) error {
+	panic("RequestAccountRange not supported in light client mode sync")
+}
+func (w *lightPeerWrapper) RequestStorageRanges(common.Hash, []common.Hash, []byte, []byte, uint64) error {
+	panic("RequestStorageRanges not supported in light client mode sync")
+}
+func (w *lightPeerWrapper) RequestByteCodes([]common.Hash, uint64) error {
+	panic("RequestByteCodes not supported in light client mode sync")
+}
 
 // newPeerConnection creates a new downloader peer.
 func newPeerConnection(id string, version int, peer Peer, logger log.Logger) *peerConnection {
@@ -135,11 +150,13 @@ func (p *peerConnection) Reset() {
 	atomic.StoreInt32(&p.blockIdle, 0)
 	atomic.StoreInt32(&p.receiptIdle, 0)
 	atomic.StoreInt32(&p.stateIdle, 0)
+	atomic.StoreInt32(&p.snapIdle, 0)
 
 	p.headerThroughput = 0
 	p.blockThroughput = 0
 	p.receiptThroughput = 0
 	p.stateThroughput = 0
+	p.snapThroughput = 0
 
 	p.lacking = make(map[common.Hash]struct{})
 }
@@ -223,6 +240,57 @@ func (p *peerConnection) FetchNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+// FetchAccountRange sends an account range retrieval request to the remote peer.
+func (p *peerConnection) FetchAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	// Sanity check the protocol version
+	if p.version < 65 {
+		panic(fmt.Sprintf("account range fetch [klay/65+] requested on klay/%d", p.version))
+	}
+	// Short circuit if the peer is already fetching
+	if !atomic.CompareAndSwapInt32(&p.snapIdle, 0, 1) {
+		return errAlreadyFetching
+	}
+	p.snapStarted = time.Now()
+
+	go p.peer.RequestAccountRange(root, origin, limit, bytes)
+
+	return nil
+}
+
+// FetchStorageRanges sends a storage ranges retrieval request to the remote peer.
+func (p *peerConnection) FetchStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	// Sanity check the protocol version
+	if p.version < 65 {
+		panic(fmt.Sprintf("storage ranges fetch [klay/65+] requested on klay/%d", p.version))
+	}
+	// Short circuit if the peer is already fetching
+	if !atomic.CompareAndSwapInt32(&p.snapIdle, 0, 1) {
+		return errAlreadyFetching
+	}
+	p.snapStarted = time.Now()
+
+	go p.peer.RequestStorageRanges(root, accounts, origin, limit, bytes)
+
+	return nil
+}
+
+// FetchByteCodes sends a contract code retrieval request to the remote peer.
+func (p *peerConnection) FetchByteCodes(hashes []common.Hash, bytes uint64) error {
+	// Sanity check the protocol version
+	if p.version < 65 {
+		panic(fmt.Sprintf("byte codes fetch [klay/65+] requested on klay/%d", p.version))
+	}
+	// Short circuit if the peer is already fetching
+	if !atomic.CompareAndSwapInt32(&p.snapIdle, 0, 1) {
+		return errAlreadyFetching
+	}
+	p.snapStarted = time.Now()
+
+	go p.peer.RequestByteCodes(hashes, bytes)
+
+	return nil
+}
+
 // SetHeadersIdle sets the peer to idle, allowing it to execute new header retrieval
 // requests. Its estimated header retrieval throughput is updated with that measured
 // just now.
@@ -258,6 +326,13 @@ func (p *peerConnection) SetNodeDataIdle(delivered int, deliveryTime time.Time)
 	p.setIdle(deliveryTime.Sub(p.stateStarted), delivered, &p.stateThroughput, &p.stateIdle)
 }
 
+// SetSnapIdle sets the peer to idle, allowing it to execute new state range
+// retrieval requests. Its estimated state range retrieval throughput is updated
+// with the number of bytes delivered just now.
+func (p *peerConnection) SetSnapIdle(delivered int, deliveryTime time.Time) {
+	p.setIdle(deliveryTime.Sub(p.snapStarted), delivered, &p.snapThroughput, &p.snapIdle)
+}
+
 // setIdle sets the peer to idle, allowing it to execute new retrieval requests.
 // Its estimated retrieval throughput is updated with that measured just now.
 func (p *peerConnection) setIdle(elapsed time.Duration, delivered int, throughput *float64, idle *int32) {
@@ -283,7 +358,7 @@ func (p *peerConnection) setIdle(elapsed time.Duration, delivered int, throughpu
 
 	p.logger.Trace("Peer throughput measurements updated",
 		"hps", p.headerThroughput, "bps", p.blockThroughput,
-		"rps", p.receiptThroughput, "sps", p.stateThroughput,
+		"rps", p.receiptThroughput, "sps", p.stateThroughput, "snps", p.snapThroughput,
 		"miss", len(p.lacking), "rtt", p.rtt)
 }
 
@@ -323,6 +398,15 @@ func (p *peerConnection) NodeDataCapacity(targetRTT time.Duration) int {
 	return int(math.Min(1+math.Max(1, p.stateThroughput*float64(targetRTT)/float64(time.Second)), float64(MaxStateFetch)))
 }
 
+// SnapCapacity retrieves the peers state range download allowance in bytes based
+// on its previously discovered throughput.
+func (p *peerConnection) SnapCapacity(targetRTT time.Duration) uint64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return uint64(math.Min(math.Max(float64(MinSnapFetchBytes), p.snapThroughput*float64(targetRTT)/float64(time.Second)), float64(MaxSnapFetchBytes)))
+}
+
 // MarkLacking appends a new entity to the set of items (blocks, receipts, states)
 // that a peer is known not to have (i.e. have been requested before). If the
 // set reaches its maximum allowed capacity, items are randomly dropped off.
@@ -403,7 +487,7 @@ func (ps *peerSet) Register(p *peerConnection) error {
 		return errAlreadyRegistered
 	}
 	if len(ps.peers) > 0 {
-		p.headerThroughput, p.blockThroughput, p.receiptThroughput, p.stateThroughput = 0, 0, 0, 0
+		p.headerThroughput, p.blockThroughput, p.receiptThroughput, p.stateThroughput, p.snapThroughput = 0, 0, 0, 0, 0
 
 		for _, peer := range ps.peers {
 			peer.lock.RLock()
@@ -411,12 +495,14 @@ func (ps *peerSet) Register(p *peerConnection) error {
 			p.blockThroughput += peer.blockThroughput
 			p.receiptThroughput += peer.receiptThroughput
 			p.stateThroughput += peer.stateThroughput
+			p.snapThroughput += peer.snapThroughput
 			peer.lock.RUnlock()
 		}
 		p.headerThroughput /= float64(len(ps.peers))
 		p.blockThroughput /= float64(len(ps.peers))
 		p.receiptThroughput /= float64(len(ps.peers))
 		p.stateThroughput /= float64(len(ps.peers))
+		p.snapThroughput /= float64(len(ps.peers))
 	}
 	ps.peers[p.id] = p
 	ps.lock.Unlock()
@@ -480,7 +566,7 @@ func (ps *peerSet) HeaderIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.headerThroughput
 	}
-	return ps.idlePeers(62, 64, idleCheck, throughput)
+	return ps.idlePeers(62, 65, idleCheck, throughput)
 }
 
 // BodyIdlePeers retrieves a flat list of all the currently body-idle peers within
@@ -494,7 +580,7 @@ func (ps *peerSet) BodyIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.blockThroughput
 	}
-	return ps.idlePeers(62, 64, idleCheck, throughput)
+	return ps.idlePeers(62, 65, idleCheck, throughput)
 }
 
 // ReceiptIdlePeers retrieves a flat list of all the currently receipt-idle peers
@@ -508,7 +594,7 @@ func (ps *peerSet) ReceiptIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.receiptThroughput
 	}
-	return ps.idlePeers(63, 64, idleCheck, throughput)
+	return ps.idlePeers(63, 65, idleCheck, throughput)
 }
 
 // NodeDataIdlePeers retrieves a flat list of all the currently node-data-idle
@@ -522,7 +608,21 @@ func (ps *peerSet) NodeDataIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.stateThroughput
 	}
-	return ps.idlePeers(63, 64, idleCheck, throughput)
+	return ps.idlePeers(63, 65, idleCheck, throughput)
+}
+
+// SnapIdlePeers retrieves a flat list of all the currently state-range-idle
+// peers within the active peer set, ordered by their reputation.
+func (ps *peerSet) SnapIdlePeers() ([]*peerConnection, int) {
+	idleCheck := func(p *peerConnection) bool {
+		return atomic.LoadInt32(&p.snapIdle) == 0
+	}
+	throughput := func(p *peerConnection) float64 {
+		p.lock.RLock()
+		defer p.lock.RUnlock()
+		return p.snapThroughput
+	}
+	return ps.idlePeers(65, 65, idleCheck, throughput)
 }
 
 // idlePeers retrieves a flat list of all currently idle peers satisfying the
@@ -0,0 +1,704 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+)
+
+const (
+	accountConcurrency     = 16  // Number of chunks to split the account hash space into
+	maxStorageRequestCount = 128 // Maximum number of accounts to request the storage slots of at once
+	maxCodeRequestCount    = 384 // Maximum number of contract codes to request at once
+
+	snapLogInterval = 8 * time.Second // Minimum interval between the progress logs of the range retrieval
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// emptyCode is the known hash of the empty EVM bytecode.
+	emptyCode = crypto.Keccak256Hash(nil)
+)
+
+// snapReq represents a state range retrieval request sent to a single peer. A
+// request retrieves either an account range, storage ranges or contract codes.
+type snapReq struct {
+	root   common.Hash // State root the ranges are requested against
+	origin common.Hash // First hash of the requested range
+	bytes  uint64      // Soft limit of the response size
+
+	account  *accountTask   // Account range task of the request
+	storages []*storageTask // Storage tasks of the request
+	codes    []common.Hash  // Hashes of the requested contract codes
+
+	timeout  time.Duration   // Maximum round trip time for this to complete
+	timer    *time.Timer     // Timer to fire when the RTT timeout expires
+	peer     *peerConnection // Peer that we're requesting from
+	response dataPack        // Response data of the peer (nil for timeouts)
+	dropped  bool            // Flag whether the peer dropped off early
+}
+
+// timedOut returns if this request timed out.
+func (req *snapReq) timedOut() bool {
+	return req.response == nil
+}
+
+// accountTask is a chunk of the account hash space retrieved range by range.
+type accountTask struct {
+	next    common.Hash     // Next account hash to request
+	last    common.Hash     // Last account hash of the chunk
+	pending []*accountBatch // Retrieved ranges waiting for their storages and codes
+	busy    bool            // Flag whether a range of the chunk is being requested
+	done    bool            // Flag whether all the ranges of the chunk are retrieved
+}
+
+// accountBatch is a range of accounts proven by the Merkle proofs. The trie nodes
+// of the range are written only after the storages and codes of the accounts are
+// written, so that an existing trie node always has all its descendants.
+type accountBatch struct {
+	task    *accountTask
+	origin  common.Hash      // First hash of the requested range
+	keys    [][]byte         // Hashes of the accounts
+	values  [][]byte         // Serialized accounts
+	omitted map[int]struct{} // Accounts whose storages are retrieved by chunks and healed later
+	pending int              // Number of storages and codes still to be retrieved
+}
+
+// storageTask is the storage of an account to be retrieved.
+type storageTask struct {
+	batch   *accountBatch
+	index   int         // Index of the account in the batch
+	account common.Hash // Hash of the account
+	root    common.Hash // Storage root of the account
+	next    common.Hash // Next slot hash to request if the storage is retrieved by chunks
+	busy    bool        // Flag whether the storage is being requested
+}
+
+// codeTask is a contract code to be retrieved.
+type codeTask struct {
+	batches []*accountBatch // Batches waiting for the code
+	busy    bool            // Flag whether the code is being requested
+}
+
+// snapSyncer retrieves the state by account and storage ranges. Its progress is
+// kept across the pivot movements, and the trie nodes left behind by the ranges
+// are healed by the node data retrieval afterwards.
+type snapSyncer struct {
+	d    *Downloader // Downloader instance to access and manage current peerset
+	root common.Hash // State root the retrieved ranges belong to
+
+	accountTasks []*accountTask
+	storageTasks []*storageTask
+	codeTasks    map[common.Hash]*codeTask
+	stateless    map[string]struct{} // Peers not serving the state of the root
+
+	accounts uint64 // Number of accounts retrieved
+	slots    uint64 // Number of storage slots retrieved
+	codes    uint64 // Number of contract codes retrieved
+	nodes    uint64 // Number of trie nodes written
+	logged   time.Time
+}
+
+// newSnapSyncer creates a range based state downloader splitting the account
+// hash space into chunks retrieved concurrently.
+func newSnapSyncer(d *Downloader) *snapSyncer {
+	s := &snapSyncer{
+		d:         d,
+		codeTasks: make(map[common.Hash]*codeTask),
+		stateless: make(map[string]struct{}),
+	}
+	step := new(big.Int).Div(new(big.Int).Lsh(common.Big1, 256), big.NewInt(accountConcurrency))
+	next := new(big.Int)
+	for i := 0; i < accountConcurrency; i++ {
+		last := new(big.Int).Sub(new(big.Int).Add(next, step), common.Big1)
+		s.accountTasks = append(s.accountTasks, &accountTask{
+			next: common.BigToHash(next),
+			last: common.BigToHash(last),
+		})
+		next = new(big.Int).Add(last, common.Big1)
+	}
+	return s
+}
+
+// sync retrieves the state ranges of the root of the given state sync until all
+// the ranges are retrieved or no peer serves the state anymore.
+func (s *snapSyncer) sync(ss *stateSync) error {
+	s.setRoot(ss.root)
+
+	// Listen for new peer events to assign tasks to them
+	newPeer := make(chan *peerConnection, 1024)
+	peerSub := s.d.peers.SubscribeNewPeers(newPeer)
+	defer peerSub.Unsubscribe()
+
+	for !s.finished() {
+		if !s.assignTasks(ss) {
+			logger.Info("No peer serves the state ranges, healing the state", "root", s.root)
+			return nil
+		}
+		select {
+		case <-newPeer:
+			// New peer arrived, try to assign it download tasks
+
+		case <-ss.cancel:
+			return errCancelStateFetch
+
+		case <-s.d.cancelCh:
+			return errCanceled
+
+		case req := <-ss.snapDeliver:
+			if err := s.process(req); err != nil {
+				logger.Error("State range write error", "err", err)
+				return err
+			}
+		}
+	}
+	logger.Info("Retrieved all the state ranges, healing the state", "root", s.root, "accounts", s.accounts, "slots", s.slots, "codes", s.codes, "nodes", s.nodes)
+	return nil
+}
+
+// setRoot prepares the tasks to retrieve the ranges of the given root. If the
+// root has moved, the ranges not written yet are dropped since their storages
+// and codes may not be available against the new root anymore. The written ones
+// are kept, and their differences from the new state are healed later.
+func (s *snapSyncer) setRoot(root common.Hash) {
+	// No request of the previous sync is alive anymore
+	for _, task := range s.accountTasks {
+		task.busy = false
+	}
+	for _, task := range s.storageTasks {
+		task.busy = false
+	}
+	for _, task := range s.codeTasks {
+		task.busy = false
+	}
+	if root == s.root {
+		return
+	}
+	for _, task := range s.accountTasks {
+		if len(task.pending) > 0 {
+			task.next, task.done = task.pending[0].origin, false
+			task.pending = nil
+		}
+	}
+	s.storageTasks = nil
+	s.codeTasks = make(map[common.Hash]*codeTask)
+	s.stateless = make(map[string]struct{})
+	s.root = root
+}
+
+// finished returns whether all the ranges are retrieved and written.
+func (s *snapSyncer) finished() bool {
+	for _, task := range s.accountTasks {
+		if !task.done || len(task.pending) > 0 {
+			return false
+		}
+	}
+	return len(s.storageTasks) == 0 && len(s.codeTasks) == 0
+}
+
+// assignTasks attempts to assign new tasks to all idle peers, returning false
+// if no peer serves the state of the root.
+func (s *snapSyncer) assignTasks(ss *stateSync) bool {
+	capable := 0
+	for _, p := range s.d.peers.AllPeers() {
+		if _, ok := s.stateless[p.id]; !ok && p.version >= 65 {
+			capable++
+		}
+	}
+	if capable == 0 {
+		return false
+	}
+	peers, _ := s.d.peers.SnapIdlePeers()
+	for _, p := range peers {
+		if _, ok := s.stateless[p.id]; ok {
+			continue
+		}
+		req := &snapReq{
+			root:    s.root,
+			bytes:   p.SnapCapacity(s.d.requestRTT()),
+			peer:    p,
+			timeout: s.d.requestTTL(),
+		}
+		if !s.fillTasks(req) {
+			break
+		}
+		req.peer.logger.Trace("Requesting new batch of data", "type", "snap", "accounts", req.account != nil, "storages", len(req.storages), "codes", len(req.codes))
+		select {
+		case s.d.trackSnapReq <- req:
+			s.fetch(req)
+		case <-ss.cancel:
+		case <-s.d.cancelCh:
+		}
+	}
+	return true
+}
+
+// fillTasks fills the given request with the tasks not being requested, returning
+// false if there is none. The codes and storages are requested first to release
+// the account ranges waiting for them as soon as possible.
+func (s *snapSyncer) fillTasks(req *snapReq) bool {
+	for hash, task := range s.codeTasks {
+		if task.busy {
+			continue
+		}
+		task.busy = true
+		req.codes = append(req.codes, hash)
+		if len(req.codes) >= maxCodeRequestCount {
+			break
+		}
+	}
+	if len(req.codes) > 0 {
+		return true
+	}
+	for _, task := range s.storageTasks {
+		if task.busy {
+			continue
+		}
+		// A storage retrieved by chunks is requested alone from the next slot
+		if task.next != (common.Hash{}) {
+			if len(req.storages) > 0 {
+				continue
+			}
+			task.busy = true
+			req.storages, req.origin = []*storageTask{task}, task.next
+			return true
+		}
+		task.busy = true
+		req.storages = append(req.storages, task)
+		if len(req.storages) >= maxStorageRequestCount {
+			break
+		}
+	}
+	if len(req.storages) > 0 {
+		return true
+	}
+	for _, task := range s.accountTasks {
+		if task.busy || task.done {
+			continue
+		}
+		task.busy = true
+		req.account, req.origin = task, task.next
+		return true
+	}
+	return false
+}
+
+// fetch sends the given request to its peer.
+func (s *snapSyncer) fetch(req *snapReq) {
+	switch {
+	case len(req.codes) > 0:
+		req.peer.FetchByteCodes(req.codes, req.bytes)
+
+	case len(req.storages) > 0:
+		accounts := make([]common.Hash, len(req.storages))
+		for i, task := range req.storages {
+			accounts[i] = task.account
+		}
+		var origin []byte
+		if req.origin != (common.Hash{}) {
+			origin = req.origin[:]
+		}
+		req.peer.FetchStorageRanges(req.root, accounts, origin, nil, req.bytes)
+
+	default:
+		req.peer.FetchAccountRange(req.root, req.origin, req.account.last, req.bytes)
+	}
+}
+
+// process verifies and writes the response of the given request, rescheduling
+// the tasks not delivered.
+func (s *snapSyncer) process(req *snapReq) error {
+	deliveryTime := time.Now()
+	logger.Trace("Received state range response", "peer", req.peer.id, "dropped", req.dropped, "timeout", !req.dropped && req.timedOut())
+
+	var (
+		size int
+		err  error
+	)
+	switch {
+	case len(req.codes) > 0:
+		size, err = s.processCodes(req)
+	case len(req.storages) > 0:
+		size, err = s.processStorages(req)
+	default:
+		size, err = s.processAccounts(req)
+	}
+	req.peer.SetSnapIdle(size, deliveryTime)
+
+	if time.Since(s.logged) > snapLogInterval {
+		s.logged = time.Now()
+		logger.Info("Imported new state ranges", "accounts", s.accounts, "slots", s.slots, "codes", s.codes, "nodes", s.nodes, "pendingStorages", len(s.storageTasks), "pendingCodes", len(s.codeTasks))
+	}
+	return err
+}
+
+// processAccounts verifies the delivered account range, and schedules the
+// retrieval of the storages and codes of the accounts.
+func (s *snapSyncer) processAccounts(req *snapReq) (int, error) {
+	task := req.account
+	task.busy = false
+
+	pack, ok := req.response.(*accountPack)
+	if !ok {
+		return 0, nil
+	}
+	if len(pack.hashes) == 0 && len(pack.proof) == 0 {
+		s.markStateless(req.peer)
+		return 0, nil
+	}
+	keys := make([][]byte, len(pack.hashes))
+	for i := range pack.hashes {
+		keys[i] = pack.hashes[i][:]
+	}
+	values := pack.accounts
+
+	last := req.origin[:]
+	if len(keys) > 0 {
+		last = keys[len(keys)-1]
+	}
+	cont, err := statedb.VerifyRangeProof(req.root, req.origin[:], last, keys, values, newProofDB(pack.proof))
+	if err != nil {
+		logger.Debug("Invalid account range", "peer", req.peer.id, "origin", req.origin, "err", err)
+		s.markStateless(req.peer)
+		return 0, nil
+	}
+	size := responseSize(keys, values, pack.proof)
+
+	// The accounts beyond the chunk belong to the next chunk
+	for len(keys) > 0 && bytes.Compare(keys[len(keys)-1], task.last[:]) > 0 {
+		keys, values, cont = keys[:len(keys)-1], values[:len(values)-1], false
+	}
+	if cont && len(keys) > 0 && !bytes.Equal(keys[len(keys)-1], task.last[:]) {
+		task.next = incHash(common.BytesToHash(keys[len(keys)-1]))
+	} else {
+		task.done = true
+	}
+	batch := &accountBatch{
+		task:    task,
+		origin:  req.origin,
+		keys:    keys,
+		values:  values,
+		omitted: make(map[int]struct{}),
+	}
+	for i, value := range values {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(value, serializer); err != nil {
+			return size, fmt.Errorf("invalid account %x: %v", keys[i], err)
+		}
+		pa := account.GetProgramAccount(serializer.GetAccount())
+		if pa == nil {
+			continue
+		}
+		if root := pa.GetStorageRoot(); root != emptyRoot && !s.hasNode(root) {
+			s.storageTasks = append(s.storageTasks, &storageTask{
+				batch:   batch,
+				index:   i,
+				account: common.BytesToHash(keys[i]),
+				root:    root,
+			})
+			batch.pending++
+		}
+		if hash := common.BytesToHash(pa.GetCodeHash()); hash != emptyCode && !s.hasNode(hash) {
+			if s.codeTasks[hash] == nil {
+				s.codeTasks[hash] = &codeTask{}
+			}
+			s.codeTasks[hash].batches = append(s.codeTasks[hash].batches, batch)
+			batch.pending++
+		}
+	}
+	s.accounts += uint64(len(keys))
+
+	task.pending = append(task.pending, batch)
+	if batch.pending == 0 {
+		return size, s.commitBatch(batch)
+	}
+	return size, nil
+}
+
+// processStorages verifies and writes the delivered storage ranges. The storages
+// not delivered are rescheduled, and a storage delivered partially continues to
+// be retrieved by chunks.
+func (s *snapSyncer) processStorages(req *snapReq) (int, error) {
+	for _, task := range req.storages {
+		task.busy = false
+	}
+	pack, ok := req.response.(*storagePack)
+	if !ok {
+		return 0, nil
+	}
+	if len(pack.hashes) == 0 && len(pack.proof) == 0 {
+		s.markStateless(req.peer)
+		return 0, nil
+	}
+	if len(pack.hashes) > len(req.storages) || len(pack.hashes) != len(pack.slots) {
+		logger.Debug("Invalid storage ranges", "peer", req.peer.id, "requested", len(req.storages), "hashes", len(pack.hashes), "slots", len(pack.slots))
+		s.markStateless(req.peer)
+		return 0, nil
+	}
+	// Verify all the ranges before writing any of them
+	var (
+		keys  = make([][][]byte, len(pack.hashes))
+		conts = make([]bool, len(pack.hashes))
+		size  int
+	)
+	for i, hashes := range pack.hashes {
+		keys[i] = make([][]byte, len(hashes))
+		for j := range hashes {
+			keys[i][j] = hashes[j][:]
+		}
+		// Only the last range can be delivered partially along with the proofs
+		var (
+			proofDB     database.DBManager
+			first, last []byte
+		)
+		if i == len(pack.hashes)-1 && len(pack.proof) > 0 {
+			proofDB = newProofDB(pack.proof)
+			first, last = req.origin[:], req.origin[:]
+			if len(keys[i]) > 0 {
+				last = keys[i][len(keys[i])-1]
+			}
+		}
+		cont, err := statedb.VerifyRangeProof(req.storages[i].root, first, last, keys[i], pack.slots[i], proofDB)
+		if err != nil {
+			logger.Debug("Invalid storage range", "peer", req.peer.id, "account", req.storages[i].account, "err", err)
+			s.markStateless(req.peer)
+			return 0, nil
+		}
+		conts[i] = cont
+		size += responseSize(keys[i], pack.slots[i], nil)
+	}
+	size += responseSize(nil, nil, pack.proof)
+
+	completed := make(map[*storageTask]struct{})
+	for i := range pack.hashes {
+		task := req.storages[i]
+		if err := s.writeTrie(keys[i], pack.slots[i]); err != nil {
+			return size, err
+		}
+		s.slots += uint64(len(keys[i]))
+
+		if conts[i] {
+			// The account of a storage retrieved by chunks is left out of the
+			// account trie nodes, so that the storage trie is healed later.
+			task.batch.omitted[task.index] = struct{}{}
+			task.next = incHash(common.BytesToHash(keys[i][len(keys[i])-1]))
+			continue
+		}
+		completed[task] = struct{}{}
+
+		task.batch.pending--
+		if task.batch.pending == 0 {
+			if err := s.commitBatch(task.batch); err != nil {
+				return size, err
+			}
+		}
+	}
+	remaining := s.storageTasks[:0]
+	for _, task := range s.storageTasks {
+		if _, ok := completed[task]; !ok {
+			remaining = append(remaining, task)
+		}
+	}
+	s.storageTasks = remaining
+	return size, nil
+}
+
+// processCodes writes the delivered contract codes. The codes not delivered are
+// rescheduled.
+func (s *snapSyncer) processCodes(req *snapReq) (int, error) {
+	for _, hash := range req.codes {
+		if task := s.codeTasks[hash]; task != nil {
+			task.busy = false
+		}
+	}
+	pack, ok := req.response.(*codePack)
+	if !ok {
+		return 0, nil
+	}
+	if len(pack.codes) == 0 {
+		s.markStateless(req.peer)
+		return 0, nil
+	}
+	var (
+		batch = s.d.stateDB.NewBatch(database.StateTrieDB)
+		done  []*codeTask
+		size  int
+	)
+	for _, code := range pack.codes {
+		hash := crypto.Keccak256Hash(code)
+		task := s.codeTasks[hash]
+		if task == nil {
+			continue
+		}
+		if err := batch.Put(hash[:], code); err != nil {
+			return size, err
+		}
+		if s.d.stateBloom != nil {
+			s.d.stateBloom.Add(hash[:])
+		}
+		delete(s.codeTasks, hash)
+		done = append(done, task)
+		size += len(code)
+	}
+	if err := batch.Write(); err != nil {
+		return size, fmt.Errorf("DB write error: %v", err)
+	}
+	s.codes += uint64(len(done))
+
+	for _, task := range done {
+		for _, b := range task.batches {
+			b.pending--
+			if b.pending == 0 {
+				if err := s.commitBatch(b); err != nil {
+					return size, err
+				}
+			}
+		}
+	}
+	return size, nil
+}
+
+// commitBatch writes the trie nodes of the given account range whose storages
+// and codes are all written.
+func (s *snapSyncer) commitBatch(batch *accountBatch) error {
+	var keys, values [][]byte
+	for i := range batch.keys {
+		if _, ok := batch.omitted[i]; ok {
+			continue
+		}
+		keys, values = append(keys, batch.keys[i]), append(values, batch.values[i])
+	}
+	if err := s.writeTrie(keys, values); err != nil {
+		return err
+	}
+	task := batch.task
+	for i, b := range task.pending {
+		if b == batch {
+			task.pending = append(task.pending[:i], task.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// writeTrie writes the trie nodes built from the given consecutive leaves into
+// the state trie database. The nodes of the subtries covered completely by the
+// leaves are the same as the original ones. The other nodes on the edges of the
+// range may differ from the original ones, which are retrieved by healing.
+func (s *snapSyncer) writeTrie(keys, values [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	triedb := statedb.NewDatabase(database.NewMemoryDBManager())
+	tr, err := statedb.NewTrie(common.Hash{}, triedb)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := tr.TryUpdate(key, values[i]); err != nil {
+			return err
+		}
+	}
+	if _, err := tr.Commit(nil); err != nil {
+		return err
+	}
+	var (
+		batch = s.d.stateDB.NewBatch(database.StateTrieDB)
+		nodes = triedb.Nodes()
+	)
+	for _, hash := range nodes {
+		blob, err := triedb.Node(hash)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(hash[:], blob); err != nil {
+			return err
+		}
+		if s.d.stateBloom != nil {
+			s.d.stateBloom.Add(hash[:])
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("DB write error: %v", err)
+	}
+	s.nodes += uint64(len(nodes))
+
+	s.d.syncStatsLock.Lock()
+	s.d.syncStatsState.processed += uint64(len(nodes))
+	s.d.syncStatsLock.Unlock()
+	return nil
+}
+
+// hasNode returns whether the trie node or the contract code of the given hash
+// exists in the state trie database.
+func (s *snapSyncer) hasNode(hash common.Hash) bool {
+	if s.d.stateBloom != nil && !s.d.stateBloom.Contains(hash[:]) {
+		return false
+	}
+	ok, _ := s.d.stateDB.HasStateTrieNode(hash[:])
+	return ok
+}
+
+// markStateless excludes the given peer from the range retrieval of the root.
+func (s *snapSyncer) markStateless(p *peerConnection) {
+	p.logger.Debug("Peer does not serve the state ranges", "root", s.root)
+	s.stateless[p.id] = struct{}{}
+}
+
+// newProofDB returns a database containing the given Merkle proof nodes.
+func newProofDB(proof [][]byte) database.DBManager {
+	proofDB := database.NewMemoryDBManager()
+	for _, node := range proof {
+		proofDB.WriteMerkleProof(crypto.Keccak256(node), node)
+	}
+	return proofDB
+}
+
+// responseSize returns the number of bytes of the given response.
+func responseSize(keys, values, proof [][]byte) int {
+	size := 0
+	for i := range keys {
+		size += len(keys[i]) + len(values[i])
+	}
+	for _, node := range proof {
+		size += len(node)
+	}
+	return size
+}
+
+// incHash returns the next hash of the given hash.
+func incHash(h common.Hash) common.Hash {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			break
+		}
+	}
+	return h
+}
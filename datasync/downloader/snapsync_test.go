@@ -0,0 +1,294 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMaxHash = common.HexToHash("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+// proveKeys returns the Merkle proof nodes of the given keys of the trie.
+func proveKeys(tr *statedb.Trie, keys ...common.Hash) [][]byte {
+	proofDB := database.NewMemoryDBManager()
+	for _, key := range keys {
+		if err := tr.Prove(key[:], 0, proofDB); err != nil {
+			return nil
+		}
+	}
+	memDB := proofDB.GetMemDB()
+
+	var proof [][]byte
+	for _, key := range memDB.Keys() {
+		node, _ := memDB.Get(key)
+		proof = append(proof, node)
+	}
+	return proof
+}
+
+// serveAccountRange answers an account range request from the state in the
+// given database, as the protocol handler does without the snapshot.
+func serveAccountRange(db database.DBManager, root, origin, limit common.Hash, maxBytes uint64) ([]common.Hash, [][]byte, [][]byte) {
+	tr, err := statedb.NewTrie(root, statedb.NewDatabase(db))
+	if err != nil {
+		return nil, nil, nil
+	}
+	var (
+		hashes   []common.Hash
+		accounts [][]byte
+		size     uint64
+	)
+	it := statedb.NewIterator(tr.NodeIterator(origin[:]))
+	for it.Next() {
+		hash := common.BytesToHash(it.Key)
+		hashes = append(hashes, hash)
+		accounts = append(accounts, common.CopyBytes(it.Value))
+		size += uint64(common.HashLength + len(it.Value))
+
+		if bytes.Compare(hash[:], limit[:]) >= 0 || size > maxBytes {
+			break
+		}
+	}
+	keys := []common.Hash{origin}
+	if len(hashes) > 0 {
+		keys = append(keys, hashes[len(hashes)-1])
+	}
+	return hashes, accounts, proveKeys(tr, keys...)
+}
+
+// serveStorageRanges answers a storage ranges request from the state in the
+// given database, as the protocol handler does without the snapshot.
+func serveStorageRanges(db database.DBManager, root common.Hash, accounts []common.Hash, origin, limit []byte, maxBytes uint64) ([][]common.Hash, [][][]byte, [][]byte) {
+	triedb := statedb.NewDatabase(db)
+	accTrie, err := statedb.NewTrie(root, triedb)
+	if err != nil {
+		return nil, nil, nil
+	}
+	var (
+		hashes [][]common.Hash
+		slots  [][][]byte
+		proof  [][]byte
+		size   uint64
+	)
+	for i, accountHash := range accounts {
+		if size >= maxBytes {
+			break
+		}
+		start, end := common.Hash{}, testMaxHash
+		if i == 0 && len(origin) > 0 {
+			start = common.BytesToHash(origin)
+		}
+		if i == len(accounts)-1 && len(limit) > 0 {
+			end = common.BytesToHash(limit)
+		}
+		blob, err := accTrie.TryGet(accountHash[:])
+		if err != nil || blob == nil {
+			return nil, nil, nil
+		}
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(blob, serializer); err != nil {
+			return nil, nil, nil
+		}
+		pa := account.GetProgramAccount(serializer.GetAccount())
+		if pa == nil {
+			return nil, nil, nil
+		}
+		stTrie, err := statedb.NewTrie(pa.GetStorageRoot(), triedb)
+		if err != nil {
+			return nil, nil, nil
+		}
+		var (
+			keys  []common.Hash
+			vals  [][]byte
+			abort bool
+		)
+		it := statedb.NewIterator(stTrie.NodeIterator(start[:]))
+		for it.Next() {
+			if size >= maxBytes {
+				abort = true
+				break
+			}
+			hash := common.BytesToHash(it.Key)
+			keys = append(keys, hash)
+			vals = append(vals, common.CopyBytes(it.Value))
+			size += uint64(common.HashLength + len(it.Value))
+
+			if bytes.Compare(hash[:], end[:]) >= 0 {
+				break
+			}
+		}
+		hashes, slots = append(hashes, keys), append(slots, vals)
+
+		if start != (common.Hash{}) || abort {
+			edges := []common.Hash{start}
+			if len(keys) > 0 {
+				edges = append(edges, keys[len(keys)-1])
+			}
+			proof = proveKeys(stTrie, edges...)
+			break
+		}
+	}
+	return hashes, slots, proof
+}
+
+// serveByteCodes answers a byte codes request from the given database.
+func serveByteCodes(db database.DBManager, hashes []common.Hash, maxBytes uint64) [][]byte {
+	var (
+		triedb = statedb.NewDatabase(db)
+		codes  [][]byte
+		size   uint64
+	)
+	for _, hash := range hashes {
+		if code, err := triedb.Node(hash); err == nil && code != nil {
+			codes = append(codes, code)
+			size += uint64(len(code))
+		}
+		if size > maxBytes {
+			break
+		}
+	}
+	return codes
+}
+
+// makeSnapTestState writes a state having externally owned accounts and smart
+// contract accounts into the given database. One of the contracts has a large
+// storage which can't be retrieved by a single response.
+func makeSnapTestState(t *testing.T, db database.DBManager, root common.Hash, seed byte) common.Hash {
+	sdb := state.NewDatabase(db)
+	st, err := state.New(root, sdb, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 500; i++ {
+		st.AddBalance(common.BytesToAddress([]byte{seed, byte(i >> 8), byte(i)}), big.NewInt(int64(i+1)))
+	}
+	for i := 0; i < 10; i++ {
+		addr := common.BytesToAddress([]byte{0xca, byte(i)})
+		if !st.Exist(addr) {
+			st.CreateSmartContractAccount(addr, params.CodeFormatEVM)
+			st.SetCode(addr, []byte{0x60, byte(i), 0x60, byte(i)})
+		}
+		slots := 10 * i
+		if i == 0 {
+			slots = 4000
+		}
+		for j := 0; j < slots; j++ {
+			st.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.BytesToHash([]byte{seed, byte(j >> 8), byte(j)}))
+		}
+	}
+	root, err = st.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.TrieDB().Commit(root, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// syncSnapState runs the state sync of the given root in snap sync mode.
+func syncSnapState(t *testing.T, tester *downloadTester, root common.Hash) {
+	d := tester.downloader
+	atomic.StoreUint32(&d.mode, uint32(SnapSync))
+
+	d.cancelLock.Lock()
+	d.cancelCh = make(chan struct{})
+	d.cancelLock.Unlock()
+	defer d.cancel()
+
+	if err := d.syncState(root).Wait(); err != nil {
+		t.Fatalf("failed to sync state %x: %v", root, err)
+	}
+}
+
+// assertSnapState checks that the state of the given root is completely
+// available in the database of the tester.
+func assertSnapState(t *testing.T, tester *downloadTester, root common.Hash) {
+	st, err := state.New(root, state.NewDatabase(tester.stateDb), nil)
+	if err != nil {
+		t.Fatalf("state %x is not available: %v", root, err)
+	}
+	it := state.NewNodeIterator(st)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("state %x is incomplete: %v", root, it.Error)
+	}
+}
+
+// Tests that the state is retrieved by ranges, and the trie nodes left behind
+// are healed.
+func TestSnapSync(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	root := makeSnapTestState(t, tester.peerDb, common.Hash{}, 0x01)
+	assert.NoError(t, tester.downloader.RegisterPeer("peer", 65, &downloadTesterPeer{dl: tester, id: "peer"}))
+
+	syncSnapState(t, tester, root)
+	assertSnapState(t, tester, root)
+
+	syncer := tester.downloader.snapSyncer
+	assert.Equal(t, uint64(510), syncer.accounts)
+	assert.True(t, syncer.slots >= 4000+10*45)
+	assert.Equal(t, uint64(10), syncer.codes)
+	assert.True(t, syncer.finished())
+}
+
+// Tests that the differences of the state of a moved pivot are healed on top of
+// the ranges retrieved for the previous pivot.
+func TestSnapSyncPivotMove(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	oldRoot := makeSnapTestState(t, tester.peerDb, common.Hash{}, 0x01)
+	newRoot := makeSnapTestState(t, tester.peerDb, oldRoot, 0x02)
+	assert.NoError(t, tester.downloader.RegisterPeer("peer", 65, &downloadTesterPeer{dl: tester, id: "peer"}))
+
+	syncSnapState(t, tester, oldRoot)
+	assertSnapState(t, tester, oldRoot)
+
+	syncSnapState(t, tester, newRoot)
+	assertSnapState(t, tester, newRoot)
+	assert.Equal(t, newRoot, tester.downloader.snapSyncer.root)
+}
+
+// Tests that the state is retrieved by the trie nodes if no peer serves the
+// state ranges.
+func TestSnapSyncWithoutSnapPeers(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	root := makeSnapTestState(t, tester.peerDb, common.Hash{}, 0x01)
+	assert.NoError(t, tester.downloader.RegisterPeer("peer", 64, &downloadTesterPeer{dl: tester, id: "peer"}))
+
+	syncSnapState(t, tester, root)
+	assertSnapState(t, tester, root)
+	assert.Equal(t, uint64(0), tester.downloader.snapSyncer.accounts)
+}
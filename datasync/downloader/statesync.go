@@ -82,6 +82,8 @@ func (d *Downloader) stateFetcher() {
 			}
 		case <-d.stateCh:
 			// Ignore state responses while no sync is running.
+		case <-d.snapCh:
+			// Ignore state range responses while no sync is running.
 		case <-d.quitCh:
 			return
 		}
@@ -95,6 +97,10 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 		active   = make(map[string]*stateReq) // Currently in-flight requests
 		finished []*stateReq                  // Completed or failed requests
 		timeout  = make(chan *stateReq)       // Timed out active requests
+
+		snapActive   = make(map[string]*snapReq) // Currently in-flight state range requests
+		snapFinished []*snapReq                  // Completed or failed state range requests
+		snapTimeout  = make(chan *snapReq)       // Timed out active state range requests
 	)
 	defer func() {
 		// Cancel active request timers on exit. Also set peers to idle so they're
@@ -103,6 +109,13 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			req.timer.Stop()
 			req.peer.SetNodeDataIdle(len(req.items), time.Now())
 		}
+		for _, req := range snapActive {
+			req.timer.Stop()
+			req.peer.SetSnapIdle(int(req.bytes), time.Now())
+		}
+		for _, req := range snapFinished {
+			req.peer.SetSnapIdle(int(req.bytes), time.Now())
+		}
 	}()
 	// Run the state sync.
 	go s.run()
@@ -123,6 +136,14 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			deliverReq = finished[0]
 			deliverReqCh = s.deliver
 		}
+		var (
+			deliverSnapReq   *snapReq
+			deliverSnapReqCh chan *snapReq
+		)
+		if len(snapFinished) > 0 {
+			deliverSnapReq = snapFinished[0]
+			deliverSnapReqCh = s.snapDeliver
+		}
 
 		select {
 		// The stateSync lifecycle:
@@ -139,6 +160,12 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			finished[len(finished)-1] = nil
 			finished = finished[:len(finished)-1]
 
+			// Send the next finished state range request to the current sync:
+		case deliverSnapReqCh <- deliverSnapReq:
+			copy(snapFinished, snapFinished[1:])
+			snapFinished[len(snapFinished)-1] = nil
+			snapFinished = snapFinished[:len(snapFinished)-1]
+
 			// Handle incoming state packs:
 		case pack := <-d.stateCh:
 			// Discard any data not requested (or previously timed out)
@@ -154,8 +181,31 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			finished = append(finished, req)
 			delete(active, pack.PeerId())
 
+			// Handle incoming state range packs:
+		case pack := <-d.snapCh:
+			// Discard any data not requested (or previously timed out)
+			req := snapActive[pack.PeerId()]
+			if req == nil {
+				logger.Debug("Unrequested state range", "peer", pack.PeerId(), "len", pack.Items())
+				continue
+			}
+			// Finalize the request and queue up for processing
+			req.timer.Stop()
+			req.response = pack
+
+			snapFinished = append(snapFinished, req)
+			delete(snapActive, pack.PeerId())
+
 			// Handle dropped peer connections:
 		case p := <-peerDrop:
+			// Finalize the pending requests of the peer and queue up for processing
+			if req := snapActive[p.id]; req != nil {
+				req.timer.Stop()
+				req.dropped = true
+
+				snapFinished = append(snapFinished, req)
+				delete(snapActive, p.id)
+			}
 			// Skip if no request is currently pending
 			req := active[p.id]
 			if req == nil {
@@ -180,6 +230,37 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			finished = append(finished, req)
 			delete(active, req.peer.id)
 
+			// Handle timed-out state range requests:
+		case req := <-snapTimeout:
+			// If the peer is already requesting something else, ignore the stale timeout.
+			if snapActive[req.peer.id] != req {
+				continue
+			}
+			// Move the timed out request back to the sync for rescheduling
+			snapFinished = append(snapFinished, req)
+			delete(snapActive, req.peer.id)
+
+			// Track outgoing state range requests:
+		case req := <-d.trackSnapReq:
+			// Same as the node data requests, a peer is never assigned two requests
+			// unless it has reconnected before the previous one times out.
+			if old := snapActive[req.peer.id]; old != nil {
+				logger.Warn("Busy peer assigned new state range fetch", "peer", old.peer.id)
+
+				old.timer.Stop()
+				old.dropped = true
+
+				snapFinished = append(snapFinished, old)
+			}
+			// Start a timer to notify the sync loop if the peer stalled.
+			req.timer = time.AfterFunc(req.timeout, func() {
+				select {
+				case snapTimeout <- req:
+				case <-s.done:
+				}
+			})
+			snapActive[req.peer.id] = req
+
 			// Track outgoing state requests:
 		case req := <-d.trackStateReq:
 			// If an active request already exists for this peer, we have a problem. In
@@ -214,7 +295,8 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 // stateSync schedules requests for downloading a particular state trie defined
 // by a given state root.
 type stateSync struct {
-	d *Downloader // Downloader instance to access and manage current peerset
+	d    *Downloader // Downloader instance to access and manage current peerset
+	root common.Hash // State root currently being synced
 
 	sched  *statedb.TrieSync          // State trie sync scheduler defining the tasks
 	keccak hash.Hash                  // Keccak256 hasher to verify deliveries with
@@ -223,11 +305,12 @@ type stateSync struct {
 	numUncommitted   int
 	bytesUncommitted int
 
-	deliver    chan *stateReq // Delivery channel multiplexing peer responses
-	cancel     chan struct{}  // Channel to signal a termination request
-	cancelOnce sync.Once      // Ensures cancel only ever gets called once
-	done       chan struct{}  // Channel to signal termination completion
-	err        error          // Any error hit during sync (set before completion)
+	deliver     chan *stateReq // Delivery channel multiplexing peer responses
+	snapDeliver chan *snapReq  // Delivery channel multiplexing peer state range responses
+	cancel      chan struct{}  // Channel to signal a termination request
+	cancelOnce  sync.Once      // Ensures cancel only ever gets called once
+	done        chan struct{}  // Channel to signal termination completion
+	err         error          // Any error hit during sync (set before completion)
 }
 
 // stateTask represents a single trie node download task, containing a set of
@@ -240,21 +323,29 @@ type stateTask struct {
 // yet start the sync. The user needs to call run to initiate.
 func newStateSync(d *Downloader, root common.Hash) *stateSync {
 	return &stateSync{
-		d:       d,
-		sched:   state.NewStateSync(root, d.stateDB, d.stateBloom, nil),
-		keccak:  sha3.NewKeccak256(),
-		tasks:   make(map[common.Hash]*stateTask),
-		deliver: make(chan *stateReq),
-		cancel:  make(chan struct{}),
-		done:    make(chan struct{}),
+		d:           d,
+		root:        root,
+		keccak:      sha3.NewKeccak256(),
+		tasks:       make(map[common.Hash]*stateTask),
+		deliver:     make(chan *stateReq),
+		snapDeliver: make(chan *snapReq),
+		cancel:      make(chan struct{}),
+		done:        make(chan struct{}),
 	}
 }
 
 // run starts the task assignment and response processing loop, blocking until
 // it finishes, and finally notifying any goroutines waiting for the loop to
-// finish.
+// finish. In snap sync, the state is downloaded by ranges first and the trie
+// nodes left behind are healed by the node data retrieval afterwards.
 func (s *stateSync) run() {
-	s.err = s.loop()
+	if s.d.getMode() == SnapSync {
+		s.err = s.d.snapSyncer.sync(s)
+	}
+	if s.err == nil {
+		s.sched = state.NewStateSync(s.root, s.d.stateDB, s.d.stateBloom, nil)
+		s.err = s.loop()
+	}
 	close(s.done)
 }
 
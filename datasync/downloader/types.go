@@ -23,6 +23,7 @@ package downloader
 import (
 	"fmt"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
 )
 
 // peerDropFn is a callback type for dropping a peer detected as malicious.
@@ -76,3 +77,37 @@ type statePack struct {
 func (p *statePack) PeerId() string { return p.peerId }
 func (p *statePack) Items() int     { return len(p.states) }
 func (p *statePack) Stats() string  { return fmt.Sprintf("%d", len(p.states)) }
+
+// accountPack is a batch of accounts of a range returned by a peer.
+type accountPack struct {
+	peerId   string
+	hashes   []common.Hash
+	accounts [][]byte
+	proof    [][]byte
+}
+
+func (p *accountPack) PeerId() string { return p.peerId }
+func (p *accountPack) Items() int     { return len(p.hashes) }
+func (p *accountPack) Stats() string  { return fmt.Sprintf("%d", len(p.hashes)) }
+
+// storagePack is a batch of storage slots of ranges returned by a peer.
+type storagePack struct {
+	peerId string
+	hashes [][]common.Hash
+	slots  [][][]byte
+	proof  [][]byte
+}
+
+func (p *storagePack) PeerId() string { return p.peerId }
+func (p *storagePack) Items() int     { return len(p.hashes) }
+func (p *storagePack) Stats() string  { return fmt.Sprintf("%d", len(p.hashes)) }
+
+// codePack is a batch of contract codes returned by a peer.
+type codePack struct {
+	peerId string
+	codes  [][]byte
+}
+
+func (p *codePack) PeerId() string { return p.peerId }
+func (p *codePack) Items() int     { return len(p.codes) }
+func (p *codePack) Stats() string  { return fmt.Sprintf("%d", len(p.codes)) }
@@ -40,4 +40,14 @@ var (
 	headerFilterOutMeter = metrics.NewRegisteredMeter("cn/fetcher/filter/headers/out", nil)
 	bodyFilterInMeter    = metrics.NewRegisteredMeter("cn/fetcher/filter/bodies/in", nil)
 	bodyFilterOutMeter   = metrics.NewRegisteredMeter("cn/fetcher/filter/bodies/out", nil)
+
+	txAnnounceInMeter     = metrics.NewRegisteredMeter("cn/fetcher/transaction/announces/in", nil)
+	txAnnounceKnownMeter  = metrics.NewRegisteredMeter("cn/fetcher/transaction/announces/known", nil)
+	txAnnounceDOSMeter    = metrics.NewRegisteredMeter("cn/fetcher/transaction/announces/dos", nil)
+	txBroadcastInMeter    = metrics.NewRegisteredMeter("cn/fetcher/transaction/broadcasts/in", nil)
+	txRequestOutMeter     = metrics.NewRegisteredMeter("cn/fetcher/transaction/request/out", nil)
+	txRequestFailMeter    = metrics.NewRegisteredMeter("cn/fetcher/transaction/request/fail", nil)
+	txRequestDoneMeter    = metrics.NewRegisteredMeter("cn/fetcher/transaction/request/done", nil)
+	txRequestTimeoutMeter = metrics.NewRegisteredMeter("cn/fetcher/transaction/request/timeout", nil)
+	txReplyInMeter        = metrics.NewRegisteredMeter("cn/fetcher/transaction/replies/in", nil)
 )
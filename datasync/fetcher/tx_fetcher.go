@@ -0,0 +1,516 @@
+// Modifications Copyright 2020 The klaytn Authors
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file is derived from eth/fetcher/tx_fetcher.go (2020/12/01).
+// Modified and improved for the klaytn development.
+
+package fetcher
+
+import (
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+const (
+	// maxTxAnnounces is the maximum number of unique transactions a peer can
+	// have announced and not yet delivered (prevent DOS).
+	maxTxAnnounces = 4096
+
+	// MaxTxRetrievals is the maximum number of transactions requested from a
+	// peer in a single request.
+	MaxTxRetrievals = 256
+
+	// txArriveTimeout is the time allowance before an announced transaction is
+	// explicitly requested. The transaction may be broadcast to us in the meantime.
+	txArriveTimeout = 500 * time.Millisecond
+
+	// txGatherSlack is the interval used to collate almost-expired announces
+	// with network fetches.
+	txGatherSlack = 100 * time.Millisecond
+
+	// txFetchTimeout is the maximum allotted time to return an explicitly
+	// requested transaction.
+	txFetchTimeout = 5 * time.Second
+
+	// txBroadcastQueue is the maximum number of the broadcast batches waiting
+	// to be untracked. The batches are not untracked if the queue is full.
+	txBroadcastQueue = 1024
+)
+
+// txHasFn is a callback type to check whether a transaction is already known.
+type txHasFn func(common.Hash) bool
+
+// txAddFn is a callback type to add a batch of transactions to the pool.
+type txAddFn func(types.Transactions)
+
+// TxRequesterFn is a callback type for sending a transaction retrieval request to a peer.
+type TxRequesterFn func(peer string, hashes []common.Hash) error
+
+// txAnnounce is the hash notification of the availability of new transactions.
+type txAnnounce struct {
+	origin string        // Identifier of the peer originating the notification
+	hashes []common.Hash // Batch of transaction hashes being announced
+}
+
+// txDelivery is the notification that a batch of transactions have been added
+// to the pool and should be untracked.
+type txDelivery struct {
+	origin string        // Identifier of the peer originating the transactions
+	hashes []common.Hash // Batch of transaction hashes having been delivered
+	direct bool          // Whether this is a direct reply or a broadcast
+}
+
+// txRequest represents an in-flight transaction retrieval request to a peer.
+type txRequest struct {
+	hashes []common.Hash            // Transactions having been requested
+	stolen map[common.Hash]struct{} // Deliveries by someone else (don't re-request)
+	time   time.Time                // Timestamp of the request
+}
+
+// TxFetcher is responsible for retrieving new transactions based on announcements.
+//
+// An announced transaction goes through three stages. It first waits for a
+// short time in the waitlist, since it may be broadcast to us directly. Then
+// it is queued to be retrieved from one of its announcers. Finally it is
+// requested from a single peer, and the other announcers are kept as alternates
+// in case the request times out or the peer disconnects.
+type TxFetcher struct {
+	notify     chan *txAnnounce
+	cleanup    chan *txDelivery
+	broadcasts chan *txDelivery
+	drop       chan string
+	quit       chan struct{}
+
+	// Stage 1: Waiting lists for newly announced transactions
+	waitlist  map[common.Hash]map[string]struct{} // Announcers of the waiting transactions
+	waittime  map[common.Hash]time.Time           // Times of the first announcements of the waiting transactions
+	waitslots map[string]map[common.Hash]struct{} // Waiting announcements grouped by peer (DOS protection)
+
+	// Stage 2: Transactions waiting to be allocated to some peer
+	announces map[string]map[common.Hash]struct{} // Announced transactions grouped by peer
+	announced map[common.Hash]map[string]struct{} // Announcers of the queued transactions
+
+	// Stage 3: Transactions currently being retrieved
+	fetching   map[common.Hash]string              // Peers the transactions are requested from
+	requests   map[string]*txRequest               // In-flight retrieval requests by peer
+	alternates map[common.Hash]map[string]struct{} // Other announcers of the transactions being retrieved
+
+	// Callbacks
+	hasTx    txHasFn       // Checks if a transaction is already in the pool
+	addTxs   txAddFn       // Inserts a batch of transactions into the pool
+	fetchTxs TxRequesterFn // Retrieves a set of transactions from a peer
+}
+
+// NewTxFetcher creates a transaction fetcher to retrieve transactions based on hash announcements.
+func NewTxFetcher(hasTx txHasFn, addTxs txAddFn, fetchTxs TxRequesterFn) *TxFetcher {
+	return &TxFetcher{
+		notify:     make(chan *txAnnounce),
+		cleanup:    make(chan *txDelivery),
+		broadcasts: make(chan *txDelivery, txBroadcastQueue),
+		drop:       make(chan string),
+		quit:       make(chan struct{}),
+		waitlist:   make(map[common.Hash]map[string]struct{}),
+		waittime:   make(map[common.Hash]time.Time),
+		waitslots:  make(map[string]map[common.Hash]struct{}),
+		announces:  make(map[string]map[common.Hash]struct{}),
+		announced:  make(map[common.Hash]map[string]struct{}),
+		fetching:   make(map[common.Hash]string),
+		requests:   make(map[string]*txRequest),
+		alternates: make(map[common.Hash]map[string]struct{}),
+		hasTx:      hasTx,
+		addTxs:     addTxs,
+		fetchTxs:   fetchTxs,
+	}
+}
+
+// Notify announces the fetcher of the potential availability of a new batch of
+// transactions in the network. The transactions already in the pool are ignored.
+func (f *TxFetcher) Notify(peer string, hashes []common.Hash) error {
+	txAnnounceInMeter.Mark(int64(len(hashes)))
+
+	unknowns := make([]common.Hash, 0, len(hashes))
+	for _, hash := range hashes {
+		if !f.hasTx(hash) {
+			unknowns = append(unknowns, hash)
+		}
+	}
+	txAnnounceKnownMeter.Mark(int64(len(hashes) - len(unknowns)))
+	if len(unknowns) == 0 {
+		return nil
+	}
+	select {
+	case f.notify <- &txAnnounce{origin: peer, hashes: unknowns}:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// Enqueue adds a batch of received transactions to the pool, and stops tracking
+// them in the fetcher. direct is true if the transactions are the reply of a
+// retrieval request, and false if they are broadcast by the peer.
+func (f *TxFetcher) Enqueue(peer string, txs types.Transactions, direct bool) error {
+	if direct {
+		txReplyInMeter.Mark(int64(len(txs)))
+	} else {
+		txBroadcastInMeter.Mark(int64(len(txs)))
+	}
+	if len(txs) > 0 {
+		f.addTxs(txs)
+	}
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	select {
+	case f.cleanup <- &txDelivery{origin: peer, hashes: hashes, direct: direct}:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// MarkBroadcast stops tracking the announcements of the transactions broadcast
+// by a peer, which are added to the pool by the caller. It never blocks the
+// caller, so the announcements may be retrieved as usual if the fetcher falls
+// behind, which the pool deduplicates.
+func (f *TxFetcher) MarkBroadcast(peer string, txs types.Transactions) {
+	txBroadcastInMeter.Mark(int64(len(txs)))
+
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	select {
+	case f.broadcasts <- &txDelivery{origin: peer, hashes: hashes}:
+	default:
+	}
+}
+
+// Drop should be called when a peer disconnects. It cleans up all the internal
+// data structures of the given peer and reschedules its requests to other peers.
+func (f *TxFetcher) Drop(peer string) error {
+	select {
+	case f.drop <- peer:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// Start boots up the announcement based synchroniser, accepting and processing
+// hash notifications and transaction retrievals until termination requested.
+func (f *TxFetcher) Start() {
+	go f.loop()
+}
+
+// Stop terminates the announcement based synchroniser, canceling all pending
+// operations.
+func (f *TxFetcher) Stop() {
+	close(f.quit)
+}
+
+func (f *TxFetcher) loop() {
+	ticker := time.NewTicker(txGatherSlack)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ann := <-f.notify:
+			f.announce(ann, time.Now())
+
+		case delivery := <-f.cleanup:
+			f.deliver(delivery)
+			f.request(f.schedule(time.Now()))
+
+		case delivery := <-f.broadcasts:
+			// The broadcasts only untrack the announcements, which are
+			// scheduled with the next tick
+			f.deliver(delivery)
+
+		case peer := <-f.drop:
+			f.dropPeer(peer)
+			f.request(f.schedule(time.Now()))
+
+		case <-ticker.C:
+			now := time.Now()
+			f.expire(now)
+			f.request(f.schedule(now))
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// request sends the scheduled retrieval requests to the peers. A peer failing
+// to send the request is dropped from the fetcher.
+func (f *TxFetcher) request(requests map[string][]common.Hash) {
+	for peer, hashes := range requests {
+		txRequestOutMeter.Mark(int64(len(hashes)))
+
+		go func(peer string, hashes []common.Hash) {
+			if err := f.fetchTxs(peer, hashes); err != nil {
+				logger.Debug("Failed to request transactions", "peer", peer, "count", len(hashes), "err", err)
+				txRequestFailMeter.Mark(int64(len(hashes)))
+				f.Drop(peer)
+			}
+		}(peer, hashes)
+	}
+}
+
+// announce puts the announced transactions into the waitlist, or adds the peer
+// to the announcers if the transactions are already tracked.
+func (f *TxFetcher) announce(ann *txAnnounce, now time.Time) {
+	used := len(f.waitslots[ann.origin]) + len(f.announces[ann.origin])
+	if used >= maxTxAnnounces {
+		txAnnounceDOSMeter.Mark(int64(len(ann.hashes)))
+		return
+	}
+	hashes := ann.hashes
+	if used+len(hashes) > maxTxAnnounces {
+		txAnnounceDOSMeter.Mark(int64(used + len(hashes) - maxTxAnnounces))
+		hashes = hashes[:maxTxAnnounces-used]
+	}
+	for _, hash := range hashes {
+		// The transaction is being retrieved, keep the peer as an alternate
+		if f.alternates[hash] != nil {
+			f.alternates[hash][ann.origin] = struct{}{}
+			addToSet(f.announces, ann.origin, hash)
+			continue
+		}
+		// The transaction is queued for retrieval, add the peer to the announcers
+		if f.announced[hash] != nil {
+			f.announced[hash][ann.origin] = struct{}{}
+			addToSet(f.announces, ann.origin, hash)
+			continue
+		}
+		// The transaction is waiting for the broadcast, add the peer to the announcers
+		if f.waitlist[hash] != nil {
+			f.waitlist[hash][ann.origin] = struct{}{}
+			addToSet(f.waitslots, ann.origin, hash)
+			continue
+		}
+		// The transaction is new, wait for a while before requesting it
+		f.waitlist[hash] = map[string]struct{}{ann.origin: {}}
+		f.waittime[hash] = now
+		addToSet(f.waitslots, ann.origin, hash)
+	}
+}
+
+// expire queues the waiting transactions past the arrival timeout for retrieval,
+// and reschedules the requests past the fetch timeout to the other announcers.
+func (f *TxFetcher) expire(now time.Time) {
+	for hash, instance := range f.waittime {
+		if now.Sub(instance)+txGatherSlack < txArriveTimeout {
+			continue
+		}
+		for peer := range f.waitlist[hash] {
+			addToSet(f.announces, peer, hash)
+			if f.announced[hash] == nil {
+				f.announced[hash] = make(map[string]struct{})
+			}
+			f.announced[hash][peer] = struct{}{}
+			removeFromSet(f.waitslots, peer, hash)
+		}
+		delete(f.waitlist, hash)
+		delete(f.waittime, hash)
+	}
+
+	for peer, req := range f.requests {
+		if req.hashes == nil || now.Sub(req.time)+txGatherSlack < txFetchTimeout {
+			continue
+		}
+		logger.Debug("Transaction request timed out", "peer", peer, "count", len(req.hashes))
+		txRequestTimeoutMeter.Mark(int64(len(req.hashes)))
+
+		// Reschedule the undelivered transactions to the other announcers
+		for _, hash := range req.hashes {
+			if _, ok := req.stolen[hash]; ok {
+				continue
+			}
+			if f.alternates[hash] != nil {
+				f.announced[hash] = f.alternates[hash]
+			}
+			delete(f.announced[hash], peer)
+			if len(f.announced[hash]) == 0 {
+				delete(f.announced, hash)
+			}
+			removeFromSet(f.announces, peer, hash)
+			delete(f.alternates, hash)
+			delete(f.fetching, hash)
+		}
+		// Keep the request as dangling, so that no more transactions are
+		// requested from the slow peer until it replies.
+		req.hashes = nil
+	}
+}
+
+// schedule allocates the queued transactions to the idle announcers, and
+// returns the transactions to request by peer.
+func (f *TxFetcher) schedule(now time.Time) map[string][]common.Hash {
+	requests := make(map[string][]common.Hash)
+	for peer, announces := range f.announces {
+		if f.requests[peer] != nil {
+			continue // The peer is busy
+		}
+		hashes := make([]common.Hash, 0, MaxTxRetrievals)
+		for hash := range announces {
+			if _, ok := f.fetching[hash]; ok {
+				continue
+			}
+			f.fetching[hash] = peer
+			f.alternates[hash] = f.announced[hash]
+			delete(f.announced, hash)
+
+			hashes = append(hashes, hash)
+			if len(hashes) >= MaxTxRetrievals {
+				break
+			}
+		}
+		if len(hashes) > 0 {
+			f.requests[peer] = &txRequest{hashes: hashes, time: now}
+			requests[peer] = hashes
+		}
+	}
+	return requests
+}
+
+// deliver stops tracking the delivered transactions. If the transactions are
+// the reply of a request, the undelivered ones are rescheduled.
+func (f *TxFetcher) deliver(delivery *txDelivery) {
+	for _, hash := range delivery.hashes {
+		if _, ok := f.waitlist[hash]; ok {
+			for peer := range f.waitlist[hash] {
+				removeFromSet(f.waitslots, peer, hash)
+			}
+			delete(f.waitlist, hash)
+			delete(f.waittime, hash)
+			continue
+		}
+		for peer := range f.announced[hash] {
+			removeFromSet(f.announces, peer, hash)
+		}
+		for peer := range f.alternates[hash] {
+			removeFromSet(f.announces, peer, hash)
+		}
+		delete(f.announced, hash)
+		delete(f.alternates, hash)
+
+		// The transaction being retrieved is delivered by someone else
+		if origin, ok := f.fetching[hash]; ok && (origin != delivery.origin || !delivery.direct) {
+			req := f.requests[origin]
+			if req.stolen == nil {
+				req.stolen = make(map[common.Hash]struct{})
+			}
+			req.stolen[hash] = struct{}{}
+		}
+		delete(f.fetching, hash)
+	}
+	if !delivery.direct {
+		return
+	}
+	req := f.requests[delivery.origin]
+	if req == nil {
+		logger.Debug("Unexpected transaction delivery", "peer", delivery.origin)
+		return
+	}
+	delete(f.requests, delivery.origin)
+	txRequestDoneMeter.Mark(int64(len(delivery.hashes)))
+
+	delivered := make(map[common.Hash]struct{}, len(delivery.hashes))
+	for _, hash := range delivery.hashes {
+		delivered[hash] = struct{}{}
+	}
+	// The transactions requested before the last delivered one are missing in
+	// the peer. The others are just cut off by the response size limit.
+	cutoff := len(req.hashes)
+	for i, hash := range req.hashes {
+		if _, ok := delivered[hash]; ok {
+			cutoff = i
+		}
+	}
+	for i, hash := range req.hashes {
+		if _, ok := req.stolen[hash]; ok {
+			continue
+		}
+		if _, ok := delivered[hash]; !ok {
+			if i < cutoff {
+				delete(f.alternates[hash], delivery.origin)
+				removeFromSet(f.announces, delivery.origin, hash)
+			}
+			if len(f.alternates[hash]) > 0 {
+				f.announced[hash] = f.alternates[hash]
+			}
+		}
+		delete(f.alternates, hash)
+		delete(f.fetching, hash)
+	}
+}
+
+// dropPeer cleans up the announcements and the request of a disconnected peer,
+// and reschedules the transactions being retrieved from the peer.
+func (f *TxFetcher) dropPeer(peer string) {
+	for hash := range f.waitslots[peer] {
+		delete(f.waitlist[hash], peer)
+		if len(f.waitlist[hash]) == 0 {
+			delete(f.waitlist, hash)
+			delete(f.waittime, hash)
+		}
+	}
+	delete(f.waitslots, peer)
+
+	if req := f.requests[peer]; req != nil {
+		for _, hash := range req.hashes {
+			if _, ok := req.stolen[hash]; ok {
+				continue
+			}
+			delete(f.alternates[hash], peer)
+			if len(f.alternates[hash]) > 0 {
+				f.announced[hash] = f.alternates[hash]
+			}
+			delete(f.alternates, hash)
+			delete(f.fetching, hash)
+		}
+		delete(f.requests, peer)
+	}
+
+	for hash := range f.announces[peer] {
+		delete(f.announced[hash], peer)
+		if len(f.announced[hash]) == 0 {
+			delete(f.announced, hash)
+		}
+		delete(f.alternates[hash], peer)
+	}
+	delete(f.announces, peer)
+}
+
+func addToSet(sets map[string]map[common.Hash]struct{}, peer string, hash common.Hash) {
+	if sets[peer] == nil {
+		sets[peer] = make(map[common.Hash]struct{})
+	}
+	sets[peer][hash] = struct{}{}
+}
+
+func removeFromSet(sets map[string]map[common.Hash]struct{}, peer string, hash common.Hash) {
+	delete(sets[peer], hash)
+	if len(sets[peer]) == 0 {
+		delete(sets, peer)
+	}
+}
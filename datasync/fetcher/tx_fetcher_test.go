@@ -0,0 +1,265 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTxs = func() types.Transactions {
+	txs := make(types.Transactions, 4)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	}
+	return txs
+}()
+
+func testTxHashes(txs types.Transactions) []common.Hash {
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+func sortHashes(hashes []common.Hash) []common.Hash {
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i].Big().Cmp(hashes[j].Big()) < 0 })
+	return hashes
+}
+
+func newTestTxFetcher() *TxFetcher {
+	return NewTxFetcher(
+		func(common.Hash) bool { return false },
+		func(types.Transactions) {},
+		func(string, []common.Hash) error { return nil },
+	)
+}
+
+// Tests that the announced transactions are requested after the arrival timeout
+// from a single announcer, and that the duplicated announcements are not requested again.
+func TestTxFetcherScheduling(t *testing.T) {
+	f := newTestTxFetcher()
+	now := time.Now()
+	hashes := testTxHashes(testTxs[:2])
+
+	f.announce(&txAnnounce{origin: "A", hashes: hashes}, now)
+	f.announce(&txAnnounce{origin: "B", hashes: hashes[:1]}, now)
+
+	// Nothing is requested before the arrival timeout
+	f.expire(now)
+	assert.Empty(t, f.schedule(now))
+
+	now = now.Add(txArriveTimeout)
+	f.expire(now)
+	var all []common.Hash
+	for _, hs := range f.schedule(now) {
+		all = append(all, hs...)
+	}
+	assert.Equal(t, sortHashes(append([]common.Hash{}, hashes...)), sortHashes(all))
+
+	// The transactions being retrieved are not requested again
+	f.announce(&txAnnounce{origin: "C", hashes: hashes}, now)
+	f.expire(now.Add(txArriveTimeout))
+	assert.Empty(t, f.schedule(now.Add(txArriveTimeout)))
+	assert.Empty(t, f.waitlist)
+}
+
+// Tests that the transactions broadcast in the waiting time are not requested.
+func TestTxFetcherBroadcastWhileWaiting(t *testing.T) {
+	f := newTestTxFetcher()
+	now := time.Now()
+
+	f.announce(&txAnnounce{origin: "A", hashes: testTxHashes(testTxs[:2])}, now)
+	f.deliver(&txDelivery{origin: "B", hashes: testTxHashes(testTxs[:1])})
+
+	now = now.Add(txArriveTimeout)
+	f.expire(now)
+	assert.Equal(t, map[string][]common.Hash{"A": {testTxs[1].Hash()}}, f.schedule(now))
+
+	// The reply cleans up all trackers
+	f.deliver(&txDelivery{origin: "A", hashes: testTxHashes(testTxs[1:2]), direct: true})
+	assert.Empty(t, f.announces)
+	assert.Empty(t, f.announced)
+	assert.Empty(t, f.fetching)
+	assert.Empty(t, f.requests)
+	assert.Empty(t, f.alternates)
+	assert.Empty(t, f.waitslots)
+}
+
+// Tests that a timed out request is rescheduled to another announcer, and
+// that the slow peer is not requested again until it replies.
+func TestTxFetcherTimeout(t *testing.T) {
+	f := newTestTxFetcher()
+	now := time.Now()
+	hash := testTxs[0].Hash()
+
+	f.announce(&txAnnounce{origin: "A", hashes: []common.Hash{hash}}, now)
+	now = now.Add(txArriveTimeout)
+	f.expire(now)
+	assert.Equal(t, map[string][]common.Hash{"A": {hash}}, f.schedule(now))
+	f.announce(&txAnnounce{origin: "B", hashes: []common.Hash{hash}}, now)
+	f.announce(&txAnnounce{origin: "A", hashes: testTxHashes(testTxs[1:2])}, now)
+
+	// Before the fetch timeout, nothing happens
+	f.expire(now.Add(txFetchTimeout / 2))
+	assert.Empty(t, f.schedule(now.Add(txFetchTimeout/2)))
+
+	now = now.Add(txFetchTimeout)
+	f.expire(now)
+	assert.Equal(t, map[string][]common.Hash{"B": {hash}}, f.schedule(now))
+
+	// The slow peer is not requested for its other announcements until it replies
+	assert.Empty(t, f.schedule(now.Add(txArriveTimeout)))
+	f.deliver(&txDelivery{origin: "A", direct: true})
+	assert.Equal(t, map[string][]common.Hash{"A": {testTxs[1].Hash()}}, f.schedule(now))
+}
+
+// Tests that the transactions being retrieved from a dropped peer are
+// rescheduled to the other announcers.
+func TestTxFetcherDrop(t *testing.T) {
+	f := newTestTxFetcher()
+	now := time.Now()
+	hash := testTxs[0].Hash()
+
+	f.announce(&txAnnounce{origin: "A", hashes: []common.Hash{hash}}, now)
+	now = now.Add(txArriveTimeout)
+	f.expire(now)
+	assert.Equal(t, map[string][]common.Hash{"A": {hash}}, f.schedule(now))
+	f.announce(&txAnnounce{origin: "B", hashes: []common.Hash{hash}}, now)
+
+	f.dropPeer("A")
+	assert.Equal(t, map[string][]common.Hash{"B": {hash}}, f.schedule(now))
+
+	f.dropPeer("B")
+	assert.Empty(t, f.announces)
+	assert.Empty(t, f.announced)
+	assert.Empty(t, f.fetching)
+	assert.Empty(t, f.requests)
+	assert.Empty(t, f.alternates)
+}
+
+// Tests that the transactions missing in the reply are requested from the other
+// announcers, not from the peer which replied.
+func TestTxFetcherPartialReply(t *testing.T) {
+	f := newTestTxFetcher()
+	now := time.Now()
+	var (
+		missing = testTxs[0].Hash()
+		replied = testTxs[1].Hash()
+	)
+	f.announce(&txAnnounce{origin: "A", hashes: []common.Hash{missing}}, now)
+	now = now.Add(txArriveTimeout)
+	f.expire(now)
+	assert.Equal(t, map[string][]common.Hash{"A": {missing}}, f.schedule(now))
+	f.announce(&txAnnounce{origin: "B", hashes: []common.Hash{missing}}, now)
+
+	// A replies only the last transaction of the request, so the first one is missing in A
+	f.requests["A"].hashes = append(f.requests["A"].hashes, replied)
+	f.fetching[replied] = "A"
+	f.deliver(&txDelivery{origin: "A", hashes: []common.Hash{replied}, direct: true})
+
+	assert.Equal(t, map[string][]common.Hash{"B": {missing}}, f.schedule(now))
+	assert.Empty(t, f.announces["A"])
+}
+
+// Tests that the number of announcements of a peer is limited.
+func TestTxFetcherAnnounceLimit(t *testing.T) {
+	f := newTestTxFetcher()
+	hashes := make([]common.Hash, maxTxAnnounces+10)
+	for i := range hashes {
+		hashes[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	f.announce(&txAnnounce{origin: "A", hashes: hashes}, time.Now())
+	assert.Equal(t, maxTxAnnounces, len(f.waitslots["A"]))
+
+	f.announce(&txAnnounce{origin: "A", hashes: []common.Hash{{0x1}}}, time.Now())
+	assert.Equal(t, maxTxAnnounces, len(f.waitslots["A"]))
+}
+
+// Tests that the running fetcher retrieves the announced transactions, and
+// ignores the transactions already in the pool.
+func TestTxFetcherRetrieval(t *testing.T) {
+	var (
+		lock      sync.Mutex
+		requested []common.Hash
+		added     = make(chan types.Transactions, 1)
+	)
+	f := NewTxFetcher(
+		func(hash common.Hash) bool { return hash == testTxs[0].Hash() },
+		func(txs types.Transactions) { added <- txs },
+		func(peer string, hashes []common.Hash) error {
+			lock.Lock()
+			defer lock.Unlock()
+			requested = append(requested, hashes...)
+			return nil
+		},
+	)
+	f.Start()
+	defer f.Stop()
+
+	assert.NoError(t, f.Notify("A", testTxHashes(testTxs[:2])))
+	time.Sleep(txArriveTimeout + 2*txGatherSlack)
+
+	lock.Lock()
+	assert.Equal(t, []common.Hash{testTxs[1].Hash()}, requested)
+	lock.Unlock()
+
+	assert.NoError(t, f.Enqueue("A", testTxs[1:2], true))
+	assert.Equal(t, testTxs[1:2], <-added)
+}
+
+// Tests that the broadcast transactions are untracked without blocking the
+// caller, even if the fetcher isn't running.
+func TestTxFetcherMarkBroadcast(t *testing.T) {
+	var (
+		lock      sync.Mutex
+		requested []common.Hash
+	)
+	f := NewTxFetcher(
+		func(common.Hash) bool { return false },
+		func(types.Transactions) { t.Error("the broadcast transactions are added by the caller") },
+		func(peer string, hashes []common.Hash) error {
+			lock.Lock()
+			defer lock.Unlock()
+			requested = append(requested, hashes...)
+			return nil
+		},
+	)
+	for i := 0; i <= txBroadcastQueue; i++ {
+		f.MarkBroadcast("B", testTxs[:1])
+	}
+	assert.Equal(t, txBroadcastQueue, len(f.broadcasts))
+
+	f.Start()
+	defer f.Stop()
+
+	assert.NoError(t, f.Notify("A", testTxHashes(testTxs[:2])))
+	f.MarkBroadcast("B", testTxs[:1])
+	time.Sleep(txArriveTimeout + 2*txGatherSlack)
+
+	lock.Lock()
+	assert.Equal(t, []common.Hash{testTxs[1].Hash()}, requested)
+	lock.Unlock()
+}
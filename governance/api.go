@@ -60,6 +60,7 @@ var (
 	errPermissionDenied       = errors.New("You don't have the right to vote")
 	errRemoveSelf             = errors.New("You can't vote on removing yourself")
 	errInvalidKeyValue        = errors.New("Your vote couldn't be placed. Please check your vote's key and value")
+	errInvalidBlockRange      = errors.New("Invalid block range")
 )
 
 // TODO-Klaytn-Governance: Refine this API and consider the gas price of txpool
@@ -164,6 +165,37 @@ func (api *PublicGovernanceAPI) ItemsAt(num *rpc.BlockNumber) (map[string]interf
 	}
 }
 
+// ItemHistory returns every change of the given governance item with its voters
+// in the block range. If `from` or `to` is not given, the range begins at the
+// genesis block or ends at the latest block respectively.
+func (api *PublicGovernanceAPI) ItemHistory(key string, from, to *rpc.BlockNumber) ([]*GovernanceItemChange, error) {
+	latest := api.governance.blockChain.CurrentHeader().Number.Uint64()
+
+	fromNum, toNum := uint64(0), latest
+	if from != nil {
+		if *from == rpc.PendingBlockNumber {
+			return nil, kerrors.ErrPendingBlockNotSupported
+		}
+		if *from == rpc.LatestBlockNumber {
+			fromNum = latest
+		} else {
+			fromNum = uint64(from.Int64())
+		}
+	}
+	if to != nil {
+		if *to == rpc.PendingBlockNumber {
+			return nil, kerrors.ErrPendingBlockNotSupported
+		}
+		if *to != rpc.LatestBlockNumber && uint64(to.Int64()) < latest {
+			toNum = uint64(to.Int64())
+		}
+	}
+	if fromNum > toNum {
+		return nil, errInvalidBlockRange
+	}
+	return api.governance.ItemHistory(key, fromNum, toNum)
+}
+
 func (api *PublicGovernanceAPI) PendingChanges() map[string]interface{} {
 	return api.governance.PendingChanges()
 }
@@ -172,6 +204,46 @@ func (api *PublicGovernanceAPI) Votes() []GovernanceVote {
 	return api.governance.Votes()
 }
 
+// Proposals returns all governance proposals with their current status.
+func (api *PublicGovernanceAPI) Proposals() []*GovernanceProposal {
+	return api.governance.Proposals()
+}
+
+// Proposal returns the governance proposal of the given id.
+func (api *PublicGovernanceAPI) Proposal(id uint64) (*GovernanceProposal, error) {
+	return api.governance.ReadProposal(id)
+}
+
+// VoteHistory returns the votes included in the blocks from `from` to `to`.
+// If `to` is not given, the votes up to the latest block are returned.
+func (api *PublicGovernanceAPI) VoteHistory(from rpc.BlockNumber, to *rpc.BlockNumber) ([]*GovernanceVoteRecord, error) {
+	latest := api.governance.blockChain.CurrentHeader().Number.Uint64()
+
+	resolve := func(num rpc.BlockNumber) (uint64, error) {
+		if num == rpc.PendingBlockNumber {
+			return 0, kerrors.ErrPendingBlockNotSupported
+		}
+		if num == rpc.LatestBlockNumber || uint64(num.Int64()) > latest {
+			return latest, nil
+		}
+		return uint64(num.Int64()), nil
+	}
+	fromNum, err := resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	toNum := latest
+	if to != nil {
+		if toNum, err = resolve(*to); err != nil {
+			return nil, err
+		}
+	}
+	if fromNum > toNum {
+		return nil, errInvalidBlockRange
+	}
+	return api.governance.VoteHistory(fromNum, toNum), nil
+}
+
 func (api *PublicGovernanceAPI) IdxCache() []uint64 {
 	return api.governance.IdxCache()
 }
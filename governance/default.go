@@ -28,6 +28,7 @@ import (
 
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul/validator"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
@@ -44,6 +45,8 @@ var (
 	ErrItemNotFound       = errors.New("Failed to find governance item")
 	ErrItemNil            = errors.New("Governance Item is nil")
 	ErrUnknownKey         = errors.New("Governnace value of the given key not found")
+	ErrProposalNotFound   = errors.New("Failed to find governance proposal")
+	ErrInvalidQuorum      = errors.New("Quorum of ballot should be in [50, 100)")
 )
 
 var (
@@ -94,17 +97,9 @@ var (
 		params.Timeout:                 "istanbul.timeout",
 	}
 
-	ProposerPolicyMap = map[string]int{
-		"roundrobin":     params.RoundRobin,
-		"sticky":         params.Sticky,
-		"weightedrandom": params.WeightedRandom,
-	}
-
-	ProposerPolicyMapReverse = map[int]string{
-		params.RoundRobin:     "roundrobin",
-		params.Sticky:         "sticky",
-		params.WeightedRandom: "weightedrandom",
-	}
+	// ProposerPolicyMap and ProposerPolicyMapReverse are derived from the
+	// proposer policy registry of the validator package.
+	ProposerPolicyMap, ProposerPolicyMapReverse = proposerPolicyMaps()
 
 	GovernanceModeMap = map[string]int{
 		"none":   params.GovernanceMode_None,
@@ -115,6 +110,18 @@ var (
 
 var logger = log.NewModuleLogger(log.Governance)
 
+// proposerPolicyMaps returns the maps between the names and the values of the
+// registered proposer policies.
+func proposerPolicyMaps() (map[string]int, map[int]string) {
+	names := validator.ProposerPolicyNames()
+	policies, reverse := make(map[string]int, len(names)), make(map[int]string, len(names))
+	for name, policy := range names {
+		policies[name] = int(policy)
+		reverse[int(policy)] = name
+	}
+	return policies, reverse
+}
+
 // Governance item set
 type GovernanceSet struct {
 	items map[string]interface{}
@@ -130,9 +137,10 @@ type GovernanceVote struct {
 
 // GovernanceTallies represents a tally for each governance item
 type GovernanceTallyItem struct {
-	Key   string      `json:"key"`
-	Value interface{} `json:"value"`
-	Votes uint64      `json:"votes"`
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Votes  uint64      `json:"votes"`
+	Opened uint64      `json:"opened"` // The block number of the first vote, used as the proposal id
 }
 
 type GovernanceTallyList struct {
@@ -582,7 +590,7 @@ func CheckGenesisValues(c *params.ChainConfig) error {
 			return errors.New(k + " value is wrong")
 		}
 	}
-	return nil
+	return checkBallotConfig(c)
 }
 
 func newGovernanceCache() common.Cache {
@@ -667,7 +675,11 @@ func (g *Governance) WriteGovernance(num uint64, data GovernanceSet, delta Gover
 		new.Merge(delta.Items())
 	}
 	g.addGovernanceCache(num, new)
-	return g.db.WriteGovernance(new.Items(), num)
+	if err := g.db.WriteGovernance(new.Items(), num); err != nil {
+		return err
+	}
+	g.applyProposals(num, delta.Items())
+	return nil
 }
 
 func (g *Governance) searchCache(num uint64) (uint64, bool) {
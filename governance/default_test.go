@@ -17,6 +17,8 @@
 package governance
 
 import (
+	"encoding/json"
+	"math"
 	"math/big"
 	"reflect"
 	"testing"
@@ -783,3 +785,196 @@ func TestGovernance_HandleGovernanceVote_Ballot_mode(t *testing.T) {
 	}
 	gov.voteMap.Clear()
 }
+
+func TestGovernance_BallotProposals(t *testing.T) {
+	council := getTestCouncil()
+	rewards := getTestRewards()
+
+	var valSet istanbul.ValidatorSet
+	valSet = validator.NewWeightedCouncil(council, rewards, getTestVotingPowers(len(council)), nil, istanbul.WeightedRandom, 21, 0, 0, nil)
+
+	const epoch = uint64(30)
+	config := getTestConfig()
+	config.Istanbul.Epoch = epoch
+	config.Governance.GovernanceMode = GovernanceModeBallot
+	config.Governance.Ballot = &params.BallotConfig{VotingWindow: 2, Quorum: 50}
+	dbm := database.NewDBManager(&database.DBConfig{DBType: database.MemoryDB})
+	gov := NewGovernanceInitialize(config, dbm)
+	gov.nodeAddress.Store(council[len(council)-1])
+	self := council[len(council)-1]
+
+	votes := make([]GovernanceVote, 0)
+	tally := make([]GovernanceTallyItem, 0)
+
+	handleVote := func(number uint64, validator common.Address, key string, value interface{}) {
+		encoded, err := rlp.EncodeToBytes(&GovernanceVote{Validator: validator, Key: key, Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), BlockScore: common.Big1, Vote: encoded}
+		valSet, votes, tally = gov.HandleGovernanceVote(valSet, votes, tally, header, validator, self)
+	}
+
+	// Two proposals are opened in the first epoch, but none of them reach the quorum
+	handleVote(1, council[0], "governance.unitprice", uint64(22000))
+	handleVote(2, council[1], "governance.unitprice", uint64(22000))
+	handleVote(3, council[3], "istanbul.committeesize", uint64(7))
+
+	p, err := gov.ReadProposal(1)
+	assert.NoError(t, err)
+	assert.Equal(t, ProposalOpen, p.Status)
+	assert.Equal(t, uint64(2), p.Votes)
+	assert.Equal(t, []common.Address{council[0], council[1]}, p.Voters)
+	assert.Equal(t, council[0], p.Proposer)
+	assert.Equal(t, 2*epoch, p.Deadline)
+	assert.Equal(t, uint64(22000), p.Value)
+
+	// The votes are carried over to the next epoch within the voting window
+	gov.ClearVotes(epoch)
+	votes, tally = gov.ExpireVotes(epoch, epoch, valSet, votes, tally)
+	assert.Equal(t, 3, len(votes))
+	assert.Equal(t, 2, len(tally))
+	assert.Equal(t, tally, gov.GovernanceTallies.Copy())
+
+	// The third vote exceeds the quorum
+	handleVote(epoch+1, council[2], "governance.unitprice", uint64(22000))
+	if _, ok := gov.changeSet.items["governance.unitprice"]; !ok {
+		t.Errorf("Vote should be applied but it was not")
+	}
+	p, _ = gov.ReadProposal(1)
+	assert.Equal(t, ProposalPassed, p.Status)
+	assert.Equal(t, epoch+1, p.ClosedBlock)
+
+	// The passed proposal is applied when the change is written at the checkpoint
+	assert.NoError(t, gov.WriteGovernance(2*epoch, gov.currentSet, gov.changeSet))
+	p, _ = gov.ReadProposal(1)
+	assert.Equal(t, ProposalApplied, p.Status)
+	assert.Equal(t, 2*epoch, p.AppliedBlock)
+
+	// The other proposal expires at the end of its voting window
+	gov.ClearVotes(2 * epoch)
+	votes, tally = gov.ExpireVotes(2*epoch, epoch, valSet, votes, tally)
+	assert.Equal(t, 0, len(votes))
+	assert.Equal(t, 0, len(tally))
+
+	p, _ = gov.ReadProposal(3)
+	assert.Equal(t, ProposalExpired, p.Status)
+	assert.Equal(t, 2*epoch, p.ClosedBlock)
+
+	proposals := gov.Proposals()
+	assert.Equal(t, 2, len(proposals))
+	assert.Equal(t, uint64(1), proposals[0].ID)
+	assert.Equal(t, uint64(3), proposals[1].ID)
+
+	_, err = gov.ReadProposal(2)
+	assert.Equal(t, ErrProposalNotFound, err)
+
+	history := gov.VoteHistory(0, 2*epoch)
+	assert.Equal(t, 4, len(history))
+	for i, id := range []uint64{1, 1, 3, 1} {
+		assert.Equal(t, id, history[i].ProposalID)
+	}
+	assert.Equal(t, council[2], history[3].Validator)
+	assert.Equal(t, 2, len(gov.VoteHistory(2, epoch)))
+}
+
+func TestGovernance_WithdrawnProposal(t *testing.T) {
+	council := getTestCouncil()
+	rewards := getTestRewards()
+
+	var valSet istanbul.ValidatorSet
+	valSet = validator.NewWeightedCouncil(council, rewards, getTestVotingPowers(len(council)), nil, istanbul.WeightedRandom, 21, 0, 0, nil)
+
+	config := getTestConfig()
+	config.Governance.GovernanceMode = GovernanceModeBallot
+	dbm := database.NewDBManager(&database.DBConfig{DBType: database.MemoryDB})
+	gov := NewGovernanceInitialize(config, dbm)
+	gov.nodeAddress.Store(council[len(council)-1])
+	self := council[len(council)-1]
+
+	votes := make([]GovernanceVote, 0)
+	tally := make([]GovernanceTallyItem, 0)
+	for i, value := range []uint64{22000, 23000} {
+		encoded, _ := rlp.EncodeToBytes(&GovernanceVote{Validator: council[0], Key: "governance.unitprice", Value: value})
+		header := &types.Header{Number: big.NewInt(int64(i + 1)), BlockScore: common.Big1, Vote: encoded}
+		valSet, votes, tally = gov.HandleGovernanceVote(valSet, votes, tally, header, council[0], self)
+	}
+
+	p, _ := gov.ReadProposal(1)
+	assert.Equal(t, ProposalWithdrawn, p.Status)
+	assert.Equal(t, uint64(2), p.ClosedBlock)
+
+	p, _ = gov.ReadProposal(2)
+	assert.Equal(t, ProposalOpen, p.Status)
+	assert.Equal(t, uint64(1), p.Votes)
+}
+
+func TestCheckBallotConfig(t *testing.T) {
+	config := getTestConfig()
+	for _, tc := range []struct {
+		quorum uint64
+		err    error
+	}{{0, nil}, {50, nil}, {67, nil}, {49, ErrInvalidQuorum}, {100, ErrInvalidQuorum}} {
+		config.Governance.Ballot = &params.BallotConfig{Quorum: tc.quorum}
+		assert.Equal(t, tc.err, checkBallotConfig(config))
+	}
+}
+
+func TestGovernance_ItemHistory(t *testing.T) {
+	council := getTestCouncil()
+
+	const epoch = uint64(30)
+	config := getTestConfig()
+	config.Istanbul.Epoch = epoch
+	dbm := database.NewDBManager(&database.DBConfig{DBType: database.MemoryDB})
+	gov := NewGovernanceInitialize(config, dbm)
+
+	writeVote := func(number uint64, validator common.Address, key string, value interface{}) {
+		encoded, err := rlp.EncodeToBytes(&GovernanceVote{Validator: validator, Key: key, Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), BlockScore: common.Big1, Vote: encoded}
+		dbm.WriteHeader(header)
+		dbm.WriteCanonicalHash(header.Hash(), number)
+	}
+	writeChange := func(number uint64, item int, value interface{}) {
+		delta := NewGovernanceSet()
+		assert.NoError(t, delta.SetValue(item, value))
+		assert.NoError(t, gov.WriteGovernance(number, gov.currentSet, delta))
+		_, data, err := gov.ReadGovernance(number + epoch)
+		assert.NoError(t, err)
+		gov.currentSet.Import(data)
+	}
+
+	// The voters of the first two changes are taken from the headers
+	writeVote(3, council[0], "governance.unitprice", uint64(22000))
+	writeVote(4, council[1], "governance.unitprice", uint64(22000))
+	writeVote(5, council[2], "istanbul.committeesize", uint64(7))
+	writeChange(epoch, params.UnitPrice, uint64(22000))
+
+	writeVote(epoch+1, council[2], "istanbul.committeesize", uint64(7))
+	writeChange(2*epoch, params.CommitteeSize, uint64(7))
+
+	// The voters of the last change are taken from the vote history
+	record, _ := json.Marshal(&GovernanceVoteRecord{BlockNumber: 2*epoch + 1, Validator: council[3], Key: "governance.unitprice", Value: uint64(23000)})
+	assert.NoError(t, dbm.WriteGovernanceVoteHistory(2*epoch+1, record))
+	writeChange(3*epoch, params.UnitPrice, uint64(23000))
+
+	changes, err := gov.ItemHistory("governance.UnitPrice", 0, math.MaxUint64)
+	assert.NoError(t, err)
+	assert.Equal(t, []*GovernanceItemChange{
+		{BlockNumber: 0, OldValue: nil, NewValue: config.UnitPrice, Voters: []common.Address{}},
+		{BlockNumber: epoch, OldValue: config.UnitPrice, NewValue: uint64(22000), Voters: []common.Address{council[0], council[1]}},
+		{BlockNumber: 3 * epoch, OldValue: uint64(22000), NewValue: uint64(23000), Voters: []common.Address{council[3]}},
+	}, changes)
+
+	changes, err = gov.ItemHistory("istanbul.committeesize", 1, 2*epoch)
+	assert.NoError(t, err)
+	assert.Equal(t, []*GovernanceItemChange{
+		{BlockNumber: 2 * epoch, OldValue: config.Istanbul.SubGroupSize, NewValue: uint64(7), Voters: []common.Address{council[2]}},
+	}, changes)
+
+	_, err = gov.ItemHistory("governance.unknown", 0, math.MaxUint64)
+	assert.Equal(t, ErrUnknownKey, err)
+}
@@ -271,6 +271,9 @@ func (gov *Governance) HandleGovernanceVote(valset istanbul.ValidatorSet, votes
 			governanceMode := GovernanceModeMap[gov.GovernanceMode()]
 			governingNode := gov.GoverningNode()
 
+			prevTally := make([]GovernanceTallyItem, len(tally))
+			copy(prevTally, tally)
+
 			// Remove old vote with same validator and key
 			votes, tally = gov.removePreviousVote(valset, votes, tally, proposer, gVote, governanceMode, governingNode)
 
@@ -279,7 +282,12 @@ func (gov *Governance) HandleGovernanceVote(valset istanbul.ValidatorSet, votes
 
 			// Tally up the new vote. This will be cleared when Epoch ends.
 			// Add to GovernanceTallies if it doesn't exist
-			valset, votes, tally = gov.addNewVote(valset, votes, tally, gVote, governanceMode, governingNode, number)
+			var approved bool
+			valset, votes, tally, approved = gov.addNewVote(valset, votes, tally, gVote, governanceMode, governingNode, number)
+
+			if number > atomic.LoadUint64(&gov.lastGovernanceStateBlock) {
+				gov.recordVote(number, gVote, approved, votes, prevTally, tally)
+			}
 
 			// If this vote was casted by this node, remove it
 			if self == proposer {
@@ -317,12 +325,12 @@ func (gov *Governance) removePreviousVote(valset istanbul.ValidatorSet, votes []
 			_, v := valset.GetByAddress(vote.Validator)
 			vp := v.VotingPower()
 			var currentVotes uint64
-			currentVotes, tally = gov.changeGovernanceTally(tally, vote.Key, vote.Value, vp, false)
+			currentVotes, tally = gov.changeGovernanceTally(tally, vote.Key, vote.Value, vp, false, 0)
 
 			// Remove the old vote from GovernanceVotes
 			ret = append(votes[:idx], votes[idx+1:]...)
 			if gov.isGovernanceModeSingleOrNone(governanceMode, governingNode, gVote.Validator) ||
-				(governanceMode == params.GovernanceMode_Ballot && currentVotes <= valset.TotalVotingPower()/2) {
+				(governanceMode == params.GovernanceMode_Ballot && currentVotes <= gov.quorumThreshold(valset.TotalVotingPower())) {
 				if v, ok := gov.changeSet.GetValue(GovernanceKeyMap[vote.Key]); ok && v == vote.Value {
 					gov.changeSet.RemoveItem(vote.Key)
 				}
@@ -334,7 +342,8 @@ func (gov *Governance) removePreviousVote(valset istanbul.ValidatorSet, votes []
 }
 
 // changeGovernanceTally updates snapshot's tally for governance votes.
-func (gov *Governance) changeGovernanceTally(tally []GovernanceTallyItem, key string, value interface{}, vp uint64, isAdd bool) (uint64, []GovernanceTallyItem) {
+// A new tally item is opened at the given block number.
+func (gov *Governance) changeGovernanceTally(tally []GovernanceTallyItem, key string, value interface{}, vp uint64, isAdd bool, blockNum uint64) (uint64, []GovernanceTallyItem) {
 	found := false
 	var currentVote uint64
 	ret := make([]GovernanceTallyItem, len(tally))
@@ -363,21 +372,25 @@ func (gov *Governance) changeGovernanceTally(tally []GovernanceTallyItem, key st
 	}
 
 	if !found && isAdd {
-		ret = append(ret, GovernanceTallyItem{Key: key, Value: value, Votes: vp})
+		ret = append(ret, GovernanceTallyItem{Key: key, Value: value, Votes: vp, Opened: blockNum})
 		return vp, ret
 	} else {
 		return currentVote, ret
 	}
 }
 
-func (gov *Governance) addNewVote(valset istanbul.ValidatorSet, votes []GovernanceVote, tally []GovernanceTallyItem, gVote *GovernanceVote, governanceMode int, governingNode common.Address, blockNum uint64) (istanbul.ValidatorSet, []GovernanceVote, []GovernanceTallyItem) {
+// addNewVote tallies up a new vote and applies it if the vote is approved.
+// It also returns whether the vote is approved or not.
+func (gov *Governance) addNewVote(valset istanbul.ValidatorSet, votes []GovernanceVote, tally []GovernanceTallyItem, gVote *GovernanceVote, governanceMode int, governingNode common.Address, blockNum uint64) (istanbul.ValidatorSet, []GovernanceVote, []GovernanceTallyItem, bool) {
+	approved := false
 	_, v := valset.GetByAddress(gVote.Validator)
 	if v != nil {
 		vp := v.VotingPower()
 		var currentVotes uint64
-		currentVotes, tally = gov.changeGovernanceTally(tally, gVote.Key, gVote.Value, vp, true)
+		currentVotes, tally = gov.changeGovernanceTally(tally, gVote.Key, gVote.Value, vp, true, blockNum)
 		if gov.isGovernanceModeSingleOrNone(governanceMode, governingNode, gVote.Validator) ||
-			(governanceMode == params.GovernanceMode_Ballot && currentVotes > valset.TotalVotingPower()/2) {
+			(governanceMode == params.GovernanceMode_Ballot && currentVotes > gov.quorumThreshold(valset.TotalVotingPower())) {
+			approved = true
 			switch GovernanceKeyMap[gVote.Key] {
 			case params.AddValidator:
 				valset.AddValidator(gVote.Value.(common.Address))
@@ -398,7 +411,7 @@ func (gov *Governance) addNewVote(valset istanbul.ValidatorSet, votes []Governan
 			}
 		}
 	}
-	return valset, votes, tally
+	return valset, votes, tally, approved
 }
 
 func (gov *Governance) removeVotesFromRemovedNode(votes []GovernanceVote, addr common.Address) []GovernanceVote {
@@ -0,0 +1,147 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package governance
+
+import (
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+)
+
+// maxVoterScanHeaders is the maximum number of headers scanned to find the
+// voters of the changes in a call to ItemHistory when the vote history isn't
+// available. The voters are not filled further once the limit is reached.
+const maxVoterScanHeaders = 100000
+
+// GovernanceItemChange represents a change of a governance item stored in the governance index.
+type GovernanceItemChange struct {
+	BlockNumber uint64           `json:"blockNumber"`
+	OldValue    interface{}      `json:"oldValue"`
+	NewValue    interface{}      `json:"newValue"`
+	Voters      []common.Address `json:"voters"`
+}
+
+// ItemHistory returns every change of the given governance item written in the
+// blocks from `from` to `to`, both inclusive. The initial value of the item is
+// returned as a change from nil.
+func (gov *Governance) ItemHistory(key string, from, to uint64) ([]*GovernanceItemChange, error) {
+	key = gov.getKey(key)
+	if _, ok := GovernanceKeyMap[key]; !ok {
+		return nil, ErrUnknownKey
+	}
+	indices, err := gov.db.ReadRecentGovernanceIdx(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ret     = []*GovernanceItemChange{}
+		prev    interface{}
+		epoch   = params.DefaultEpoch
+		written = false
+		budget  = maxVoterScanHeaders
+	)
+	if gov.ChainConfig.Istanbul != nil {
+		epoch = gov.ChainConfig.Istanbul.Epoch
+	}
+	for _, num := range indices {
+		if num > to {
+			break
+		}
+		data, err := gov.db.ReadGovernance(num)
+		if err != nil {
+			return nil, err
+		}
+		data = adjustDecodedSet(data)
+
+		value, ok := data[key]
+		if ok && (!written || value != prev) && num >= from {
+			change := &GovernanceItemChange{
+				BlockNumber: num,
+				OldValue:    prev,
+				NewValue:    value,
+				Voters:      []common.Address{},
+			}
+			if written {
+				change.Voters = gov.itemVoters(key, value, num, epoch, &budget)
+			}
+			ret = append(ret, change)
+		}
+		if ok {
+			prev, written = value, true
+		}
+		// The votes applied at the next index are collected in the epoch written here
+		if e, ok := data[GovernanceKeyMapReverse[params.Epoch]].(uint64); ok && e > 0 {
+			epoch = e
+		}
+	}
+	return ret, nil
+}
+
+// itemVoters returns the validators who voted for the given value of the key
+// during the voting window closed at the given checkpoint block. The votes are
+// taken from the vote history if available, or from the headers otherwise. At
+// most budget headers up to the checkpoint block are scanned, and the budget is
+// decreased by the number of the scanned headers.
+func (gov *Governance) itemVoters(key string, value interface{}, num uint64, epoch uint64, budget *int) []common.Address {
+	var (
+		voters = []common.Address{}
+		seen   = make(map[common.Address]bool)
+		start  = uint64(1)
+	)
+	if span := gov.votingWindow() * epoch; num > span {
+		start = num - span + 1
+	}
+	add := func(validator common.Address) {
+		if !seen[validator] {
+			seen[validator] = true
+			voters = append(voters, validator)
+		}
+	}
+
+	for _, record := range gov.VoteHistory(start, num) {
+		if record.Key == key && record.Value == value {
+			add(record.Validator)
+		}
+	}
+	if len(voters) > 0 {
+		return voters
+	}
+
+	if *budget <= 0 {
+		return voters
+	}
+	if limit := uint64(*budget); num-start+1 > limit {
+		start = num - limit + 1
+	}
+	*budget -= int(num - start + 1)
+	for n := start; n <= num; n++ {
+		header := gov.db.ReadHeader(gov.db.ReadCanonicalHash(n), n)
+		if header == nil || len(header.Vote) == 0 {
+			continue
+		}
+		vote := new(GovernanceVote)
+		if err := rlp.DecodeBytes(header.Vote, vote); err != nil || gov.getKey(vote.Key) != key {
+			continue
+		}
+		vote.Key = key
+		if vote, err := gov.ParseVoteValue(vote); err == nil && vote.Value == value {
+			add(vote.Validator)
+		}
+	}
+	return voters
+}
@@ -0,0 +1,309 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package governance
+
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/params"
+)
+
+// Status of a governance proposal
+const (
+	ProposalOpen      = "open"      // Votes are being collected
+	ProposalPassed    = "passed"    // The quorum is reached and the change waits for the next epoch
+	ProposalApplied   = "applied"   // The change is applied
+	ProposalExpired   = "expired"   // The voting window ended before the quorum is reached
+	ProposalWithdrawn = "withdrawn" // All votes were changed to other values
+)
+
+// GovernanceProposal represents the lifecycle of a governance change.
+// A proposal is opened by the first vote on a key and a value, and it is
+// identified by the number of the block containing that vote.
+type GovernanceProposal struct {
+	ID           uint64           `json:"id"`
+	Key          string           `json:"key"`
+	Value        interface{}      `json:"value"`
+	Proposer     common.Address   `json:"proposer"`
+	Deadline     uint64           `json:"deadline"` // The checkpoint block number at which the votes expire
+	Votes        uint64           `json:"votes"`
+	Voters       []common.Address `json:"voters"`
+	Status       string           `json:"status"`
+	ClosedBlock  uint64           `json:"closedBlock,omitempty"`
+	AppliedBlock uint64           `json:"appliedBlock,omitempty"`
+}
+
+// GovernanceVoteRecord represents a valid vote included in a block.
+type GovernanceVoteRecord struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	Validator   common.Address `json:"validator"`
+	Key         string         `json:"key"`
+	Value       interface{}    `json:"value"`
+	ProposalID  uint64         `json:"proposalId"`
+}
+
+// votingWindow returns the number of epochs in which a proposal collects votes.
+func (gov *Governance) votingWindow() uint64 {
+	if c := gov.ChainConfig.Governance; c != nil && c.Ballot != nil && c.Ballot.VotingWindow > 0 {
+		return c.Ballot.VotingWindow
+	}
+	return params.DefaultVotingWindow
+}
+
+// quorumThreshold returns the voting power to be exceeded to pass a proposal
+// in the ballot mode.
+func (gov *Governance) quorumThreshold(totalVotingPower uint64) uint64 {
+	quorum := params.DefaultQuorum
+	if c := gov.ChainConfig.Governance; c != nil && c.Ballot != nil && c.Ballot.Quorum > 0 {
+		quorum = c.Ballot.Quorum
+	}
+	return totalVotingPower * quorum / 100
+}
+
+// checkBallotConfig returns an error if the ballot rules in the chain config are invalid.
+// A quorum below 50% is not allowed since it can pass conflicting proposals together.
+func checkBallotConfig(c *params.ChainConfig) error {
+	if c.Governance == nil || c.Governance.Ballot == nil {
+		return nil
+	}
+	if q := c.Governance.Ballot.Quorum; q != 0 && (q < 50 || q >= 100) {
+		return ErrInvalidQuorum
+	}
+	return nil
+}
+
+// calcProposalDeadline returns the checkpoint block number at which the votes of
+// a proposal opened at the given block number expire.
+func calcProposalDeadline(opened, epoch, window uint64) uint64 {
+	firstCheckpoint := opened + (epoch-opened%epoch)%epoch
+	return firstCheckpoint + (window-1)*epoch
+}
+
+// ExpireVotes is called at a checkpoint block after the votes are cleared. It
+// returns the votes and the tally of the proposals still in their voting
+// windows, and closes the proposals which are expired or finished.
+// With the default voting window, no votes are carried over to the next epoch.
+func (gov *Governance) ExpireVotes(number uint64, epoch uint64, valset istanbul.ValidatorSet, votes []GovernanceVote, tally []GovernanceTallyItem) ([]GovernanceVote, []GovernanceTallyItem) {
+	var (
+		window    = gov.votingWindow()
+		threshold = gov.quorumThreshold(valset.TotalVotingPower())
+		ballot    = GovernanceModeMap[gov.GovernanceMode()] == params.GovernanceMode_Ballot
+		keptVotes = make([]GovernanceVote, 0)
+		keptTally = make([]GovernanceTallyItem, 0)
+		isNew     = number > atomic.LoadUint64(&gov.lastGovernanceStateBlock)
+	)
+	for _, item := range tally {
+		// Votes are carried over to the next epoch only in the ballot mode
+		passed := ballot && item.Votes > threshold
+		expired := !ballot || number >= calcProposalDeadline(item.Opened, epoch, window)
+
+		if !passed && !expired {
+			keptTally = append(keptTally, item)
+			for _, vote := range votes {
+				if vote.Key == item.Key && vote.Value == item.Value {
+					keptVotes = append(keptVotes, vote)
+				}
+			}
+			continue
+		}
+		if isNew && !passed {
+			gov.closeProposal(item.Opened, number, ProposalExpired)
+		}
+	}
+	if isNew {
+		gov.GovernanceVotes.Import(keptVotes)
+		gov.GovernanceTallies.Import(keptTally)
+	}
+	return keptVotes, keptTally
+}
+
+// recordVote stores a valid vote included in the block of the given number and
+// updates the proposals affected by the vote. prevTally is the tally before
+// the vote is handled.
+func (gov *Governance) recordVote(number uint64, vote *GovernanceVote, approved bool, votes []GovernanceVote, prevTally, tally []GovernanceTallyItem) {
+	if gov.db == nil {
+		return
+	}
+	var proposalID uint64
+	for _, item := range tally {
+		if item.Key == vote.Key && item.Value == vote.Value {
+			proposalID = item.Opened
+		}
+	}
+	record := &GovernanceVoteRecord{
+		BlockNumber: number,
+		Validator:   vote.Validator,
+		Key:         vote.Key,
+		Value:       vote.Value,
+		ProposalID:  proposalID,
+	}
+	if b, err := json.Marshal(record); err != nil {
+		logger.Error("Failed to marshal a governance vote record", "number", number, "err", err)
+	} else if err := gov.db.WriteGovernanceVoteHistory(number, b); err != nil {
+		logger.Error("Failed to write a governance vote record", "number", number, "err", err)
+	}
+
+	// Close the proposals of which all votes were changed to other values
+	for _, prev := range prevTally {
+		if !containsTallyItem(tally, prev.Key, prev.Value) {
+			gov.closeProposal(prev.Opened, number, ProposalWithdrawn)
+		}
+	}
+
+	for _, item := range tally {
+		if item.Key != vote.Key {
+			continue
+		}
+		p, err := gov.ReadProposal(item.Opened)
+		if err != nil {
+			p = &GovernanceProposal{
+				ID:       item.Opened,
+				Key:      item.Key,
+				Value:    item.Value,
+				Proposer: vote.Validator,
+				Deadline: calcProposalDeadline(item.Opened, gov.Epoch(), gov.votingWindow()),
+				Status:   ProposalOpen,
+			}
+		}
+		p.Votes = item.Votes
+		p.Voters = p.Voters[:0]
+		for _, v := range votes {
+			if v.Key == item.Key && v.Value == item.Value {
+				p.Voters = append(p.Voters, v.Validator)
+			}
+		}
+		if approved && item.Value == vote.Value && p.Status == ProposalOpen {
+			switch GovernanceKeyMap[item.Key] {
+			case params.AddValidator, params.RemoveValidator:
+				// Validators are changed as soon as the proposal is passed
+				p.Status = ProposalApplied
+				p.AppliedBlock = number
+			default:
+				p.Status = ProposalPassed
+			}
+			p.ClosedBlock = number
+		}
+		gov.writeProposal(p)
+	}
+}
+
+// applyProposals marks the passed proposals changing the given items as applied.
+func (gov *Governance) applyProposals(number uint64, changes map[string]interface{}) {
+	if gov.db == nil || len(changes) == 0 {
+		return
+	}
+	for _, item := range gov.GovernanceTallies.Copy() {
+		if value, ok := changes[item.Key]; !ok || value != item.Value {
+			continue
+		}
+		if p, err := gov.ReadProposal(item.Opened); err == nil && p.Status == ProposalPassed {
+			p.Status = ProposalApplied
+			p.AppliedBlock = number
+			gov.writeProposal(p)
+		}
+	}
+}
+
+// closeProposal closes an open proposal with the given status.
+func (gov *Governance) closeProposal(id uint64, number uint64, status string) {
+	if gov.db == nil {
+		return
+	}
+	if p, err := gov.ReadProposal(id); err == nil && p.Status == ProposalOpen {
+		p.Status = status
+		p.ClosedBlock = number
+		gov.writeProposal(p)
+	}
+}
+
+func (gov *Governance) writeProposal(p *GovernanceProposal) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		logger.Error("Failed to marshal a governance proposal", "id", p.ID, "err", err)
+		return
+	}
+	if err := gov.db.WriteGovernanceProposal(p.ID, b); err != nil {
+		logger.Error("Failed to write a governance proposal", "id", p.ID, "err", err)
+	}
+}
+
+// ReadProposal returns the governance proposal of the given id.
+func (gov *Governance) ReadProposal(id uint64) (*GovernanceProposal, error) {
+	if gov.db == nil {
+		return nil, ErrProposalNotFound
+	}
+	b, err := gov.db.ReadGovernanceProposal(id)
+	if err != nil || len(b) == 0 {
+		return nil, ErrProposalNotFound
+	}
+	return decodeProposal(b)
+}
+
+// Proposals returns all governance proposals in ascending order of their ids.
+func (gov *Governance) Proposals() []*GovernanceProposal {
+	ret := []*GovernanceProposal{}
+	if gov.db == nil {
+		return ret
+	}
+	for _, b := range gov.db.ReadGovernanceProposals() {
+		if p, err := decodeProposal(b); err == nil {
+			ret = append(ret, p)
+		} else {
+			logger.Error("Failed to decode a governance proposal", "err", err)
+		}
+	}
+	return ret
+}
+
+// VoteHistory returns the votes included in the blocks from `from` to `to`, both inclusive.
+func (gov *Governance) VoteHistory(from, to uint64) []*GovernanceVoteRecord {
+	ret := []*GovernanceVoteRecord{}
+	if gov.db == nil {
+		return ret
+	}
+	for _, b := range gov.db.ReadGovernanceVoteHistory(from, to) {
+		record := new(GovernanceVoteRecord)
+		if err := json.Unmarshal(b, record); err != nil {
+			logger.Error("Failed to decode a governance vote record", "err", err)
+			continue
+		}
+		record.Value = adjustDecodedSet(map[string]interface{}{record.Key: record.Value})[record.Key]
+		ret = append(ret, record)
+	}
+	return ret
+}
+
+func decodeProposal(b []byte) (*GovernanceProposal, error) {
+	p := new(GovernanceProposal)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	p.Value = adjustDecodedSet(map[string]interface{}{p.Key: p.Value})[p.Key]
+	return p, nil
+}
+
+func containsTallyItem(tally []GovernanceTallyItem, key string, value interface{}) bool {
+	for _, item := range tally {
+		if item.Key == key && item.Value == value {
+			return true
+		}
+	}
+	return false
+}
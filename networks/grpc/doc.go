@@ -25,7 +25,10 @@ Source files
 Each file provides the following features
  - gClient.go : gRPC client implementation.
  - gServer.go : gRPC server implementation.
+ - gAPIServer.go : typed API server implementation backed by api.Backend.
  - klaytn.proto : Define a interface and messages to use in gRPC server and clients.
  - klaytn.pb.go : the generated Go file from klaytn.proto by protoc-gen-go.
+ - klaytn_api.proto : Define the typed API for blocks, transactions, receipts, logs and accounts.
+ - klaytn_api.pb.go : the generated Go file from klaytn_api.proto by protoc-gen-go.
 */
 package grpc
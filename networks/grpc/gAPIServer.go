@@ -0,0 +1,537 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package grpc
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/klaytn/klaytn/api"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/node/cn/filters"
+	"github.com/klaytn/klaytn/rlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chainEventChanSize is the size of channel listening to the chain events.
+const chainEventChanSize = 10
+
+// apiMethods maps the methods of the typed API to the equivalent JSON-RPC
+// methods, whose method policy, limits and metrics are applied to the typed
+// API as well. The methods taking a block request are mapped to the methods
+// by number, which are replaced with the methods by hash for a block hash.
+var apiMethods = map[string]string{
+	"/grpc.KlaytnAPI/GetBlockNumber":        "klay_blockNumber",
+	"/grpc.KlaytnAPI/GetHeader":             "klay_getBlockByNumber",
+	"/grpc.KlaytnAPI/GetBlock":              "klay_getBlockByNumber",
+	"/grpc.KlaytnAPI/GetBlockReceipts":      "klay_getBlockReceipts",
+	"/grpc.KlaytnAPI/GetTransaction":        "klay_getTransactionByHash",
+	"/grpc.KlaytnAPI/GetTransactionReceipt": "klay_getTransactionReceipt",
+	"/grpc.KlaytnAPI/SendRawTransaction":    "klay_sendRawTransaction",
+	"/grpc.KlaytnAPI/GetLogs":               "klay_getLogs",
+	"/grpc.KlaytnAPI/GetAccount":            "klay_getAccount",
+	"/grpc.KlaytnAPI/GetCode":               "klay_getCode",
+	"/grpc.KlaytnAPI/GetStorageAt":          "klay_getStorageAt",
+	"/grpc.KlaytnAPI/SubscribeNewHeads":     "klay_subscribe",
+	"/grpc.KlaytnAPI/SubscribeLogs":         "klay_subscribe",
+}
+
+// apiMethod returns the JSON-RPC method equivalent to the given typed API
+// request, or false if the request isn't of the typed API.
+func apiMethod(fullMethod string, req interface{}) (string, bool) {
+	method, ok := apiMethods[fullMethod]
+	if !ok {
+		return "", false
+	}
+	if blockReq, ok := req.(*BlockRequest); ok {
+		if _, byHash := blockReq.GetBlock().(*BlockRequest_Hash); byHash {
+			method = strings.Replace(method, "ByNumber", "ByHash", 1)
+		}
+	}
+	return method, true
+}
+
+// beginAPICall admits a request of the typed API as a request of the
+// equivalent JSON-RPC method, converting the rejection into a gRPC status.
+func beginAPICall(ctx context.Context, method string) (func(err error), error) {
+	end, err := rpc.BeginCall(ctx, method)
+	if err != nil {
+		code := codes.ResourceExhausted
+		if rpcErr, ok := err.(rpc.Error); ok && rpcErr.ErrorCode() == -32601 {
+			code = codes.PermissionDenied
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	return end, nil
+}
+
+// klaytnAPIServer is an implementation of KlaytnAPIServer, which serves the
+// typed API with the same backend as the JSON-RPC API. The logs are queried
+// with the filter API of the JSON-RPC API to apply the same limits.
+type klaytnAPIServer struct {
+	b       api.Backend
+	filters *filters.PublicFilterAPI
+}
+
+// blockNumber converts the tag or the number of the request into a block number.
+func blockNumber(req *BlockRequest) (rpc.BlockNumber, error) {
+	switch req.GetBlock().(type) {
+	case *BlockRequest_Number:
+		if req.GetNumber() > math.MaxInt64 {
+			return 0, status.Errorf(codes.InvalidArgument, "invalid block number %d", req.GetNumber())
+		}
+		return rpc.BlockNumber(req.GetNumber()), nil
+	case *BlockRequest_Tag:
+		switch req.GetTag() {
+		case BlockTag_PENDING:
+			return rpc.PendingBlockNumber, nil
+		case BlockTag_EARLIEST:
+			return rpc.EarliestBlockNumber, nil
+		}
+	}
+	return rpc.LatestBlockNumber, nil
+}
+
+// block returns the block of the request, or the latest block if the request is nil.
+func (s *klaytnAPIServer) block(ctx context.Context, req *BlockRequest) (*types.Block, error) {
+	var (
+		block *types.Block
+		err   error
+	)
+	if _, ok := req.GetBlock().(*BlockRequest_Hash); ok {
+		block, err = s.b.GetBlock(ctx, common.BytesToHash(req.GetHash()))
+	} else {
+		number, numErr := blockNumber(req)
+		if numErr != nil {
+			return nil, numErr
+		}
+		block, err = s.b.BlockByNumber(ctx, number)
+	}
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if block == nil {
+		return nil, status.Error(codes.NotFound, "the block does not exist")
+	}
+	return block, nil
+}
+
+// header returns the header of the request, or the latest header if the request is nil.
+func (s *klaytnAPIServer) header(ctx context.Context, req *BlockRequest) (*types.Header, error) {
+	if _, ok := req.GetBlock().(*BlockRequest_Hash); ok {
+		block, err := s.block(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return block.Header(), nil
+	}
+	number, err := blockNumber(req)
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.b.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if header == nil {
+		return nil, status.Error(codes.NotFound, "the block does not exist")
+	}
+	return header, nil
+}
+
+// state returns the state of the block of the request, or the latest state if
+// the request is nil.
+func (s *klaytnAPIServer) state(ctx context.Context, req *BlockRequest) (vmState, error) {
+	var number rpc.BlockNumber
+	if _, ok := req.GetBlock().(*BlockRequest_Hash); ok {
+		header, err := s.header(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		number = rpc.BlockNumber(header.Number.Uint64())
+	} else {
+		var err error
+		if number, err = blockNumber(req); err != nil {
+			return nil, err
+		}
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if state == nil {
+		return nil, status.Error(codes.NotFound, "the block does not exist")
+	}
+	// The block of the hash may not be the canonical block of its number
+	if hash, ok := req.GetBlock().(*BlockRequest_Hash); ok && header.Hash() != common.BytesToHash(hash.Hash) {
+		return nil, status.Error(codes.NotFound, "the state of the non-canonical block is not available")
+	}
+	return state, nil
+}
+
+// vmState is the part of the state used by the typed API.
+type vmState interface {
+	GetBalance(addr common.Address) *big.Int
+	GetNonce(addr common.Address) uint64
+	GetCode(addr common.Address) []byte
+	GetCodeHash(addr common.Address) common.Hash
+	GetState(addr common.Address, key common.Hash) common.Hash
+	Error() error
+}
+
+func (s *klaytnAPIServer) GetBlockNumber(ctx context.Context, req *Empty) (*BlockNumber, error) {
+	header, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockNumber{Number: header.Number.Uint64()}, nil
+}
+
+func (s *klaytnAPIServer) GetHeader(ctx context.Context, req *BlockRequest) (*Header, error) {
+	header, err := s.header(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return newHeader(header), nil
+}
+
+func (s *klaytnAPIServer) GetBlock(ctx context.Context, req *BlockRequest) (*Block, error) {
+	block, err := s.block(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	result := &Block{Header: newHeader(block.Header()), TransactionHashes: make([][]byte, len(txs))}
+	for i, tx := range txs {
+		result.TransactionHashes[i] = tx.Hash().Bytes()
+	}
+	if req.GetFullTransactions() {
+		result.Transactions = make([]*Transaction, len(txs))
+		for i, tx := range txs {
+			if result.Transactions[i], err = newTransaction(tx, block.Hash(), block.NumberU64(), uint64(i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *klaytnAPIServer) GetBlockReceipts(ctx context.Context, req *BlockRequest) (*Receipts, error) {
+	block, err := s.block(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	receipts := s.b.GetBlockReceipts(ctx, block.Hash())
+	if receipts == nil && len(block.Transactions()) > 0 {
+		return nil, status.Error(codes.NotFound, "the receipts do not exist")
+	}
+	return &Receipts{Receipts: newReceipts(receipts, block.Hash(), block.NumberU64())}, nil
+}
+
+func (s *klaytnAPIServer) GetTransaction(ctx context.Context, req *TransactionRequest) (*Transaction, error) {
+	hash := common.BytesToHash(req.GetHash())
+	if tx, blockHash, blockNumber, index := s.b.GetTxAndLookupInfo(hash); tx != nil {
+		return newTransaction(tx, blockHash, blockNumber, index)
+	}
+	if tx := s.b.GetPoolTransaction(hash); tx != nil {
+		return newTransaction(tx, common.Hash{}, 0, 0)
+	}
+	return nil, status.Error(codes.NotFound, "the transaction does not exist")
+}
+
+func (s *klaytnAPIServer) GetTransactionReceipt(ctx context.Context, req *TransactionRequest) (*Receipt, error) {
+	tx, blockHash, blockNumber, index, _ := s.b.GetTxLookupInfoAndReceipt(ctx, common.BytesToHash(req.GetHash()))
+	if tx == nil {
+		return nil, status.Error(codes.NotFound, "the receipt does not exist")
+	}
+	// The receipts of the block are required to know the indexes of the logs in the block
+	receipts := newReceipts(s.b.GetBlockReceipts(ctx, blockHash), blockHash, blockNumber)
+	if index >= uint64(len(receipts)) {
+		return nil, status.Error(codes.NotFound, "the receipt does not exist")
+	}
+	return receipts[index], nil
+}
+
+func (s *klaytnAPIServer) SendRawTransaction(ctx context.Context, req *RawTransaction) (*TransactionHash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(req.GetRaw(), tx); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.b.SendTx(ctx, tx); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &TransactionHash{Hash: tx.Hash().Bytes()}, nil
+}
+
+// GetLogs returns the logs in the block range matching the filter, which is
+// limited as klay_getLogs by the log query limits of the node.
+func (s *klaytnAPIServer) GetLogs(ctx context.Context, req *LogFilter) (*Logs, error) {
+	if s.filters == nil {
+		return nil, status.Error(codes.Unimplemented, "the log query is not available")
+	}
+	from, err := s.header(ctx, req.GetFromBlock())
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.header(ctx, req.GetToBlock())
+	if err != nil {
+		return nil, err
+	}
+	if from.Number.Cmp(to.Number) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block range %d-%d", from.Number, to.Number)
+	}
+	addresses, topics := logCriteria(req)
+	logs, err := s.filters.GetLogs(ctx, filters.FilterCriteria{
+		FromBlock: from.Number,
+		ToBlock:   to.Number,
+		Addresses: addresses,
+		Topics:    topics,
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	result := &Logs{Logs: make([]*Log, len(logs))}
+	for i, log := range logs {
+		result.Logs[i] = newLog(log)
+	}
+	return result, nil
+}
+
+func (s *klaytnAPIServer) GetAccount(ctx context.Context, req *AccountRequest) (*Account, error) {
+	state, err := s.state(ctx, req.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	addr := common.BytesToAddress(req.GetAddress())
+	account := &Account{
+		Balance:  state.GetBalance(addr).Bytes(),
+		Nonce:    state.GetNonce(addr),
+		CodeHash: state.GetCodeHash(addr).Bytes(),
+	}
+	return account, state.Error()
+}
+
+func (s *klaytnAPIServer) GetCode(ctx context.Context, req *AccountRequest) (*Code, error) {
+	state, err := s.state(ctx, req.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	code := state.GetCode(common.BytesToAddress(req.GetAddress()))
+	return &Code{Code: code}, state.Error()
+}
+
+func (s *klaytnAPIServer) GetStorageAt(ctx context.Context, req *StorageRequest) (*StorageValue, error) {
+	state, err := s.state(ctx, req.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	value := state.GetState(common.BytesToAddress(req.GetAddress()), common.BytesToHash(req.GetKey()))
+	return &StorageValue{Value: value.Bytes()}, state.Error()
+}
+
+func (s *klaytnAPIServer) SubscribeNewHeads(req *Empty, stream KlaytnAPI_SubscribeNewHeadsServer) error {
+	headCh := make(chan blockchain.ChainHeadEvent, chainEventChanSize)
+	headSub := s.b.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			if err := stream.Send(newHeader(ev.Block.Header())); err != nil {
+				return err
+			}
+		case err := <-headSub.Err():
+			return err
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// SubscribeLogs streams the logs of the new blocks matching the filter. The
+// logs of the blocks removed by a chain reorganization aren't streamed.
+func (s *klaytnAPIServer) SubscribeLogs(req *LogFilter, stream KlaytnAPI_SubscribeLogsServer) error {
+	addresses, topics := logCriteria(req)
+	chainCh := make(chan blockchain.ChainEvent, chainEventChanSize)
+	chainSub := s.b.SubscribeChainEvent(chainCh)
+	defer chainSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-chainCh:
+			if !filters.BloomFilter(ev.Block.Bloom(), addresses, topics) {
+				continue
+			}
+			for i, receipt := range newReceipts(ev.Receipts, ev.Hash, ev.Block.NumberU64()) {
+				// The matched logs are in the same order as the logs of the receipt
+				matched := filters.FilterLogs(ev.Receipts[i].Logs, addresses, topics)
+				for j, log := range ev.Receipts[i].Logs {
+					if len(matched) == 0 || matched[0] != log {
+						continue
+					}
+					matched = matched[1:]
+					if err := stream.Send(receipt.Logs[j]); err != nil {
+						return err
+					}
+				}
+			}
+		case err := <-chainSub.Err():
+			return err
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// logCriteria returns the addresses and the topics of a LogFilter.
+func logCriteria(req *LogFilter) ([]common.Address, [][]common.Hash) {
+	addresses := make([]common.Address, len(req.GetAddresses()))
+	for i, addr := range req.GetAddresses() {
+		addresses[i] = common.BytesToAddress(addr)
+	}
+	topics := make([][]common.Hash, len(req.GetTopics()))
+	for i, sub := range req.GetTopics() {
+		for _, topic := range sub.GetHashes() {
+			topics[i] = append(topics[i], common.BytesToHash(topic))
+		}
+	}
+	return addresses, topics
+}
+
+func newHeader(header *types.Header) *Header {
+	return &Header{
+		Hash:             header.Hash().Bytes(),
+		ParentHash:       header.ParentHash.Bytes(),
+		Rewardbase:       header.Rewardbase.Bytes(),
+		StateRoot:        header.Root.Bytes(),
+		TransactionsRoot: header.TxHash.Bytes(),
+		ReceiptsRoot:     header.ReceiptHash.Bytes(),
+		LogsBloom:        header.Bloom.Bytes(),
+		BlockScore:       header.BlockScore.Bytes(),
+		Number:           header.Number.Uint64(),
+		GasUsed:          header.GasUsed,
+		Time:             header.Time.Uint64(),
+		TimeFos:          uint32(header.TimeFoS),
+		ExtraData:        header.Extra,
+		GovernanceData:   header.Governance,
+		VoteData:         header.Vote,
+	}
+}
+
+func newTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) (*Transaction, error) {
+	var from common.Address
+	if tx.IsLegacyTransaction() {
+		from, _ = types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+	} else {
+		from, _ = tx.From()
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &Transaction{
+		Hash:     tx.Hash().Bytes(),
+		Type:     uint32(tx.Type()),
+		Nonce:    tx.Nonce(),
+		GasPrice: tx.GasPrice().Bytes(),
+		Gas:      tx.Gas(),
+		From:     from.Bytes(),
+		Value:    tx.Value().Bytes(),
+		Input:    tx.Data(),
+		Raw:      raw,
+	}
+	if to := tx.To(); to != nil {
+		result.To = to.Bytes()
+	}
+	if blockHash != (common.Hash{}) {
+		result.BlockHash = blockHash.Bytes()
+		result.BlockNumber = blockNumber
+		result.TransactionIndex = index
+	}
+	return result, nil
+}
+
+// newReceipts converts the receipts of a block, filling the locations of the
+// receipts and their logs in the block.
+func newReceipts(receipts types.Receipts, blockHash common.Hash, blockNumber uint64) []*Receipt {
+	var (
+		result   = make([]*Receipt, len(receipts))
+		logIndex = uint64(0)
+	)
+	for i, receipt := range receipts {
+		result[i] = &Receipt{
+			TransactionHash:  receipt.TxHash.Bytes(),
+			Status:           uint64(receipt.Status),
+			GasUsed:          receipt.GasUsed,
+			LogsBloom:        receipt.Bloom.Bytes(),
+			Logs:             make([]*Log, len(receipt.Logs)),
+			BlockHash:        blockHash.Bytes(),
+			BlockNumber:      blockNumber,
+			TransactionIndex: uint64(i),
+		}
+		if receipt.ContractAddress != (common.Address{}) {
+			result[i].ContractAddress = receipt.ContractAddress.Bytes()
+		}
+		for j, log := range receipt.Logs {
+			topics := make([][]byte, len(log.Topics))
+			for k, topic := range log.Topics {
+				topics[k] = topic.Bytes()
+			}
+			result[i].Logs[j] = &Log{
+				Address:          log.Address.Bytes(),
+				Topics:           topics,
+				Data:             log.Data,
+				BlockNumber:      blockNumber,
+				TransactionHash:  receipt.TxHash.Bytes(),
+				TransactionIndex: uint64(i),
+				BlockHash:        blockHash.Bytes(),
+				LogIndex:         logIndex,
+				Removed:          log.Removed,
+			}
+			logIndex++
+		}
+	}
+	return result
+}
+
+// newLog converts a log having its location in the block.
+func newLog(log *types.Log) *Log {
+	topics := make([][]byte, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic.Bytes()
+	}
+	return &Log{
+		Address:          log.Address.Bytes(),
+		Topics:           topics,
+		Data:             log.Data,
+		BlockNumber:      log.BlockNumber,
+		TransactionHash:  log.TxHash.Bytes(),
+		TransactionIndex: uint64(log.TxIndex),
+		BlockHash:        log.BlockHash.Bytes(),
+		LogIndex:         uint64(log.Index),
+		Removed:          log.Removed,
+	}
+}
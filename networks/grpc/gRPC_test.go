@@ -17,9 +17,16 @@
 package grpc
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
 	"sync"
 	"testing"
 	"time"
@@ -78,6 +85,120 @@ func testCall(t *testing.T, addr string, wg *sync.WaitGroup) {
 	assert.Equal(t, out.Result, TEST_BLOCK_NUMBER)
 }
 
+// TestAuthenticate tests that the bearer token is read from the authorization
+// metadata of the gRPC requests.
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	_, err := authenticate(ctx)
+	assert.NoError(t, err)
+
+	auth, err := rpc.NewAuthenticator(map[string][]string{"token": {"klay"}}, nil)
+	assert.NoError(t, err)
+	rpc.SetAuthenticator(auth)
+	defer rpc.SetAuthenticator(nil)
+
+	_, err = authenticate(ctx)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = authenticate(metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer wrong")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = authenticate(metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token")))
+	assert.NoError(t, err)
+}
+
+// TestAPIMethodPolicy tests that the typed API requests are admitted by the
+// method policy of the equivalent JSON-RPC methods.
+func TestAPIMethodPolicy(t *testing.T) {
+	method, ok := apiMethod("/grpc.KlaytnAPI/GetBlock", &BlockRequest{Block: &BlockRequest_Number{Number: 1}})
+	assert.True(t, ok)
+	assert.Equal(t, "klay_getBlockByNumber", method)
+	method, ok = apiMethod("/grpc.KlaytnAPI/GetBlock", &BlockRequest{Block: &BlockRequest_Hash{Hash: []byte{1}}})
+	assert.True(t, ok)
+	assert.Equal(t, "klay_getBlockByHash", method)
+	_, ok = apiMethod("/grpc.KlaytnNode/Call", &RPCRequest{})
+	assert.False(t, ok)
+
+	assert.NoError(t, rpc.SetMethodPolicy(nil, []string{"klay_sendRawTransaction"}, map[string]int{"klay_getLogs": 1}))
+	defer rpc.SetMethodPolicy(nil, nil, nil)
+
+	served := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		served++
+		return nil, nil
+	}
+	call := func(fullMethod string, handler func(ctx context.Context, req interface{}) (interface{}, error)) error {
+		_, err := unaryAuthInterceptor(context.Background(), &Empty{}, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+		return err
+	}
+
+	// The denied method isn't served
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("/grpc.KlaytnAPI/SendRawTransaction", handler)))
+	assert.Equal(t, 0, served)
+	assert.NoError(t, call("/grpc.KlaytnAPI/GetBlockNumber", handler))
+	assert.Equal(t, 1, served)
+
+	// The concurrent requests exceeding the limit are rejected
+	var nested error
+	err := call("/grpc.KlaytnAPI/GetLogs", func(ctx context.Context, req interface{}) (interface{}, error) {
+		nested = call("/grpc.KlaytnAPI/GetLogs", handler)
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(nested))
+	assert.NoError(t, call("/grpc.KlaytnAPI/GetLogs", handler))
+	assert.Equal(t, 2, served)
+}
+
+// TestAPIRateLimit tests that the typed API requests and subscriptions are
+// limited by the rate limit and the subscription limit.
+func TestAPIRateLimit(t *testing.T) {
+	assert.NoError(t, rpc.SetRateLimit(0.001, 2, nil))
+	defer rpc.SetRateLimit(0, 0, nil)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1000}})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.KlaytnAPI/GetBlockNumber"}
+	_, err := unaryAuthInterceptor(ctx, &Empty{}, info, handler)
+	assert.NoError(t, err)
+
+	// The subscription takes the last token of the client
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/grpc.KlaytnAPI/SubscribeNewHeads"}
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+	assert.NoError(t, streamAuthInterceptor(nil, &testServerStream{ctx: ctx}, streamInfo, streamHandler))
+	_, err = unaryAuthInterceptor(ctx, &Empty{}, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	err = streamAuthInterceptor(nil, &testServerStream{ctx: ctx}, streamInfo, streamHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The subscriptions streaming at once are limited for a connection
+	assert.NoError(t, rpc.SetRateLimit(0, 0, nil))
+	maxSubscriptions := rpc.MaxSubscriptionPerWSConn
+	rpc.MaxSubscriptionPerWSConn = 1
+	defer func() { rpc.MaxSubscriptionPerWSConn = maxSubscriptions }()
+
+	var nested error
+	err = streamAuthInterceptor(nil, &testServerStream{ctx: ctx}, streamInfo, func(srv interface{}, stream grpc.ServerStream) error {
+		nested = streamAuthInterceptor(nil, &testServerStream{ctx: ctx}, streamInfo, streamHandler)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(nested))
+	assert.NoError(t, streamAuthInterceptor(nil, &testServerStream{ctx: ctx}, streamInfo, streamHandler))
+}
+
+// testServerStream is a grpc.ServerStream having only its context.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
 func testBiCall(t *testing.T, addr string, wg *sync.WaitGroup) {
 	defer wg.Done()
 
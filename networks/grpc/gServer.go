@@ -25,20 +25,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/node/cn/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"io"
 	"net"
+	"sync"
 )
 
 var logger = log.NewModuleLogger(log.NetworksGRPC)
 
+// apiSubscriptions has the number of the typed API subscriptions of each
+// connection, which is limited like the subscriptions of a websocket connection.
+var apiSubscriptions = struct {
+	mu     sync.Mutex
+	counts map[string]int32 // remote address of a connection -> number of its subscriptions
+}{counts: make(map[string]int32)}
+
 type Listener struct {
 	Addr       string
 	handler    *rpc.Server
+	backend    api.Backend
+	filterAPI  *filters.PublicFilterAPI
 	grpcServer *grpc.Server
 }
 
@@ -122,7 +138,7 @@ func (kns *klaytnServer) BiCall(stream KlaytnNode_BiCallServer) error {
 			return dec.Decode(v)
 		}
 
-		ctx := context.Background()
+		ctx := stream.Context()
 
 		reader := bufio.NewReaderSize(preader, common.MaxRequestContentLength)
 		kns.handler.ServeSingleRequest(ctx, rpc.NewCodec(&grpcReadWriteNopCloser{reader, &grpcWriter{stream, nil}}, encoder, decoder), rpc.OptionMethodInvocation|rpc.OptionSubscriptions)
@@ -162,7 +178,7 @@ func (kns *klaytnServer) Subscribe(request *RPCRequest, stream KlaytnNode_Subscr
 		return err
 	}
 
-	ctx := context.Background()
+	ctx := stream.Context()
 
 	reader := bufio.NewReaderSize(preader, common.MaxRequestContentLength)
 	kns.handler.ServeSingleRequest(ctx, rpc.NewCodec(&grpcReadWriteNopCloser{reader, &grpcWriter{stream, writeErr}}, encoder, decoder), rpc.OptionMethodInvocation|rpc.OptionSubscriptions)
@@ -244,20 +260,133 @@ loop:
 	}
 }
 
+// authenticate verifies the bearer token in the authorization metadata of the
+// request, and returns a context carrying the namespaces allowed to the client
+// and its address limited by the rate limit of the RPC server.
+func authenticate(ctx context.Context) (context.Context, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = context.WithValue(ctx, "remote", p.Addr.String())
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	ctx, err := rpc.AuthenticateContext(ctx, authorization)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ctx, nil
+}
+
+// unaryAuthInterceptor authenticates a unary request. A request of the typed
+// API is admitted as the equivalent JSON-RPC method, which the JSON-RPC server
+// does for the requests of the JSON-RPC API.
+func unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method, ok := apiMethod(info.FullMethod, req)
+	if !ok {
+		return handler(ctx, req)
+	}
+	end, err := beginAPICall(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	end(err)
+	return resp, err
+}
+
+// streamAuthInterceptor authenticates a stream. A subscription of the typed
+// API is admitted as klay_subscribe, which ends when the stream starts, and is
+// counted against the subscription limit of the connection while it streams.
+func streamAuthInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	if method, ok := apiMethod(info.FullMethod, nil); ok {
+		end, err := beginAPICall(ctx, method)
+		if err != nil {
+			return err
+		}
+		end(nil)
+
+		remote, _ := ctx.Value("remote").(string)
+		release, limit, ok := acquireSubscription(remote)
+		if !ok {
+			return status.Errorf(codes.ResourceExhausted, "Maximum %d subscriptions are allowed for a gRPC connection", limit)
+		}
+		defer release()
+	}
+	return handler(srv, &authServerStream{stream, ctx})
+}
+
+// acquireSubscription counts a subscription of the connection of the remote
+// address. It returns a function releasing the subscription, or false with
+// the limit if the connection has the maximum subscriptions already.
+func acquireSubscription(remote string) (func(), int32, bool) {
+	apiSubscriptions.mu.Lock()
+	defer apiSubscriptions.mu.Unlock()
+
+	limit := rpc.MaxSubscriptionPerWSConn
+	if apiSubscriptions.counts[remote] >= limit {
+		return nil, limit, false
+	}
+	apiSubscriptions.counts[remote]++
+	return func() {
+		apiSubscriptions.mu.Lock()
+		defer apiSubscriptions.mu.Unlock()
+
+		if apiSubscriptions.counts[remote]--; apiSubscriptions.counts[remote] <= 0 {
+			delete(apiSubscriptions.counts, remote)
+		}
+	}, limit, true
+}
+
+// authServerStream is a grpc.ServerStream whose context carries the namespaces
+// allowed to the authenticated client.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
 // SetRPCServer sets the RPC server.
 func (gs *Listener) SetRPCServer(handler *rpc.Server) {
 	gs.handler = handler
 }
 
+// SetAPIBackend sets the backend of the typed API. The typed API is served
+// next to the JSON-RPC API only if the backend is set.
+func (gs *Listener) SetAPIBackend(backend api.Backend) {
+	gs.backend = backend
+}
+
+// SetFilterAPI sets the filter API serving the log queries of the typed API.
+func (gs *Listener) SetFilterAPI(filterAPI *filters.PublicFilterAPI) {
+	gs.filterAPI = filterAPI
+}
+
 func (gs *Listener) Start() {
 	lis, err := net.Listen("tcp", gs.Addr)
 	if err != nil {
 		// TODO-Klaytn-gRPC Need to handle err
 		logger.Error("failed to listen", "err", err)
 	}
-	gs.grpcServer = grpc.NewServer()
+	gs.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(unaryAuthInterceptor), grpc.StreamInterceptor(streamAuthInterceptor))
 
 	RegisterKlaytnNodeServer(gs.grpcServer, &klaytnServer{handler: gs.handler})
+	if gs.backend != nil {
+		RegisterKlaytnAPIServer(gs.grpcServer, &klaytnAPIServer{b: gs.backend, filters: gs.filterAPI})
+	}
 
 	// Register reflection service on gRPC server.
 	reflection.Register(gs.grpcServer)
@@ -0,0 +1,1966 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: klaytn_api.proto
+
+package grpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type BlockTag int32
+
+const (
+	BlockTag_LATEST   BlockTag = 0
+	BlockTag_PENDING  BlockTag = 1
+	BlockTag_EARLIEST BlockTag = 2
+)
+
+var BlockTag_name = map[int32]string{
+	0: "LATEST",
+	1: "PENDING",
+	2: "EARLIEST",
+}
+
+var BlockTag_value = map[string]int32{
+	"LATEST":   0,
+	"PENDING":  1,
+	"EARLIEST": 2,
+}
+
+func (x BlockTag) String() string {
+	return proto.EnumName(BlockTag_name, int32(x))
+}
+
+func (BlockTag) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{0}
+}
+
+type BlockRequest struct {
+	// Types that are valid to be assigned to Block:
+	//	*BlockRequest_Tag
+	//	*BlockRequest_Number
+	//	*BlockRequest_Hash
+	Block                isBlockRequest_Block `protobuf_oneof:"block"`
+	FullTransactions     bool                 `protobuf:"varint,4,opt,name=full_transactions,json=fullTransactions,proto3" json:"full_transactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BlockRequest) Reset()         { *m = BlockRequest{} }
+func (m *BlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockRequest) ProtoMessage()    {}
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{0}
+}
+
+func (m *BlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRequest.Unmarshal(m, b)
+}
+func (m *BlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRequest.Merge(m, src)
+}
+func (m *BlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockRequest.Size(m)
+}
+func (m *BlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRequest proto.InternalMessageInfo
+
+type isBlockRequest_Block interface {
+	isBlockRequest_Block()
+}
+
+type BlockRequest_Tag struct {
+	Tag BlockTag `protobuf:"varint,1,opt,name=tag,proto3,enum=grpc.BlockTag,oneof"`
+}
+
+type BlockRequest_Number struct {
+	Number uint64 `protobuf:"varint,2,opt,name=number,proto3,oneof"`
+}
+
+type BlockRequest_Hash struct {
+	Hash []byte `protobuf:"bytes,3,opt,name=hash,proto3,oneof"`
+}
+
+func (*BlockRequest_Tag) isBlockRequest_Block() {}
+
+func (*BlockRequest_Number) isBlockRequest_Block() {}
+
+func (*BlockRequest_Hash) isBlockRequest_Block() {}
+
+func (m *BlockRequest) GetBlock() isBlockRequest_Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BlockRequest) GetTag() BlockTag {
+	if x, ok := m.GetBlock().(*BlockRequest_Tag); ok {
+		return x.Tag
+	}
+	return BlockTag_LATEST
+}
+
+func (m *BlockRequest) GetNumber() uint64 {
+	if x, ok := m.GetBlock().(*BlockRequest_Number); ok {
+		return x.Number
+	}
+	return 0
+}
+
+func (m *BlockRequest) GetHash() []byte {
+	if x, ok := m.GetBlock().(*BlockRequest_Hash); ok {
+		return x.Hash
+	}
+	return nil
+}
+
+func (m *BlockRequest) GetFullTransactions() bool {
+	if m != nil {
+		return m.FullTransactions
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*BlockRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*BlockRequest_Tag)(nil),
+		(*BlockRequest_Number)(nil),
+		(*BlockRequest_Hash)(nil),
+	}
+}
+
+type BlockNumber struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockNumber) Reset()         { *m = BlockNumber{} }
+func (m *BlockNumber) String() string { return proto.CompactTextString(m) }
+func (*BlockNumber) ProtoMessage()    {}
+func (*BlockNumber) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{1}
+}
+
+func (m *BlockNumber) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockNumber.Unmarshal(m, b)
+}
+func (m *BlockNumber) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockNumber.Marshal(b, m, deterministic)
+}
+func (m *BlockNumber) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockNumber.Merge(m, src)
+}
+func (m *BlockNumber) XXX_Size() int {
+	return xxx_messageInfo_BlockNumber.Size(m)
+}
+func (m *BlockNumber) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockNumber.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockNumber proto.InternalMessageInfo
+
+func (m *BlockNumber) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type Header struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	ParentHash           []byte   `protobuf:"bytes,2,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	Rewardbase           []byte   `protobuf:"bytes,3,opt,name=rewardbase,proto3" json:"rewardbase,omitempty"`
+	StateRoot            []byte   `protobuf:"bytes,4,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	TransactionsRoot     []byte   `protobuf:"bytes,5,opt,name=transactions_root,json=transactionsRoot,proto3" json:"transactions_root,omitempty"`
+	ReceiptsRoot         []byte   `protobuf:"bytes,6,opt,name=receipts_root,json=receiptsRoot,proto3" json:"receipts_root,omitempty"`
+	LogsBloom            []byte   `protobuf:"bytes,7,opt,name=logs_bloom,json=logsBloom,proto3" json:"logs_bloom,omitempty"`
+	BlockScore           []byte   `protobuf:"bytes,8,opt,name=block_score,json=blockScore,proto3" json:"block_score,omitempty"`
+	Number               uint64   `protobuf:"varint,9,opt,name=number,proto3" json:"number,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,10,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	Time                 uint64   `protobuf:"varint,11,opt,name=time,proto3" json:"time,omitempty"`
+	TimeFos              uint32   `protobuf:"varint,12,opt,name=time_fos,json=timeFos,proto3" json:"time_fos,omitempty"`
+	ExtraData            []byte   `protobuf:"bytes,13,opt,name=extra_data,json=extraData,proto3" json:"extra_data,omitempty"`
+	GovernanceData       []byte   `protobuf:"bytes,14,opt,name=governance_data,json=governanceData,proto3" json:"governance_data,omitempty"`
+	VoteData             []byte   `protobuf:"bytes,15,opt,name=vote_data,json=voteData,proto3" json:"vote_data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+func (*Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{2}
+}
+
+func (m *Header) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Header.Unmarshal(m, b)
+}
+func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Header.Marshal(b, m, deterministic)
+}
+func (m *Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Header.Merge(m, src)
+}
+func (m *Header) XXX_Size() int {
+	return xxx_messageInfo_Header.Size(m)
+}
+func (m *Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Header proto.InternalMessageInfo
+
+func (m *Header) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *Header) GetParentHash() []byte {
+	if m != nil {
+		return m.ParentHash
+	}
+	return nil
+}
+
+func (m *Header) GetRewardbase() []byte {
+	if m != nil {
+		return m.Rewardbase
+	}
+	return nil
+}
+
+func (m *Header) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+func (m *Header) GetTransactionsRoot() []byte {
+	if m != nil {
+		return m.TransactionsRoot
+	}
+	return nil
+}
+
+func (m *Header) GetReceiptsRoot() []byte {
+	if m != nil {
+		return m.ReceiptsRoot
+	}
+	return nil
+}
+
+func (m *Header) GetLogsBloom() []byte {
+	if m != nil {
+		return m.LogsBloom
+	}
+	return nil
+}
+
+func (m *Header) GetBlockScore() []byte {
+	if m != nil {
+		return m.BlockScore
+	}
+	return nil
+}
+
+func (m *Header) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Header) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Header) GetTime() uint64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Header) GetTimeFos() uint32 {
+	if m != nil {
+		return m.TimeFos
+	}
+	return 0
+}
+
+func (m *Header) GetExtraData() []byte {
+	if m != nil {
+		return m.ExtraData
+	}
+	return nil
+}
+
+func (m *Header) GetGovernanceData() []byte {
+	if m != nil {
+		return m.GovernanceData
+	}
+	return nil
+}
+
+func (m *Header) GetVoteData() []byte {
+	if m != nil {
+		return m.VoteData
+	}
+	return nil
+}
+
+type Block struct {
+	Header            *Header  `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	TransactionHashes [][]byte `protobuf:"bytes,2,rep,name=transaction_hashes,json=transactionHashes,proto3" json:"transaction_hashes,omitempty"`
+	// transactions are filled only if full_transactions is requested.
+	Transactions         []*Transaction `protobuf:"bytes,3,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{3}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetHeader() *Header {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Block) GetTransactionHashes() [][]byte {
+	if m != nil {
+		return m.TransactionHashes
+	}
+	return nil
+}
+
+func (m *Block) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+type Transaction struct {
+	Hash     []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Type     uint32 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Nonce    uint64 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	GasPrice []byte `protobuf:"bytes,4,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	Gas      uint64 `protobuf:"varint,5,opt,name=gas,proto3" json:"gas,omitempty"`
+	From     []byte `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	// to is empty for the contract deployment transactions.
+	To    []byte `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	Value []byte `protobuf:"bytes,8,opt,name=value,proto3" json:"value,omitempty"`
+	Input []byte `protobuf:"bytes,9,opt,name=input,proto3" json:"input,omitempty"`
+	// raw is the RLP encoding of the transaction having all type specific fields.
+	Raw []byte `protobuf:"bytes,10,opt,name=raw,proto3" json:"raw,omitempty"`
+	// The location of the transaction is empty for the pending transactions.
+	BlockHash            []byte   `protobuf:"bytes,11,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,12,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TransactionIndex     uint64   `protobuf:"varint,13,opt,name=transaction_index,json=transactionIndex,proto3" json:"transaction_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{4}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *Transaction) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Transaction) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *Transaction) GetGasPrice() []byte {
+	if m != nil {
+		return m.GasPrice
+	}
+	return nil
+}
+
+func (m *Transaction) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *Transaction) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *Transaction) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *Transaction) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Transaction) GetInput() []byte {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (m *Transaction) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
+func (m *Transaction) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *Transaction) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *Transaction) GetTransactionIndex() uint64 {
+	if m != nil {
+		return m.TransactionIndex
+	}
+	return 0
+}
+
+type TransactionRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionRequest) Reset()         { *m = TransactionRequest{} }
+func (m *TransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionRequest) ProtoMessage()    {}
+func (*TransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{5}
+}
+
+func (m *TransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionRequest.Unmarshal(m, b)
+}
+func (m *TransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *TransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionRequest.Merge(m, src)
+}
+func (m *TransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_TransactionRequest.Size(m)
+}
+func (m *TransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionRequest proto.InternalMessageInfo
+
+func (m *TransactionRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type RawTransaction struct {
+	Raw                  []byte   `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RawTransaction) Reset()         { *m = RawTransaction{} }
+func (m *RawTransaction) String() string { return proto.CompactTextString(m) }
+func (*RawTransaction) ProtoMessage()    {}
+func (*RawTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{6}
+}
+
+func (m *RawTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RawTransaction.Unmarshal(m, b)
+}
+func (m *RawTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RawTransaction.Marshal(b, m, deterministic)
+}
+func (m *RawTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawTransaction.Merge(m, src)
+}
+func (m *RawTransaction) XXX_Size() int {
+	return xxx_messageInfo_RawTransaction.Size(m)
+}
+func (m *RawTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RawTransaction proto.InternalMessageInfo
+
+func (m *RawTransaction) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
+type TransactionHash struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionHash) Reset()         { *m = TransactionHash{} }
+func (m *TransactionHash) String() string { return proto.CompactTextString(m) }
+func (*TransactionHash) ProtoMessage()    {}
+func (*TransactionHash) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{7}
+}
+
+func (m *TransactionHash) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionHash.Unmarshal(m, b)
+}
+func (m *TransactionHash) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionHash.Marshal(b, m, deterministic)
+}
+func (m *TransactionHash) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionHash.Merge(m, src)
+}
+func (m *TransactionHash) XXX_Size() int {
+	return xxx_messageInfo_TransactionHash.Size(m)
+}
+func (m *TransactionHash) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionHash.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionHash proto.InternalMessageInfo
+
+func (m *TransactionHash) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type Log struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Topics               [][]byte `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,4,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TransactionHash      []byte   `protobuf:"bytes,5,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	TransactionIndex     uint64   `protobuf:"varint,6,opt,name=transaction_index,json=transactionIndex,proto3" json:"transaction_index,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,7,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	LogIndex             uint64   `protobuf:"varint,8,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	Removed              bool     `protobuf:"varint,9,opt,name=removed,proto3" json:"removed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Log) Reset()         { *m = Log{} }
+func (m *Log) String() string { return proto.CompactTextString(m) }
+func (*Log) ProtoMessage()    {}
+func (*Log) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{8}
+}
+
+func (m *Log) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Log.Unmarshal(m, b)
+}
+func (m *Log) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Log.Marshal(b, m, deterministic)
+}
+func (m *Log) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Log.Merge(m, src)
+}
+func (m *Log) XXX_Size() int {
+	return xxx_messageInfo_Log.Size(m)
+}
+func (m *Log) XXX_DiscardUnknown() {
+	xxx_messageInfo_Log.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Log proto.InternalMessageInfo
+
+func (m *Log) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Log) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Log) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Log) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *Log) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *Log) GetTransactionIndex() uint64 {
+	if m != nil {
+		return m.TransactionIndex
+	}
+	return 0
+}
+
+func (m *Log) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *Log) GetLogIndex() uint64 {
+	if m != nil {
+		return m.LogIndex
+	}
+	return 0
+}
+
+func (m *Log) GetRemoved() bool {
+	if m != nil {
+		return m.Removed
+	}
+	return false
+}
+
+type Receipt struct {
+	TransactionHash      []byte   `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	Status               uint64   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,3,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	ContractAddress      []byte   `protobuf:"bytes,4,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	LogsBloom            []byte   `protobuf:"bytes,5,opt,name=logs_bloom,json=logsBloom,proto3" json:"logs_bloom,omitempty"`
+	Logs                 []*Log   `protobuf:"bytes,6,rep,name=logs,proto3" json:"logs,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,7,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,8,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TransactionIndex     uint64   `protobuf:"varint,9,opt,name=transaction_index,json=transactionIndex,proto3" json:"transaction_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{9}
+}
+
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (m *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(m, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *Receipt) GetStatus() uint64 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *Receipt) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Receipt) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *Receipt) GetLogsBloom() []byte {
+	if m != nil {
+		return m.LogsBloom
+	}
+	return nil
+}
+
+func (m *Receipt) GetLogs() []*Log {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *Receipt) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *Receipt) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *Receipt) GetTransactionIndex() uint64 {
+	if m != nil {
+		return m.TransactionIndex
+	}
+	return 0
+}
+
+type Receipts struct {
+	Receipts             []*Receipt `protobuf:"bytes,1,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Receipts) Reset()         { *m = Receipts{} }
+func (m *Receipts) String() string { return proto.CompactTextString(m) }
+func (*Receipts) ProtoMessage()    {}
+func (*Receipts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{10}
+}
+
+func (m *Receipts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipts.Unmarshal(m, b)
+}
+func (m *Receipts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipts.Marshal(b, m, deterministic)
+}
+func (m *Receipts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipts.Merge(m, src)
+}
+func (m *Receipts) XXX_Size() int {
+	return xxx_messageInfo_Receipts.Size(m)
+}
+func (m *Receipts) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipts proto.InternalMessageInfo
+
+func (m *Receipts) GetReceipts() []*Receipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+type Topics struct {
+	// hashes are the alternatives of a topic position, and any topic is
+	// matched if it is empty.
+	Hashes               [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Topics) Reset()         { *m = Topics{} }
+func (m *Topics) String() string { return proto.CompactTextString(m) }
+func (*Topics) ProtoMessage()    {}
+func (*Topics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{11}
+}
+
+func (m *Topics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Topics.Unmarshal(m, b)
+}
+func (m *Topics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Topics.Marshal(b, m, deterministic)
+}
+func (m *Topics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Topics.Merge(m, src)
+}
+func (m *Topics) XXX_Size() int {
+	return xxx_messageInfo_Topics.Size(m)
+}
+func (m *Topics) XXX_DiscardUnknown() {
+	xxx_messageInfo_Topics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Topics proto.InternalMessageInfo
+
+func (m *Topics) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type LogFilter struct {
+	// from_block and to_block are the latest block if not given, and they are
+	// ignored by SubscribeLogs.
+	FromBlock            *BlockRequest `protobuf:"bytes,1,opt,name=from_block,json=fromBlock,proto3" json:"from_block,omitempty"`
+	ToBlock              *BlockRequest `protobuf:"bytes,2,opt,name=to_block,json=toBlock,proto3" json:"to_block,omitempty"`
+	Addresses            [][]byte      `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Topics               []*Topics     `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *LogFilter) Reset()         { *m = LogFilter{} }
+func (m *LogFilter) String() string { return proto.CompactTextString(m) }
+func (*LogFilter) ProtoMessage()    {}
+func (*LogFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{12}
+}
+
+func (m *LogFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogFilter.Unmarshal(m, b)
+}
+func (m *LogFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogFilter.Marshal(b, m, deterministic)
+}
+func (m *LogFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogFilter.Merge(m, src)
+}
+func (m *LogFilter) XXX_Size() int {
+	return xxx_messageInfo_LogFilter.Size(m)
+}
+func (m *LogFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogFilter proto.InternalMessageInfo
+
+func (m *LogFilter) GetFromBlock() *BlockRequest {
+	if m != nil {
+		return m.FromBlock
+	}
+	return nil
+}
+
+func (m *LogFilter) GetToBlock() *BlockRequest {
+	if m != nil {
+		return m.ToBlock
+	}
+	return nil
+}
+
+func (m *LogFilter) GetAddresses() [][]byte {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *LogFilter) GetTopics() []*Topics {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+type Logs struct {
+	Logs                 []*Log   `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Logs) Reset()         { *m = Logs{} }
+func (m *Logs) String() string { return proto.CompactTextString(m) }
+func (*Logs) ProtoMessage()    {}
+func (*Logs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{13}
+}
+
+func (m *Logs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Logs.Unmarshal(m, b)
+}
+func (m *Logs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Logs.Marshal(b, m, deterministic)
+}
+func (m *Logs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Logs.Merge(m, src)
+}
+func (m *Logs) XXX_Size() int {
+	return xxx_messageInfo_Logs.Size(m)
+}
+func (m *Logs) XXX_DiscardUnknown() {
+	xxx_messageInfo_Logs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Logs proto.InternalMessageInfo
+
+func (m *Logs) GetLogs() []*Log {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+type AccountRequest struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// block is the latest block if not given.
+	Block                *BlockRequest `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AccountRequest) Reset()         { *m = AccountRequest{} }
+func (m *AccountRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountRequest) ProtoMessage()    {}
+func (*AccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{14}
+}
+
+func (m *AccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountRequest.Unmarshal(m, b)
+}
+func (m *AccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountRequest.Marshal(b, m, deterministic)
+}
+func (m *AccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountRequest.Merge(m, src)
+}
+func (m *AccountRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountRequest.Size(m)
+}
+func (m *AccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountRequest proto.InternalMessageInfo
+
+func (m *AccountRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *AccountRequest) GetBlock() *BlockRequest {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type Account struct {
+	Balance              []byte   `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Nonce                uint64   `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	CodeHash             []byte   `protobuf:"bytes,3,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+func (*Account) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{15}
+}
+
+func (m *Account) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Account.Unmarshal(m, b)
+}
+func (m *Account) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Account.Marshal(b, m, deterministic)
+}
+func (m *Account) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Account.Merge(m, src)
+}
+func (m *Account) XXX_Size() int {
+	return xxx_messageInfo_Account.Size(m)
+}
+func (m *Account) XXX_DiscardUnknown() {
+	xxx_messageInfo_Account.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Account proto.InternalMessageInfo
+
+func (m *Account) GetBalance() []byte {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *Account) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *Account) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
+type Code struct {
+	Code                 []byte   `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Code) Reset()         { *m = Code{} }
+func (m *Code) String() string { return proto.CompactTextString(m) }
+func (*Code) ProtoMessage()    {}
+func (*Code) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{16}
+}
+
+func (m *Code) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Code.Unmarshal(m, b)
+}
+func (m *Code) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Code.Marshal(b, m, deterministic)
+}
+func (m *Code) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Code.Merge(m, src)
+}
+func (m *Code) XXX_Size() int {
+	return xxx_messageInfo_Code.Size(m)
+}
+func (m *Code) XXX_DiscardUnknown() {
+	xxx_messageInfo_Code.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Code proto.InternalMessageInfo
+
+func (m *Code) GetCode() []byte {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+type StorageRequest struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Key     []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// block is the latest block if not given.
+	Block                *BlockRequest `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *StorageRequest) Reset()         { *m = StorageRequest{} }
+func (m *StorageRequest) String() string { return proto.CompactTextString(m) }
+func (*StorageRequest) ProtoMessage()    {}
+func (*StorageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{17}
+}
+
+func (m *StorageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageRequest.Unmarshal(m, b)
+}
+func (m *StorageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageRequest.Marshal(b, m, deterministic)
+}
+func (m *StorageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageRequest.Merge(m, src)
+}
+func (m *StorageRequest) XXX_Size() int {
+	return xxx_messageInfo_StorageRequest.Size(m)
+}
+func (m *StorageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageRequest proto.InternalMessageInfo
+
+func (m *StorageRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *StorageRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StorageRequest) GetBlock() *BlockRequest {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type StorageValue struct {
+	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageValue) Reset()         { *m = StorageValue{} }
+func (m *StorageValue) String() string { return proto.CompactTextString(m) }
+func (*StorageValue) ProtoMessage()    {}
+func (*StorageValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_10e39d47e32f3936, []int{18}
+}
+
+func (m *StorageValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageValue.Unmarshal(m, b)
+}
+func (m *StorageValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageValue.Marshal(b, m, deterministic)
+}
+func (m *StorageValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageValue.Merge(m, src)
+}
+func (m *StorageValue) XXX_Size() int {
+	return xxx_messageInfo_StorageValue.Size(m)
+}
+func (m *StorageValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageValue proto.InternalMessageInfo
+
+func (m *StorageValue) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("grpc.BlockTag", BlockTag_name, BlockTag_value)
+	proto.RegisterType((*BlockRequest)(nil), "grpc.BlockRequest")
+	proto.RegisterType((*BlockNumber)(nil), "grpc.BlockNumber")
+	proto.RegisterType((*Header)(nil), "grpc.Header")
+	proto.RegisterType((*Block)(nil), "grpc.Block")
+	proto.RegisterType((*Transaction)(nil), "grpc.Transaction")
+	proto.RegisterType((*TransactionRequest)(nil), "grpc.TransactionRequest")
+	proto.RegisterType((*RawTransaction)(nil), "grpc.RawTransaction")
+	proto.RegisterType((*TransactionHash)(nil), "grpc.TransactionHash")
+	proto.RegisterType((*Log)(nil), "grpc.Log")
+	proto.RegisterType((*Receipt)(nil), "grpc.Receipt")
+	proto.RegisterType((*Receipts)(nil), "grpc.Receipts")
+	proto.RegisterType((*Topics)(nil), "grpc.Topics")
+	proto.RegisterType((*LogFilter)(nil), "grpc.LogFilter")
+	proto.RegisterType((*Logs)(nil), "grpc.Logs")
+	proto.RegisterType((*AccountRequest)(nil), "grpc.AccountRequest")
+	proto.RegisterType((*Account)(nil), "grpc.Account")
+	proto.RegisterType((*Code)(nil), "grpc.Code")
+	proto.RegisterType((*StorageRequest)(nil), "grpc.StorageRequest")
+	proto.RegisterType((*StorageValue)(nil), "grpc.StorageValue")
+}
+
+func init() { proto.RegisterFile("klaytn_api.proto", fileDescriptor_10e39d47e32f3936) }
+
+var fileDescriptor_10e39d47e32f3936 = []byte{
+	// 1367 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0xc1, 0x6e, 0x1b, 0x37,
+	0x10, 0xd5, 0x4a, 0x6b, 0x69, 0x35, 0x5a, 0xcb, 0x32, 0x91, 0x04, 0x5b, 0xa5, 0x69, 0xd5, 0x6d,
+	0x82, 0x2a, 0x29, 0xe2, 0x34, 0x0e, 0x02, 0x14, 0xbd, 0x14, 0x76, 0xe2, 0xd8, 0x46, 0x0d, 0xc3,
+	0xa0, 0xd5, 0x5c, 0x05, 0x6a, 0x45, 0x2b, 0x42, 0xa4, 0xa5, 0xba, 0x4b, 0xd9, 0xf1, 0x77, 0xf4,
+	0xd2, 0x43, 0x4f, 0xfd, 0x83, 0x7e, 0x41, 0x3f, 0xa0, 0xb7, 0x02, 0xfd, 0x9f, 0x62, 0x86, 0x5c,
+	0x69, 0x57, 0x96, 0xe1, 0x9e, 0x44, 0xbe, 0x19, 0x92, 0xc3, 0x99, 0x37, 0x6f, 0x29, 0x68, 0x7d,
+	0x9c, 0x88, 0x6b, 0x1d, 0xf7, 0xc5, 0x6c, 0xbc, 0x33, 0x4b, 0x94, 0x56, 0xcc, 0x1d, 0x25, 0xb3,
+	0xa8, 0xed, 0x1b, 0xdc, 0x60, 0xe1, 0x6f, 0x0e, 0xf8, 0xfb, 0x13, 0x15, 0x7d, 0xe4, 0xf2, 0x97,
+	0xb9, 0x4c, 0x35, 0x0b, 0xa1, 0xa2, 0xc5, 0x28, 0x70, 0x3a, 0x4e, 0xb7, 0xb9, 0xdb, 0xdc, 0xc1,
+	0x25, 0x3b, 0xe4, 0xd0, 0x13, 0xa3, 0xa3, 0x12, 0x47, 0x23, 0x0b, 0xa0, 0x1a, 0xcf, 0xa7, 0x03,
+	0x99, 0x04, 0xe5, 0x8e, 0xd3, 0x75, 0x8f, 0x4a, 0xdc, 0xce, 0xd9, 0x3d, 0x70, 0x3f, 0x88, 0xf4,
+	0x43, 0x50, 0xe9, 0x38, 0x5d, 0xff, 0xa8, 0xc4, 0x69, 0xc6, 0xbe, 0x85, 0xed, 0x8b, 0xf9, 0x64,
+	0xd2, 0xd7, 0x89, 0x88, 0x53, 0x11, 0xe9, 0xb1, 0x8a, 0xd3, 0xc0, 0xed, 0x38, 0x5d, 0x8f, 0xb7,
+	0xd0, 0xd0, 0xcb, 0xe1, 0xfb, 0x35, 0xd8, 0x18, 0xe0, 0x79, 0xe1, 0x13, 0x68, 0xd0, 0xc1, 0xa7,
+	0x66, 0xeb, 0x07, 0x8b, 0x43, 0x31, 0x36, 0x37, 0x3b, 0x32, 0xfc, 0xb7, 0x02, 0xd5, 0x23, 0x29,
+	0x86, 0x32, 0x61, 0xcc, 0x9e, 0x8e, 0x0e, 0xbe, 0x3d, 0xfb, 0x4b, 0x68, 0xcc, 0x44, 0x22, 0x63,
+	0xdd, 0x27, 0x53, 0x99, 0x4c, 0x60, 0xa0, 0x23, 0x74, 0xf8, 0x02, 0x20, 0x91, 0x57, 0x22, 0x19,
+	0x0e, 0x44, 0x2a, 0x4d, 0xe0, 0x3c, 0x87, 0xb0, 0x47, 0x00, 0xa9, 0x16, 0x5a, 0xf6, 0x13, 0xa5,
+	0x34, 0x45, 0xed, 0xf3, 0x3a, 0x21, 0x5c, 0x29, 0x8d, 0x77, 0xcb, 0x5f, 0xcb, 0x78, 0x6d, 0x90,
+	0x57, 0x2b, 0x6f, 0x20, 0xe7, 0xaf, 0x61, 0x33, 0x91, 0x91, 0x1c, 0xcf, 0xb4, 0x75, 0xac, 0x92,
+	0xa3, 0x9f, 0x81, 0xe4, 0xf4, 0x08, 0x60, 0xa2, 0x46, 0x69, 0x7f, 0x30, 0x51, 0x6a, 0x1a, 0xd4,
+	0xcc, 0x81, 0x88, 0xec, 0x23, 0x80, 0x17, 0xa2, 0xfc, 0xf4, 0xd3, 0x48, 0x25, 0x32, 0xf0, 0x4c,
+	0xc0, 0x04, 0x9d, 0x23, 0x92, 0x4b, 0x54, 0x3d, 0x9f, 0x28, 0xf6, 0x19, 0x78, 0x23, 0x91, 0xf6,
+	0xe7, 0xa9, 0x1c, 0x06, 0x40, 0x96, 0xda, 0x48, 0xa4, 0x3f, 0xa7, 0x72, 0x88, 0x89, 0xd3, 0xe3,
+	0xa9, 0x0c, 0x1a, 0x04, 0xd3, 0x18, 0xdd, 0xf1, 0xb7, 0x7f, 0xa1, 0xd2, 0xc0, 0xef, 0x38, 0xdd,
+	0x4d, 0x5e, 0xc3, 0xf9, 0x3b, 0x95, 0x62, 0x84, 0xf2, 0x93, 0x4e, 0x44, 0x7f, 0x28, 0xb4, 0x08,
+	0x36, 0x4d, 0x84, 0x84, 0xbc, 0x15, 0x5a, 0xb0, 0x6f, 0x60, 0x6b, 0xa4, 0x2e, 0x65, 0x12, 0x8b,
+	0x38, 0x92, 0xc6, 0xa7, 0x49, 0x3e, 0xcd, 0x25, 0x4c, 0x8e, 0x0f, 0xa1, 0x7e, 0xa9, 0xb4, 0x75,
+	0xd9, 0x22, 0x17, 0x0f, 0x01, 0x34, 0x86, 0xbf, 0x3a, 0xb0, 0x41, 0xf5, 0x67, 0x8f, 0xa1, 0xfa,
+	0x81, 0x0a, 0x4c, 0x85, 0x6d, 0xec, 0xfa, 0x86, 0x95, 0xa6, 0xe8, 0xdc, 0xda, 0xd8, 0x73, 0x60,
+	0xb9, 0x7c, 0x53, 0xb5, 0x65, 0x1a, 0x94, 0x3b, 0x95, 0xae, 0xcf, 0xf3, 0x25, 0x3a, 0x22, 0x03,
+	0x7b, 0x0d, 0x7e, 0x81, 0x8e, 0x95, 0x4e, 0xa5, 0xdb, 0xd8, 0xdd, 0x36, 0x5b, 0xe7, 0x08, 0xc9,
+	0x0b, 0x6e, 0xe1, 0xdf, 0x65, 0x68, 0xe4, 0xac, 0x6b, 0x29, 0x87, 0xd9, 0xbc, 0x9e, 0x49, 0xe2,
+	0xda, 0x26, 0xa7, 0x31, 0xbb, 0x07, 0x1b, 0xb1, 0x8a, 0x23, 0x43, 0x30, 0x97, 0x9b, 0x09, 0x26,
+	0x00, 0x4b, 0x32, 0x4b, 0xc6, 0x91, 0xb4, 0xd4, 0xc2, 0x1a, 0x9d, 0xe1, 0x9c, 0xb5, 0xa0, 0x32,
+	0x12, 0x29, 0x71, 0xc9, 0xe5, 0x38, 0xc4, 0x8d, 0x2f, 0x12, 0x35, 0xb5, 0xac, 0xa1, 0x31, 0x6b,
+	0x42, 0x59, 0x2b, 0xcb, 0x92, 0xb2, 0x56, 0x78, 0xd0, 0xa5, 0x98, 0xcc, 0x33, 0x62, 0x98, 0x09,
+	0xa2, 0xe3, 0x78, 0x36, 0xd7, 0x44, 0x09, 0x9f, 0x9b, 0x09, 0x9e, 0x90, 0x88, 0x2b, 0x22, 0x83,
+	0xcf, 0x71, 0x88, 0x95, 0x35, 0xe4, 0xa2, 0x4b, 0x35, 0x4c, 0x65, 0x09, 0xa1, 0x5e, 0xf9, 0x0a,
+	0x7c, 0x63, 0xb6, 0x04, 0xf3, 0x29, 0xb6, 0xc6, 0x20, 0xd7, 0xa6, 0xc5, 0x7e, 0xe8, 0x8f, 0xe3,
+	0xa1, 0xfc, 0x44, 0x14, 0x71, 0x0b, 0xfd, 0x70, 0x8c, 0x78, 0xd8, 0x05, 0x96, 0x4f, 0xb5, 0x95,
+	0xa0, 0x35, 0x39, 0x0d, 0x43, 0x68, 0x72, 0x71, 0x95, 0xcf, 0xbc, 0x0d, 0xde, 0x59, 0x04, 0x1f,
+	0x3e, 0x81, 0xad, 0x5e, 0xb1, 0xce, 0x6b, 0xb7, 0xfa, 0xbd, 0x0c, 0x95, 0x13, 0x85, 0x2a, 0x56,
+	0x13, 0xc3, 0x61, 0x22, 0xd3, 0xd4, 0x9a, 0xb3, 0x29, 0x76, 0x90, 0x56, 0xb3, 0x71, 0x94, 0xd1,
+	0xc7, 0xce, 0x70, 0x37, 0xa2, 0xaa, 0x11, 0x09, 0x1a, 0xdf, 0x48, 0x89, 0x7b, 0x33, 0x25, 0x4f,
+	0xa1, 0xb5, 0xca, 0x4c, 0xab, 0x10, 0x5b, 0x2b, 0xbc, 0x5c, 0x9f, 0xbd, 0xea, 0xfa, 0xec, 0xad,
+	0x14, 0xab, 0xb6, 0x5a, 0xac, 0x87, 0x80, 0xaa, 0x61, 0xf7, 0xf0, 0x68, 0x0f, 0x6f, 0xa2, 0x46,
+	0x66, 0x6d, 0x00, 0xb5, 0x44, 0x4e, 0xd5, 0xa5, 0x1c, 0x12, 0x25, 0x3c, 0x9e, 0x4d, 0xc3, 0xbf,
+	0xca, 0x50, 0xe3, 0x46, 0x8f, 0xd6, 0x46, 0xee, 0xac, 0x8f, 0xfc, 0x01, 0x54, 0x53, 0x2d, 0xf4,
+	0x3c, 0x35, 0xdf, 0x04, 0x6e, 0x67, 0x05, 0xd5, 0xa9, 0x14, 0x55, 0xe7, 0x29, 0xb4, 0x22, 0x15,
+	0xeb, 0x44, 0x44, 0xba, 0x9f, 0x55, 0xc2, 0x34, 0xc1, 0x56, 0x86, 0xef, 0xd9, 0x8a, 0x14, 0x35,
+	0x71, 0x63, 0x55, 0x13, 0x1f, 0x81, 0x8b, 0x93, 0xa0, 0x4a, 0x4d, 0x5c, 0x37, 0x4d, 0x7c, 0xa2,
+	0x46, 0x9c, 0xe0, 0xbb, 0x12, 0xb5, 0x5a, 0x42, 0xef, 0x7f, 0xb2, 0xba, 0x7e, 0x0b, 0xab, 0x5f,
+	0x83, 0x67, 0x13, 0x98, 0xb2, 0xa7, 0xe0, 0x65, 0xe2, 0x1e, 0x38, 0x14, 0xdd, 0xa6, 0x89, 0xce,
+	0x7a, 0xf0, 0x85, 0x39, 0xec, 0x40, 0xb5, 0x67, 0x78, 0xf6, 0x00, 0xaa, 0x56, 0xbe, 0x1c, 0xc3,
+	0x3f, 0x33, 0x0b, 0xff, 0x74, 0xa0, 0x7e, 0xa2, 0x46, 0xef, 0xc6, 0x13, 0x2d, 0x13, 0xf6, 0x12,
+	0x00, 0x15, 0xa0, 0x4f, 0x71, 0x5a, 0x69, 0x64, 0xb9, 0x0f, 0xb6, 0x6d, 0x27, 0x5e, 0x47, 0x2f,
+	0x42, 0xd8, 0x73, 0xf0, 0xb4, 0xb2, 0x0b, 0xca, 0xb7, 0x2e, 0xa8, 0x69, 0x65, 0xdc, 0x3f, 0x87,
+	0xba, 0xad, 0x8b, 0x34, 0x02, 0xe9, 0xf3, 0x25, 0x80, 0xb2, 0x6c, 0xbb, 0xc4, 0xed, 0x54, 0x96,
+	0xb2, 0x6c, 0xee, 0x90, 0xf5, 0x4c, 0xf8, 0x04, 0xdc, 0x13, 0x53, 0x03, 0x53, 0x22, 0x67, 0x6d,
+	0x89, 0xc2, 0x1e, 0x34, 0xf7, 0xa2, 0x48, 0xcd, 0x63, 0x9d, 0xa9, 0xc0, 0xed, 0xed, 0xd9, 0x85,
+	0x8d, 0xbb, 0xae, 0x60, 0x9f, 0x10, 0xef, 0xa1, 0x66, 0x77, 0xc5, 0xed, 0x06, 0x62, 0x82, 0x9f,
+	0x9e, 0x6c, 0x3b, 0x3b, 0x5d, 0x4a, 0x73, 0x79, 0x45, 0x9a, 0x23, 0x35, 0x94, 0xfd, 0xe5, 0x73,
+	0x86, 0x7b, 0x08, 0x20, 0x63, 0xc2, 0x36, 0xb8, 0x6f, 0xd4, 0x50, 0xa2, 0x20, 0x20, 0x96, 0xc9,
+	0x0b, 0x8e, 0xc3, 0x0b, 0x68, 0x9e, 0x6b, 0x95, 0x88, 0x91, 0xbc, 0xfb, 0x26, 0x2d, 0xa8, 0x7c,
+	0x94, 0xd7, 0xf6, 0x51, 0x82, 0xc3, 0xe5, 0xdd, 0x2a, 0x77, 0xdd, 0xed, 0x31, 0xf8, 0xf6, 0x9c,
+	0xf7, 0x99, 0xc4, 0x1b, 0xe1, 0x77, 0x72, 0xc2, 0xff, 0xec, 0x25, 0x78, 0xd9, 0xeb, 0x8d, 0x01,
+	0x54, 0x4f, 0xf6, 0x7a, 0x07, 0xe7, 0xbd, 0x56, 0x89, 0x35, 0xa0, 0x76, 0x76, 0x70, 0xfa, 0xf6,
+	0xf8, 0xf4, 0xb0, 0xe5, 0x30, 0x1f, 0xbc, 0x83, 0x3d, 0x7e, 0x72, 0x8c, 0xa6, 0xf2, 0xee, 0x3f,
+	0x1b, 0x50, 0xff, 0x89, 0xde, 0x88, 0x7b, 0x67, 0xc7, 0x6c, 0x17, 0x9a, 0x87, 0x52, 0xe7, 0x1f,
+	0x62, 0x0d, 0x13, 0xd3, 0xc1, 0x74, 0xa6, 0xaf, 0xdb, 0xdb, 0xb9, 0x00, 0x8d, 0x3d, 0x2c, 0xb1,
+	0x17, 0x50, 0x3f, 0x94, 0x3a, 0x7b, 0x94, 0xdd, 0xbc, 0x42, 0xbb, 0xf0, 0x05, 0x0f, 0x4b, 0xc8,
+	0xcb, 0xec, 0x90, 0xb5, 0xfe, 0x8d, 0x1c, 0x16, 0x96, 0xd8, 0xf7, 0xd0, 0xca, 0xdc, 0x17, 0x8d,
+	0xb6, 0x6e, 0x59, 0xb3, 0xd0, 0x6a, 0x69, 0x58, 0x62, 0x3f, 0xd2, 0x6d, 0xf2, 0x9f, 0x91, 0xe0,
+	0xe6, 0x17, 0xdf, 0xae, 0xbe, 0xf9, 0x16, 0x08, 0x4b, 0x6c, 0x1f, 0xee, 0x17, 0x37, 0xc8, 0xa4,
+	0xf2, 0xf6, 0x7d, 0x8a, 0x0d, 0x1f, 0x96, 0xd8, 0x1b, 0x60, 0xe7, 0x32, 0x1e, 0xae, 0x7c, 0xcf,
+	0xee, 0x59, 0xb7, 0x02, 0xda, 0xbe, 0x7f, 0x63, 0x5b, 0x22, 0x60, 0x89, 0x75, 0xa1, 0x76, 0x28,
+	0x35, 0xb5, 0xd6, 0xd6, 0xa2, 0x99, 0x8c, 0x32, 0xb4, 0x61, 0x01, 0xe0, 0x9d, 0x5f, 0x01, 0x1c,
+	0x4a, 0x9d, 0xf5, 0x81, 0x3d, 0xa6, 0xd8, 0x6c, 0xed, 0xcd, 0x02, 0x4a, 0x15, 0xc1, 0xed, 0x89,
+	0xe4, 0xeb, 0x57, 0xd8, 0x33, 0xd0, 0x23, 0x2c, 0xb1, 0x1f, 0xc0, 0x3f, 0x94, 0xda, 0xf2, 0x71,
+	0x6f, 0x71, 0x4a, 0xb1, 0x11, 0xda, 0xac, 0x80, 0x12, 0x6d, 0xc3, 0x12, 0xdb, 0x85, 0xed, 0xf3,
+	0xf9, 0x20, 0x8d, 0x92, 0xf1, 0x40, 0x9e, 0xca, 0x2b, 0x24, 0x45, 0x5a, 0x24, 0xd9, 0x0a, 0x5d,
+	0xbe, 0x73, 0xd8, 0x0b, 0xd8, 0x5c, 0xac, 0x59, 0x9f, 0x83, 0xa5, 0xc2, 0xe0, 0x82, 0xfd, 0x67,
+	0xb0, 0x15, 0xa9, 0xe9, 0x8e, 0xfd, 0xef, 0x83, 0xa6, 0xfd, 0xe6, 0x82, 0xe4, 0x67, 0x89, 0xd2,
+	0xea, 0xcc, 0xf9, 0xa3, 0xec, 0x22, 0x34, 0xa8, 0xd2, 0x5f, 0xa3, 0x57, 0xff, 0x0d, 0x00, 0xac,
+	0x16, 0xa0, 0x52, 0x42, 0x0d, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// KlaytnAPIClient is the client API for KlaytnAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KlaytnAPIClient interface {
+	GetBlockNumber(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockNumber, error)
+	GetHeader(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Header, error)
+	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+	GetBlockReceipts(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Receipts, error)
+	GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	GetTransactionReceipt(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Receipt, error)
+	SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*TransactionHash, error)
+	GetLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (*Logs, error)
+	GetAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*Account, error)
+	GetCode(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*Code, error)
+	GetStorageAt(ctx context.Context, in *StorageRequest, opts ...grpc.CallOption) (*StorageValue, error)
+	SubscribeNewHeads(ctx context.Context, in *Empty, opts ...grpc.CallOption) (KlaytnAPI_SubscribeNewHeadsClient, error)
+	SubscribeLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (KlaytnAPI_SubscribeLogsClient, error)
+}
+
+type klaytnAPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewKlaytnAPIClient(cc *grpc.ClientConn) KlaytnAPIClient {
+	return &klaytnAPIClient{cc}
+}
+
+func (c *klaytnAPIClient) GetBlockNumber(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockNumber, error) {
+	out := new(BlockNumber)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetBlockNumber", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetHeader(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Header, error) {
+	out := new(Header)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetBlockReceipts(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Receipts, error) {
+	out := new(Receipts)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetBlockReceipts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetTransactionReceipt(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetTransactionReceipt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*TransactionHash, error) {
+	out := new(TransactionHash)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/SendRawTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (*Logs, error) {
+	out := new(Logs)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetCode(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*Code, error) {
+	out := new(Code)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) GetStorageAt(ctx context.Context, in *StorageRequest, opts ...grpc.CallOption) (*StorageValue, error) {
+	out := new(StorageValue)
+	err := c.cc.Invoke(ctx, "/grpc.KlaytnAPI/GetStorageAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *klaytnAPIClient) SubscribeNewHeads(ctx context.Context, in *Empty, opts ...grpc.CallOption) (KlaytnAPI_SubscribeNewHeadsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_KlaytnAPI_serviceDesc.Streams[0], "/grpc.KlaytnAPI/SubscribeNewHeads", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &klaytnAPISubscribeNewHeadsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KlaytnAPI_SubscribeNewHeadsClient interface {
+	Recv() (*Header, error)
+	grpc.ClientStream
+}
+
+type klaytnAPISubscribeNewHeadsClient struct {
+	grpc.ClientStream
+}
+
+func (x *klaytnAPISubscribeNewHeadsClient) Recv() (*Header, error) {
+	m := new(Header)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *klaytnAPIClient) SubscribeLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (KlaytnAPI_SubscribeLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_KlaytnAPI_serviceDesc.Streams[1], "/grpc.KlaytnAPI/SubscribeLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &klaytnAPISubscribeLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KlaytnAPI_SubscribeLogsClient interface {
+	Recv() (*Log, error)
+	grpc.ClientStream
+}
+
+type klaytnAPISubscribeLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *klaytnAPISubscribeLogsClient) Recv() (*Log, error) {
+	m := new(Log)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KlaytnAPIServer is the server API for KlaytnAPI service.
+type KlaytnAPIServer interface {
+	GetBlockNumber(context.Context, *Empty) (*BlockNumber, error)
+	GetHeader(context.Context, *BlockRequest) (*Header, error)
+	GetBlock(context.Context, *BlockRequest) (*Block, error)
+	GetBlockReceipts(context.Context, *BlockRequest) (*Receipts, error)
+	GetTransaction(context.Context, *TransactionRequest) (*Transaction, error)
+	GetTransactionReceipt(context.Context, *TransactionRequest) (*Receipt, error)
+	SendRawTransaction(context.Context, *RawTransaction) (*TransactionHash, error)
+	GetLogs(context.Context, *LogFilter) (*Logs, error)
+	GetAccount(context.Context, *AccountRequest) (*Account, error)
+	GetCode(context.Context, *AccountRequest) (*Code, error)
+	GetStorageAt(context.Context, *StorageRequest) (*StorageValue, error)
+	SubscribeNewHeads(*Empty, KlaytnAPI_SubscribeNewHeadsServer) error
+	SubscribeLogs(*LogFilter, KlaytnAPI_SubscribeLogsServer) error
+}
+
+// UnimplementedKlaytnAPIServer can be embedded to have forward compatible implementations.
+type UnimplementedKlaytnAPIServer struct {
+}
+
+func (*UnimplementedKlaytnAPIServer) GetBlockNumber(ctx context.Context, req *Empty) (*BlockNumber, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockNumber not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetHeader(ctx context.Context, req *BlockRequest) (*Header, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeader not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetBlock(ctx context.Context, req *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetBlockReceipts(ctx context.Context, req *BlockRequest) (*Receipts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockReceipts not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetTransaction(ctx context.Context, req *TransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetTransactionReceipt(ctx context.Context, req *TransactionRequest) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionReceipt not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) SendRawTransaction(ctx context.Context, req *RawTransaction) (*TransactionHash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendRawTransaction not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetLogs(ctx context.Context, req *LogFilter) (*Logs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetAccount(ctx context.Context, req *AccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetCode(ctx context.Context, req *AccountRequest) (*Code, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCode not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) GetStorageAt(ctx context.Context, req *StorageRequest) (*StorageValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageAt not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) SubscribeNewHeads(req *Empty, srv KlaytnAPI_SubscribeNewHeadsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeNewHeads not implemented")
+}
+func (*UnimplementedKlaytnAPIServer) SubscribeLogs(req *LogFilter, srv KlaytnAPI_SubscribeLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeLogs not implemented")
+}
+
+func RegisterKlaytnAPIServer(s *grpc.Server, srv KlaytnAPIServer) {
+	s.RegisterService(&_KlaytnAPI_serviceDesc, srv)
+}
+
+func _KlaytnAPI_GetBlockNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetBlockNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetBlockNumber",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetBlockNumber(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetHeader(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetBlockReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetBlockReceipts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetBlockReceipts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetBlockReceipts(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetTransaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetTransactionReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetTransactionReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetTransactionReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetTransactionReceipt(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_SendRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawTransaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).SendRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/SendRawTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).SendRawTransaction(ctx, req.(*RawTransaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetLogs(ctx, req.(*LogFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetAccount(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetCode(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_GetStorageAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KlaytnAPIServer).GetStorageAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.KlaytnAPI/GetStorageAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KlaytnAPIServer).GetStorageAt(ctx, req.(*StorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KlaytnAPI_SubscribeNewHeads_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KlaytnAPIServer).SubscribeNewHeads(m, &klaytnAPISubscribeNewHeadsServer{stream})
+}
+
+type KlaytnAPI_SubscribeNewHeadsServer interface {
+	Send(*Header) error
+	grpc.ServerStream
+}
+
+type klaytnAPISubscribeNewHeadsServer struct {
+	grpc.ServerStream
+}
+
+func (x *klaytnAPISubscribeNewHeadsServer) Send(m *Header) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _KlaytnAPI_SubscribeLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KlaytnAPIServer).SubscribeLogs(m, &klaytnAPISubscribeLogsServer{stream})
+}
+
+type KlaytnAPI_SubscribeLogsServer interface {
+	Send(*Log) error
+	grpc.ServerStream
+}
+
+type klaytnAPISubscribeLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *klaytnAPISubscribeLogsServer) Send(m *Log) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _KlaytnAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc.KlaytnAPI",
+	HandlerType: (*KlaytnAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlockNumber",
+			Handler:    _KlaytnAPI_GetBlockNumber_Handler,
+		},
+		{
+			MethodName: "GetHeader",
+			Handler:    _KlaytnAPI_GetHeader_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _KlaytnAPI_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetBlockReceipts",
+			Handler:    _KlaytnAPI_GetBlockReceipts_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _KlaytnAPI_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransactionReceipt",
+			Handler:    _KlaytnAPI_GetTransactionReceipt_Handler,
+		},
+		{
+			MethodName: "SendRawTransaction",
+			Handler:    _KlaytnAPI_SendRawTransaction_Handler,
+		},
+		{
+			MethodName: "GetLogs",
+			Handler:    _KlaytnAPI_GetLogs_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _KlaytnAPI_GetAccount_Handler,
+		},
+		{
+			MethodName: "GetCode",
+			Handler:    _KlaytnAPI_GetCode_Handler,
+		},
+		{
+			MethodName: "GetStorageAt",
+			Handler:    _KlaytnAPI_GetStorageAt_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewHeads",
+			Handler:       _KlaytnAPI_SubscribeNewHeads_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeLogs",
+			Handler:       _KlaytnAPI_SubscribeLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "klaytn_api.proto",
+}
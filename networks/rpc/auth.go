@@ -0,0 +1,295 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// allNamespaces is the namespace allowing a client to call all namespaces.
+	allNamespaces = "*"
+	// jwtClockSkew is the allowed difference between the clocks of the token
+	// issuer and the node.
+	jwtClockSkew = 5 * time.Second
+	// jwtMaxAge is the maximum age of a JWT without the expiration time, which
+	// is measured from its issued time.
+	jwtMaxAge = 60 * time.Second
+)
+
+var (
+	errMissingToken = errors.New("missing bearer token")
+	errInvalidToken = errors.New("invalid bearer token")
+	errExpiredToken = errors.New("expired bearer token")
+)
+
+// currentAuthenticator is the authenticator applied to the HTTP, WebSocket and
+// gRPC servers.
+var currentAuthenticator atomic.Value
+
+// authNamespacesKey is the context key of the namespaces allowed to the
+// authenticated client.
+type authNamespacesKey struct{}
+
+// authIdentityKey is the context key of the identity of the authenticated
+// client, which is derived from its token.
+type authIdentityKey struct{}
+
+// Authenticator verifies the bearer tokens of the requests to the RPC
+// endpoints. A token is either one of the static tokens or a JWT signed by
+// the shared secret with HS256, and it gives access to a set of namespaces.
+type Authenticator struct {
+	tokens    map[string][]string // static token -> namespaces allowed by the token
+	jwtSecret []byte              // shared secret of JWT, JWT is not accepted if empty
+}
+
+// jwtClaims are the claims of a JWT checked by the authenticator. A token
+// should have either the expiration time or the issued time, and it is valid
+// for jwtMaxAge from the issued time if it doesn't have the expiration time.
+// The namespaces claim has the namespaces allowed by the token, and all
+// namespaces are allowed if it is absent.
+type jwtClaims struct {
+	ExpiresAt  *int64    `json:"exp"`
+	NotBefore  *int64    `json:"nbf"`
+	IssuedAt   *int64    `json:"iat"`
+	Namespaces *[]string `json:"namespaces"`
+}
+
+// NewAuthenticator creates an authenticator accepting the given static tokens
+// and the JWTs signed by the given secret. The namespace "*" allows all
+// namespaces.
+func NewAuthenticator(tokens map[string][]string, jwtSecret []byte) (*Authenticator, error) {
+	if len(tokens) == 0 && len(jwtSecret) == 0 {
+		return nil, errors.New("no token or JWT secret is given")
+	}
+	auth := &Authenticator{tokens: make(map[string][]string, len(tokens)), jwtSecret: jwtSecret}
+	for token, namespaces := range tokens {
+		if token == "" || len(namespaces) == 0 {
+			return nil, fmt.Errorf("invalid token with namespaces %v", namespaces)
+		}
+		auth.tokens[token] = normalizeNamespaces(namespaces)
+	}
+	return auth, nil
+}
+
+// LoadAuthenticator creates an authenticator from the token file and the JWT
+// secret file. Each line of the token file has a token and the comma-separated
+// namespaces allowed by the token, e.g. "mytoken admin,debug", and the lines
+// starting with # are ignored. The JWT secret file has a hex-encoded secret.
+// Either of the files can be omitted.
+func LoadAuthenticator(tokenFile, jwtSecretFile string) (*Authenticator, error) {
+	tokens := make(map[string][]string)
+	if tokenFile != "" {
+		f, err := os.Open(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			fields := strings.Fields(text)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("invalid token at line %d of %s", line, tokenFile)
+			}
+			if _, exist := tokens[fields[0]]; exist {
+				return nil, fmt.Errorf("duplicated token at line %d of %s", line, tokenFile)
+			}
+			tokens[fields[0]] = strings.Split(fields[1], ",")
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	var jwtSecret []byte
+	if jwtSecretFile != "" {
+		data, err := ioutil.ReadFile(jwtSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		jwtSecret, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT secret in %s: %v", jwtSecretFile, err)
+		}
+		if len(jwtSecret) < 32 {
+			return nil, fmt.Errorf("JWT secret in %s is shorter than 32 bytes", jwtSecretFile)
+		}
+	}
+	return NewAuthenticator(tokens, jwtSecret)
+}
+
+// SetAuthenticator sets the authenticator applied to the HTTP, WebSocket and
+// gRPC servers. The requests are not authenticated if it is nil. The IPC and
+// in-process servers are never authenticated.
+func SetAuthenticator(auth *Authenticator) {
+	currentAuthenticator.Store(auth)
+}
+
+// loadAuthenticator returns the current authenticator, or nil if it isn't set.
+func loadAuthenticator() *Authenticator {
+	auth, _ := currentAuthenticator.Load().(*Authenticator)
+	return auth
+}
+
+// normalizeNamespaces converts the eth namespace into the klay namespace as the
+// requests are served.
+func normalizeNamespaces(namespaces []string) []string {
+	normalized := make([]string, len(namespaces))
+	for i, namespace := range namespaces {
+		if namespace == "eth" {
+			namespace = "klay"
+		}
+		normalized[i] = namespace
+	}
+	return normalized
+}
+
+// AuthenticateContext verifies the given value of the Authorization header
+// with the current authenticator, and returns a context carrying the
+// namespaces allowed to the client and its identity. The context is returned
+// as it is if no authenticator is set.
+func AuthenticateContext(ctx context.Context, authorization string) (context.Context, error) {
+	auth := loadAuthenticator()
+	if auth == nil {
+		return ctx, nil
+	}
+	namespaces, err := auth.authenticate(authorization)
+	if err != nil {
+		return ctx, err
+	}
+	ctx = context.WithValue(ctx, authNamespacesKey{}, namespaces)
+	return context.WithValue(ctx, authIdentityKey{}, tokenIdentity(authorization)), nil
+}
+
+// tokenIdentity returns the identity of the client having the given value of
+// the Authorization header, which doesn't reveal the token.
+func tokenIdentity(authorization string) string {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(authorization[len("Bearer "):])))
+	return "token:" + hex.EncodeToString(hash[:8])
+}
+
+// authenticate verifies the given value of the Authorization header and
+// returns the namespaces allowed to the client.
+func (a *Authenticator) authenticate(authorization string) ([]string, error) {
+	const prefix = "Bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return nil, errMissingToken
+	}
+	token := strings.TrimSpace(authorization[len(prefix):])
+
+	// All static tokens are compared to take constant time regardless of the token
+	var namespaces []string
+	for candidate, allowed := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			namespaces = allowed
+		}
+	}
+	if namespaces != nil {
+		return namespaces, nil
+	}
+	if len(a.jwtSecret) == 0 || strings.Count(token, ".") != 2 {
+		return nil, errInvalidToken
+	}
+	return a.verifyJWT(token)
+}
+
+// verifyJWT verifies the signature and the claims of a JWT signed with HS256,
+// and returns the namespaces allowed by the token.
+func (a *Authenticator) verifyJWT(token string) ([]string, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if data, err := base64.RawURLEncoding.DecodeString(parts[0]); err != nil || json.Unmarshal(data, &header) != nil || header.Alg != "HS256" {
+		return nil, errInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errInvalidToken
+	}
+
+	var claims jwtClaims
+	if data, err := base64.RawURLEncoding.DecodeString(parts[1]); err != nil || json.Unmarshal(data, &claims) != nil {
+		return nil, errInvalidToken
+	}
+	now := time.Now()
+	if claims.ExpiresAt == nil && claims.IssuedAt == nil {
+		return nil, errInvalidToken
+	}
+	if claims.ExpiresAt != nil && now.After(time.Unix(*claims.ExpiresAt, 0).Add(jwtClockSkew)) {
+		return nil, errExpiredToken
+	}
+	if claims.ExpiresAt == nil && now.After(time.Unix(*claims.IssuedAt, 0).Add(jwtMaxAge+jwtClockSkew)) {
+		return nil, errExpiredToken
+	}
+	if claims.NotBefore != nil && now.Add(jwtClockSkew).Before(time.Unix(*claims.NotBefore, 0)) {
+		return nil, errInvalidToken
+	}
+	if claims.IssuedAt != nil && now.Add(jwtClockSkew).Before(time.Unix(*claims.IssuedAt, 0)) {
+		return nil, errInvalidToken
+	}
+	if claims.Namespaces == nil {
+		return []string{allNamespaces}, nil
+	}
+	return normalizeNamespaces(*claims.Namespaces), nil
+}
+
+// authorized returns true if the method is allowed to the client of the
+// request.
+func authorized(ctx context.Context, method string) bool {
+	return NamespaceAuthorized(ctx, strings.SplitN(method, serviceMethodSeparator, 2)[0])
+}
+
+// NamespaceAuthorized returns true if the namespace is allowed to the client
+// of the request. The requests not authenticated by the transport are always
+// allowed.
+func NamespaceAuthorized(ctx context.Context, namespace string) bool {
+	namespaces, ok := ctx.Value(authNamespacesKey{}).([]string)
+	if !ok {
+		return true
+	}
+	for _, allowed := range namespaces {
+		if allowed == allNamespaces || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
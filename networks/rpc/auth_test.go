@@ -0,0 +1,216 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signJWT returns a JWT of the given claims signed with HS256.
+func signJWT(secret []byte, claims map[string]interface{}) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, _ := json.Marshal(claims)
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticator(t *testing.T) {
+	_, err := NewAuthenticator(nil, nil)
+	assert.Error(t, err)
+	_, err = NewAuthenticator(map[string][]string{"token": nil}, nil)
+	assert.Error(t, err)
+
+	auth, err := NewAuthenticator(map[string][]string{"token1": {"eth", "net"}, "token2": {"*"}}, testJWTSecret)
+	assert.NoError(t, err)
+
+	// The eth namespace is regarded as the klay namespace
+	namespaces, err := auth.authenticate("Bearer token1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"klay", "net"}, namespaces)
+	namespaces, err = auth.authenticate("bearer token2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*"}, namespaces)
+
+	_, err = auth.authenticate("")
+	assert.Equal(t, errMissingToken, err)
+	_, err = auth.authenticate("Basic token1")
+	assert.Equal(t, errMissingToken, err)
+	_, err = auth.authenticate("Bearer token3")
+	assert.Equal(t, errInvalidToken, err)
+
+	now := time.Now().Unix()
+	namespaces, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"iat": now, "exp": now + 60}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*"}, namespaces)
+	namespaces, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"iat": now, "namespaces": []string{"admin"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, namespaces)
+
+	_, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"exp": now - 60}))
+	assert.Equal(t, errExpiredToken, err)
+	_, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"exp": now + 60, "nbf": now + 60}))
+	assert.Equal(t, errInvalidToken, err)
+	_, err = auth.authenticate("Bearer " + signJWT([]byte("wrong secret"), map[string]interface{}{"exp": now + 60}))
+	assert.Equal(t, errInvalidToken, err)
+
+	// A token without the expiration time is valid for a while from its issued time,
+	// and a token without both of them is not accepted
+	_, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"iat": now - 600}))
+	assert.Equal(t, errExpiredToken, err)
+	_, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"namespaces": []string{"admin"}}))
+	assert.Equal(t, errInvalidToken, err)
+
+	// JWT is not accepted without the secret
+	auth, err = NewAuthenticator(map[string][]string{"token1": {"klay"}}, nil)
+	assert.NoError(t, err)
+	_, err = auth.authenticate("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"exp": now + 60}))
+	assert.Equal(t, errInvalidToken, err)
+}
+
+func TestLoadAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-rpc-auth")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile, secretFile := filepath.Join(dir, "tokens"), filepath.Join(dir, "jwtsecret")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("# admin token\ntoken1 admin,debug\n\ntoken2 *\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(secretFile, []byte("0x"+hex.EncodeToString(testJWTSecret)+"\n"), 0600))
+
+	auth, err := LoadAuthenticator(tokenFile, secretFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"token1": {"admin", "debug"}, "token2": {"*"}}, auth.tokens)
+	assert.Equal(t, testJWTSecret, auth.jwtSecret)
+
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("token1\n"), 0600))
+	_, err = LoadAuthenticator(tokenFile, "")
+	assert.Error(t, err)
+	assert.NoError(t, ioutil.WriteFile(secretFile, []byte("0011"), 0600))
+	_, err = LoadAuthenticator("", secretFile)
+	assert.Error(t, err)
+}
+
+// authTransport is an http.RoundTripper setting the Authorization header.
+type authTransport struct {
+	authorization string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", t.authorization)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestHTTPAuth tests that the HTTP requests are rejected without a valid token,
+// and that a token only gives access to its namespaces.
+func TestHTTPAuth(t *testing.T) {
+	srv := newTestServer("service", new(Service))
+	assert.NoError(t, srv.RegisterName("other", new(Service)))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	auth, err := NewAuthenticator(map[string][]string{"token": {"service"}}, testJWTSecret)
+	assert.NoError(t, err)
+	SetAuthenticator(auth)
+	defer SetAuthenticator(nil)
+
+	dial := func(authorization string) *Client {
+		client, err := DialHTTPWithClient(httpsrv.URL, &http.Client{Transport: &authTransport{authorization}})
+		assert.NoError(t, err)
+		return client
+	}
+	var result echoResult
+
+	client := dial("")
+	err = client.Call(&result, "service_echo", "hello", 1)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "401"))
+	client.Close()
+
+	client = dial("Bearer token")
+	assert.NoError(t, client.Call(&result, "service_echo", "hello", 1))
+	err = client.Call(&result, "other_echo", "hello", 1)
+	assert.Error(t, err)
+	assert.Equal(t, -32601, err.(Error).ErrorCode())
+	client.Close()
+
+	client = dial("Bearer " + signJWT(testJWTSecret, map[string]interface{}{"exp": time.Now().Unix() + 60, "namespaces": []string{"other"}}))
+	assert.NoError(t, client.Call(&result, "other_echo", "hello", 1))
+	assert.Error(t, client.Call(&result, "service_echo", "hello", 1))
+	client.Close()
+}
+
+// TestWebsocketAuth tests that the websocket handshake is rejected without a
+// valid token, and that a token only gives access to its namespaces.
+func TestWebsocketAuth(t *testing.T) {
+	srv := newTestServer("service", new(Service))
+	assert.NoError(t, srv.RegisterName("other", new(Service)))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+	wsAddr := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+	auth, err := NewAuthenticator(map[string][]string{"token": {"service"}}, nil)
+	assert.NoError(t, err)
+	SetAuthenticator(auth)
+	defer SetAuthenticator(nil)
+
+	dial := func(authorization string) (*websocket.Conn, error) {
+		config, err := websocket.NewConfig(wsAddr, httpsrv.URL)
+		assert.NoError(t, err)
+		config.Header.Set("Authorization", authorization)
+		return websocket.DialConfig(config)
+	}
+
+	_, err = dial("Bearer wrong")
+	assert.Error(t, err)
+	_, err = DialWebsocket(context.Background(), wsAddr, "")
+	assert.Error(t, err)
+
+	conn, err := dial("Bearer token")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	var resp jsonSuccessResponse
+	assert.NoError(t, websocket.JSON.Send(conn, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "service_echo", "params": []interface{}{"hello", 1}}))
+	assert.NoError(t, websocket.JSON.Receive(conn, &resp))
+	assert.NotNil(t, resp.Result)
+
+	var errResp jsonErrResponse
+	assert.NoError(t, websocket.JSON.Send(conn, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "other_echo", "params": []interface{}{"hello", 1}}))
+	assert.NoError(t, websocket.JSON.Receive(conn, &errResp))
+	assert.Equal(t, -32601, errResp.Error.Code)
+}
@@ -16,7 +16,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // request is for an unknown service
 type methodNotFoundError struct {
@@ -30,6 +33,61 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("The method %s%s%s does not exist/is not available", e.service, serviceMethodSeparator, e.method)
 }
 
+// request is for a method not allowed by the method policy
+type methodNotAllowedError struct{ method string }
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32601 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("The method %s is not allowed", e.method)
+}
+
+// request exceeds the concurrency limit of the method
+type methodBusyError struct {
+	method string
+	limit  int
+}
+
+func (e *methodBusyError) ErrorCode() int { return -32005 }
+
+func (e *methodBusyError) Error() string {
+	return fmt.Sprintf("Maximum %d concurrent requests are allowed for the method %s", e.limit, e.method)
+}
+
+// request exceeds the rate limit of the client
+type rateLimitedError struct {
+	method     string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("Rate limit exceeded for the method %s, retry after %v", e.method, e.retryAfter)
+}
+
+// subscription request exceeds the maximum subscriptions of the connection
+type subscriptionLimitError struct{ limit int32 }
+
+func (e *subscriptionLimitError) ErrorCode() int { return -32005 }
+
+func (e *subscriptionLimitError) Error() string {
+	return fmt.Sprintf("Maximum %d subscriptions are allowed for a websocket connection. "+
+		"The limit can be updated with 'admin_setMaxSubscriptionPerWSConn' API", e.limit)
+}
+
+// result of a request is larger than the response size limit
+type responseTooLargeError struct {
+	size  int
+	limit int
+}
+
+func (e *responseTooLargeError) ErrorCode() int { return -32003 }
+
+func (e *responseTooLargeError) Error() string {
+	return fmt.Sprintf("Response size %d exceeds the limit %d", e.size, e.limit)
+}
+
 // received message isn't a valid request
 type invalidRequestError struct{ message string }
 
@@ -203,10 +203,15 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), code)
 		return
 	}
+	ctx, err := AuthenticateContext(r.Context(), r.Header.Get("Authorization"))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
 	// All checks passed, create a codec that reads direct from the request body
 	// untilEOF and writes the response to w and order the server to process a
 	// single request.
-	ctx := r.Context()
 	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
@@ -235,11 +240,15 @@ func (srv *Server) HandleFastHTTP(requestCtx *fasthttp.RequestCtx) {
 		fmt.Fprintf(requestCtx, err.Error())
 		return
 	}
+	ctx, err := AuthenticateContext(requestCtx, string(r.Header.Peek("Authorization")))
+	if err != nil {
+		requestCtx.Error(err.Error(), fasthttp.StatusUnauthorized)
+		w.Header.Set("WWW-Authenticate", "Bearer")
+		return
+	}
 	// All checks passed, create a codec that reads direct from the request body
 	// untilEOF and writes the response to w and order the server to process a
 	// single request.
-	var ctx context.Context
-	ctx = requestCtx
 	ctx = context.WithValue(ctx, "remote", requestCtx.RemoteAddr().String())
 	ctx = context.WithValue(ctx, "scheme", string(requestCtx.URI().Scheme()))
 	ctx = context.WithValue(ctx, "local", requestCtx.LocalAddr().String())
@@ -0,0 +1,229 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// currentMethodPolicy is the method policy applied to all RPC servers.
+var currentMethodPolicy atomic.Value
+
+// methodPolicy has the access control and the concurrency limits of the RPC
+// methods. A method pattern is either a full method name like klay_getLogs or
+// a namespace wildcard like debug_*.
+type methodPolicy struct {
+	allow  []string       // patterns of the allowed methods, all methods are allowed if empty
+	deny   []string       // patterns of the denied methods, which take precedence over allow
+	limits map[string]int // maximum number of concurrent requests of each method pattern
+
+	semaphores sync.Map // method -> chan struct{} limiting the concurrent requests
+}
+
+// SetMethodPolicy replaces the method policy applied to all RPC servers. The
+// limits are applied to each method matched by a pattern, and a full method
+// name takes precedence over a wildcard. The eth namespace is regarded as the
+// klay namespace as the requests are served.
+func SetMethodPolicy(allow, deny []string, limits map[string]int) error {
+	policy := &methodPolicy{limits: make(map[string]int, len(limits))}
+	for _, pattern := range allow {
+		pattern, err := normalizeMethodPattern(pattern)
+		if err != nil {
+			return err
+		}
+		policy.allow = append(policy.allow, pattern)
+	}
+	for _, pattern := range deny {
+		pattern, err := normalizeMethodPattern(pattern)
+		if err != nil {
+			return err
+		}
+		policy.deny = append(policy.deny, pattern)
+	}
+	for pattern, limit := range limits {
+		normalized, err := normalizeMethodPattern(pattern)
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return fmt.Errorf("invalid concurrency limit %d of %s", limit, pattern)
+		}
+		policy.limits[normalized] = limit
+	}
+	currentMethodPolicy.Store(policy)
+	return nil
+}
+
+// loadMethodPolicy returns the current method policy, or nil if it isn't set.
+func loadMethodPolicy() *methodPolicy {
+	policy, _ := currentMethodPolicy.Load().(*methodPolicy)
+	return policy
+}
+
+// normalizeMethodPattern validates the given method pattern, and converts the
+// eth namespace into the klay namespace.
+func normalizeMethodPattern(pattern string) (string, error) {
+	elems := strings.Split(pattern, serviceMethodSeparator)
+	if len(elems) != 2 || elems[0] == "" || elems[1] == "" || (strings.Contains(elems[1], "*") && elems[1] != "*") {
+		return "", fmt.Errorf("invalid method pattern %q", pattern)
+	}
+	if elems[0] == "eth" {
+		elems[0] = "klay"
+	}
+	return elems[0] + serviceMethodSeparator + elems[1], nil
+}
+
+// matchMethod returns true if the method matches the given pattern.
+func matchMethod(pattern, method string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(method, pattern[:len(pattern)-1])
+	}
+	return pattern == method
+}
+
+// allowed returns true if the method is allowed by the policy.
+func (p *methodPolicy) allowed(method string) bool {
+	if p == nil {
+		return true
+	}
+	for _, pattern := range p.deny {
+		if matchMethod(pattern, method) {
+			return false
+		}
+	}
+	if len(p.allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.allow {
+		if matchMethod(pattern, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// limit returns the concurrency limit of the method, or 0 if there is no limit.
+func (p *methodPolicy) limit(method string) int {
+	if limit, ok := p.limits[method]; ok {
+		return limit
+	}
+	service := strings.SplitN(method, serviceMethodSeparator, 2)[0]
+	return p.limits[service+serviceMethodSeparator+"*"]
+}
+
+// acquire takes a slot of the concurrent requests of the method. It returns
+// a function releasing the slot, or false with the limit if no slot is left.
+func (p *methodPolicy) acquire(method string) (func(), int, bool) {
+	if p == nil {
+		return func() {}, 0, true
+	}
+	limit := p.limit(method)
+	if limit == 0 {
+		return func() {}, 0, true
+	}
+	sem, _ := p.semaphores.LoadOrStore(method, make(chan struct{}, limit))
+	select {
+	case sem.(chan struct{}) <- struct{}{}:
+		return func() { <-sem.(chan struct{}) }, limit, true
+	default:
+		return nil, limit, false
+	}
+}
+
+// methodCall is a call of a method admitted by the method policy and the rate
+// limit, which holds a slot of the concurrent requests of the method.
+type methodCall struct {
+	metrics *methodMetrics
+	release func()
+	start   time.Time
+}
+
+// beginMethodCall admits a call of the method from the client of the request.
+// It returns an error if the method isn't allowed to the client, or the call
+// exceeds the rate limit of the client or the concurrency limit of the method.
+func beginMethodCall(ctx context.Context, method string) (*methodCall, Error) {
+	metrics := getMethodMetrics(method)
+	policy := loadMethodPolicy()
+	if !policy.allowed(method) || !authorized(ctx, method) {
+		metrics.rejected.Inc(1)
+		return nil, &methodNotAllowedError{method}
+	}
+	if retryAfter, ok := loadRateLimiter().take(clientIdentity(ctx), method, time.Now()); !ok {
+		rpcThrottledRequestsCounter.Inc(1)
+		metrics.throttled.Inc(1)
+		return nil, &rateLimitedError{method, retryAfter.Round(time.Millisecond)}
+	}
+	release, limit, ok := policy.acquire(method)
+	if !ok {
+		metrics.rejected.Inc(1)
+		return nil, &methodBusyError{method, limit}
+	}
+	return &methodCall{metrics: metrics, release: release, start: time.Now()}, nil
+}
+
+// end releases the slot of the call and records the metrics of the method. It
+// returns the elapsed time of the call.
+func (c *methodCall) end(succeeded bool) time.Duration {
+	c.release()
+	elapsed := time.Since(c.start)
+	c.metrics.duration.Update(elapsed)
+	if !succeeded {
+		c.metrics.errors.Inc(1)
+	}
+	return elapsed
+}
+
+// BeginCall admits a call of the method served outside of the RPC servers,
+// like the typed gRPC API, with the same method policy, rate limit and
+// concurrency limit as the RPC servers. The returned error is an Error with
+// the code of the rejection. The call must be ended by the returned function
+// with its result to release the slot and to record the metrics of the method.
+func BeginCall(ctx context.Context, method string) (func(err error), error) {
+	call, err := beginMethodCall(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	return func(err error) {
+		if elapsed := call.end(err == nil); SlowRequestThreshold > 0 && elapsed >= SlowRequestThreshold {
+			logger.Warn("Slow RPC request", "method", method, "elapsed", elapsed)
+		}
+	}, nil
+}
+
+// slowRequestParams returns the encoded params of a request to be logged with a
+// slow request, truncated to slowRequestParamsLimit bytes. The params of the
+// personal methods and the signing methods are redacted, since they may have
+// secrets like passphrases and private keys.
+func slowRequestParams(method string, params interface{}) string {
+	raw, ok := params.(json.RawMessage)
+	if !ok || len(raw) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(method, "personal"+serviceMethodSeparator) || strings.Contains(method, serviceMethodSeparator+"sign") {
+		return "<redacted>"
+	}
+	if len(raw) > slowRequestParamsLimit {
+		return string(raw[:slowRequestParamsLimit]) + "..."
+	}
+	return string(raw)
+}
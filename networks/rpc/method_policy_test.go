@@ -0,0 +1,117 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodPolicy(t *testing.T) {
+	assert.Error(t, SetMethodPolicy([]string{"klay"}, nil, nil))
+	assert.Error(t, SetMethodPolicy(nil, []string{"klay_get*"}, nil))
+	assert.Error(t, SetMethodPolicy(nil, nil, map[string]int{"klay_call": 0}))
+
+	assert.NoError(t, SetMethodPolicy([]string{"eth_*", "net_version"}, []string{"klay_getLogs"}, map[string]int{"klay_*": 2, "klay_call": 1}))
+	defer SetMethodPolicy(nil, nil, nil)
+	policy := loadMethodPolicy()
+
+	// The eth namespace is regarded as the klay namespace, and deny takes precedence
+	assert.True(t, policy.allowed("klay_call"))
+	assert.True(t, policy.allowed("net_version"))
+	assert.False(t, policy.allowed("net_peerCount"))
+	assert.False(t, policy.allowed("klay_getLogs"))
+	assert.False(t, policy.allowed("debug_traceCall"))
+
+	// A full method name takes precedence over a wildcard
+	assert.Equal(t, 1, policy.limit("klay_call"))
+	assert.Equal(t, 2, policy.limit("klay_estimateGas"))
+	assert.Equal(t, 0, policy.limit("net_version"))
+
+	release, _, ok := policy.acquire("klay_call")
+	assert.True(t, ok)
+	_, limit, ok := policy.acquire("klay_call")
+	assert.False(t, ok)
+	assert.Equal(t, 1, limit)
+	release()
+	_, _, ok = policy.acquire("klay_call")
+	assert.True(t, ok)
+
+	// The limit is applied to each method matched by a wildcard
+	_, _, ok = policy.acquire("klay_estimateGas")
+	assert.True(t, ok)
+	_, _, ok = policy.acquire("klay_getBalance")
+	assert.True(t, ok)
+}
+
+// TestServerMethodPolicy tests that the method policy is enforced when the
+// requests are handled by the server.
+func TestServerMethodPolicy(t *testing.T) {
+	server := newTestServer("policy", new(Service))
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	assert.NoError(t, SetMethodPolicy(nil, []string{"policy_echo"}, map[string]int{"policy_sleep": 1}))
+	defer SetMethodPolicy(nil, nil, nil)
+
+	var result Result
+	err := client.Call(&result, "policy_echo", "hello", 10, &Args{"world"})
+	assert.Error(t, err)
+	assert.Equal(t, -32601, err.(Error).ErrorCode())
+
+	// The second request is rejected while the first one is running
+	done := make(chan error)
+	go func() {
+		done <- client.Call(nil, "policy_sleep", 200*time.Millisecond)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	err = client.Call(nil, "policy_sleep", time.Millisecond)
+	assert.Error(t, err)
+	assert.Equal(t, -32005, err.(Error).ErrorCode())
+	assert.NoError(t, <-done)
+	assert.NoError(t, client.Call(nil, "policy_sleep", time.Millisecond))
+
+	// The metrics are recorded for each method
+	metrics := getMethodMetrics("policy_sleep")
+	assert.Equal(t, int64(2), metrics.duration.Count())
+	assert.Equal(t, int64(1), metrics.rejected.Count())
+	assert.Equal(t, int64(1), getMethodMetrics("policy_echo").rejected.Count())
+
+	assert.NoError(t, client.Call(nil, "policy_rets"))
+	assert.Equal(t, int64(0), getMethodMetrics("policy_rets").errors.Count())
+	assert.Error(t, client.Call(nil, "policy_echo"))
+}
+
+// Tests that the params of a slow request are truncated to be logged, and the
+// params of the methods handling secrets are redacted.
+func TestSlowRequestParams(t *testing.T) {
+	assert.Equal(t, `["0x1234",true]`, slowRequestParams("klay_getBlockByNumber", json.RawMessage(`["0x1234",true]`)))
+	assert.Equal(t, "", slowRequestParams("klay_blockNumber", nil))
+	assert.Equal(t, "", slowRequestParams("klay_blockNumber", json.RawMessage{}))
+
+	long := json.RawMessage(`["` + strings.Repeat("a", slowRequestParamsLimit) + `"]`)
+	assert.Equal(t, string(long[:slowRequestParamsLimit])+"...", slowRequestParams("klay_call", long))
+
+	for _, method := range []string{"personal_unlockAccount", "personal_importRawKey", "klay_sign", "klay_signTransaction", "eth_signTypedData"} {
+		assert.Equal(t, "<redacted>", slowRequestParams(method, json.RawMessage(`["0x1234","passphrase"]`)), method)
+	}
+}
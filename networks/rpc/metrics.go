@@ -1,14 +1,45 @@
 package rpc
 
-import "github.com/rcrowley/go-metrics"
+import (
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
 
 var (
-	rpcTotalRequestsCounter    = metrics.NewRegisteredCounter("rpc/counts/total", nil)
-	rpcSuccessResponsesCounter = metrics.NewRegisteredCounter("rpc/counts/success", nil)
-	rpcErrorResponsesCounter   = metrics.NewRegisteredCounter("rpc/counts/errors", nil)
-	rpcPendingRequestsCount    = metrics.NewRegisteredCounter("rpc/counts/pending", nil)
+	rpcTotalRequestsCounter     = metrics.NewRegisteredCounter("rpc/counts/total", nil)
+	rpcSuccessResponsesCounter  = metrics.NewRegisteredCounter("rpc/counts/success", nil)
+	rpcErrorResponsesCounter    = metrics.NewRegisteredCounter("rpc/counts/errors", nil)
+	rpcPendingRequestsCount     = metrics.NewRegisteredCounter("rpc/counts/pending", nil)
+	rpcThrottledRequestsCounter = metrics.NewRegisteredCounter("rpc/counts/throttled", nil)
 
 	wsSubscriptionReqCounter   = metrics.NewRegisteredCounter("ws/counts/subscription/request", nil)
 	wsUnsubscriptionReqCounter = metrics.NewRegisteredCounter("ws/counts/unsubscription/request", nil)
 	wsConnCounter              = metrics.NewRegisteredCounter("ws/counts/connections/total", nil)
+
+	// rpcMethodMetrics caches the metrics of each RPC method
+	rpcMethodMetrics sync.Map
 )
+
+// methodMetrics has the metrics of an RPC method.
+type methodMetrics struct {
+	duration  metrics.Timer   // latency histogram and the number of the handled requests
+	errors    metrics.Counter // number of the requests responded with an error
+	rejected  metrics.Counter // number of the requests rejected by the method policy
+	throttled metrics.Counter // number of the requests rejected by the rate limit
+}
+
+// getMethodMetrics returns the metrics of the given method, registering them
+// at the first request of the method.
+func getMethodMetrics(method string) *methodMetrics {
+	if m, ok := rpcMethodMetrics.Load(method); ok {
+		return m.(*methodMetrics)
+	}
+	m, _ := rpcMethodMetrics.LoadOrStore(method, &methodMetrics{
+		duration:  metrics.GetOrRegisterTimer("rpc/methods/"+method+"/duration", nil),
+		errors:    metrics.GetOrRegisterCounter("rpc/methods/"+method+"/errors", nil),
+		rejected:  metrics.GetOrRegisterCounter("rpc/methods/"+method+"/rejected", nil),
+		throttled: metrics.GetOrRegisterCounter("rpc/methods/"+method+"/throttled", nil),
+	})
+	return m.(*methodMetrics)
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rateLimitSweepInterval is the interval to drop the buckets of the idle clients.
+const rateLimitSweepInterval = time.Minute
+
+// currentRateLimiter is the rate limiter applied to all RPC servers.
+var currentRateLimiter atomic.Value
+
+// rateLimiter limits the requests of each client with a token bucket. A client
+// is identified by its bearer token if it is authenticated, or by its IP
+// address otherwise. A request takes the tokens as many as the cost of its
+// method, which is 1 by default.
+type rateLimiter struct {
+	rate  float64            // tokens refilled to a bucket per second
+	burst float64            // capacity of a bucket
+	costs map[string]float64 // method pattern -> tokens taken by a request
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket // client -> bucket of the client
+	lastSweep time.Time
+}
+
+// tokenBucket has the tokens left to a client.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time // time when the tokens are refilled last
+}
+
+// SetRateLimit replaces the rate limiter applied to all RPC servers. Each
+// client can send rate requests per second on average and burst requests at
+// once. The costs are the number of the requests counted for each method
+// pattern like SetMethodPolicy. The rate limit is disabled if rate is 0.
+func SetRateLimit(rate float64, burst int, costs map[string]int) error {
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("invalid rate limit %v", rate)
+	}
+	if rate == 0 {
+		currentRateLimiter.Store((*rateLimiter)(nil))
+		return nil
+	}
+	if burst <= 0 {
+		return fmt.Errorf("invalid rate limit burst %d", burst)
+	}
+	limiter := &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		costs:   make(map[string]float64, len(costs)),
+		buckets: make(map[string]*tokenBucket),
+	}
+	for pattern, cost := range costs {
+		normalized, err := normalizeMethodPattern(pattern)
+		if err != nil {
+			return err
+		}
+		if cost <= 0 || cost > burst {
+			return fmt.Errorf("invalid rate limit cost %d of %s, which should be between 1 and the burst %d", cost, pattern, burst)
+		}
+		limiter.costs[normalized] = float64(cost)
+	}
+	currentRateLimiter.Store(limiter)
+	return nil
+}
+
+// loadRateLimiter returns the current rate limiter, or nil if it isn't set.
+func loadRateLimiter() *rateLimiter {
+	limiter, _ := currentRateLimiter.Load().(*rateLimiter)
+	return limiter
+}
+
+// cost returns the number of the tokens taken by a request of the method.
+func (l *rateLimiter) cost(method string) float64 {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	service := strings.SplitN(method, serviceMethodSeparator, 2)[0]
+	if cost, ok := l.costs[service+serviceMethodSeparator+"*"]; ok {
+		return cost
+	}
+	return 1
+}
+
+// take takes the tokens of a request of the method from the bucket of the
+// client. If the bucket doesn't have enough tokens, it returns false with the
+// time until they are refilled. The requests of an unknown client, such as
+// the ones over IPC, are not limited.
+func (l *rateLimiter) take(client, method string, now time.Time) (time.Duration, bool) {
+	if l == nil || client == "" {
+		return 0, true
+	}
+	cost := l.cost(method)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = l.refill(bucket, now)
+	bucket.updated = now
+	if bucket.tokens < cost {
+		return time.Duration((cost - bucket.tokens) / l.rate * float64(time.Second)), false
+	}
+	bucket.tokens -= cost
+	return 0, true
+}
+
+// refill returns the tokens of the bucket refilled until now.
+func (l *rateLimiter) refill(bucket *tokenBucket, now time.Time) float64 {
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		return math.Min(l.burst, bucket.tokens+elapsed.Seconds()*l.rate)
+	}
+	return bucket.tokens
+}
+
+// sweep drops the full buckets, which are the same as the ones of new clients.
+func (l *rateLimiter) sweep(now time.Time) {
+	for client, bucket := range l.buckets {
+		if l.refill(bucket, now) >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIdentity returns the identity of the client of the request, which is
+// its token if it is authenticated, or its IP address otherwise. It returns
+// an empty string if the transport doesn't give the address of the client.
+// The IP address is the remote address of the connection, so the clients
+// behind a reverse proxy share the bucket of the proxy. Forwarded headers like
+// X-Forwarded-For are not trusted since any client can set them, and such
+// clients should be identified by their bearer tokens instead.
+func clientIdentity(ctx context.Context) string {
+	if identity, ok := ctx.Value(authIdentityKey{}).(string); ok {
+		return identity
+	}
+	remote, _ := ctx.Value("remote").(string)
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert.Error(t, SetRateLimit(-1, 1, nil))
+	assert.Error(t, SetRateLimit(1, 0, nil))
+	assert.Error(t, SetRateLimit(1, 2, map[string]int{"klay_getLogs": 3}))
+	assert.Error(t, SetRateLimit(1, 2, map[string]int{"klay": 1}))
+
+	assert.NoError(t, SetRateLimit(1, 2, map[string]int{"eth_getLogs": 2, "debug_*": 2}))
+	defer SetRateLimit(0, 0, nil)
+	limiter := loadRateLimiter()
+	now := time.Now()
+
+	// The costs of the methods are taken from the bucket of each client
+	_, ok := limiter.take("client1", "klay_call", now)
+	assert.True(t, ok)
+	_, ok = limiter.take("client1", "klay_call", now)
+	assert.True(t, ok)
+	retryAfter, ok := limiter.take("client1", "klay_call", now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	_, ok = limiter.take("client2", "klay_getLogs", now)
+	assert.True(t, ok)
+	retryAfter, ok = limiter.take("client2", "debug_traceTransaction", now)
+	assert.False(t, ok)
+	assert.Equal(t, 2*time.Second, retryAfter)
+
+	// The tokens are refilled as time goes
+	_, ok = limiter.take("client1", "klay_call", now.Add(time.Second))
+	assert.True(t, ok)
+	_, ok = limiter.take("client1", "klay_call", now.Add(time.Second))
+	assert.False(t, ok)
+
+	// The requests of unknown clients are not limited
+	for i := 0; i < 3; i++ {
+		_, ok = limiter.take("", "klay_getLogs", now)
+		assert.True(t, ok)
+	}
+
+	// The full buckets of idle clients are dropped
+	limiter.take("client3", "klay_call", now.Add(rateLimitSweepInterval))
+	assert.Equal(t, 1, len(limiter.buckets))
+
+	assert.NoError(t, SetRateLimit(0, 0, nil))
+	assert.Nil(t, loadRateLimiter())
+}
+
+// TestServerRateLimit tests that the rate limit is applied to each client when
+// the requests are handled by the server.
+func TestServerRateLimit(t *testing.T) {
+	srv := newTestServer("ratelimit", new(Service))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	assert.NoError(t, SetRateLimit(0.001, 2, nil))
+	defer SetRateLimit(0, 0, nil)
+
+	client, err := DialHTTP(httpsrv.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	var result echoResult
+	assert.NoError(t, client.Call(&result, "ratelimit_echo", "hello", 1))
+	assert.NoError(t, client.Call(&result, "ratelimit_echo", "hello", 1))
+	err = client.Call(&result, "ratelimit_echo", "hello", 1)
+	assert.Error(t, err)
+	assert.Equal(t, -32005, err.(Error).ErrorCode())
+	assert.Equal(t, int64(1), getMethodMetrics("ratelimit_echo").throttled.Count())
+
+	// An authenticated client is limited by its token instead of its address
+	auth, err := NewAuthenticator(map[string][]string{"token": {"*"}}, nil)
+	assert.NoError(t, err)
+	SetAuthenticator(auth)
+	defer SetAuthenticator(nil)
+
+	authClient, err := DialHTTPWithClient(httpsrv.URL, &http.Client{Transport: &authTransport{"Bearer token"}})
+	assert.NoError(t, err)
+	defer authClient.Close()
+	assert.NoError(t, authClient.Call(&result, "ratelimit_echo", "hello", 1))
+
+	// The requests over in-process connections are not limited
+	inproc := DialInProc(srv)
+	defer inproc.Close()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, inproc.Call(&result, "ratelimit_echo", "hello", 1))
+	}
+}
+
+func TestServerBatchLimit(t *testing.T) {
+	srv := newTestServer("batch", new(Service))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	defer func(limit int) { MaxBatchRequests = limit }(MaxBatchRequests)
+	MaxBatchRequests = 2
+
+	post := func(body string) string {
+		resp, err := http.Post(httpsrv.URL, contentType, strings.NewReader(body))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return string(data)
+	}
+	request := `{"jsonrpc":"2.0","id":1,"method":"batch_rets"}`
+
+	resp := post("[" + request + "," + request + "]")
+	assert.Equal(t, 2, strings.Count(resp, `"result":""`))
+
+	resp = post("[" + request + "," + request + "," + request + "]")
+	assert.True(t, strings.Contains(resp, `"code":-32600`))
+	assert.True(t, strings.Contains(resp, "batch of 3 requests exceeds the limit 2"))
+}
+
+func TestServerResponseLimit(t *testing.T) {
+	srv := newTestServer("response", new(Service))
+	defer srv.Stop()
+	client := DialInProc(srv)
+	defer client.Close()
+
+	defer func(limit int) { MaxResponseSize = limit }(MaxResponseSize)
+	var result echoResult
+
+	MaxResponseSize = 16
+	err := client.Call(&result, "response_echo", "hello", 1)
+	assert.Error(t, err)
+	assert.Equal(t, -32003, err.(Error).ErrorCode())
+
+	MaxResponseSize = 1024
+	assert.NoError(t, client.Call(&result, "response_echo", "hello", 1))
+	assert.Equal(t, echoResult{"hello", 1, nil}, result)
+}
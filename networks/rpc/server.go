@@ -22,12 +22,14 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"gopkg.in/fatih/set.v0"
 )
@@ -49,6 +51,9 @@ const (
 
 	// concurrencyLimit is a limit for the number of concurrency connection for RPC servers
 	concurrencyLimit = 3000
+
+	// slowRequestParamsLimit is a maximum size in bytes of the params logged with a slow request
+	slowRequestParamsLimit = 256
 )
 
 var (
@@ -56,7 +61,8 @@ var (
 	pendingRequestCount int64 = 0
 
 	// TODO-Klaytn: move websocket configurations to Config struct in /network/rpc/server.go
-	// MaxSubscriptionPerWSConn is a maximum number of subscription for a websocket connection
+	// MaxSubscriptionPerWSConn is a maximum number of subscription for a websocket connection,
+	// which limits the typed API subscriptions of a gRPC connection as well
 	MaxSubscriptionPerWSConn int32 = 5
 
 	// WebsocketReadDeadline is the read deadline on the underlying network connection in seconds. 0 means read will not timeout
@@ -67,6 +73,15 @@ var (
 
 	// MaxSubscription is a maximum number of websocket connections
 	MaxWebsocketConnections int32 = 3000
+
+	// SlowRequestThreshold is the duration of a request to be logged with its params as a slow one. 0 means no logging
+	SlowRequestThreshold time.Duration = 0
+
+	// MaxBatchRequests is a maximum number of requests in a batch. 0 means no limit
+	MaxBatchRequests = 0
+
+	// MaxResponseSize is a maximum size in bytes of the result of a request. 0 means no limit
+	MaxResponseSize = 0
 )
 
 // NewServer will create a new server instance with no registered handlers.
@@ -287,8 +302,14 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 // response back using the given codec. It will block until the codec is closed or the server is
 // stopped. In either case the codec is closed.
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
+	s.serveCodec(context.Background(), codec, options)
+}
+
+// serveCodec is ServeCodec with the context carrying the values of the
+// connection, such as the namespaces allowed to the authenticated client.
+func (s *Server) serveCodec(ctx context.Context, codec ServerCodec, options CodecOption) {
 	defer codec.Close()
-	s.serveRequest(context.Background(), codec, false, options)
+	s.serveRequest(ctx, codec, false, options)
 }
 
 // ServeSingleRequest reads and processes a single RPC request from the given codec. It will not
@@ -329,48 +350,69 @@ func (s *Server) createSubscription(ctx context.Context, c ServerCodec, req *ser
 var callCount = 0
 var callSendTx = 0
 
-// handle executes a request and returns the response from the callback.
+// handle executes a request after checking the method policy and the rate
+// limit of the client, and returns the response from the callback. It records
+// the metrics of the method and logs the request if it takes longer than
+// SlowRequestThreshold with its params truncated to slowRequestParamsLimit. The
+// params of the methods handling secrets are redacted.
 func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverRequest, subCnt *int32) (interface{}, func()) {
 	if req.err != nil {
 		rpcErrorResponsesCounter.Inc(1)
 		return codec.CreateErrorResponse(&req.id, req.err), nil
 	}
 
+	call, err := beginMethodCall(ctx, req.method)
+	if err != nil {
+		rpcErrorResponsesCounter.Inc(1)
+		return codec.CreateErrorResponse(&req.id, err), nil
+	}
+
+	res, callback, succeeded := s.handleRequest(ctx, codec, req, subCnt)
+	elapsed := call.end(succeeded)
+
+	if SlowRequestThreshold > 0 && elapsed >= SlowRequestThreshold {
+		logger.Warn("Slow RPC request", "method", req.method, "elapsed", elapsed, "params", slowRequestParams(req.method, req.params))
+	}
+	return res, callback
+}
+
+// handleRequest executes a request and returns the response from the callback
+// with whether the request succeeded or not.
+func (s *Server) handleRequest(ctx context.Context, codec ServerCodec, req *serverRequest, subCnt *int32) (interface{}, func(), bool) {
 	if req.isUnsubscribe { // cancel subscription, first param must be the subscription id
 		if len(req.args) >= 1 && req.args[0].Kind() == reflect.String {
 			notifier, supported := NotifierFromContext(ctx)
 			if !supported { // interface doesn't support subscriptions (e.g. http)
 				rpcErrorResponsesCounter.Inc(1)
-				return codec.CreateErrorResponse(&req.id, &callbackError{ErrNotificationsUnsupported.Error()}), nil
+				return codec.CreateErrorResponse(&req.id, &callbackError{ErrNotificationsUnsupported.Error()}), nil, false
 			}
 
 			subid := ID(req.args[0].String())
 			if err := notifier.unsubscribe(subid); err != nil {
 				rpcErrorResponsesCounter.Inc(1)
-				return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil
+				return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil, false
 			}
 
 			atomic.AddInt32(subCnt, -1)
 			rpcSuccessResponsesCounter.Inc(1)
-			return codec.CreateResponse(req.id, true), nil
+			return codec.CreateResponse(req.id, true), nil, true
 		}
 		rpcErrorResponsesCounter.Inc(1)
 		wsUnsubscriptionReqCounter.Inc(1)
-		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
+		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil, false
 	}
 
 	if req.callb.isSubscribe {
-		if atomic.LoadInt32(subCnt) >= MaxSubscriptionPerWSConn {
-			return codec.CreateErrorResponse(&req.id, &callbackError{
-				fmt.Sprintf("Maximum %d subscriptions are allowed for a websocket connection. "+
-					"The limit can be updated with 'admin_setMaxSubscriptionPerWSConn' API", MaxSubscriptionPerWSConn),
-			}), nil
+		if limit := MaxSubscriptionPerWSConn; atomic.LoadInt32(subCnt) >= limit {
+			rpcErrorResponsesCounter.Inc(1)
+			rpcThrottledRequestsCounter.Inc(1)
+			return codec.CreateErrorResponse(&req.id, &subscriptionLimitError{limit}), nil, false
 		}
 
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
 			rpcErrorResponsesCounter.Inc(1)
-			return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil
+			return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil, false
 		}
 
 		// active the subscription after the sub id was successfully sent to the client
@@ -381,7 +423,7 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		atomic.AddInt32(subCnt, 1)
 		rpcSuccessResponsesCounter.Inc(1)
 		wsSubscriptionReqCounter.Inc(1)
-		return codec.CreateResponse(req.id, subid), activateSub
+		return codec.CreateResponse(req.id, subid), activateSub, true
 	}
 
 	// regular RPC call, prepare arguments
@@ -390,7 +432,7 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 			req.svcname, serviceMethodSeparator, req.callb.method.Name,
 			len(req.callb.argTypes), len(req.args))}
 		rpcErrorResponsesCounter.Inc(1)
-		return codec.CreateErrorResponse(&req.id, rpcErr), nil
+		return codec.CreateErrorResponse(&req.id, rpcErr), nil, false
 	}
 
 	arguments := []reflect.Value{req.callb.rcvr}
@@ -413,19 +455,34 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	reply := req.callb.method.Func.Call(arguments)
 	if len(reply) == 0 {
 		rpcSuccessResponsesCounter.Inc(1)
-		return codec.CreateResponse(req.id, nil), nil
+		return codec.CreateResponse(req.id, nil), nil, true
 	}
 	if req.callb.errPos >= 0 { // test if method returned an error
 		if !reply[req.callb.errPos].IsNil() {
 			e := reply[req.callb.errPos].Interface().(error)
 			rpcErrorResponsesCounter.Inc(1)
 			res := codec.CreateErrorResponse(&req.id, &callbackError{e.Error()})
-			return res, nil
+			return res, nil, false
 		}
 	}
 
+	result := reply[0].Interface()
+	if MaxResponseSize > 0 {
+		// encode the result in advance to check its size, which is written as it is
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			rpcErrorResponsesCounter.Inc(1)
+			return codec.CreateErrorResponse(&req.id, &callbackError{err.Error()}), nil, false
+		}
+		if len(encoded) > MaxResponseSize {
+			rpcErrorResponsesCounter.Inc(1)
+			rpcThrottledRequestsCounter.Inc(1)
+			return codec.CreateErrorResponse(&req.id, &responseTooLargeError{len(encoded), MaxResponseSize}), nil, false
+		}
+		result = json.RawMessage(encoded)
+	}
 	rpcSuccessResponsesCounter.Inc(1)
-	return codec.CreateResponse(req.id, reply[0].Interface()), nil
+	return codec.CreateResponse(req.id, result), nil, true
 }
 
 // exec executes the given request and writes the result back using the codec.
@@ -486,6 +543,12 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 	if err != nil {
 		return nil, batch, err
 	}
+	// a batch exceeding the limit is responded with an error as a single request
+	if batch && MaxBatchRequests > 0 && len(reqs) > MaxBatchRequests {
+		rpcThrottledRequestsCounter.Inc(1)
+		err := &invalidRequestError{fmt.Sprintf("batch of %d requests exceeds the limit %d", len(reqs), MaxBatchRequests)}
+		return []*serverRequest{{err: err}}, false, nil
+	}
 
 	requests := make([]*serverRequest, len(reqs))
 
@@ -500,7 +563,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if r.isPubSub && strings.HasSuffix(r.method, unsubscribeMethodSuffix) {
-			requests[i] = &serverRequest{id: r.id, isUnsubscribe: true}
+			requests[i] = &serverRequest{id: r.id, method: strings.Replace(r.method, "eth_", "klay_", 1), isUnsubscribe: true}
 			argTypes := []reflect.Type{reflect.TypeOf("")} // expect subscription id as first arg
 			if args, err := codec.ParseRequestArguments(argTypes, r.params); err == nil {
 				requests[i].args = args
@@ -523,7 +586,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
-				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+				requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + subscribeMethodSuffix, callb: callb, params: r.params}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
 					argTypes = append(argTypes, callb.argTypes...)
@@ -540,7 +603,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
-			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+			requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + serviceMethodSeparator + r.method, callb: callb, params: r.params}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
 					requests[i].args = args
@@ -62,8 +62,10 @@ type service struct {
 type serverRequest struct {
 	id            interface{}
 	svcname       string
+	method        string // full name of the requested method, e.g. klay_call
 	callb         *callback
 	args          []reflect.Value
+	params        interface{} // encoded params, only logged with a slow request
 	isUnsubscribe bool
 	err           Error
 }
@@ -72,6 +72,12 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			if atomic.LoadInt32(&srv.wsConnCount) >= MaxWebsocketConnections {
 				return
 			}
+			// The token has been verified by the handshake validator
+			ctx, err := AuthenticateContext(context.Background(), conn.Request().Header.Get("Authorization"))
+			if err != nil {
+				return
+			}
+			ctx = context.WithValue(ctx, "remote", conn.Request().RemoteAddr)
 			atomic.AddInt32(&srv.wsConnCount, 1)
 			wsConnCounter.Inc(1)
 			defer func() {
@@ -93,7 +99,7 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			srv.serveCodec(ctx, NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
@@ -111,8 +117,12 @@ func (srv *Server) FastWebsocketHandler(ctx *fasthttp.RequestCtx) {
 		ctx.Response.Header.Set("Sec-WebSocket-Protocol", string(protocol))
 	}
 
+	// The token has been verified by the handshake validator
+	authCtx, authErr := AuthenticateContext(context.Background(), string(ctx.Request.Header.Peek("Authorization")))
+	authCtx = context.WithValue(authCtx, "remote", ctx.RemoteAddr().String())
+
 	err := upgrader.Upgrade(ctx, func(conn *fastws.Conn) {
-		if atomic.LoadInt32(&srv.wsConnCount) >= MaxWebsocketConnections {
+		if atomic.LoadInt32(&srv.wsConnCount) >= MaxWebsocketConnections || authErr != nil {
 			return
 		}
 		atomic.AddInt32(&srv.wsConnCount, 1)
@@ -152,7 +162,7 @@ func (srv *Server) FastWebsocketHandler(ctx *fasthttp.RequestCtx) {
 		}
 
 		reader := bufio.NewReaderSize(bytes.NewReader(ctx.Request.Body()), common.MaxRequestContentLength)
-		srv.ServeCodec(NewCodec(&httpReadWriteNopCloser{reader, ctx.Response.BodyWriter()}, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+		srv.serveCodec(authCtx, NewCodec(&httpReadWriteNopCloser{reader, ctx.Response.BodyWriter()}, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
 	})
 	if err != nil {
 		logger.Error("FastWebsocketHandler fail to upgrade message", "err", err)
@@ -199,19 +209,23 @@ func wsFastHandshakeValidator(allowedOrigins []string) func(ctx *fasthttp.Reques
 
 	f := func(ctx *fasthttp.RequestCtx) bool {
 		origin := strings.ToLower(string(ctx.Request.Header.Peek("Origin")))
-		if allowAllOrigins || origins.Has(origin) {
-			return true
+		if !allowAllOrigins && !origins.Has(origin) {
+			logger.Warn(fmt.Sprintf("origin '%s' not allowed on WS-RPC interface\n", origin))
+			return false
 		}
-		logger.Warn(fmt.Sprintf("origin '%s' not allowed on WS-RPC interface\n", origin))
-		return false
+		if _, err := AuthenticateContext(ctx, string(ctx.Request.Header.Peek("Authorization"))); err != nil {
+			logger.Warn("unauthenticated request on WS-RPC interface", "remote", ctx.RemoteAddr(), "err", err)
+			return false
+		}
+		return true
 	}
 
 	return f
 }
 
-// wsHandshakeValidator returns a handler that verifies the origin during the
-// websocket upgrade process. When a '*' is specified as an allowed origins all
-// connections are accepted.
+// wsHandshakeValidator returns a handler that verifies the origin and the bearer
+// token during the websocket upgrade process. When a '*' is specified as an
+// allowed origins all origins are accepted.
 func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http.Request) error {
 	origins := set.New()
 	allowAllOrigins := false
@@ -237,11 +251,15 @@ func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http
 
 	f := func(cfg *websocket.Config, req *http.Request) error {
 		origin := strings.ToLower(req.Header.Get("Origin"))
-		if allowAllOrigins || origins.Has(origin) {
-			return nil
+		if !allowAllOrigins && !origins.Has(origin) {
+			logger.Warn(fmt.Sprintf("origin '%s' not allowed on WS-RPC interface\n", origin))
+			return fmt.Errorf("origin %s not allowed", origin)
+		}
+		if _, err := AuthenticateContext(req.Context(), req.Header.Get("Authorization")); err != nil {
+			logger.Warn("unauthenticated request on WS-RPC interface", "remote", req.RemoteAddr, "err", err)
+			return err
 		}
-		logger.Warn(fmt.Sprintf("origin '%s' not allowed on WS-RPC interface\n", origin))
-		return fmt.Errorf("origin %s not allowed", origin)
+		return nil
 	}
 
 	return f
@@ -40,6 +40,7 @@ import (
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/statedb"
 	"github.com/klaytn/klaytn/work"
 )
@@ -202,6 +203,16 @@ func (api *PrivateAdminAPI) SaveTrieNodeCacheToDisk() error {
 	return api.cn.BlockChain().SaveTrieNodeCacheToDisk()
 }
 
+// SnapshotStatus returns the status of the state snapshot.
+func (api *PrivateAdminAPI) SnapshotStatus() (*snapshot.Status, error) {
+	return api.cn.BlockChain().SnapshotStatus()
+}
+
+// RebuildSnapshot regenerates the state snapshot from the current head state.
+func (api *PrivateAdminAPI) RebuildSnapshot() error {
+	return api.cn.BlockChain().RebuildSnapshot()
+}
+
 // PublicDebugAPI is the collection of Klaytn full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -260,7 +271,7 @@ func (api *PublicDebugAPI) DumpStateTrie(blockNr uint64) (DumpStateTrieResult, e
 	}
 
 	db := state.NewDatabaseWithExistingCache(api.cn.chainDB, api.cn.blockchain.StateCache().TrieDB().TrieNodeCache())
-	stateDB, err := state.New(block.Root(), db)
+	stateDB, err := state.New(block.Root(), db, nil)
 	if err != nil {
 		return DumpStateTrieResult{}, err
 	}
@@ -340,17 +351,19 @@ type storageEntry struct {
 }
 
 // StorageRangeAt returns the storage at the given block height and transaction index.
-//func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
-//	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
-//	if err != nil {
-//		return StorageRangeResult{}, err
-//	}
-//	st := statedb.StorageTrie(contractAddress)
-//	if st == nil {
-//		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
-//	}
-//	return storageRangeAt(st, keyStart, maxResult)
-//}
+// The storage of a smart contract account is read from its storage root in the state
+// right before the transaction at txIndex is executed.
+func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
+	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	st := statedb.StorageTrie(contractAddress)
+	if st == nil {
+		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+	}
+	return storageRangeAt(st, keyStart, maxResult)
+}
 
 func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeResult, error) {
 	it := statedb.NewIterator(st.NodeIterator(start))
@@ -520,3 +533,135 @@ func (api *PrivateDebugAPI) getModifiedStorageNodes(contractAddr common.Address,
 		"startBlock", startBlock.NumberU64(), "endBlock", endBlock.NumberU64(), "numModifiedNodes", numModifiedNodes, "elapsed", time.Since(start))
 	return numModifiedNodes, nil
 }
+
+// PrivateTxPoolAPI is the collection of CN full node APIs exposed over the
+// private txpool endpoint to manage the admission policies of the transaction pool.
+type PrivateTxPoolAPI struct {
+	cn *CN
+}
+
+// NewPrivateTxPoolAPI creates a new API definition for the full node private
+// txpool methods of the CN service.
+func NewPrivateTxPoolAPI(cn *CN) *PrivateTxPoolAPI {
+	return &PrivateTxPoolAPI{cn: cn}
+}
+
+var errAdmissionPolicyNotSupported = errors.New("admission policies are not supported by the transaction pool")
+
+func (api *PrivateTxPoolAPI) txPool() (*blockchain.TxPool, error) {
+	pool, ok := api.cn.txPool.(*blockchain.TxPool)
+	if !ok {
+		return nil, errAdmissionPolicyNotSupported
+	}
+	return pool, nil
+}
+
+// SetDenyList replaces the addresses whose transactions are rejected as a sender or a fee payer.
+func (api *PrivateTxPoolAPI) SetDenyList(addrs []common.Address) (bool, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return false, err
+	}
+	pool.AddressList().SetDenyList(addrs)
+	return true, nil
+}
+
+// SetAllowList replaces the addresses whose transactions are only admitted.
+// An empty list admits the transactions from any sender.
+func (api *PrivateTxPoolAPI) SetAllowList(addrs []common.Address) (bool, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return false, err
+	}
+	pool.AddressList().SetAllowList(addrs)
+	return true, nil
+}
+
+// AddressLists returns the deny and allow lists of the transaction pool.
+func (api *PrivateTxPoolAPI) AddressLists() (map[string][]common.Address, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]common.Address{
+		"deny":  pool.AddressList().DenyList(),
+		"allow": pool.AddressList().AllowList(),
+	}, nil
+}
+
+// AdmissionPolicies returns the names of the admission policies in the order they are checked.
+func (api *PrivateTxPoolAPI) AdmissionPolicies() ([]string, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return nil, err
+	}
+	return pool.AdmissionPolicies(), nil
+}
+
+// InspectJournal returns the status of the local and the remote transaction journals.
+func (api *PrivateTxPoolAPI) InspectJournal() (map[string]*blockchain.TxJournalStats, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return nil, err
+	}
+	return pool.JournalStats(), nil
+}
+
+// ExportPending exports the pending transactions into a local file, and returns
+// the number of the exported transactions.
+func (api *PrivateTxPoolAPI) ExportPending(file string) (int, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vecotor,
+		// since the 'file' may point to arbitrary paths on the drive
+		return 0, errors.New("location would overwrite an existing file")
+	}
+
+	// Make sure we can create the file to export into
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	var writer io.Writer = out
+	if strings.HasSuffix(file, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	return pool.ExportPending(writer)
+}
+
+// ImportPending imports the transactions exported by ExportPending from a local
+// file as remote transactions, and returns the number of the imported and the
+// dropped transactions.
+func (api *PrivateTxPoolAPI) ImportPending(file string) (map[string]int, error) {
+	pool, err := api.txPool()
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the can access the file to import
+	in, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(file, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	total, dropped, err := pool.ImportPending(reader)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		"imported": total - dropped,
+		"dropped":  dropped,
+	}, nil
+}
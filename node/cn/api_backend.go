@@ -246,6 +246,14 @@ func (b *CNAPIBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, sections
 }
 
+func (b *CNAPIBackend) LogIndexStatus() (uint64, uint64) {
+	if b.cn.logIndexer == nil {
+		return params.BloomBitsBlocks, 0
+	}
+	sections, _, _ := b.cn.logIndexer.Sections()
+	return params.BloomBitsBlocks, sections
+}
+
 func (b *CNAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.cn.bloomRequests)
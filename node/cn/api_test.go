@@ -20,13 +20,16 @@
 package cn
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -34,7 +37,7 @@ var dumper = spew.ConfigState{Indent: "    "}
 func TestStorageRangeAt(t *testing.T) {
 	// Create a state where account 0x010000... has a few storage entries.
 	var (
-		state, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()))
+		state, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
 		addr     = common.Address{0x01}
 		keys     = []common.Hash{ // hashes of Keys of storage
 			common.HexToHash("340dd630ad21bf010b4e676dbfa9ba9a02175262d1fa356232cfde6cb5b47ef2"),
@@ -91,3 +94,54 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+// TestStorageRangeAt_SmartContractAccount checks if the storage of a smart contract
+// account is read from its committed storage root.
+func TestStorageRangeAt_SmartContractAccount(t *testing.T) {
+	var (
+		db       = state.NewDatabase(database.NewMemoryDBManager())
+		sdb, _   = state.New(common.Hash{}, db, nil)
+		contract = common.Address{0x02}
+		eoa      = common.Address{0x03}
+	)
+	sdb.CreateSmartContractAccount(contract, params.CodeFormatEVM)
+	sdb.SetState(contract, common.Hash{0x01}, common.Hash{0x11})
+	sdb.SetState(contract, common.Hash{0x02}, common.Hash{0x22})
+	sdb.AddBalance(eoa, common.Big1)
+
+	root, err := sdb.Commit(false)
+	assert.NoError(t, err)
+	assert.NoError(t, db.TrieDB().Commit(root, false, 0))
+
+	reopened, err := state.New(root, db, nil)
+	assert.NoError(t, err)
+
+	result, err := storageRangeAt(reopened.StorageTrie(contract), nil, 10)
+	assert.NoError(t, err)
+	assert.Nil(t, result.NextKey)
+	assert.Equal(t, 2, len(result.Storage))
+	for _, entry := range result.Storage {
+		assert.NotNil(t, entry.Key)
+		assert.Equal(t, common.Hash{(*entry.Key)[0] * 0x11}, entry.Value)
+	}
+
+	// An account without a storage root has an empty storage.
+	result, err = storageRangeAt(reopened.StorageTrie(eoa), nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, StorageRangeResult{Storage: storageMap{}}, result)
+
+	// A non-existing account has no storage trie.
+	assert.Nil(t, reopened.StorageTrie(common.Address{0x04}))
+}
+
+func TestPrivateDebugAPI_StorageRangeAt(t *testing.T) {
+	mockCtrl, api, _, mockBlockChain, _ := createCNMocks(t)
+	defer mockCtrl.Finish()
+
+	blockHash := common.HexToHash("0x1")
+	mockBlockChain.EXPECT().GetBlockByHash(blockHash).Return(nil).Times(1)
+
+	result, err := api.StorageRangeAt(context.Background(), blockHash, 0, common.Address{0x01}, nil, 10)
+	assert.Error(t, err)
+	assert.Equal(t, StorageRangeResult{}, result)
+}
@@ -24,9 +24,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"reflect"
 	"runtime"
@@ -66,9 +68,17 @@ const (
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
-	Tracer  *string
-	Timeout *string
-	Reexec  *uint64
+	Tracer       *string
+	TracerConfig json.RawMessage // Configuration of the native tracer, e.g. {"diffMode": true} for prestateTracer
+	Timeout      *string
+	Reexec       *uint64
+}
+
+// TraceCallConfig holds extra parameters to trace a message call.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *klaytnapi.StateOverride
+	BlockOverrides *klaytnapi.BlockOverrides
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -165,7 +175,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			return nil, fmt.Errorf("parent block #%d not found", number-1)
 		}
 	}
-	statedb, err := state.New(start.Root(), database)
+	statedb, err := state.New(start.Root(), database, nil)
 	if err != nil {
 		// If the starting state is missing, allow some number of blocks to be reexecuted
 		reexec := defaultTraceReexec
@@ -178,7 +188,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			if start == nil {
 				break
 			}
-			if statedb, err = state.New(start.Root(), database); err == nil {
+			if statedb, err = state.New(start.Root(), database, nil); err == nil {
 				break
 			}
 		}
@@ -224,6 +234,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 					vmctx := blockchain.NewEVMContext(msg, task.block.Header(), api.cn.blockchain, nil)
 
+					task.statedb.Prepare(tx.Hash(), task.block.Hash(), i)
 					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 					if err != nil {
 						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
@@ -510,6 +521,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 				vmctx := blockchain.NewEVMContext(msg, block.Header(), api.cn.blockchain, nil)
 
+				task.statedb.Prepare(txs[task.index].Hash(), block.Hash(), task.index)
 				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
@@ -535,6 +547,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 		vmctx := blockchain.NewEVMContext(msg, block.Header(), api.cn.blockchain, nil)
 
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
 		vmenv := vm.NewEVM(vmctx, statedb, api.config, &vm.Config{})
 		if _, _, kerr := blockchain.ApplyMessage(vmenv, msg); kerr.ErrTxInvalid != nil {
 			failed = kerr.ErrTxInvalid
@@ -634,6 +647,7 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 			}
 		}
 		// Execute the transaction and flush any traces to disk
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
 		vmenv := vm.NewEVM(vmctx, statedb, api.config, &vmConf)
 		_, _, kerr := blockchain.ApplyMessage(vmenv, msg)
 
@@ -677,7 +691,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	var err error
 
 	for i := uint64(0); i < reexec; i++ {
-		if statedb, err = state.New(block.Root(), database); err == nil {
+		if statedb, err = state.New(block.Root(), database, nil); err == nil {
 			break
 		}
 		blockNumber := block.NumberU64()
@@ -753,6 +767,57 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
 
+// TraceCall lets you trace a given klay_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object. The state and
+// the block information can be overridden before the execution.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args klaytnapi.CallArgs, blockNr rpc.BlockNumber, config *TraceCallConfig) (interface{}, error) {
+	// Fetch the block that we want to trace on top of
+	var block *types.Block
+
+	switch blockNr {
+	case rpc.PendingBlockNumber:
+		return nil, kerrors.ErrPendingBlockNotSupported
+	case rpc.LatestBlockNumber:
+		block = api.cn.blockchain.CurrentBlock()
+	default:
+		block = api.cn.blockchain.GetBlockByNumber(uint64(blockNr))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, release, err := api.stateAt(block, reexec)
+	defer release()
+	if err != nil {
+		return nil, fmt.Errorf("can not get the state of block %#x: %v", block.Root(), err)
+	}
+	// Apply the customized state rules if required
+	var traceConfig *TraceConfig
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		traceConfig = &config.TraceConfig
+	}
+	msg, err := args.ToMessage(api.cn.APIBackend, api.cn.APIBackend.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	// Add gas fee to the sender, the same as klay_call does, so that the call
+	// can be traced regardless of the balance of the sender.
+	statedb.AddBalance(msg.ValidatedSender(), new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.GasPrice()))
+
+	vmctx := blockchain.NewEVMContext(msg, block.Header(), api.cn.blockchain, nil)
+	if config != nil {
+		config.BlockOverrides.Apply(&vmctx)
+	}
+	return api.traceTx(ctx, msg, vmctx, statedb, traceConfig)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
@@ -774,6 +839,12 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message blockchain.Mess
 
 		if *config.Tracer == fastCallTracer {
 			tracer = vm.NewInternalTxTracer()
+		} else if nativeTracer, ok, err := tracers.NewNativeTracer(*config.Tracer, config.TracerConfig); ok {
+			// Use the native tracer instead of the JavaScript one of the same name
+			if err != nil {
+				return nil, err
+			}
+			tracer = nativeTracer
 		} else {
 			// Constuct the JavaScript tracer to execute with
 			if tracer, err = tracers.New(*config.Tracer); err != nil {
@@ -787,6 +858,8 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message blockchain.Mess
 			switch t := tracer.(type) {
 			case *tracers.Tracer:
 				t.Stop(errors.New("execution timeout"))
+			case tracers.NativeTracer:
+				t.Stop(errors.New("execution timeout"))
 			case *vm.InternalTxTracer:
 				t.Stop(errors.New("execution timeout"))
 			default:
@@ -820,6 +893,8 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message blockchain.Mess
 
 	case *tracers.Tracer:
 		return tracer.GetResult()
+	case tracers.NativeTracer:
+		return tracer.GetResult()
 	case *vm.InternalTxTracer:
 		return tracer.GetResult()
 
@@ -889,6 +964,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 		}
 
 		context := blockchain.NewEVMContext(msg, block.Header(), api.cn.blockchain, nil)
+		statedb.Prepare(tx.Hash(), block.Hash(), idx)
 		if idx == txIndex {
 			return msg, context, statedb, nil
 		}
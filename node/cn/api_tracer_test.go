@@ -19,16 +19,23 @@ package cn
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	klaytnapi "github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	mocks2 "github.com/klaytn/klaytn/consensus/mocks"
 	"github.com/klaytn/klaytn/kerrors"
 	"github.com/klaytn/klaytn/networks/rpc"
 	mocks3 "github.com/klaytn/klaytn/node/cn/mocks"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/work/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -224,3 +231,119 @@ func TestPrivateDebugAPI_computeTxEnv(t *testing.T) {
 		mockCtrl.Finish()
 	}
 }
+
+func TestPrivateDebugAPI_TraceCall(t *testing.T) {
+	blockNumber := rpc.BlockNumber(123)
+	{
+		mockCtrl, api, _, _, _ := createCNMocks(t)
+		res, err := api.TraceCall(context.Background(), klaytnapi.CallArgs{}, rpc.PendingBlockNumber, nil)
+		assert.Nil(t, res)
+		assert.Equal(t, kerrors.ErrPendingBlockNotSupported, err)
+		mockCtrl.Finish()
+	}
+	{
+		mockCtrl, api, _, mockBlockChain, _ := createCNMocks(t)
+		mockBlockChain.EXPECT().CurrentBlock().Return(nil).Times(1)
+		res, err := api.TraceCall(context.Background(), klaytnapi.CallArgs{}, rpc.LatestBlockNumber, nil)
+		assert.Nil(t, res)
+		assert.Error(t, err)
+		mockCtrl.Finish()
+	}
+	{
+		mockCtrl, api, _, mockBlockChain, _ := createCNMocks(t)
+		mockBlockChain.EXPECT().GetBlockByNumber(uint64(blockNumber)).Return(nil).Times(1)
+		res, err := api.TraceCall(context.Background(), klaytnapi.CallArgs{}, blockNumber, nil)
+		assert.Nil(t, res)
+		assert.Error(t, err)
+		mockCtrl.Finish()
+	}
+}
+
+// Tests that TraceCall traces a call with the overridden state and block.
+func TestPrivateDebugAPI_TraceCallWithOverrides(t *testing.T) {
+	mockCtrl, mockEngine, mockBlockChain, _ := newMocks(t)
+	defer mockCtrl.Finish()
+
+	cn := &CN{blockchain: mockBlockChain, engine: mockEngine, config: &Config{}}
+	cn.APIBackend = &CNAPIBackend{cn, nil}
+	api := NewPrivateDebugAPI(params.TestChainConfig, cn)
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(123), BlockScore: big.NewInt(1), Time: big.NewInt(1)})
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+
+	mockBlockChain.EXPECT().GetBlockByNumber(uint64(123)).Return(block).Times(1)
+	mockBlockChain.EXPECT().StateAtWithGCLock(block.Root()).Return(nil, expectedErr).Times(1)
+	mockBlockChain.EXPECT().StateAt(block.Root()).Return(statedb, nil).Times(1)
+	mockBlockChain.EXPECT().Engine().Return(mockEngine).Times(1)
+	mockEngine.EXPECT().Author(gomock.Any()).Return(common.Address{}, nil).Times(1)
+
+	// The contract returns the sum of the storage slot 0 and the block number
+	contract := common.HexToAddress("0x1000")
+	code := hexutil.Bytes(common.FromHex("0x600054430160005260206000f3"))
+	storage := map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(5))}
+	number := hexutil.Big(*big.NewInt(10))
+	config := &TraceCallConfig{
+		StateOverrides: &klaytnapi.StateOverride{contract: {Code: &code, StateDiff: &storage}},
+		BlockOverrides: &klaytnapi.BlockOverrides{Number: &number},
+	}
+	res, err := api.TraceCall(context.Background(), klaytnapi.CallArgs{From: addrs[0], To: &contract}, rpc.BlockNumber(123), config)
+	assert.NoError(t, err)
+
+	result, ok := res.(*klaytnapi.ExecutionResult)
+	assert.True(t, ok)
+	assert.False(t, result.Failed)
+	assert.Equal(t, common.BigToHash(big.NewInt(15)).Hex()[2:], result.ReturnValue)
+	assert.NotEmpty(t, result.StructLogs)
+}
+
+// Tests that the access list of a transaction doesn't carry over to the next
+// transaction when a block after the berlin compatible block is traced.
+func TestPrivateDebugAPI_TraceBlockAccessList(t *testing.T) {
+	mockCtrl, mockEngine, mockBlockChain, _ := newMocks(t)
+	defer mockCtrl.Finish()
+
+	config := *params.TestChainConfig
+	config.IstanbulCompatibleBlock = big.NewInt(0)
+	config.BerlinCompatibleBlock = big.NewInt(0)
+	cn := &CN{blockchain: mockBlockChain, engine: mockEngine, config: &Config{}}
+	api := NewPrivateDebugAPI(&config, cn)
+
+	// The contract loads the storage slot 0, which is cold for each transaction
+	contract := common.HexToAddress("0x1000")
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, statedb.SetCode(contract, common.FromHex("0x6000545000")))
+	statedb.AddBalance(addrs[0], big.NewInt(params.KLAY))
+
+	signer := types.MakeSigner(&config, big.NewInt(124))
+	var txs types.Transactions
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx, err := types.SignTx(types.NewTransaction(nonce, contract, common.Big0, 100000, big.NewInt(1), nil), signer, keys[0])
+		assert.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	parent := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(123), BlockScore: big.NewInt(1), Time: big.NewInt(1)})
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(124), ParentHash: parent.Hash(), BlockScore: big.NewInt(1), Time: big.NewInt(2)}).WithBody(txs)
+
+	mockEngine.EXPECT().VerifyHeader(mockBlockChain, gomock.Any(), true).Return(nil).Times(1)
+	mockBlockChain.EXPECT().GetBlock(parent.Hash(), parent.NumberU64()).Return(parent).Times(1)
+	mockBlockChain.EXPECT().StateAtWithGCLock(parent.Root()).Return(nil, expectedErr).Times(1)
+	mockBlockChain.EXPECT().StateAt(parent.Root()).Return(statedb, nil).Times(1)
+	mockBlockChain.EXPECT().Engine().Return(mockEngine).AnyTimes()
+	mockEngine.EXPECT().Author(gomock.Any()).Return(common.Address{}, nil).AnyTimes()
+
+	results, err := api.traceBlock(context.Background(), block, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	var gas []uint64
+	for _, result := range results {
+		assert.Empty(t, result.Error)
+		res, ok := result.Result.(*klaytnapi.ExecutionResult)
+		assert.True(t, ok)
+		assert.False(t, res.Failed)
+		gas = append(gas, res.Gas)
+	}
+	assert.Equal(t, gas[0], gas[1])
+}
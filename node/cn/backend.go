@@ -39,7 +39,6 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/consensus"
-	"github.com/klaytn/klaytn/consensus/istanbul"
 	istanbulBackend "github.com/klaytn/klaytn/consensus/istanbul/backend"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/datasync/downloader"
@@ -117,6 +116,7 @@ type CN struct {
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *blockchain.ChainIndexer       // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
+	logIndexer        *blockchain.ChainIndexer // Log indexer operating during block imports, nil if disabled
 
 	APIBackend *CNAPIBackend
 
@@ -251,7 +251,9 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		vmConfig    = config.getVMConfig()
 		cacheConfig = &blockchain.CacheConfig{ArchiveMode: config.NoPruning, CacheSize: config.TrieCacheSize,
 			BlockInterval: config.TrieBlockInterval, TriesInMemory: config.TriesInMemory,
-			TrieNodeCacheConfig: &config.TrieNodeCacheConfig, SenderTxHashIndexing: config.SenderTxHashIndexing}
+			TrieNodeCacheConfig: &config.TrieNodeCacheConfig, SenderTxHashIndexing: config.SenderTxHashIndexing,
+			SnapshotCacheSize: config.SnapshotCacheSize, SnapshotAsyncGen: config.SnapshotAsyncGen,
+			StatePruning: config.StatePruning, StatePruningRetention: config.StatePruningRetention}
 	)
 
 	bc, err := blockchain.NewBlockChain(chainDB, cacheConfig, cn.chainConfig, cn.engine, vmConfig)
@@ -283,10 +285,17 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		chainDB.WriteChainConfig(genesisHash, cn.chainConfig)
 	}
 	cn.bloomIndexer.Start(cn.blockchain)
+	if config.LogIndexing {
+		cn.logIndexer = NewLogIndexer(chainDB, params.BloomBitsBlocks)
+		cn.logIndexer.Start(cn.blockchain)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.RemoteJournal != "" {
+		config.TxPool.RemoteJournal = ctx.ResolvePath(config.TxPool.RemoteJournal)
+	}
 	// TODO-Klaytn-ServiceChain: add account creation prevention in the txPool if TxTypeAccountCreation is supported.
 	config.TxPool.NoAccountCreation = config.NoAccountCreation
 	cn.txPool = blockchain.NewTxPool(config.TxPool, cn.chainConfig, bc)
@@ -310,7 +319,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		logger.Error("Error happened while setting the reward wallet", "err", err)
 	}
 
-	if governance.ProposerPolicy() == uint64(istanbul.WeightedRandom) {
+	if params.IsWeightedRandomPolicy(governance.ProposerPolicy()) {
 		// NewStakingManager is called with proper non-nil parameters
 		reward.NewStakingManager(cn.blockchain, governance, cn.chainDB)
 	}
@@ -340,6 +349,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	cn.addComponent(cn.txPool)
 	cn.addComponent(cn.APIs())
 	cn.addComponent(cn.ChainDB())
+	cn.addComponent(cn.APIBackend)
 
 	if config.AutoRestartFlag {
 		daemonPath := config.DaemonPathFlag
@@ -479,12 +489,17 @@ func (s *CN) APIs() []rpc.API {
 		}, {
 			Namespace: "klay",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.APIBackend, false),
+			Service:   filters.NewPublicFilterAPI(s.APIBackend, false, &filters.Config{MaxBlockRange: s.config.RPCLogQueryBlockRange, MaxResults: s.config.RPCLogQueryResultLimit}),
 			Public:    true,
 		}, {
 			Namespace: "admin",
 			Version:   "1.0",
 			Service:   NewPrivateAdminAPI(s),
+		}, {
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPrivateTxPoolAPI(s),
+			Public:    false,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -642,6 +657,9 @@ func (s *CN) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
 	reward.StakingManagerUnsubscribe()
@@ -59,12 +59,21 @@ func NewChannelManager(channelSize int) *ChannelManager {
 	channelMgr.RegisterMsgCode(BlockChannel, NewBlockMsg)
 
 	channelMgr.RegisterMsgCode(TxChannel, TxMsg)
+	channelMgr.RegisterMsgCode(TxChannel, NewPooledTransactionHashesMsg)
+	channelMgr.RegisterMsgCode(TxChannel, GetPooledTransactionsMsg)
+	channelMgr.RegisterMsgCode(TxChannel, PooledTransactionsMsg)
 
 	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsRequestMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, StatusMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, NodeDataRequestMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, NodeDataMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, AccountRangeRequestMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, AccountRangeMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, StorageRangesRequestMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, StorageRangesMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, ByteCodesRequestMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, ByteCodesMsg)
 
 	return channelMgr
 }
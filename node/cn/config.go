@@ -45,14 +45,16 @@ var logger = log.NewModuleLogger(log.NodeCN)
 // GetDefaultConfig returns default settings for use on the Klaytn main net.
 func GetDefaultConfig() *Config {
 	return &Config{
-		SyncMode:          downloader.FullSync,
-		NetworkId:         params.CypressNetworkId,
-		LevelDBCacheSize:  768,
-		TrieCacheSize:     512,
-		TrieTimeout:       5 * time.Minute,
-		TrieBlockInterval: blockchain.DefaultBlockInterval,
-		TriesInMemory:     blockchain.DefaultTriesInMemory,
-		GasPrice:          big.NewInt(18 * params.Ston),
+		SyncMode:              downloader.FullSync,
+		NetworkId:             params.CypressNetworkId,
+		LevelDBCacheSize:      768,
+		TrieCacheSize:         512,
+		TrieTimeout:           5 * time.Minute,
+		TrieBlockInterval:     blockchain.DefaultBlockInterval,
+		TriesInMemory:         blockchain.DefaultTriesInMemory,
+		StatePruningRetention: blockchain.DefaultStatePruningRetention,
+		SnapshotAsyncGen:      true,
+		GasPrice:              big.NewInt(18 * params.Ston),
 
 		TxPool: blockchain.DefaultTxPoolConfig,
 		GPO: gasprice.Config{
@@ -104,22 +106,27 @@ type Config struct {
 	StartBlockNumber uint64
 
 	// Database options
-	DBType               database.DBType
-	SkipBcVersionCheck   bool `toml:"-"`
-	SingleDB             bool
-	NumStateTrieShards   uint
-	EnableDBPerfMetrics  bool
-	LevelDBCompression   database.LevelDBCompressionType
-	LevelDBBufferPool    bool
-	LevelDBCacheSize     int
-	DynamoDBConfig       database.DynamoDBConfig
-	TrieCacheSize        int
-	TrieTimeout          time.Duration
-	TrieBlockInterval    uint
-	TriesInMemory        uint64
-	SenderTxHashIndexing bool
-	ParallelDBWrite      bool
-	TrieNodeCacheConfig  statedb.TrieNodeCacheConfig
+	DBType                database.DBType
+	SkipBcVersionCheck    bool `toml:"-"`
+	SingleDB              bool
+	NumStateTrieShards    uint
+	EnableDBPerfMetrics   bool
+	LevelDBCompression    database.LevelDBCompressionType
+	LevelDBBufferPool     bool
+	LevelDBCacheSize      int
+	DynamoDBConfig        database.DynamoDBConfig
+	TrieCacheSize         int
+	TrieTimeout           time.Duration
+	TrieBlockInterval     uint
+	TriesInMemory         uint64
+	StatePruning          bool
+	StatePruningRetention uint64
+	SnapshotCacheSize     int
+	SnapshotAsyncGen      bool
+	SenderTxHashIndexing  bool
+	LogIndexing           bool
+	ParallelDBWrite       bool
+	TrieNodeCacheConfig   statedb.TrieNodeCacheConfig
 
 	// Mining-related options
 	ServiceChainSigner common.Address `toml:",omitempty"`
@@ -165,6 +172,12 @@ type Config struct {
 
 	// RPCGasCap is the global gas cap for eth-call variants.
 	RPCGasCap *big.Int `toml:",omitempty"`
+
+	// RPCLogQueryBlockRange is the maximum number of blocks scanned by a log query (0 = no limit).
+	RPCLogQueryBlockRange uint64
+
+	// RPCLogQueryResultLimit is the maximum number of logs returned by a log query (0 = no limit).
+	RPCLogQueryResultLimit int
 }
 
 type configMarshaling struct {
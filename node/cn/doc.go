@@ -32,6 +32,7 @@ Source Files
   - config.go           : defines the configuration used by CN struct
   - gen_config.go       : is automatically generated from config.go
   - handler.go          : implements ProtocolManager which handles the message and manages network peers
+  - logindex.go         : implements LogIndexer, an indexer of the blocks having the logs of each address and topic
   - metrics.go          : includes statistics used in cn package
   - peer.go             : provides the interface and implementation of Peer interface
   - peer_set.go         : provides the interface and implementation of PeerSet interface
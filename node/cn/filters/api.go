@@ -22,9 +22,11 @@ package filters
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sync"
 	"time"
@@ -41,8 +43,24 @@ import (
 
 var (
 	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
+
+	errInvalidCursor     = errors.New("invalid cursor")
+	errInvalidBlockRange = errors.New("invalid from and to block combination: from > to")
 )
 
+// Config has the limits of the log queries served by the filter API.
+type Config struct {
+	MaxBlockRange uint64 // Maximum number of blocks scanned by a log query (0 = no limit)
+	MaxResults    int    // Maximum number of logs returned by a log query (0 = no limit)
+}
+
+// LogsPage is a page of the logs returned by klay_getLogsPage. The cursor is
+// given to the next call to continue the query, and it is nil at the last page.
+type LogsPage struct {
+	Logs   []*types.Log   `json:"logs"`
+	Cursor *hexutil.Bytes `json:"cursor"`
+}
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -64,11 +82,17 @@ type PublicFilterAPI struct {
 	events    *EventSystem
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
+	config    Config
 }
 
-// NewPublicFilterAPI returns a new PublicFilterAPI instance.
-func NewPublicFilterAPI(backend Backend, lightMode bool) *PublicFilterAPI {
+// NewPublicFilterAPI returns a new PublicFilterAPI instance. The log queries
+// are not limited if the config is nil.
+func NewPublicFilterAPI(backend Backend, lightMode bool, config *Config) *PublicFilterAPI {
+	if config == nil {
+		config = &Config{}
+	}
 	api := &PublicFilterAPI{
+		config:  *config,
 		backend: backend,
 		mux:     backend.EventMux(),
 		chainDB: backend.ChainDB(),
@@ -204,13 +228,36 @@ func (api *PublicFilterAPI) NewBlockFilter() rpc.ID {
 	return headerSub.ID
 }
 
+// NewHeadsCriteria has the optional arguments of the newHeads subscription.
+type NewHeadsCriteria struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"` // block to replay the stored headers from
+}
+
 // NewHeads send a notification each time a new (header) block is appended to the chain.
-func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+// If fromBlock is given, the stored headers from the block are sent before the
+// new ones without gaps or duplicates. The headers replaced by a reorganization
+// are followed by the new canonical headers of the same numbers.
+func (api *PublicFilterAPI) NewHeads(ctx context.Context, crit *NewHeadsCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	if crit != nil && crit.FromBlock != nil {
+		from, replay, err := api.replayFrom(ctx, big.NewInt(crit.FromBlock.Int64()))
+		if err != nil {
+			return nil, err
+		}
+		if replay {
+			rpcSub := notifier.CreateSubscription()
+			added := func(header *types.Header) {
+				notifier.Notify(rpcSub.ID, header)
+			}
+			go api.replayChain(rpcSub, notifier, from, math.MaxUint64, added, func(*types.Header) {})
+			return rpcSub, nil
+		}
+	}
+
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
@@ -235,12 +282,43 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+// If fromBlock is given, the stored logs from the block are sent before the new
+// ones without gaps or duplicates, and the logs reverted by a reorganization
+// are sent again with removed set to true. The logs after toBlock are not sent,
+// and the pending logs are not supported with fromBlock.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	from, replay, err := api.replayFrom(ctx, crit.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	if replay {
+		to, err := replayTo(from, crit.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		rpcSub := notifier.CreateSubscription()
+		added := func(header *types.Header) {
+			for _, log := range api.blockLogs(header, crit.Addresses, crit.Topics) {
+				notifier.Notify(rpcSub.ID, log)
+			}
+		}
+		removed := func(header *types.Header) {
+			logs := api.blockLogs(header, crit.Addresses, crit.Topics)
+			for i := len(logs) - 1; i >= 0; i-- {
+				log := *logs[i]
+				log.Removed = true
+				notifier.Notify(rpcSub.ID, &log)
+			}
+		}
+		go api.replayChain(rpcSub, notifier, from, to, added, removed)
+		return rpcSub, nil
+	}
+
 	var (
 		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
@@ -328,14 +406,167 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	if crit.ToBlock == nil {
 		crit.ToBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
 	}
-	// Create and run the filter to get all the logs
-	filter := NewRangeFilter(api.backend, crit.FromBlock.Int64(), crit.ToBlock.Int64(), crit.Addresses, crit.Topics)
+	logs, err := api.rangeLogs(ctx, crit.FromBlock.Int64(), crit.ToBlock.Int64(), crit.Addresses, crit.Topics)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), err
+}
+
+// GetLogsPage returns a page of the logs matching the given argument. A page
+// scans the blocks up to the block range limit and stops at the block where the
+// number of the logs reaches the result limit. If the returned cursor is not
+// nil, the query is continued by calling it again with the same argument and
+// the cursor, which overrides the block range of the argument.
+func (api *PublicFilterAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, cursor *hexutil.Bytes) (*LogsPage, error) {
+	var begin, end uint64
+	if cursor != nil {
+		if len(*cursor) != 16 {
+			return nil, errInvalidCursor
+		}
+		begin, end = binary.BigEndian.Uint64((*cursor)[:8]), binary.BigEndian.Uint64((*cursor)[8:])
+		if begin > end {
+			return nil, errInvalidCursor
+		}
+	} else {
+		var err error
+		if begin, end, err = api.resolveRange(ctx, crit.FromBlock, crit.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+	// The blocks beyond the head are not scanned, so the pages end at the head
+	// instead of returning a cursor until the blocks are inserted
+	_, head, err := api.resolveRange(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if end > head {
+		end = head
+	}
+	last := end
+	if limit := api.config.MaxBlockRange; limit > 0 && end >= begin && end-begin >= limit {
+		last = begin + limit - 1
+	}
+	filter := NewRangeFilter(api.backend, int64(begin), int64(last), crit.Addresses, crit.Topics)
+	filter.SetLimit(api.config.MaxResults)
 
 	logs, err := filter.Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), err
+	page := &LogsPage{Logs: returnLogs(logs)}
+	if next := uint64(filter.Next()); next <= end {
+		encoded := make(hexutil.Bytes, 16)
+		binary.BigEndian.PutUint64(encoded[:8], next)
+		binary.BigEndian.PutUint64(encoded[8:], end)
+		page.Cursor = &encoded
+	}
+	return page, nil
+}
+
+// resolveRange returns the block numbers of the given range, resolving the
+// latest block number. A nil number is regarded as the latest block.
+func (api *PublicFilterAPI) resolveRange(ctx context.Context, from, to *big.Int) (uint64, uint64, error) {
+	header, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, 0, err
+	}
+	if header == nil {
+		return 0, 0, errors.New("latest header not found")
+	}
+	head := header.Number.Uint64()
+
+	begin, end := head, head
+	if from != nil && from.Sign() >= 0 {
+		begin = from.Uint64()
+	}
+	if to != nil && to.Sign() >= 0 {
+		end = to.Uint64()
+	}
+	return begin, end, nil
+}
+
+// rangeLogs returns the logs in the given block range matching the given
+// criteria. It returns an error if the query exceeds the limits.
+func (api *PublicFilterAPI) rangeLogs(ctx context.Context, begin, end int64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if limit := api.config.MaxBlockRange; limit > 0 {
+		from, to, err := api.resolveRange(ctx, big.NewInt(begin), big.NewInt(end))
+		if err != nil {
+			return nil, err
+		}
+		if to >= from && to-from >= limit {
+			return nil, fmt.Errorf("query exceeds the limit of %d blocks, narrow the block range or use klay_getLogsPage", limit)
+		}
+	}
+	// Create and run the filter to get all the logs
+	filter := NewRangeFilter(api.backend, begin, end, addresses, topics)
+	if limit := api.config.MaxResults; limit > 0 {
+		filter.SetLimit(limit + 1)
+	}
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit := api.config.MaxResults; limit > 0 && len(logs) > limit {
+		return nil, fmt.Errorf("query returns more than %d logs, narrow the block range or use klay_getLogsPage", limit)
+	}
+	return logs, nil
+}
+
+// replayFrom returns the number of the block to replay a subscription from,
+// or false if the subscription isn't replayed from a stored block. The replay
+// is limited to the block range limit of the log queries.
+func (api *PublicFilterAPI) replayFrom(ctx context.Context, fromBlock *big.Int) (uint64, bool, error) {
+	if fromBlock == nil || fromBlock.Sign() < 0 {
+		if fromBlock != nil && fromBlock.Int64() == rpc.PendingBlockNumber.Int64() {
+			return 0, false, kerrors.ErrPendingBlockNotSupported
+		}
+		return 0, false, nil
+	}
+	from := fromBlock.Uint64()
+	if limit := api.config.MaxBlockRange; limit > 0 {
+		_, head, err := api.resolveRange(ctx, nil, nil)
+		if err != nil {
+			return 0, false, err
+		}
+		if head >= from && head-from >= limit {
+			return 0, false, fmt.Errorf("replay exceeds the limit of %d blocks, subscribe from a later block", limit)
+		}
+	}
+	return from, true, nil
+}
+
+// replayTo returns the number of the last block to replay a log subscription
+// from the given block, which is unlimited for the latest block. The block
+// range is validated as a log subscription without a replay.
+func replayTo(from uint64, toBlock *big.Int) (uint64, error) {
+	if toBlock == nil || toBlock.Int64() == rpc.LatestBlockNumber.Int64() {
+		return math.MaxUint64, nil
+	}
+	if toBlock.Int64() == rpc.PendingBlockNumber.Int64() {
+		return 0, kerrors.ErrPendingBlockNotSupported
+	}
+	if toBlock.Sign() < 0 || toBlock.Uint64() < from {
+		return 0, errInvalidBlockRange
+	}
+	return toBlock.Uint64(), nil
+}
+
+// blockLogs returns the logs of the given block matching the given criteria.
+func (api *PublicFilterAPI) blockLogs(header *types.Header, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	if !bloomFilter(header.Bloom, addresses, topics) {
+		return nil
+	}
+	logsList, err := api.backend.GetLogs(context.Background(), header.Hash())
+	if err != nil {
+		logger.Warn("Failed to read the logs to replay", "number", header.Number, "hash", header.Hash(), "err", err)
+		return nil
+	}
+	var unfiltered []*types.Log
+	for _, logs := range logsList {
+		unfiltered = append(unfiltered, logs...)
+	}
+	return filterLogs(unfiltered, nil, nil, addresses, topics)
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -372,10 +603,7 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 	if f.crit.ToBlock != nil {
 		end = f.crit.ToBlock.Int64()
 	}
-	// Create and run the filter to get all the logs
-	filter := NewRangeFilter(api.backend, begin, end, f.crit.Addresses, f.crit.Topics)
-
-	logs, err := filter.Logs(ctx)
+	logs, err := api.rangeLogs(ctx, begin, end, f.crit.Addresses, f.crit.Topics)
 	if err != nil {
 		return nil, err
 	}
@@ -21,12 +21,21 @@
 package filters
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/consensus/gxhash"
 	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
@@ -187,3 +196,240 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+// TestGetLogsLimits tests that the log queries exceeding the limits are rejected.
+func TestGetLogsLimits(t *testing.T) {
+	addr := common.HexToAddress("0x1000")
+	backend := newLogsTestBackend(t, 100, func(number uint64) []*types.Log {
+		if number%10 == 0 {
+			return []*types.Log{{Address: addr}}
+		}
+		return nil
+	})
+	defer backend.db.Close()
+
+	api := NewPublicFilterAPI(backend, false, &Config{MaxBlockRange: 30, MaxResults: 2})
+	crit := FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(29), Addresses: []common.Address{addr}}
+	logs, err := api.GetLogs(context.Background(), crit)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+
+	// The latest block is resolved to check the block range
+	crit.ToBlock = nil
+	_, err = api.GetLogs(context.Background(), crit)
+	assert.Error(t, err)
+
+	crit.FromBlock, crit.ToBlock = big.NewInt(1), big.NewInt(30)
+	_, err = api.GetLogs(context.Background(), crit)
+	assert.Error(t, err)
+
+	// The filter logs are limited as well
+	api = NewPublicFilterAPI(backend, false, &Config{MaxResults: 2})
+	_, err = api.GetLogs(context.Background(), crit)
+	assert.Error(t, err)
+
+	id, err := api.NewFilter(crit)
+	assert.NoError(t, err)
+	_, err = api.GetFilterLogs(context.Background(), id)
+	assert.Error(t, err)
+
+	// The queries aren't limited without a config
+	api = NewPublicFilterAPI(backend, false, nil)
+	crit.FromBlock, crit.ToBlock = big.NewInt(0), nil
+	logs, err = api.GetLogs(context.Background(), crit)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, len(logs))
+}
+
+// TestGetLogsPage tests that all the logs are returned through the pages
+// within the limits.
+func TestGetLogsPage(t *testing.T) {
+	addr := common.HexToAddress("0x1000")
+	backend := newLogsTestBackend(t, 100, func(number uint64) []*types.Log {
+		if number%10 == 0 {
+			return []*types.Log{{Address: addr, Topics: []common.Hash{common.BigToHash(new(big.Int).SetUint64(number))}}}
+		}
+		return nil
+	})
+	defer backend.db.Close()
+
+	api := NewPublicFilterAPI(backend, false, &Config{MaxBlockRange: 30, MaxResults: 2})
+	crit := FilterCriteria{FromBlock: big.NewInt(0), Addresses: []common.Address{addr}}
+
+	var (
+		logs   []*types.Log
+		pages  int
+		cursor *hexutil.Bytes
+	)
+	for {
+		page, err := api.GetLogsPage(context.Background(), crit, cursor)
+		assert.NoError(t, err)
+		assert.True(t, len(page.Logs) <= 2)
+
+		logs = append(logs, page.Logs...)
+		pages++
+		if cursor = page.Cursor; cursor == nil {
+			break
+		}
+	}
+	assert.Equal(t, 10, len(logs))
+	for i, log := range logs {
+		assert.Equal(t, common.BigToHash(big.NewInt(int64(i+1)*10)), log.Topics[0])
+	}
+	// 0-20, 21-40, 41-60, 61-80, 81-100
+	assert.Equal(t, 5, pages)
+
+	// The pages end at the head even if the range is beyond the head
+	crit.FromBlock, crit.ToBlock = big.NewInt(95), big.NewInt(1000)
+	page, err := api.GetLogsPage(context.Background(), crit, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Logs))
+	assert.Nil(t, page.Cursor)
+
+	// The cursor of an unknown format is rejected
+	_, err = api.GetLogsPage(context.Background(), crit, &hexutil.Bytes{0x1})
+	assert.Error(t, err)
+}
+
+// TestSubscriptionReplay tests that the stored headers and logs are replayed
+// from the given block before the new ones, and the logs reverted by a
+// reorganization are sent again as removed.
+func TestSubscriptionReplay(t *testing.T) {
+	addr := common.HexToAddress("0x1000")
+	logsAt := func(number uint64) []*types.Log {
+		return []*types.Log{{Address: addr, BlockNumber: number}}
+	}
+	backend := newLogsTestBackend(t, 10, logsAt)
+	defer backend.db.Close()
+
+	api := NewPublicFilterAPI(backend, false, &Config{MaxBlockRange: 8})
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("klay", api))
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The replay is limited to the block range limit
+	_, err := client.KlaySubscribe(ctx, make(chan types.Log), "logs", map[string]interface{}{"fromBlock": "0x2"})
+	assert.Error(t, err)
+
+	logs := make(chan types.Log, 20)
+	logsSub, err := client.KlaySubscribe(ctx, logs, "logs", map[string]interface{}{"fromBlock": "0x5", "address": addr})
+	assert.NoError(t, err)
+	defer logsSub.Unsubscribe()
+	heads := make(chan types.Header, 20)
+	headsSub, err := client.KlaySubscribe(ctx, heads, "newHeads", map[string]interface{}{"fromBlock": "0x8"})
+	assert.NoError(t, err)
+	defer headsSub.Unsubscribe()
+
+	receiveLog := func() types.Log {
+		select {
+		case log := <-logs:
+			return log
+		case <-ctx.Done():
+			t.Fatal("timeout to receive a log")
+			return types.Log{}
+		}
+	}
+	receiveHead := func() types.Header {
+		select {
+		case head := <-heads:
+			return head
+		case <-ctx.Done():
+			t.Fatal("timeout to receive a header")
+			return types.Header{}
+		}
+	}
+	for number := uint64(5); number <= 10; number++ {
+		log := receiveLog()
+		assert.Equal(t, number, log.BlockNumber)
+		assert.False(t, log.Removed)
+	}
+	for number := int64(8); number <= 10; number++ {
+		assert.Equal(t, number, receiveHead().Number.Int64())
+	}
+
+	// Replace the blocks 9 and 10 with the ones having the different logs
+	parentHash := backend.db.ReadCanonicalHash(8)
+	parent := backend.db.ReadBlock(parentHash, 8)
+	fork, receipts := blockchain.GenerateChain(params.TestChainConfig, parent, gxhash.NewFaker(), backend.db, 3, func(i int, gen *blockchain.BlockGen) {
+		receipt := genReceipt(false, 0)
+		receipt.Logs = []*types.Log{{Address: addr, BlockNumber: uint64(i + 9), Data: []byte{1}}}
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x2"), big.NewInt(1), 1, big.NewInt(1), nil))
+	})
+	for i, block := range fork {
+		backend.db.WriteBlock(block)
+		backend.db.WriteCanonicalHash(block.Hash(), block.NumberU64())
+		backend.db.WriteHeadBlockHash(block.Hash())
+		backend.db.WriteReceipts(block.Hash(), block.NumberU64(), receipts[i])
+	}
+	backend.chainFeed.Send(blockchain.ChainEvent{Block: fork[2], Hash: fork[2].Hash()})
+
+	for _, number := range []uint64{10, 9} {
+		log := receiveLog()
+		assert.Equal(t, number, log.BlockNumber)
+		assert.True(t, log.Removed)
+		assert.Empty(t, log.Data)
+	}
+	for number := uint64(9); number <= 11; number++ {
+		log := receiveLog()
+		assert.Equal(t, number, log.BlockNumber)
+		assert.False(t, log.Removed)
+		assert.Equal(t, []byte{1}, log.Data)
+	}
+	for i, block := range fork {
+		head := receiveHead()
+		assert.Equal(t, block.Hash(), head.Hash(), i)
+	}
+}
+
+// TestSubscriptionReplayRange tests that the logs are replayed up to the given
+// block, and the invalid block ranges are rejected.
+func TestSubscriptionReplayRange(t *testing.T) {
+	addr := common.HexToAddress("0x1000")
+	backend := newLogsTestBackend(t, 10, func(number uint64) []*types.Log {
+		return []*types.Log{{Address: addr, BlockNumber: number}}
+	})
+	defer backend.db.Close()
+
+	api := NewPublicFilterAPI(backend, false, &Config{MaxBlockRange: 8})
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("klay", api))
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The pending logs are not replayed, and the range should be in order
+	_, err := client.KlaySubscribe(ctx, make(chan types.Log), "logs", map[string]interface{}{"fromBlock": "0x5", "toBlock": "pending"})
+	assert.Error(t, err)
+	_, err = client.KlaySubscribe(ctx, make(chan types.Log), "logs", map[string]interface{}{"fromBlock": "0x5", "toBlock": "0x4"})
+	assert.Error(t, err)
+
+	logs := make(chan types.Log, 20)
+	logsSub, err := client.KlaySubscribe(ctx, logs, "logs", map[string]interface{}{"fromBlock": "0x3", "toBlock": "0x5", "address": addr})
+	assert.NoError(t, err)
+	defer logsSub.Unsubscribe()
+
+	for number := uint64(3); number <= 5; number++ {
+		select {
+		case log := <-logs:
+			assert.Equal(t, number, log.BlockNumber)
+		case <-ctx.Done():
+			t.Fatal("timeout to receive a log")
+		}
+	}
+	// The logs after the block are not sent with the new heads
+	backend.chainFeed.Send(blockchain.ChainEvent{Block: backend.db.ReadBlockByNumber(10), Hash: backend.db.ReadCanonicalHash(10)})
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log of the block %d", log.BlockNumber)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -26,5 +26,6 @@ Source Files
   - api.go           : provides public filter API functions to generate filters and use them to filter the result
   - filter.go        : implements basic filtering system based on bloom filter
   - filter_system.go : provides subscription scheme to register and filter the specific events
+  - replay.go        : replays the stored headers to the subscriptions from a given block before the new ones
 */
 package filters
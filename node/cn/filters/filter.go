@@ -26,6 +26,7 @@ import (
 	"github.com/klaytn/klaytn/blockchain/bloombits"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/bitutil"
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/storage/database"
@@ -46,6 +47,7 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 
 	BloomStatus() (uint64, uint64)
+	LogIndexStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 }
 
@@ -57,6 +59,10 @@ type Filter struct {
 	addresses  []common.Address
 	topics     [][]common.Hash
 
+	// limit is the number of logs to stop the search at. The logs of the last
+	// block searched are always returned together, so more logs can be returned.
+	limit int
+
 	matcher *bloombits.Matcher
 }
 
@@ -104,6 +110,36 @@ func newFilter(backend Backend, addresses []common.Address, topics [][]common.Ha
 	}
 }
 
+// SetLimit makes the filter stop the search at the end of the block where the
+// number of the found logs reaches the given limit. Zero means no limit.
+func (f *Filter) SetLimit(limit int) {
+	f.limit = limit
+}
+
+// Next returns the number of the next block to be searched by the filter.
+func (f *Filter) Next() int64 {
+	return f.begin
+}
+
+// full returns true if the given logs reach the limit of the filter.
+func (f *Filter) full(logs []*types.Log) bool {
+	return f.limit > 0 && len(logs) >= f.limit
+}
+
+// selective returns true if the filter has any criteria of the addresses or
+// topics, so the log index can be used to find the matching blocks.
+func (f *Filter) selective() bool {
+	if len(f.addresses) > 0 {
+		return true
+	}
+	for _, topics := range f.topics {
+		if len(topics) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
@@ -121,30 +157,134 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
-	// Gather all indexed logs, and finish with non indexed ones
+	// Gather the logs with the log index first, then the bloom bits indexed ones,
+	// and finish with non indexed ones
 	var (
 		logs []*types.Log
 		err  error
 	)
+	if size, sections := f.backend.LogIndexStatus(); f.selective() && sections*size > uint64(f.begin) {
+		if indexed := sections * size; indexed > end {
+			logs, err = f.logIndexedLogs(ctx, end, logs)
+		} else {
+			logs, err = f.logIndexedLogs(ctx, indexed-1, logs)
+		}
+		if err != nil || f.full(logs) {
+			return logs, err
+		}
+	}
 	size, sections := f.backend.BloomStatus()
 	if indexed := sections * size; indexed > uint64(f.begin) {
 		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
+			logs, err = f.indexedLogs(ctx, end, logs)
 		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+			logs, err = f.indexedLogs(ctx, indexed-1, logs)
+		}
+		if err != nil || f.full(logs) {
+			return logs, err
+		}
+	}
+	return f.unindexedLogs(ctx, end, logs)
+}
+
+// logIndexedLogs appends the logs matching the filter criteria to the given
+// logs based on the log index of the addresses and topics.
+func (f *Filter) logIndexedLogs(ctx context.Context, end uint64, logs []*types.Log) ([]*types.Log, error) {
+	size, _ := f.backend.LogIndexStatus()
+
+	for section := uint64(f.begin) / size; section*size <= end; section++ {
+		first, last := section*size, (section+1)*size-1
+		if last > end {
+			last = end
 		}
+		blocks, err := f.logIndexBlocks(section, size)
 		if err != nil {
 			return logs, err
 		}
+		for number := uint64(f.begin); number <= last; number++ {
+			offset := number - first
+			if blocks[offset/8]&(1<<(7-offset%8)) == 0 {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return logs, ctx.Err()
+			default:
+			}
+			f.begin = int64(number) + 1
+
+			// Retrieve the indexed block and pull the matching logs, which can
+			// be none if the addresses and topics are in different logs
+			header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return logs, err
+			}
+			found, err := f.checkMatches(ctx, header)
+			if err != nil {
+				return logs, err
+			}
+			logs = append(logs, found...)
+			if f.full(logs) {
+				return logs, nil
+			}
+		}
+		f.begin = int64(last) + 1
 	}
-	rest, err := f.unindexedLogs(ctx, end)
-	logs = append(logs, rest...)
-	return logs, err
+	return logs, nil
 }
 
-// indexedLogs returns the logs matching the filter criteria based on the bloom
-// bits indexed available locally or via the network.
-func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+// logIndexBlocks returns the bitset of the blocks in the given section, which
+// contain any of the addresses and any of the topics at each position.
+func (f *Filter) logIndexBlocks(section, size uint64) ([]byte, error) {
+	db := f.backend.ChainDB()
+	head := db.ReadCanonicalHash((section+1)*size - 1)
+
+	var criteria [][][]byte
+	if len(f.addresses) > 0 {
+		keys := make([][]byte, len(f.addresses))
+		for i, address := range f.addresses {
+			keys[i] = address.Bytes()
+		}
+		criteria = append(criteria, keys)
+	}
+	for _, topics := range f.topics {
+		if len(topics) == 0 {
+			continue // empty rule set == wildcard
+		}
+		keys := make([][]byte, len(topics))
+		for i, topic := range topics {
+			keys[i] = topic.Bytes()
+		}
+		criteria = append(criteria, keys)
+	}
+	// Union the blocks of the alternatives of a criterion and intersect them
+	// across the criteria
+	var blocks []byte
+	for _, keys := range criteria {
+		union := make([]byte, size/8)
+		for _, key := range keys {
+			compVector, err := db.ReadLogIndex(database.LogIndexKey(key, section, head))
+			if err != nil {
+				continue // no log of the section has the address or topic
+			}
+			vector, err := bitutil.DecompressBytes(compVector, int(size/8))
+			if err != nil {
+				return nil, err
+			}
+			bitutil.ORBytes(union, union, vector)
+		}
+		if blocks == nil {
+			blocks = union
+		} else {
+			bitutil.ANDBytes(blocks, blocks, union)
+		}
+	}
+	return blocks, nil
+}
+
+// indexedLogs appends the logs matching the filter criteria to the given logs
+// based on the bloom bits indexed available locally or via the network.
+func (f *Filter) indexedLogs(ctx context.Context, end uint64, logs []*types.Log) ([]*types.Log, error) {
 	// Create a matcher session and request servicing from the backend
 	matches := make(chan uint64, 64)
 
@@ -157,8 +297,6 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 	f.backend.ServiceFilter(ctx, session)
 
 	// Iterate over the matches until exhausted or context closed
-	var logs []*types.Log
-
 	for {
 		select {
 		case number, ok := <-matches:
@@ -182,6 +320,9 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 				return logs, err
 			}
 			logs = append(logs, found...)
+			if f.full(logs) {
+				return logs, nil
+			}
 
 		case <-ctx.Done():
 			return logs, ctx.Err()
@@ -189,11 +330,9 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 	}
 }
 
-// indexedLogs returns the logs matching the filter criteria based on raw block
-// iteration and bloom matching.
-func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
-	var logs []*types.Log
-
+// unindexedLogs appends the logs matching the filter criteria to the given logs
+// based on raw block iteration and bloom matching.
+func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logs []*types.Log) ([]*types.Log, error) {
 	for ; f.begin <= int64(end); f.begin++ {
 		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.begin))
 		if header == nil || err != nil {
@@ -205,6 +344,10 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 				return logs, err
 			}
 			logs = append(logs, found...)
+			if f.full(logs) {
+				f.begin++
+				return logs, nil
+			}
 		}
 	}
 	return logs, nil
@@ -312,3 +455,15 @@ func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]commo
 	}
 	return true
 }
+
+// FilterLogs returns the logs matching the given addresses and topics, which
+// are the criteria of a log filter without the block range.
+func FilterLogs(logs []*types.Log, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	return filterLogs(logs, nil, nil, addresses, topics)
+}
+
+// BloomFilter returns false if the block of the given bloom has no log
+// matching the given addresses and topics.
+func BloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	return bloomFilter(bloom, addresses, topics)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/bitutil"
 	"github.com/klaytn/klaytn/consensus/gxhash"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/event"
@@ -104,7 +105,7 @@ func TestFilter_unindexedLogs(t *testing.T) {
 	{
 		mockCtrl, mockBackend, newFilter := genFilter(t)
 		mockBackend.EXPECT().HeaderByNumber(ctx, rpc.BlockNumber(newFilter.begin)).Times(1).Return(nil, nil)
-		logs, err := newFilter.unindexedLogs(ctx, uint64(newFilter.end))
+		logs, err := newFilter.unindexedLogs(ctx, uint64(newFilter.end), nil)
 		assert.Nil(t, logs)
 		assert.NoError(t, err)
 		mockCtrl.Finish()
@@ -112,7 +113,7 @@ func TestFilter_unindexedLogs(t *testing.T) {
 	{
 		mockCtrl, mockBackend, newFilter := genFilter(t)
 		mockBackend.EXPECT().HeaderByNumber(ctx, rpc.BlockNumber(newFilter.begin)).Times(1).Return(header, nil)
-		logs, err := newFilter.unindexedLogs(ctx, uint64(newFilter.end))
+		logs, err := newFilter.unindexedLogs(ctx, uint64(newFilter.end), nil)
 		assert.Nil(t, logs)
 		assert.NoError(t, err)
 		mockCtrl.Finish()
@@ -168,7 +169,7 @@ func BenchmarkFilters(b *testing.B) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
 		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		addr1      = crypto.PubkeyToAddress(key1.PublicKey)
 		addr2      = common.BytesToAddress([]byte("jeff"))
@@ -231,7 +232,7 @@ func TestFilters(t *testing.T) {
 		rmLogsFeed = new(event.Feed)
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
-		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		backend    = &testBackend{mux, db, 0, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
 		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		addr       = crypto.PubkeyToAddress(key1.PublicKey)
 
@@ -350,3 +351,116 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// newLogsTestBackend returns a test backend having a chain of the given number
+// of blocks, whose receipts have the logs returned by the given function.
+func newLogsTestBackend(t *testing.T, n int, logsAt func(number uint64) []*types.Log) *testBackend {
+	var (
+		db      = database.NewMemoryDBManager()
+		backend = &testBackend{new(event.TypeMux), db, 0, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)}
+		genesis = blockchain.GenesisBlockForTesting(db, common.HexToAddress("0x1"), big.NewInt(1000000))
+	)
+	chain, receipts := blockchain.GenerateChain(params.TestChainConfig, genesis, gxhash.NewFaker(), db, n, func(i int, gen *blockchain.BlockGen) {
+		logs := logsAt(uint64(i + 1))
+		if len(logs) == 0 {
+			return
+		}
+		receipt := genReceipt(false, 0)
+		receipt.Logs = logs
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+	})
+	for i, block := range chain {
+		db.WriteBlock(block)
+		db.WriteCanonicalHash(block.Hash(), block.NumberU64())
+		db.WriteHeadBlockHash(block.Hash())
+		db.WriteReceipts(block.Hash(), block.NumberU64(), receipts[i])
+	}
+	return backend
+}
+
+// writeLogIndex stores the given blocks as the ones containing the given
+// address or topic in the log index.
+func writeLogIndex(db database.DBManager, key []byte, section uint64, blocks ...uint64) {
+	size := params.BloomBitsBlocks
+	bits := make([]byte, size/8)
+	for _, number := range blocks {
+		offset := number - section*size
+		bits[offset/8] |= 1 << (7 - offset%8)
+	}
+	head := db.ReadCanonicalHash((section+1)*size - 1)
+	batch := db.NewBatch(database.MiscDB)
+	batch.Put(database.LogIndexKey(key, section, head), bitutil.CompressBytes(bits))
+	batch.Write()
+}
+
+// TestFilters_LogIndex tests that the blocks having the matching logs are
+// found with the log index in the indexed sections.
+func TestFilters_LogIndex(t *testing.T) {
+	var (
+		addr      = common.HexToAddress("0x1000")
+		unindexed = common.HexToAddress("0x2000")
+		hash1     = common.BytesToHash([]byte("topic1"))
+		hash2     = common.BytesToHash([]byte("topic2"))
+		hash3     = common.BytesToHash([]byte("topic3"))
+	)
+	backend := newLogsTestBackend(t, int(params.BloomBitsBlocks)+2, func(number uint64) []*types.Log {
+		switch number {
+		case 1:
+			return []*types.Log{{Address: addr, Topics: []common.Hash{hash1}}}
+		case 2:
+			return []*types.Log{{Address: addr, Topics: []common.Hash{hash2}}, {Address: unindexed}}
+		case 4000:
+			return []*types.Log{{Address: addr, Topics: []common.Hash{hash3}}}
+		case params.BloomBitsBlocks + 1:
+			return []*types.Log{{Address: addr, Topics: []common.Hash{hash1}}}
+		}
+		return nil
+	})
+	defer backend.db.Close()
+
+	backend.logIndexed = 1
+	writeLogIndex(backend.db, addr.Bytes(), 0, 1, 2, 4000)
+	writeLogIndex(backend.db, hash1.Bytes(), 0, 1)
+	writeLogIndex(backend.db, hash2.Bytes(), 0, 2)
+	writeLogIndex(backend.db, hash3.Bytes(), 0, 4000)
+
+	// The logs after the indexed section are found without the log index
+	filter := NewRangeFilter(backend, 0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash3}})
+	logs, err := filter.Logs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(logs))
+	for i, topic := range []common.Hash{hash1, hash3, hash1} {
+		assert.Equal(t, topic, logs[i].Topics[0])
+	}
+
+	// The blocks not in the log index aren't searched in the indexed section
+	filter = NewRangeFilter(backend, 0, int64(params.BloomBitsBlocks-1), []common.Address{unindexed}, nil)
+	logs, err = filter.Logs(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	// The search stops at the block where the logs reach the limit
+	filter = NewRangeFilter(backend, 0, -1, []common.Address{addr}, nil)
+	filter.SetLimit(2)
+	logs, err = filter.Logs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, int64(3), filter.Next())
+
+	filter = NewRangeFilter(backend, filter.Next(), -1, []common.Address{addr}, nil)
+	filter.SetLimit(2)
+	logs, err = filter.Logs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, int64(params.BloomBitsBlocks+2), filter.Next())
+
+	// The search without the log index also stops at the limit
+	backend.logIndexed = 0
+	filter = NewRangeFilter(backend, 0, -1, nil, [][]common.Hash{{hash1, hash2}})
+	filter.SetLimit(1)
+	logs, err = filter.Logs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, int64(2), filter.Next())
+}
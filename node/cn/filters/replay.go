@@ -0,0 +1,218 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+var errReplayClosed = errors.New("replay closed")
+
+// chainReplayer delivers the canonical headers of a given block range to a
+// subscriber in order. When the delivered headers are removed from the
+// canonical chain by a reorganization, they are reverted in reverse order
+// before the new canonical headers are delivered, so that each header is
+// delivered once unless it is reverted.
+type chainReplayer struct {
+	backend Backend
+	chainDB database.DBManager
+	quit    <-chan struct{} // closed when the subscription is ended
+
+	from uint64        // number of the first header to be delivered
+	to   uint64        // number of the last header to be delivered
+	last *types.Header // last delivered header or the parent of the first one
+	next uint64        // number of the next header to be delivered
+
+	added   func(header *types.Header) // delivers a header
+	removed func(header *types.Header) // reverts a delivered header
+}
+
+// newChainReplayer creates a replayer delivering the headers between the given
+// block numbers.
+func newChainReplayer(backend Backend, chainDB database.DBManager, quit <-chan struct{}, from, to uint64, added, removed func(*types.Header)) *chainReplayer {
+	r := &chainReplayer{backend: backend, chainDB: chainDB, quit: quit, from: from, to: to, next: from, added: added, removed: removed}
+	if from > 0 {
+		// The parent is the last delivered header to detect a reorganization
+		// before the first header is delivered.
+		r.last, _ = backend.HeaderByNumber(context.Background(), rpc.BlockNumber(from-1))
+	}
+	return r
+}
+
+// advance reverts the delivered headers which are not canonical anymore, and
+// delivers the canonical headers up to the given block number.
+func (r *chainReplayer) advance(ctx context.Context, head uint64) error {
+	if head > r.to {
+		head = r.to
+	}
+	for {
+		if err := r.revert(ctx); err != nil {
+			return err
+		}
+		reorged, err := r.deliver(ctx, head)
+		if err != nil || !reorged {
+			return err
+		}
+	}
+}
+
+// revert reverts the delivered headers until the last delivered one is on the
+// canonical chain.
+func (r *chainReplayer) revert(ctx context.Context) error {
+	for r.last != nil {
+		number := r.last.Number.Uint64()
+		canonical, err := r.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return err
+		}
+		if canonical != nil && canonical.Hash() == r.last.Hash() {
+			return nil
+		}
+		if number < r.from {
+			// The parent of the first header isn't delivered, so it is just replaced
+			r.last = canonical
+			return nil
+		}
+		r.removed(r.last)
+		r.next = number
+		if number == 0 {
+			r.last = nil
+		} else {
+			r.last = r.chainDB.ReadHeader(r.last.ParentHash, number-1)
+		}
+	}
+	return nil
+}
+
+// deliver delivers the canonical headers following the last delivered one up
+// to the given block number. It returns true if the canonical chain doesn't
+// follow the last delivered header by a reorganization.
+func (r *chainReplayer) deliver(ctx context.Context, head uint64) (bool, error) {
+	for ; r.next <= head; r.next++ {
+		select {
+		case <-r.quit:
+			return false, errReplayClosed
+		default:
+		}
+		header, err := r.backend.HeaderByNumber(ctx, rpc.BlockNumber(r.next))
+		if err != nil {
+			return false, err
+		}
+		if header == nil {
+			// The block isn't inserted yet, it is delivered with the next head
+			return false, nil
+		}
+		if r.last != nil && header.ParentHash != r.last.Hash() {
+			return true, nil
+		}
+		r.added(header)
+		r.last = header
+	}
+	return false, nil
+}
+
+// replayChain runs a subscription delivering the headers between the given
+// block numbers with the replayer, and then the new headers as they are
+// appended to the chain until the subscription is ended.
+func (api *PublicFilterAPI) replayChain(rpcSub *rpc.Subscription, notifier *rpc.Notifier, from, to uint64, added, removed func(*types.Header)) {
+	quit := make(chan struct{})
+	go func() {
+		select {
+		case <-rpcSub.Err():
+		case <-notifier.Closed():
+		}
+		close(quit)
+	}()
+
+	var (
+		ctx      = context.Background()
+		replayer = newChainReplayer(api.backend, api.chainDB, quit, from, to, added, removed)
+	)
+	// Replay the stored headers until catching up the latest one before the
+	// new headers are subscribed, not to block the event system for a long time.
+	for {
+		latest, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if err != nil || latest == nil {
+			logger.Error("Failed to read the latest header to replay", "err", err)
+			<-quit
+			return
+		}
+		next := replayer.next
+		if next > latest.Number.Uint64() {
+			break
+		}
+		if err := replayer.advance(ctx, latest.Number.Uint64()); err == errReplayClosed {
+			return
+		} else if err != nil {
+			logger.Error("Failed to replay the headers", "from", replayer.next, "err", err)
+			<-quit
+			return
+		}
+		if replayer.next <= next {
+			// The rest is delivered with the new headers
+			break
+		}
+	}
+
+	headers := make(chan *types.Header)
+	headersSub := api.events.SubscribeNewHeads(headers)
+	defer headersSub.Unsubscribe()
+
+	// The new heads are forwarded not to block the event system while the
+	// headers are delivered. Only the latest head is kept since the replayer
+	// delivers all the headers up to it.
+	heads := make(chan uint64, 1)
+	go func() {
+		for {
+			select {
+			case h := <-headers:
+				select {
+				case <-heads:
+				default:
+				}
+				heads <- h.Number.Uint64()
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	// The headers appended during the subscription are delivered at first.
+	// The headers already delivered are skipped by the replayer.
+	if latest, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber); err == nil && latest != nil {
+		if err := replayer.advance(ctx, latest.Number.Uint64()); err == errReplayClosed {
+			return
+		}
+	}
+	for {
+		select {
+		case head := <-heads:
+			if err := replayer.advance(ctx, head); err == errReplayClosed {
+				return
+			} else if err != nil {
+				logger.Warn("Failed to deliver the new headers", "number", head, "err", err)
+			}
+		case <-quit:
+			return
+		}
+	}
+}
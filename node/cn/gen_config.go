@@ -41,6 +41,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieTimeout             time.Duration
 		TrieBlockInterval       uint
 		TriesInMemory           uint64
+		StatePruning            bool
+		StatePruningRetention   uint64
+		SnapshotCacheSize       int
+		SnapshotAsyncGen        bool
 		SenderTxHashIndexing    bool
 		ParallelDBWrite         bool
 		TrieNodeCacheConfig     statedb.TrieNodeCacheConfig
@@ -85,6 +89,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrieTimeout = c.TrieTimeout
 	enc.TrieBlockInterval = c.TrieBlockInterval
 	enc.TriesInMemory = c.TriesInMemory
+	enc.StatePruning = c.StatePruning
+	enc.StatePruningRetention = c.StatePruningRetention
+	enc.SnapshotCacheSize = c.SnapshotCacheSize
+	enc.SnapshotAsyncGen = c.SnapshotAsyncGen
 	enc.SenderTxHashIndexing = c.SenderTxHashIndexing
 	enc.ParallelDBWrite = c.ParallelDBWrite
 	enc.TrieNodeCacheConfig = c.TrieNodeCacheConfig
@@ -133,6 +141,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieTimeout             *time.Duration
 		TrieBlockInterval       *uint
 		TriesInMemory           *uint64
+		StatePruning            *bool
+		StatePruningRetention   *uint64
+		SnapshotCacheSize       *int
+		SnapshotAsyncGen        *bool
 		SenderTxHashIndexing    *bool
 		ParallelDBWrite         *bool
 		TrieNodeCacheConfig     *statedb.TrieNodeCacheConfig
@@ -220,6 +232,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TriesInMemory != nil {
 		c.TriesInMemory = *dec.TriesInMemory
 	}
+	if dec.StatePruning != nil {
+		c.StatePruning = *dec.StatePruning
+	}
+	if dec.StatePruningRetention != nil {
+		c.StatePruningRetention = *dec.StatePruningRetention
+	}
+	if dec.SnapshotCacheSize != nil {
+		c.SnapshotCacheSize = *dec.SnapshotCacheSize
+	}
+	if dec.SnapshotAsyncGen != nil {
+		c.SnapshotAsyncGen = *dec.SnapshotAsyncGen
+	}
 	if dec.SenderTxHashIndexing != nil {
 		c.SenderTxHashIndexing = *dec.SenderTxHashIndexing
 	}
@@ -84,6 +84,7 @@ type ProtocolManager struct {
 	networkId uint64
 
 	fastSync  uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
+	snapSync  uint32 // Flag whether fast sync should operate on top of the range based state download
 	acceptTxs uint32 // Flag whether we're considered synchronised (enables transaction processing)
 
 	txpool      work.TxPool
@@ -93,6 +94,7 @@ type ProtocolManager struct {
 
 	downloader ProtocolManagerDownloader
 	fetcher    ProtocolManagerFetcher
+	txFetcher  ProtocolManagerTxFetcher
 	peers      PeerSet
 
 	SubProtocols []p2p.Protocol
@@ -153,13 +155,16 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	}
 
 	// Figure out whether to allow fast sync or not
-	if mode == downloader.FastSync && blockchain.CurrentBlock().NumberU64() > 0 {
+	if (mode == downloader.FastSync || mode == downloader.SnapSync) && blockchain.CurrentBlock().NumberU64() > 0 {
 		logger.Error("Blockchain not empty, fast sync disabled")
 		mode = downloader.FullSync
 	}
-	if mode == downloader.FastSync {
+	if mode == downloader.FastSync || mode == downloader.SnapSync {
 		manager.fastSync = uint32(1)
 	}
+	if mode == downloader.SnapSync {
+		manager.snapSync = uint32(1)
+	}
 	// istanbul BFT
 	protocol := engine.Protocol()
 	// Initiate a sub-protocol for every implemented version we can handle
@@ -169,6 +174,9 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		if mode == downloader.FastSync && version < klay63 {
 			continue
 		}
+		if mode == downloader.SnapSync && version < klay65 {
+			continue
+		}
 		// Compatible; initialise the sub-protocol
 		version := version
 		manager.SubProtocols = append(manager.SubProtocols, p2p.Protocol{
@@ -273,6 +281,19 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, manager.BroadcastBlockHash, heighter, inserter, manager.removePeer)
 	}
 
+	// Create and set tx fetcher retrieving the announced transactions
+	hasTx := func(hash common.Hash) bool {
+		return txpool.Get(hash) != nil
+	}
+	fetchTxs := func(id string, hashes []common.Hash) error {
+		p := manager.peers.Peer(id)
+		if p == nil {
+			return errNotRegistered
+		}
+		return p.RequestTxs(hashes)
+	}
+	manager.txFetcher = fetcher.NewTxFetcher(hasTx, txpool.HandleTxMsg, fetchTxs)
+
 	if manager.useTxResend() {
 		go manager.txResendLoop(cnconfig.TxResendInterval, cnconfig.TxResendCount)
 	}
@@ -304,8 +325,9 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 	logger.Debug("Removing Klaytn peer", "peer", id)
 
-	// Unregister the peer from the downloader and peer set
+	// Unregister the peer from the downloader, tx fetcher and peer set
 	pm.downloader.UnregisterPeer(id)
+	pm.txFetcher.Drop(id)
 	if err := pm.peers.Unregister(id); err != nil {
 		logger.Error("Peer removal failed", "peer", id, "err", err)
 	}
@@ -588,6 +610,36 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 			return err
 		}
 
+	case p.GetVersion() >= klay65 && msg.Code == AccountRangeRequestMsg:
+		if err := handleAccountRangeRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay65 && msg.Code == AccountRangeMsg:
+		if err := handleAccountRangeMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay65 && msg.Code == StorageRangesRequestMsg:
+		if err := handleStorageRangesRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay65 && msg.Code == StorageRangesMsg:
+		if err := handleStorageRangesMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay65 && msg.Code == ByteCodesRequestMsg:
+		if err := handleByteCodesRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay65 && msg.Code == ByteCodesMsg:
+		if err := handleByteCodesMsg(pm, p, msg); err != nil {
+			return err
+		}
+
 	case msg.Code == NewBlockHashesMsg:
 		if err := handleNewBlockHashesMsg(pm, p, msg); err != nil {
 			return err
@@ -623,6 +675,21 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 			return err
 		}
 
+	case p.GetVersion() >= klay66 && msg.Code == NewPooledTransactionHashesMsg:
+		if err := handleNewPooledTransactionHashesMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay66 && msg.Code == GetPooledTransactionsMsg:
+		if err := handleGetPooledTransactionsMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay66 && msg.Code == PooledTransactionsMsg:
+		if err := handlePooledTransactionsMsg(pm, p, msg); err != nil {
+			return err
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -828,6 +895,85 @@ func handleNodeDataMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	return nil
 }
 
+// handleAccountRangeRequestMsg handles account range request message.
+func handleAccountRangeRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var req accountRangeRequestData
+	if err := msg.Decode(&req); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	accounts, proof := pm.answerAccountRange(&req)
+	return p.SendAccountRange(accounts, proof)
+}
+
+// handleAccountRangeMsg handles account range response message.
+func handleAccountRangeMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var res accountRangeData
+	if err := msg.Decode(&res); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	hashes := make([]common.Hash, len(res.Accounts))
+	accounts := make([][]byte, len(res.Accounts))
+	for i, acc := range res.Accounts {
+		hashes[i], accounts[i] = acc.Hash, acc.Body
+	}
+	if err := pm.downloader.DeliverAccountRange(p.GetID(), hashes, accounts, res.Proof); err != nil {
+		logger.Debug("Failed to deliver account range", "err", err)
+	}
+	return nil
+}
+
+// handleStorageRangesRequestMsg handles storage ranges request message.
+func handleStorageRangesRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var req storageRangesRequestData
+	if err := msg.Decode(&req); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	slots, proof := pm.answerStorageRanges(&req)
+	return p.SendStorageRanges(slots, proof)
+}
+
+// handleStorageRangesMsg handles storage ranges response message.
+func handleStorageRangesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var res storageRangesData
+	if err := msg.Decode(&res); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	hashes := make([][]common.Hash, len(res.Slots))
+	slots := make([][][]byte, len(res.Slots))
+	for i, storage := range res.Slots {
+		hashes[i] = make([]common.Hash, len(storage))
+		slots[i] = make([][]byte, len(storage))
+		for j, slot := range storage {
+			hashes[i][j], slots[i][j] = slot.Hash, slot.Body
+		}
+	}
+	if err := pm.downloader.DeliverStorageRanges(p.GetID(), hashes, slots, res.Proof); err != nil {
+		logger.Debug("Failed to deliver storage ranges", "err", err)
+	}
+	return nil
+}
+
+// handleByteCodesRequestMsg handles byte codes request message.
+func handleByteCodesRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var req byteCodesRequestData
+	if err := msg.Decode(&req); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	return p.SendByteCodes(pm.answerByteCodes(&req))
+}
+
+// handleByteCodesMsg handles byte codes response message.
+func handleByteCodesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var codes [][]byte
+	if err := msg.Decode(&codes); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if err := pm.downloader.DeliverByteCodes(p.GetID(), codes); err != nil {
+		logger.Debug("Failed to deliver byte codes", "err", err)
+	}
+	return nil
+}
+
 // handleGetReceiptsMsg handles receipt request message.
 func handleReceiptsRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	// Decode the retrieval message
@@ -1036,9 +1182,82 @@ func handleTxMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 		txReceiveCounter.Inc(1)
 	}
 	pm.txpool.HandleTxMsg(validTxs)
+	// The tx fetcher stops waiting for the announcements of the transactions
+	pm.txFetcher.MarkBroadcast(p.GetID(), validTxs)
 	return err
 }
 
+// handleNewPooledTransactionHashesMsg handles transaction hash announcement message.
+func handleNewPooledTransactionHashesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	// New transaction announcement arrived, make sure we have
+	// a valid and fresh chain to handle them
+	if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+		return nil
+	}
+	var hashes []common.Hash
+	if err := msg.Decode(&hashes); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	// Schedule all the unknown hashes for retrieval
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+	}
+	return pm.txFetcher.Notify(p.GetID(), hashes)
+}
+
+// handleGetPooledTransactionsMsg handles transaction retrieval request message.
+// The transactions not in the pool are skipped in the reply.
+func handleGetPooledTransactionsMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	// Decode the retrieval message
+	msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
+	if _, err := msgStream.List(); err != nil {
+		return err
+	}
+	// Gather transactions until the fetch or network limits is reached
+	var (
+		hash  common.Hash
+		bytes common.StorageSize
+		txs   types.Transactions
+	)
+	for bytes < softResponseLimit && len(txs) < fetcher.MaxTxRetrievals {
+		// Retrieve the hash of the next transaction
+		if err := msgStream.Decode(&hash); err == rlp.EOL {
+			break
+		} else if err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		// Retrieve the requested transaction, skipping if unknown to us
+		tx := pm.txpool.Get(hash)
+		if tx == nil {
+			continue
+		}
+		txs = append(txs, tx)
+		bytes += tx.Size()
+	}
+	return p.SendPooledTransactions(txs)
+}
+
+// handlePooledTransactionsMsg handles transaction retrieval response message.
+func handlePooledTransactionsMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	// Transactions arrived, make sure we have a valid and fresh chain to handle them
+	if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+		return nil
+	}
+	var txs types.Transactions
+	if err := msg.Decode(&txs); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	for i, tx := range txs {
+		// Validate and mark the remote transaction
+		if tx == nil {
+			return errResp(ErrDecode, "transaction %d is nil", i)
+		}
+		p.AddToKnownTxs(tx.Hash())
+		txReceiveCounter.Inc(1)
+	}
+	return pm.txFetcher.Enqueue(p.GetID(), txs, true)
+}
+
 // sampleSize calculates the number of peers to send block.
 // If calcSampleSize is smaller than minNumPeersToSendBlock, it returns minNumPeersToSendBlock.
 // Otherwise, it returns calcSampleSize.
@@ -1152,20 +1371,22 @@ func (pm *ProtocolManager) broadcastTxsFromPN(txs types.Transactions) {
 
 	propTxPeersGauge.Update(int64(len(peersWithoutTxs) + len(cnPeersWithoutTxs)))
 	sendTransactions(cnPeersWithoutTxs)
-	sendTransactions(peersWithoutTxs)
+	announceTransactions(peersWithoutTxs)
 }
 
 func (pm *ProtocolManager) broadcastTxsFromEN(txs types.Transactions) {
+	cnPeersWithoutTxs := make(map[Peer]types.Transactions)
 	peersWithoutTxs := make(map[Peer]types.Transactions)
 	for _, tx := range txs {
-		pm.peers.UpdateTypePeersWithoutTxs(tx, common.CONSENSUSNODE, peersWithoutTxs)
+		pm.peers.UpdateTypePeersWithoutTxs(tx, common.CONSENSUSNODE, cnPeersWithoutTxs)
 		pm.peers.UpdateTypePeersWithoutTxs(tx, common.PROXYNODE, peersWithoutTxs)
 		pm.peers.UpdateTypePeersWithoutTxs(tx, common.ENDPOINTNODE, peersWithoutTxs)
 		txSendCounter.Inc(1)
 	}
 
-	propTxPeersGauge.Update(int64(len(peersWithoutTxs)))
-	sendTransactions(peersWithoutTxs)
+	propTxPeersGauge.Update(int64(len(peersWithoutTxs) + len(cnPeersWithoutTxs)))
+	sendTransactions(cnPeersWithoutTxs)
+	announceTransactions(peersWithoutTxs)
 }
 
 // ReBroadcastTxs sends transactions, not considering whether the peer has the transaction or not.
@@ -1199,6 +1420,28 @@ func sendTransactions(txsSet map[Peer]types.Transactions) {
 	}
 }
 
+// announceTransactions iterates the given map with the key-value pair of Peer and Transactions
+// and announces the hashes of the paired transactions to the peer, so that the peer retrieves
+// only the unknown ones. The transactions are sent directly to the peers which do not support
+// the transaction announcement.
+func announceTransactions(txsSet map[Peer]types.Transactions) {
+	for peer, txs := range txsSet {
+		if peer.GetVersion() < klay66 {
+			if err := peer.SendTransactions(txs); err != nil {
+				logger.Error("Failed to send txs", "peer", peer.GetAddr(), "peerType", peer.ConnType(), "numTxs", len(txs), "err", err)
+			}
+			continue
+		}
+		hashes := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		if err := peer.SendPooledTransactionHashes(hashes); err != nil {
+			logger.Error("Failed to announce txs", "peer", peer.GetAddr(), "peerType", peer.ConnType(), "numTxs", len(txs), "err", err)
+		}
+	}
+}
+
 func samplingPeers(peers []Peer, pickSize int) []Peer {
 	if len(peers) <= pickSize {
 		return peers
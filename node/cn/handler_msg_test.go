@@ -258,18 +258,101 @@ func TestHandleTxMsg(t *testing.T) {
 	{
 		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
 	}
-	// If pm.acceptTxs == 1, TxPool.HandleTxMsg is called.
+	// If pm.acceptTxs == 1, the transactions are added to the pool and marked in the tx fetcher.
 	{
 		atomic.StoreUint32(&pm.acceptTxs, 1)
 		mockTxPool := mocks.NewMockTxPool(mockCtrl)
-		mockTxPool.EXPECT().HandleTxMsg(gomock.Eq(txs)).AnyTimes()
+		mockTxPool.EXPECT().HandleTxMsg(gomock.Eq(txs)).Times(1)
 		pm.txpool = mockTxPool
+		mockTxFetcher := mocks2.NewMockProtocolManagerTxFetcher(mockCtrl)
+		mockTxFetcher.EXPECT().MarkBroadcast(nodeids[0].String(), gomock.Eq(txs)).Times(1)
+		pm.txFetcher = mockTxFetcher
 
+		mockPeer.EXPECT().GetID().Return(nodeids[0].String()).AnyTimes()
 		mockPeer.EXPECT().AddToKnownTxs(txs[0].Hash()).Times(1)
 		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
 	}
 }
 
+func TestHandleNewPooledTransactionHashesMsg(t *testing.T) {
+	pm := &ProtocolManager{}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPeer := NewMockPeer(mockCtrl)
+	mockPeer.EXPECT().GetID().Return(nodeids[0].String()).AnyTimes()
+
+	hashes := []common.Hash{tx1.Hash()}
+	msg := generateMsg(t, NewPooledTransactionHashesMsg, hashes)
+
+	// The message is not handled if the peer does not support the announcement.
+	{
+		oldPeer := NewMockPeer(mockCtrl)
+		oldPeer.EXPECT().GetVersion().Return(klay65).AnyTimes()
+		assert.Error(t, pm.handleMsg(oldPeer, addrs[0], msg))
+	}
+
+	mockPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+	// If pm.acceptTxs == 0, nothing happens.
+	{
+		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+	}
+	// If pm.acceptTxs == 1, the hashes are notified to the tx fetcher.
+	{
+		atomic.StoreUint32(&pm.acceptTxs, 1)
+		mockTxFetcher := mocks2.NewMockProtocolManagerTxFetcher(mockCtrl)
+		mockTxFetcher.EXPECT().Notify(nodeids[0].String(), hashes).Return(nil).Times(1)
+		pm.txFetcher = mockTxFetcher
+
+		mockPeer.EXPECT().AddToKnownTxs(tx1.Hash()).Times(1)
+		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+	}
+}
+
+func TestHandleGetPooledTransactionsMsg(t *testing.T) {
+	pm := &ProtocolManager{}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPeer := NewMockPeer(mockCtrl)
+	mockPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+
+	mockTxPool := mocks.NewMockTxPool(mockCtrl)
+	mockTxPool.EXPECT().Get(tx1.Hash()).Return(tx1).Times(1)
+	mockTxPool.EXPECT().Get(hash1).Return(nil).Times(1)
+	pm.txpool = mockTxPool
+
+	// The transactions not in the pool are skipped in the reply.
+	msg := generateMsg(t, GetPooledTransactionsMsg, []common.Hash{hash1, tx1.Hash()})
+	mockPeer.EXPECT().SendPooledTransactions(types.Transactions{tx1}).Return(nil).Times(1)
+	assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+}
+
+func TestHandlePooledTransactionsMsg(t *testing.T) {
+	pm := &ProtocolManager{}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPeer := NewMockPeer(mockCtrl)
+	mockPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+	mockPeer.EXPECT().GetID().Return(nodeids[0].String()).AnyTimes()
+
+	txs := types.Transactions{tx1}
+	msg := generateMsg(t, PooledTransactionsMsg, txs)
+
+	// If pm.acceptTxs == 0, nothing happens.
+	{
+		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+	}
+	// If pm.acceptTxs == 1, the transactions are delivered to the tx fetcher.
+	{
+		atomic.StoreUint32(&pm.acceptTxs, 1)
+		mockTxFetcher := mocks2.NewMockProtocolManagerTxFetcher(mockCtrl)
+		mockTxFetcher.EXPECT().Enqueue(nodeids[0].String(), gomock.Eq(txs), true).Return(nil).Times(1)
+		pm.txFetcher = mockTxFetcher
+
+		mockPeer.EXPECT().AddToKnownTxs(tx1.Hash()).Times(1)
+		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+	}
+}
+
 func prepareTestHandleBlockHeaderFetchRequestMsg(t *testing.T) (*gomock.Controller, *MockPeer, *mocks.MockBlockChain, *ProtocolManager) {
 	mockCtrl := gomock.NewController(t)
 	mockPeer := NewMockPeer(mockCtrl)
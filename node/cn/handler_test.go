@@ -229,6 +229,10 @@ func TestProtocolManager_removePeer(t *testing.T) {
 		mockDownloader.EXPECT().UnregisterPeer(peerID).Times(1)
 		pm.downloader = mockDownloader
 
+		mockTxFetcher := mocks.NewMockProtocolManagerTxFetcher(mockCtrl)
+		mockTxFetcher.EXPECT().Drop(peerID).Times(1)
+		pm.txFetcher = mockTxFetcher
+
 		// Return
 		mockPeerSet.EXPECT().Unregister(peerID).Return(expectedErr).Times(1)
 
@@ -253,6 +257,10 @@ func TestProtocolManager_removePeer(t *testing.T) {
 		mockDownloader.EXPECT().UnregisterPeer(peerID).Times(1)
 		pm.downloader = mockDownloader
 
+		mockTxFetcher := mocks.NewMockProtocolManagerTxFetcher(mockCtrl)
+		mockTxFetcher.EXPECT().Drop(peerID).Times(1)
+		pm.txFetcher = mockTxFetcher
+
 		// Return
 		mockPeerSet.EXPECT().Unregister(peerID).Return(nil).Times(1)
 
@@ -513,6 +521,7 @@ func TestBroadcastTxsFromPN_PN_Exists(t *testing.T) {
 	enPeer.EXPECT().ConnType().Return(common.ENDPOINTNODE).Times(1)
 
 	pnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+	pnPeer.EXPECT().GetVersion().Return(klay65).AnyTimes()
 
 	cnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
 	pnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
@@ -521,6 +530,33 @@ func TestBroadcastTxsFromPN_PN_Exists(t *testing.T) {
 	pm.BroadcastTxs(txs)
 }
 
+func TestBroadcastTxsFromPN_AnnounceHashes(t *testing.T) {
+	pm := &ProtocolManager{}
+	pm.nodetype = common.PROXYNODE
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	peers := newPeerSet()
+	pm.peers = peers
+	cnPeer, pnPeer, enPeer := createAndRegisterPeers(mockCtrl, peers)
+
+	cnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+
+	cnPeer.EXPECT().ConnType().Return(common.CONSENSUSNODE).Times(1)
+	pnPeer.EXPECT().ConnType().Return(common.PROXYNODE).Times(1)
+	enPeer.EXPECT().ConnType().Return(common.ENDPOINTNODE).Times(1)
+
+	pnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+	pnPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+
+	// The transactions are sent to CN directly, while the hashes are announced to PN.
+	cnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
+	pnPeer.EXPECT().SendTransactions(gomock.Any()).Times(0)
+	pnPeer.EXPECT().SendPooledTransactionHashes([]common.Hash{tx1.Hash()}).Times(1)
+
+	pm.BroadcastTxs(txs)
+}
+
 func TestBroadcastTxsFromEN_ALL_NotExists(t *testing.T) {
 	pm := &ProtocolManager{}
 	pm.nodetype = common.ENDPOINTNODE
@@ -564,6 +600,9 @@ func TestBroadcastTxsFromEN_ALL_Exists(t *testing.T) {
 	pnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
 	enPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
 
+	pnPeer.EXPECT().GetVersion().Return(klay65).AnyTimes()
+	enPeer.EXPECT().GetVersion().Return(klay65).AnyTimes()
+
 	cnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
 	pnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
 	enPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
@@ -571,6 +610,36 @@ func TestBroadcastTxsFromEN_ALL_Exists(t *testing.T) {
 	pm.BroadcastTxs(txs)
 }
 
+func TestBroadcastTxsFromEN_AnnounceHashes(t *testing.T) {
+	pm := &ProtocolManager{}
+	pm.nodetype = common.ENDPOINTNODE
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	peers := newPeerSet()
+	pm.peers = peers
+	cnPeer, pnPeer, enPeer := createAndRegisterPeers(mockCtrl, peers)
+
+	cnPeer.EXPECT().ConnType().Return(common.CONSENSUSNODE).AnyTimes()
+	pnPeer.EXPECT().ConnType().Return(common.PROXYNODE).AnyTimes()
+	enPeer.EXPECT().ConnType().Return(common.ENDPOINTNODE).AnyTimes()
+
+	cnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+	pnPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+	enPeer.EXPECT().KnowsTx(tx1.Hash()).Return(false).Times(1)
+
+	// The hashes are announced to the peers supporting the announcement,
+	// and the transactions are sent to the others and CN.
+	pnPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+	enPeer.EXPECT().GetVersion().Return(klay65).AnyTimes()
+
+	cnPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
+	pnPeer.EXPECT().SendPooledTransactionHashes([]common.Hash{tx1.Hash()}).Times(1)
+	enPeer.EXPECT().SendTransactions(gomock.Eq(txs)).Times(1)
+
+	pm.BroadcastTxs(txs)
+}
+
 func TestBroadcastTxsFrom_DefaultCase(t *testing.T) {
 	pm := &ProtocolManager{}
 	pm.nodetype = common.BOOTNODE
@@ -0,0 +1,145 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/bitutil"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// logIndexDB is the view of the database used by the log index chain indexer to
+// track its progress apart from the bloom bits indexer.
+type logIndexDB struct {
+	database.DBManager
+}
+
+func (db logIndexDB) ReadValidSections() ([]byte, error) {
+	return db.ReadLogIndexValidSections()
+}
+
+func (db logIndexDB) WriteValidSections(encodedSections []byte) {
+	db.WriteLogIndexValidSections(encodedSections)
+}
+
+func (db logIndexDB) ReadSectionHead(encodedSection []byte) ([]byte, error) {
+	return db.ReadLogIndexSectionHead(encodedSection)
+}
+
+func (db logIndexDB) WriteSectionHead(encodedSection []byte, hash common.Hash) {
+	db.WriteLogIndexSectionHead(encodedSection, hash)
+}
+
+func (db logIndexDB) DeleteSectionHead(encodedSection []byte) {
+	db.DeleteLogIndexSectionHead(encodedSection)
+}
+
+// LogIndexer implements a blockchain.ChainIndexer, building up an index of the
+// blocks containing the logs of each address and topic. Unlike the bloom bits,
+// the index has no false positives, so highly selective log queries only read
+// the receipts of the blocks which actually have the matching logs.
+type LogIndexer struct {
+	size uint64 // section size to generate the log index for
+
+	db     database.DBManager  // database instance to read receipts and write index data into
+	blocks map[string][]uint64 // offsets of the blocks containing each address or topic
+
+	section uint64      // Section is the section number being processed currently
+	head    common.Hash // Head is the hash of the last header processed
+	err     error       // err is the error occurred while processing the section
+}
+
+// NewLogIndexer returns a chain indexer that generates the log index for the
+// canonical chain for fast logs filtering of specific addresses and topics.
+func NewLogIndexer(db database.DBManager, size uint64) *blockchain.ChainIndexer {
+	backend := &LogIndexer{
+		db:   db,
+		size: size,
+	}
+
+	return blockchain.NewChainIndexer(db, logIndexDB{db}, backend, size, bloomConfirms, bloomThrottling, "logindex")
+}
+
+// Reset implements blockchain.ChainIndexerBackend, starting a new log index
+// section.
+func (b *LogIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	b.blocks, b.section, b.head, b.err = make(map[string][]uint64), section, common.Hash{}, nil
+	return nil
+}
+
+// Process implements blockchain.ChainIndexerBackend, adding the addresses and
+// topics of a new header's logs into the index.
+func (b *LogIndexer) Process(header *types.Header) {
+	b.head = header.Hash()
+	if header.Bloom == (types.Bloom{}) || b.err != nil {
+		return
+	}
+	number := header.Number.Uint64()
+	receipts := b.db.ReadReceipts(b.head, number)
+	if receipts == nil {
+		b.err = fmt.Errorf("missing receipts of block %d", number)
+		return
+	}
+	offset := number - b.section*b.size
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			b.add(log.Address.Bytes(), offset)
+			for _, topic := range log.Topics {
+				b.add(topic.Bytes(), offset)
+			}
+		}
+	}
+}
+
+// add marks the block of the given offset as containing the given address or topic.
+func (b *LogIndexer) add(key []byte, offset uint64) {
+	blocks := b.blocks[string(key)]
+	if len(blocks) > 0 && blocks[len(blocks)-1] == offset {
+		return
+	}
+	b.blocks[string(key)] = append(blocks, offset)
+}
+
+// Commit implements blockchain.ChainIndexerBackend, finalizing the log index
+// section and writing it out into the database.
+func (b *LogIndexer) Commit() error {
+	if b.err != nil {
+		return b.err
+	}
+	batch := b.db.NewBatch(database.MiscDB)
+
+	for key, blocks := range b.blocks {
+		bits := make([]byte, b.size/8)
+		for _, offset := range blocks {
+			bits[offset/8] |= 1 << (7 - offset%8)
+		}
+		if err := batch.Put(database.LogIndexKey([]byte(key), b.section, b.head), bitutil.CompressBytes(bits)); err != nil {
+			logger.Crit("Failed to store log index", "err", err)
+		}
+		if batch.ValueSize() > database.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
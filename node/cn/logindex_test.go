@@ -0,0 +1,120 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/bitutil"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLogIndexer tests that the log indexer stores the blocks containing the
+// logs of each address and topic.
+func TestLogIndexer(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		size    = uint64(16)
+		addr1   = common.HexToAddress("0x1")
+		addr2   = common.HexToAddress("0x2")
+		topic   = common.HexToHash("0x3")
+		genesis = blockchain.GenesisBlockForTesting(db, addr1, big.NewInt(1000000))
+	)
+	defer db.Close()
+
+	chain, receipts := blockchain.GenerateChain(params.TestChainConfig, genesis, gxhash.NewFaker(), db, int(2*size), func(i int, gen *blockchain.BlockGen) {
+		var logs []*types.Log
+		switch i {
+		case 2, 7:
+			logs = []*types.Log{{Address: addr1, Topics: []common.Hash{topic}}, {Address: addr1}}
+		case 9, 20:
+			logs = []*types.Log{{Address: addr2}}
+		default:
+			return
+		}
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: logs}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+	})
+	for i, block := range chain {
+		db.WriteBlock(block)
+		db.WriteCanonicalHash(block.Hash(), block.NumberU64())
+		db.WriteReceipts(block.Hash(), block.NumberU64(), receipts[i])
+	}
+	headers := make([]*types.Header, 0, len(chain)+1)
+	headers = append(headers, genesis.Header())
+	for _, block := range chain {
+		headers = append(headers, block.Header())
+	}
+
+	indexer := &LogIndexer{db: db, size: size}
+	readBlocks := func(key []byte, section uint64) []uint64 {
+		head := headers[(section+1)*size-1].Hash()
+		compVector, err := db.ReadLogIndex(database.LogIndexKey(key, section, head))
+		if err != nil {
+			return nil
+		}
+		vector, err := bitutil.DecompressBytes(compVector, int(size/8))
+		assert.NoError(t, err)
+
+		var blocks []uint64
+		for i := uint64(0); i < size; i++ {
+			if vector[i/8]&(1<<(7-i%8)) != 0 {
+				blocks = append(blocks, section*size+i)
+			}
+		}
+		return blocks
+	}
+	for section := uint64(0); section < 2; section++ {
+		assert.NoError(t, indexer.Reset(section, common.Hash{}))
+		for _, header := range headers[section*size : (section+1)*size] {
+			indexer.Process(header)
+		}
+		assert.NoError(t, indexer.Commit())
+	}
+	assert.Equal(t, []uint64{3, 8}, readBlocks(addr1.Bytes(), 0))
+	assert.Equal(t, []uint64{3, 8}, readBlocks(topic.Bytes(), 0))
+	assert.Equal(t, []uint64{10}, readBlocks(addr2.Bytes(), 0))
+	assert.Equal(t, []uint64{21}, readBlocks(addr2.Bytes(), 1))
+	assert.Nil(t, readBlocks(addr1.Bytes(), 1))
+
+	// The section fails if the receipts of a block having logs are missing
+	db.DeleteReceipts(headers[3].Hash(), 3)
+	assert.NoError(t, indexer.Reset(0, common.Hash{}))
+	for _, header := range headers[:size] {
+		indexer.Process(header)
+	}
+	assert.Error(t, indexer.Commit())
+
+	// The progress of the log indexer is stored apart from the bloom bits indexer
+	var encoded [8]byte
+	binary.BigEndian.PutUint64(encoded[:], 1)
+	logIndexDB{db}.WriteValidSections(encoded[:])
+	data, _ := db.ReadLogIndexValidSections()
+	assert.Equal(t, encoded[:], data)
+	data, _ = db.ReadValidSections()
+	assert.Nil(t, data)
+}
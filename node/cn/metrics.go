@@ -32,6 +32,10 @@ var (
 	propTxnInTrafficMeter                = metrics.NewRegisteredMeter("klay/prop/txns/in/traffic", nil)
 	propTxnOutPacketsMeter               = metrics.NewRegisteredMeter("klay/prop/txns/out/packets", nil)
 	propTxnOutTrafficMeter               = metrics.NewRegisteredMeter("klay/prop/txns/out/traffic", nil)
+	propTxnHashInPacketsMeter            = metrics.NewRegisteredMeter("klay/prop/txhashes/in/packets", nil)
+	propTxnHashInTrafficMeter            = metrics.NewRegisteredMeter("klay/prop/txhashes/in/traffic", nil)
+	propTxnHashOutPacketsMeter           = metrics.NewRegisteredMeter("klay/prop/txhashes/out/packets", nil)
+	propTxnHashOutTrafficMeter           = metrics.NewRegisteredMeter("klay/prop/txhashes/out/traffic", nil)
 	propTxPeersGauge                     = metrics.NewRegisteredGauge("klay/prop/tx/peers/gauge", nil)
 	propHashInPacketsMeter               = metrics.NewRegisteredMeter("klay/prop/hashes/in/packets", nil)
 	propHashInTrafficMeter               = metrics.NewRegisteredMeter("klay/prop/hashes/in/traffic", nil)
@@ -57,6 +61,14 @@ var (
 	reqReceiptInTrafficMeter             = metrics.NewRegisteredMeter("klay/req/receipts/in/traffic", nil)
 	reqReceiptOutPacketsMeter            = metrics.NewRegisteredMeter("klay/req/receipts/out/packets", nil)
 	reqReceiptOutTrafficMeter            = metrics.NewRegisteredMeter("klay/req/receipts/out/traffic", nil)
+	reqSnapInPacketsMeter                = metrics.NewRegisteredMeter("klay/req/snap/in/packets", nil)
+	reqSnapInTrafficMeter                = metrics.NewRegisteredMeter("klay/req/snap/in/traffic", nil)
+	reqSnapOutPacketsMeter               = metrics.NewRegisteredMeter("klay/req/snap/out/packets", nil)
+	reqSnapOutTrafficMeter               = metrics.NewRegisteredMeter("klay/req/snap/out/traffic", nil)
+	reqTxnInPacketsMeter                 = metrics.NewRegisteredMeter("klay/req/txns/in/packets", nil)
+	reqTxnInTrafficMeter                 = metrics.NewRegisteredMeter("klay/req/txns/in/traffic", nil)
+	reqTxnOutPacketsMeter                = metrics.NewRegisteredMeter("klay/req/txns/out/packets", nil)
+	reqTxnOutTrafficMeter                = metrics.NewRegisteredMeter("klay/req/txns/out/traffic", nil)
 	miscInPacketsMeter                   = metrics.NewRegisteredMeter("klay/misc/in/packets", nil)
 	miscInTrafficMeter                   = metrics.NewRegisteredMeter("klay/misc/in/traffic", nil)
 	miscOutPacketsMeter                  = metrics.NewRegisteredMeter("klay/misc/out/packets", nil)
@@ -114,6 +126,10 @@ func (rw *meteredMsgReadWriter) ReadMsg() (p2p.Msg, error) {
 		packets, traffic = reqStateInPacketsMeter, reqStateInTrafficMeter
 	case rw.version >= klay63 && msg.Code == ReceiptsMsg:
 		packets, traffic = reqReceiptInPacketsMeter, reqReceiptInTrafficMeter
+	case rw.version >= klay65 && (msg.Code == AccountRangeMsg || msg.Code == StorageRangesMsg || msg.Code == ByteCodesMsg):
+		packets, traffic = reqSnapInPacketsMeter, reqSnapInTrafficMeter
+	case rw.version >= klay66 && msg.Code == PooledTransactionsMsg:
+		packets, traffic = reqTxnInPacketsMeter, reqTxnInTrafficMeter
 
 	case msg.Code == NewBlockHashesMsg:
 		packets, traffic = propHashInPacketsMeter, propHashInTrafficMeter
@@ -121,6 +137,8 @@ func (rw *meteredMsgReadWriter) ReadMsg() (p2p.Msg, error) {
 		packets, traffic = propBlockInPacketsMeter, propBlockInTrafficMeter
 	case msg.Code == TxMsg:
 		packets, traffic = propTxnInPacketsMeter, propTxnInTrafficMeter
+	case rw.version >= klay66 && msg.Code == NewPooledTransactionHashesMsg:
+		packets, traffic = propTxnHashInPacketsMeter, propTxnHashInTrafficMeter
 	case msg.Code == backend.IstanbulMsg:
 		packets, traffic = propConsensusIstanbulInPacketsMeter, propConsensusIstanbulInTrafficMeter
 	}
@@ -143,6 +161,10 @@ func (rw *meteredMsgReadWriter) WriteMsg(msg p2p.Msg) error {
 		packets, traffic = reqStateOutPacketsMeter, reqStateOutTrafficMeter
 	case rw.version >= klay63 && msg.Code == ReceiptsMsg:
 		packets, traffic = reqReceiptOutPacketsMeter, reqReceiptOutTrafficMeter
+	case rw.version >= klay65 && (msg.Code == AccountRangeMsg || msg.Code == StorageRangesMsg || msg.Code == ByteCodesMsg):
+		packets, traffic = reqSnapOutPacketsMeter, reqSnapOutTrafficMeter
+	case rw.version >= klay66 && msg.Code == PooledTransactionsMsg:
+		packets, traffic = reqTxnOutPacketsMeter, reqTxnOutTrafficMeter
 
 	case msg.Code == NewBlockHashesMsg:
 		packets, traffic = propHashOutPacketsMeter, propHashOutTrafficMeter
@@ -150,6 +172,8 @@ func (rw *meteredMsgReadWriter) WriteMsg(msg p2p.Msg) error {
 		packets, traffic = propBlockOutPacketsMeter, propBlockOutTrafficMeter
 	case msg.Code == TxMsg:
 		packets, traffic = propTxnOutPacketsMeter, propTxnOutTrafficMeter
+	case rw.version >= klay66 && msg.Code == NewPooledTransactionHashesMsg:
+		packets, traffic = propTxnHashOutPacketsMeter, propTxnHashOutTrafficMeter
 	case msg.Code == backend.IstanbulMsg:
 		packets, traffic = propConsensusIstanbulOutPacketsMeter, propConsensusIstanbulOutTrafficMeter
 	}
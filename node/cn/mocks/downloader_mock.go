@@ -37,6 +37,20 @@ func (m *MockProtocolManagerDownloader) EXPECT() *MockProtocolManagerDownloaderM
 	return m.recorder
 }
 
+// DeliverAccountRange mocks base method
+func (m *MockProtocolManagerDownloader) DeliverAccountRange(arg0 string, arg1 []common.Hash, arg2 [][]byte, arg3 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverAccountRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverAccountRange indicates an expected call of DeliverAccountRange
+func (mr *MockProtocolManagerDownloaderMockRecorder) DeliverAccountRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverAccountRange", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).DeliverAccountRange), arg0, arg1, arg2, arg3)
+}
+
 // DeliverBodies mocks base method
 func (m *MockProtocolManagerDownloader) DeliverBodies(arg0 string, arg1 [][]*types.Transaction) error {
 	m.ctrl.T.Helper()
@@ -51,6 +65,20 @@ func (mr *MockProtocolManagerDownloaderMockRecorder) DeliverBodies(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverBodies", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).DeliverBodies), arg0, arg1)
 }
 
+// DeliverByteCodes mocks base method
+func (m *MockProtocolManagerDownloader) DeliverByteCodes(arg0 string, arg1 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverByteCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverByteCodes indicates an expected call of DeliverByteCodes
+func (mr *MockProtocolManagerDownloaderMockRecorder) DeliverByteCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverByteCodes", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).DeliverByteCodes), arg0, arg1)
+}
+
 // DeliverHeaders mocks base method
 func (m *MockProtocolManagerDownloader) DeliverHeaders(arg0 string, arg1 []*types.Header) error {
 	m.ctrl.T.Helper()
@@ -93,6 +121,20 @@ func (mr *MockProtocolManagerDownloaderMockRecorder) DeliverReceipts(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverReceipts", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).DeliverReceipts), arg0, arg1)
 }
 
+// DeliverStorageRanges mocks base method
+func (m *MockProtocolManagerDownloader) DeliverStorageRanges(arg0 string, arg1 [][]common.Hash, arg2 [][][]byte, arg3 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverStorageRanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverStorageRanges indicates an expected call of DeliverStorageRanges
+func (mr *MockProtocolManagerDownloaderMockRecorder) DeliverStorageRanges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverStorageRanges", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).DeliverStorageRanges), arg0, arg1, arg2, arg3)
+}
+
 // Progress mocks base method
 func (m *MockProtocolManagerDownloader) Progress() klaytn.SyncProgress {
 	m.ctrl.T.Helper()
@@ -121,6 +121,18 @@ type Peer interface {
 	// AsyncSendTransactions sends transactions asynchronously to the peer.
 	AsyncSendTransactions(txs types.Transactions)
 
+	// SendPooledTransactionHashes announces the availability of transactions to the
+	// peer and includes the hashes in its transaction hash set for future reference.
+	SendPooledTransactionHashes(hashes []common.Hash) error
+
+	// SendPooledTransactions sends a batch of transactions, corresponding to the
+	// hashes requested.
+	SendPooledTransactions(txs types.Transactions) error
+
+	// RequestTxs fetches a batch of transactions corresponding to the hashes
+	// announced by the peer.
+	RequestTxs(hashes []common.Hash) error
+
 	// SendNewBlockHashes announces the availability of a number of blocks through
 	// a hash notification.
 	SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error
@@ -162,6 +174,18 @@ type Peer interface {
 	// ones requested from an already RLP encoded format.
 	SendReceiptsRLP(receipts []rlp.RawValue) error
 
+	// SendAccountRange sends a range of accounts along with the Merkle proofs of
+	// the edges, corresponding to the range requested.
+	SendAccountRange(accounts []*accountData, proof [][]byte) error
+
+	// SendStorageRanges sends ranges of storage slots along with the Merkle proofs
+	// of the edges of the last range, corresponding to the ranges requested.
+	SendStorageRanges(slots [][]*storageData, proof [][]byte) error
+
+	// SendByteCodes sends a batch of contract codes, corresponding to the hashes
+	// requested.
+	SendByteCodes(codes [][]byte) error
+
 	// FetchBlockHeader is a wrapper around the header query functions to fetch a
 	// single header. It is used solely by the fetcher.
 	FetchBlockHeader(hash common.Hash) error
@@ -301,6 +325,19 @@ var ChannelOfMessage = map[uint64]int{
 	NodeDataMsg:        p2p.ConnDefault,
 	ReceiptsRequestMsg: p2p.ConnDefault,
 	ReceiptsMsg:        p2p.ConnDefault,
+
+	// Protocol messages belonging to klay/65
+	AccountRangeRequestMsg:  p2p.ConnDefault,
+	AccountRangeMsg:         p2p.ConnDefault,
+	StorageRangesRequestMsg: p2p.ConnDefault,
+	StorageRangesMsg:        p2p.ConnDefault,
+	ByteCodesRequestMsg:     p2p.ConnDefault,
+	ByteCodesMsg:            p2p.ConnDefault,
+
+	// Protocol messages belonging to klay/66
+	NewPooledTransactionHashesMsg: p2p.ConnTxMsg,
+	GetPooledTransactionsMsg:      p2p.ConnTxMsg,
+	PooledTransactionsMsg:         p2p.ConnTxMsg,
 }
 
 var ConcurrentOfChannel = []int{
@@ -453,6 +490,24 @@ func (p *basePeer) AsyncSendTransactions(txs types.Transactions) {
 	}
 }
 
+// SendPooledTransactionHashes announces the availability of transactions to the
+// peer and includes the hashes in its transaction hash set for future reference.
+func (p *basePeer) SendPooledTransactionHashes(hashes []common.Hash) error {
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+	}
+	return p2p.Send(p.rw, NewPooledTransactionHashesMsg, hashes)
+}
+
+// SendPooledTransactions sends a batch of transactions, corresponding to the
+// hashes requested.
+func (p *basePeer) SendPooledTransactions(txs types.Transactions) error {
+	for _, tx := range txs {
+		p.AddToKnownTxs(tx.Hash())
+	}
+	return p2p.Send(p.rw, PooledTransactionsMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *basePeer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -535,6 +590,24 @@ func (p *basePeer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p2p.Send(p.rw, ReceiptsMsg, receipts)
 }
 
+// SendAccountRange sends a range of accounts along with the Merkle proofs of
+// the edges, corresponding to the range requested.
+func (p *basePeer) SendAccountRange(accounts []*accountData, proof [][]byte) error {
+	return p2p.Send(p.rw, AccountRangeMsg, &accountRangeData{Accounts: accounts, Proof: proof})
+}
+
+// SendStorageRanges sends ranges of storage slots along with the Merkle proofs
+// of the edges of the last range, corresponding to the ranges requested.
+func (p *basePeer) SendStorageRanges(slots [][]*storageData, proof [][]byte) error {
+	return p2p.Send(p.rw, StorageRangesMsg, &storageRangesData{Slots: slots, Proof: proof})
+}
+
+// SendByteCodes sends a batch of contract codes, corresponding to the hashes
+// requested.
+func (p *basePeer) SendByteCodes(codes [][]byte) error {
+	return p2p.Send(p.rw, ByteCodesMsg, codes)
+}
+
 // FetchBlockHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *basePeer) FetchBlockHeader(hash common.Hash) error {
@@ -583,6 +656,34 @@ func (p *basePeer) RequestReceipts(hashes []common.Hash) error {
 	return p2p.Send(p.rw, ReceiptsRequestMsg, hashes)
 }
 
+// RequestAccountRange fetches a range of accounts of the state of the root,
+// starting from the origin and ending around the limit.
+func (p *basePeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching range of accounts", "root", root, "origin", origin, "limit", limit, "bytes", bytes)
+	return p2p.Send(p.rw, AccountRangeRequestMsg, &accountRangeRequestData{Root: root, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestStorageRanges fetches ranges of storage slots of the accounts of the
+// state of the root. The origin applies to the first account and the limit
+// applies to the last account.
+func (p *basePeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	p.Log().Debug("Fetching ranges of storage slots", "root", root, "accounts", len(accounts), "origin", common.Bytes2Hex(origin), "limit", common.Bytes2Hex(limit), "bytes", bytes)
+	return p2p.Send(p.rw, StorageRangesRequestMsg, &storageRangesRequestData{Root: root, Accounts: accounts, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestByteCodes fetches a batch of contract codes corresponding to the hashes.
+func (p *basePeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching batch of byte codes", "count", len(hashes), "bytes", bytes)
+	return p2p.Send(p.rw, ByteCodesRequestMsg, &byteCodesRequestData{Hashes: hashes, Bytes: bytes})
+}
+
+// RequestTxs fetches a batch of transactions corresponding to the hashes
+// announced by the peer.
+func (p *basePeer) RequestTxs(hashes []common.Hash) error {
+	p.Log().Trace("Fetching batch of transactions", "count", len(hashes))
+	return p2p.Send(p.rw, GetPooledTransactionsMsg, hashes)
+}
+
 // Handshake executes the Klaytn protocol handshake, negotiating version number,
 // network IDs, difficulties, head and genesis blocks.
 func (p *basePeer) Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash) error {
@@ -811,6 +912,24 @@ func (p *multiChannelPeer) ReSendTransactions(txs types.Transactions) error {
 	return p.msgSender(TxMsg, txs)
 }
 
+// SendPooledTransactionHashes announces the availability of transactions to the
+// peer and includes the hashes in its transaction hash set for future reference.
+func (p *multiChannelPeer) SendPooledTransactionHashes(hashes []common.Hash) error {
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+	}
+	return p.msgSender(NewPooledTransactionHashesMsg, hashes)
+}
+
+// SendPooledTransactions sends a batch of transactions, corresponding to the
+// hashes requested.
+func (p *multiChannelPeer) SendPooledTransactions(txs types.Transactions) error {
+	for _, tx := range txs {
+		p.AddToKnownTxs(tx.Hash())
+	}
+	return p.msgSender(PooledTransactionsMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *multiChannelPeer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -870,6 +989,24 @@ func (p *multiChannelPeer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p.msgSender(ReceiptsMsg, receipts)
 }
 
+// SendAccountRange sends a range of accounts along with the Merkle proofs of
+// the edges, corresponding to the range requested.
+func (p *multiChannelPeer) SendAccountRange(accounts []*accountData, proof [][]byte) error {
+	return p.msgSender(AccountRangeMsg, &accountRangeData{Accounts: accounts, Proof: proof})
+}
+
+// SendStorageRanges sends ranges of storage slots along with the Merkle proofs
+// of the edges of the last range, corresponding to the ranges requested.
+func (p *multiChannelPeer) SendStorageRanges(slots [][]*storageData, proof [][]byte) error {
+	return p.msgSender(StorageRangesMsg, &storageRangesData{Slots: slots, Proof: proof})
+}
+
+// SendByteCodes sends a batch of contract codes, corresponding to the hashes
+// requested.
+func (p *multiChannelPeer) SendByteCodes(codes [][]byte) error {
+	return p.msgSender(ByteCodesMsg, codes)
+}
+
 // FetchBlockHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *multiChannelPeer) FetchBlockHeader(hash common.Hash) error {
@@ -918,6 +1055,34 @@ func (p *multiChannelPeer) RequestReceipts(hashes []common.Hash) error {
 	return p.msgSender(ReceiptsRequestMsg, hashes)
 }
 
+// RequestAccountRange fetches a range of accounts of the state of the root,
+// starting from the origin and ending around the limit.
+func (p *multiChannelPeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching range of accounts", "root", root, "origin", origin, "limit", limit, "bytes", bytes)
+	return p.msgSender(AccountRangeRequestMsg, &accountRangeRequestData{Root: root, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestStorageRanges fetches ranges of storage slots of the accounts of the
+// state of the root. The origin applies to the first account and the limit
+// applies to the last account.
+func (p *multiChannelPeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	p.Log().Debug("Fetching ranges of storage slots", "root", root, "accounts", len(accounts), "origin", common.Bytes2Hex(origin), "limit", common.Bytes2Hex(limit), "bytes", bytes)
+	return p.msgSender(StorageRangesRequestMsg, &storageRangesRequestData{Root: root, Accounts: accounts, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestByteCodes fetches a batch of contract codes corresponding to the hashes.
+func (p *multiChannelPeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching batch of byte codes", "count", len(hashes), "bytes", bytes)
+	return p.msgSender(ByteCodesRequestMsg, &byteCodesRequestData{Hashes: hashes, Bytes: bytes})
+}
+
+// RequestTxs fetches a batch of transactions corresponding to the hashes
+// announced by the peer.
+func (p *multiChannelPeer) RequestTxs(hashes []common.Hash) error {
+	p.Log().Trace("Fetching batch of transactions", "count", len(hashes))
+	return p.msgSender(GetPooledTransactionsMsg, hashes)
+}
+
 // msgSender sends data to the peer.
 func (p *multiChannelPeer) msgSender(msgcode uint64, data interface{}) error {
 	if ch, ok := ChannelOfMessage[msgcode]; ok && len(p.rws) > ch {
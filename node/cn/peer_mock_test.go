@@ -388,6 +388,20 @@ func (mr *MockPeerMockRecorder) RegisterConsensusMsgCode(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterConsensusMsgCode", reflect.TypeOf((*MockPeer)(nil).RegisterConsensusMsgCode), arg0)
 }
 
+// RequestAccountRange mocks base method
+func (m *MockPeer) RequestAccountRange(arg0 common.Hash, arg1 common.Hash, arg2 common.Hash, arg3 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestAccountRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestAccountRange indicates an expected call of RequestAccountRange
+func (mr *MockPeerMockRecorder) RequestAccountRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAccountRange", reflect.TypeOf((*MockPeer)(nil).RequestAccountRange), arg0, arg1, arg2, arg3)
+}
+
 // RequestBodies mocks base method
 func (m *MockPeer) RequestBodies(arg0 []common.Hash) error {
 	m.ctrl.T.Helper()
@@ -402,6 +416,20 @@ func (mr *MockPeerMockRecorder) RequestBodies(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestBodies", reflect.TypeOf((*MockPeer)(nil).RequestBodies), arg0)
 }
 
+// RequestByteCodes mocks base method
+func (m *MockPeer) RequestByteCodes(arg0 []common.Hash, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestByteCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestByteCodes indicates an expected call of RequestByteCodes
+func (mr *MockPeerMockRecorder) RequestByteCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestByteCodes", reflect.TypeOf((*MockPeer)(nil).RequestByteCodes), arg0, arg1)
+}
+
 // RequestHeadersByHash mocks base method
 func (m *MockPeer) RequestHeadersByHash(arg0 common.Hash, arg1, arg2 int, arg3 bool) error {
 	m.ctrl.T.Helper()
@@ -458,6 +486,34 @@ func (mr *MockPeerMockRecorder) RequestReceipts(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReceipts", reflect.TypeOf((*MockPeer)(nil).RequestReceipts), arg0)
 }
 
+// RequestStorageRanges mocks base method
+func (m *MockPeer) RequestStorageRanges(arg0 common.Hash, arg1 []common.Hash, arg2 []byte, arg3 []byte, arg4 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestStorageRanges", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestStorageRanges indicates an expected call of RequestStorageRanges
+func (mr *MockPeerMockRecorder) RequestStorageRanges(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestStorageRanges", reflect.TypeOf((*MockPeer)(nil).RequestStorageRanges), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RequestTxs mocks base method
+func (m *MockPeer) RequestTxs(arg0 []common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestTxs", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestTxs indicates an expected call of RequestTxs
+func (mr *MockPeerMockRecorder) RequestTxs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestTxs", reflect.TypeOf((*MockPeer)(nil).RequestTxs), arg0)
+}
+
 // Send mocks base method
 func (m *MockPeer) Send(arg0 uint64, arg1 interface{}) error {
 	m.ctrl.T.Helper()
@@ -472,6 +528,20 @@ func (mr *MockPeerMockRecorder) Send(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockPeer)(nil).Send), arg0, arg1)
 }
 
+// SendAccountRange mocks base method
+func (m *MockPeer) SendAccountRange(arg0 []*accountData, arg1 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAccountRange", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAccountRange indicates an expected call of SendAccountRange
+func (mr *MockPeerMockRecorder) SendAccountRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAccountRange", reflect.TypeOf((*MockPeer)(nil).SendAccountRange), arg0, arg1)
+}
+
 // SendBlockBodies mocks base method
 func (m *MockPeer) SendBlockBodies(arg0 []*blockBody) error {
 	m.ctrl.T.Helper()
@@ -514,6 +584,20 @@ func (mr *MockPeerMockRecorder) SendBlockHeaders(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBlockHeaders", reflect.TypeOf((*MockPeer)(nil).SendBlockHeaders), arg0)
 }
 
+// SendByteCodes mocks base method
+func (m *MockPeer) SendByteCodes(arg0 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendByteCodes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendByteCodes indicates an expected call of SendByteCodes
+func (mr *MockPeerMockRecorder) SendByteCodes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendByteCodes", reflect.TypeOf((*MockPeer)(nil).SendByteCodes), arg0)
+}
+
 // SendFetchedBlockBodiesRLP mocks base method
 func (m *MockPeer) SendFetchedBlockBodiesRLP(arg0 []rlp.RawValue) error {
 	m.ctrl.T.Helper()
@@ -584,6 +668,34 @@ func (mr *MockPeerMockRecorder) SendNodeData(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendNodeData", reflect.TypeOf((*MockPeer)(nil).SendNodeData), arg0)
 }
 
+// SendPooledTransactionHashes mocks base method
+func (m *MockPeer) SendPooledTransactionHashes(arg0 []common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPooledTransactionHashes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPooledTransactionHashes indicates an expected call of SendPooledTransactionHashes
+func (mr *MockPeerMockRecorder) SendPooledTransactionHashes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPooledTransactionHashes", reflect.TypeOf((*MockPeer)(nil).SendPooledTransactionHashes), arg0)
+}
+
+// SendPooledTransactions mocks base method
+func (m *MockPeer) SendPooledTransactions(arg0 types.Transactions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPooledTransactions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPooledTransactions indicates an expected call of SendPooledTransactions
+func (mr *MockPeerMockRecorder) SendPooledTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPooledTransactions", reflect.TypeOf((*MockPeer)(nil).SendPooledTransactions), arg0)
+}
+
 // SendReceiptsRLP mocks base method
 func (m *MockPeer) SendReceiptsRLP(arg0 []rlp.RawValue) error {
 	m.ctrl.T.Helper()
@@ -598,6 +710,20 @@ func (mr *MockPeerMockRecorder) SendReceiptsRLP(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendReceiptsRLP", reflect.TypeOf((*MockPeer)(nil).SendReceiptsRLP), arg0)
 }
 
+// SendStorageRanges mocks base method
+func (m *MockPeer) SendStorageRanges(arg0 [][]*storageData, arg1 [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendStorageRanges", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendStorageRanges indicates an expected call of SendStorageRanges
+func (mr *MockPeerMockRecorder) SendStorageRanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendStorageRanges", reflect.TypeOf((*MockPeer)(nil).SendStorageRanges), arg0, arg1)
+}
+
 // SendTransactions mocks base method
 func (m *MockPeer) SendTransactions(arg0 types.Transactions) error {
 	m.ctrl.T.Helper()
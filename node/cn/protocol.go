@@ -38,16 +38,18 @@ import (
 const (
 	klay62 = 62
 	klay63 = 63
+	klay65 = 65
+	klay66 = 66
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "klay"
 
 // ProtocolVersions are the upported versions of the klay protocol (first is primary).
-var ProtocolVersions = []uint{klay63, klay62}
+var ProtocolVersions = []uint{klay66, klay65, klay63, klay62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{17, 8}
+var ProtocolLengths = []uint64{30, 27, 17, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -75,6 +77,24 @@ const (
 	ReceiptsMsg        = 0x0f
 
 	MsgCodeEnd = 0x10
+
+	// Protocol messages belonging to klay/65
+	// The message codes between MsgCodeEnd and AccountRangeRequestMsg are reserved for the consensus messages.
+	AccountRangeRequestMsg  = 0x15
+	AccountRangeMsg         = 0x16
+	StorageRangesRequestMsg = 0x17
+	StorageRangesMsg        = 0x18
+	ByteCodesRequestMsg     = 0x19
+	ByteCodesMsg            = 0x1a
+
+	SnapMsgCodeEnd = 0x1b
+
+	// Protocol messages belonging to klay/66
+	NewPooledTransactionHashesMsg = 0x1b
+	GetPooledTransactionsMsg      = 0x1c
+	PooledTransactionsMsg         = 0x1d
+
+	PooledTxMsgCodeEnd = 0x1e
 )
 
 type errCode int
@@ -125,6 +145,10 @@ type ProtocolManagerDownloader interface {
 	DeliverNodeData(id string, data [][]byte) error
 	DeliverReceipts(id string, receipts [][]*types.Receipt) error
 
+	DeliverAccountRange(id string, hashes []common.Hash, accounts [][]byte, proof [][]byte) error
+	DeliverStorageRanges(id string, hashes [][]common.Hash, slots [][][]byte, proof [][]byte) error
+	DeliverByteCodes(id string, codes [][]byte) error
+
 	Terminate()
 	Synchronise(id string, head common.Hash, td *big.Int, mode downloader.SyncMode) error
 	Progress() klaytn.SyncProgress
@@ -141,6 +165,17 @@ type ProtocolManagerFetcher interface {
 	Stop()
 }
 
+//go:generate mockgen -destination=node/cn/mocks/tx_fetcher_mock.go -package=mocks github.com/klaytn/klaytn/node/cn ProtocolManagerTxFetcher
+// ProtocolManagerTxFetcher is an interface of fetcher.TxFetcher used by ProtocolManager.
+type ProtocolManagerTxFetcher interface {
+	Notify(peer string, hashes []common.Hash) error
+	Enqueue(peer string, txs types.Transactions, direct bool) error
+	MarkBroadcast(peer string, txs types.Transactions)
+	Drop(peer string) error
+	Start()
+	Stop()
+}
+
 // statusData is the network packet for the status message.
 type statusData struct {
 	ProtocolVersion uint32
@@ -214,3 +249,50 @@ type blockBody struct {
 
 // blockBodiesData is the network packet for block content distribution.
 type blockBodiesData []*blockBody
+
+// accountRangeRequestData represents an account range query.
+type accountRangeRequestData struct {
+	Root   common.Hash // Root hash of the account trie to serve
+	Origin common.Hash // Hash of the first account to retrieve
+	Limit  common.Hash // Hash of the last account to retrieve
+	Bytes  uint64      // Soft limit at which to stop returning data
+}
+
+// accountData represents a single account in an account range response.
+type accountData struct {
+	Hash common.Hash // Hash of the account
+	Body []byte      // RLP encoded account of the account trie
+}
+
+// accountRangeData is the network packet for the account range distribution.
+type accountRangeData struct {
+	Accounts []*accountData // List of consecutive accounts from the trie
+	Proof    [][]byte       // List of trie nodes proving the account range
+}
+
+// storageRangesRequestData represents a storage slot range query.
+type storageRangesRequestData struct {
+	Root     common.Hash   // Root hash of the account trie to serve
+	Accounts []common.Hash // Account hashes of the storage tries to serve
+	Origin   []byte        // Hash of the first storage slot to retrieve (large contract mode)
+	Limit    []byte        // Hash of the last storage slot to retrieve (large contract mode)
+	Bytes    uint64        // Soft limit at which to stop returning data
+}
+
+// storageData represents a single storage slot in a storage range response.
+type storageData struct {
+	Hash common.Hash // Hash of the storage slot
+	Body []byte      // Data content of the slot
+}
+
+// storageRangesData is the network packet for the storage range distribution.
+type storageRangesData struct {
+	Slots [][]*storageData // Lists of consecutive storage slots for the requested accounts
+	Proof [][]byte         // Merkle proofs for the *last* slot range, if it's incomplete
+}
+
+// byteCodesRequestData represents a contract bytecode query.
+type byteCodesRequestData struct {
+	Hashes []common.Hash // Code hashes to retrieve the code for
+	Bytes  uint64        // Soft limit at which to stop returning data
+}
@@ -0,0 +1,324 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"bytes"
+
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+)
+
+const (
+	// maxCodeLookups is the maximum number of bytecodes to serve. This number is
+	// there to limit the number of disk lookups.
+	maxCodeLookups = 1024
+
+	// maxStorageRangeAccounts is the maximum number of accounts whose storage
+	// ranges are served. This number is there to limit the trie iterations even
+	// if the accounts have no storage.
+	maxStorageRangeAccounts = 1024
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// emptyCode is the known hash of the empty EVM bytecode.
+	emptyCode = crypto.Keccak256Hash(nil)
+
+	// maxHash is the biggest hash value, used as the default limit of a range.
+	maxHash = common.HexToHash("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+)
+
+// rangeIterator iterates over the leaves of a trie in the order of their hashes,
+// regardless of whether the leaves are read from the snapshot or the trie.
+type rangeIterator interface {
+	Next() bool
+	Hash() common.Hash
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// trieRangeIterator is a rangeIterator over the leaves of a trie.
+type trieRangeIterator struct {
+	it *statedb.Iterator
+}
+
+func (it *trieRangeIterator) Next() bool        { return it.it.Next() }
+func (it *trieRangeIterator) Hash() common.Hash { return common.BytesToHash(it.it.Key) }
+func (it *trieRangeIterator) Value() []byte     { return it.it.Value }
+func (it *trieRangeIterator) Error() error      { return it.it.Err }
+func (it *trieRangeIterator) Release()          {}
+
+// snapAccountRangeIterator is a rangeIterator over the accounts of a snapshot.
+type snapAccountRangeIterator struct {
+	snapshot.AccountIterator
+}
+
+func (it *snapAccountRangeIterator) Value() []byte { return it.Account() }
+
+// snapStorageRangeIterator is a rangeIterator over the storage slots of a snapshot.
+type snapStorageRangeIterator struct {
+	snapshot.StorageIterator
+}
+
+func (it *snapStorageRangeIterator) Value() []byte { return it.Slot() }
+
+// newAccountRangeIterator returns an iterator over the accounts of the given
+// state root starting from the origin. The snapshot is preferred if available.
+func (pm *ProtocolManager) newAccountRangeIterator(root common.Hash, tr *statedb.Trie, origin common.Hash) rangeIterator {
+	if snaps := pm.blockchain.Snapshots(); snaps != nil {
+		if it, err := snaps.AccountIterator(root, origin); err == nil {
+			return &snapAccountRangeIterator{it}
+		}
+	}
+	return &trieRangeIterator{statedb.NewIterator(tr.NodeIterator(origin[:]))}
+}
+
+// newStorageRangeIterator returns an iterator over the storage slots of the
+// given account starting from the origin. The snapshot is preferred if available.
+func (pm *ProtocolManager) newStorageRangeIterator(root, accountHash common.Hash, tr *statedb.Trie, origin common.Hash) rangeIterator {
+	if snaps := pm.blockchain.Snapshots(); snaps != nil {
+		if it, err := snaps.StorageIterator(root, accountHash, origin); err == nil {
+			return &snapStorageRangeIterator{it}
+		}
+	}
+	return &trieRangeIterator{statedb.NewIterator(tr.NodeIterator(origin[:]))}
+}
+
+// proveRange generates the Merkle proofs of the given keys of the trie,
+// returning the list of the proof nodes.
+func proveRange(tr *statedb.Trie, keys ...common.Hash) ([][]byte, error) {
+	proofDB := database.NewMemoryDBManager()
+	for _, key := range keys {
+		if err := tr.Prove(key[:], 0, proofDB); err != nil {
+			return nil, err
+		}
+	}
+	memDB := proofDB.GetMemDB()
+
+	var proof [][]byte
+	for _, key := range memDB.Keys() {
+		node, err := memDB.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, node)
+	}
+	return proof, nil
+}
+
+// answerAccountRange retrieves the consecutive accounts of the requested range
+// along with the proofs of the edges. An empty response is returned if the
+// state of the requested root is not available.
+func (pm *ProtocolManager) answerAccountRange(req *accountRangeRequestData) ([]*accountData, [][]byte) {
+	limit := req.Bytes
+	if limit > softResponseLimit {
+		limit = softResponseLimit
+	}
+	tr, err := statedb.NewTrie(req.Root, pm.blockchain.StateCache().TrieDB())
+	if err != nil {
+		return nil, nil
+	}
+	it := pm.newAccountRangeIterator(req.Root, tr, req.Origin)
+	defer it.Release()
+
+	var (
+		accounts []*accountData
+		size     uint64
+		last     common.Hash
+	)
+	for it.Next() {
+		hash, blob := it.Hash(), it.Value()
+		if len(blob) == 0 {
+			continue
+		}
+		last = hash
+		accounts = append(accounts, &accountData{Hash: hash, Body: common.CopyBytes(blob)})
+		size += uint64(common.HashLength + len(blob))
+
+		// If we've exceeded the request threshold, abort
+		if bytes.Compare(hash[:], req.Limit[:]) >= 0 || size > limit {
+			break
+		}
+	}
+	if it.Error() != nil {
+		return nil, nil
+	}
+	// Generate the Merkle proofs for the first and last account
+	keys := []common.Hash{req.Origin}
+	if len(accounts) > 0 {
+		keys = append(keys, last)
+	}
+	proof, err := proveRange(tr, keys...)
+	if err != nil {
+		logger.Warn("Failed to prove account range", "origin", req.Origin, "last", last, "err", err)
+		return nil, nil
+	}
+	return accounts, proof
+}
+
+// answerStorageRanges retrieves the consecutive storage slots of the requested
+// accounts. The origin only applies to the first account and the limit only
+// applies to the last account. If the slots of an account are not delivered
+// completely, the proofs of the edges are attached and the remaining accounts
+// are not served.
+func (pm *ProtocolManager) answerStorageRanges(req *storageRangesRequestData) ([][]*storageData, [][]byte) {
+	limit := req.Bytes
+	if limit > softResponseLimit {
+		limit = softResponseLimit
+	}
+	// The limit of the request only applies to the last requested account, so
+	// it is not applied if the accounts are truncated.
+	accounts := req.Accounts
+	if len(accounts) > maxStorageRangeAccounts {
+		accounts = accounts[:maxStorageRangeAccounts]
+	}
+	triedb := pm.blockchain.StateCache().TrieDB()
+	accTrie, err := statedb.NewTrie(req.Root, triedb)
+	if err != nil {
+		return nil, nil
+	}
+	var (
+		slots [][]*storageData
+		proof [][]byte
+		size  uint64
+	)
+	for i, accountHash := range accounts {
+		// If we've exceeded the requested data limit, abort without opening
+		// a new storage range (that we'd need to prove due to exceeded size)
+		if size >= limit {
+			break
+		}
+		origin, last := common.Hash{}, maxHash
+		if i == 0 && len(req.Origin) > 0 {
+			origin = common.BytesToHash(req.Origin)
+		}
+		if i == len(req.Accounts)-1 && len(req.Limit) > 0 {
+			last = common.BytesToHash(req.Limit)
+		}
+		storageRoot, err := accountStorageRoot(accTrie, accountHash)
+		if err != nil {
+			return nil, nil
+		}
+		stTrie, err := statedb.NewTrie(storageRoot, triedb)
+		if err != nil {
+			return nil, nil
+		}
+		it := pm.newStorageRangeIterator(req.Root, accountHash, stTrie, origin)
+
+		var (
+			storage []*storageData
+			lastKey common.Hash
+			abort   bool
+		)
+		for it.Next() {
+			if size >= limit {
+				abort = true
+				break
+			}
+			hash, slot := it.Hash(), it.Value()
+			if len(slot) == 0 {
+				continue
+			}
+			lastKey = hash
+			storage = append(storage, &storageData{Hash: hash, Body: common.CopyBytes(slot)})
+			size += uint64(common.HashLength + len(slot))
+
+			// If we've exceeded the request threshold, abort
+			if bytes.Compare(hash[:], last[:]) >= 0 {
+				break
+			}
+		}
+		if err := it.Error(); err != nil {
+			it.Release()
+			return nil, nil
+		}
+		it.Release()
+		slots = append(slots, storage)
+
+		// If the storage is served partially, generate the Merkle proofs for
+		// the first and last slot and stop serving the remaining accounts.
+		if origin != (common.Hash{}) || abort {
+			keys := []common.Hash{origin}
+			if len(storage) > 0 {
+				keys = append(keys, lastKey)
+			}
+			if proof, err = proveRange(stTrie, keys...); err != nil {
+				logger.Warn("Failed to prove storage range", "account", accountHash, "origin", origin, "err", err)
+				return nil, nil
+			}
+			break
+		}
+	}
+	return slots, proof
+}
+
+// answerByteCodes retrieves the contract codes of the requested hashes. Unknown
+// codes are skipped.
+func (pm *ProtocolManager) answerByteCodes(req *byteCodesRequestData) [][]byte {
+	limit := req.Bytes
+	if limit > softResponseLimit {
+		limit = softResponseLimit
+	}
+	if len(req.Hashes) > maxCodeLookups {
+		req.Hashes = req.Hashes[:maxCodeLookups]
+	}
+	var (
+		codes [][]byte
+		size  uint64
+	)
+	for _, hash := range req.Hashes {
+		if hash == emptyCode {
+			// Peers should not request the empty code, but if they do, at
+			// least sent them back a correct response without db lookups
+			codes = append(codes, []byte{})
+		} else if blob, err := pm.blockchain.StateCache().ContractCode(hash); err == nil {
+			codes = append(codes, blob)
+			size += uint64(len(blob))
+		}
+		if size > limit {
+			break
+		}
+	}
+	return codes
+}
+
+// accountStorageRoot returns the storage root of the given account of the
+// account trie. The root of an empty trie is returned if the account is not
+// a program account.
+func accountStorageRoot(accTrie *statedb.Trie, accountHash common.Hash) (common.Hash, error) {
+	blob, err := accTrie.TryGet(accountHash[:])
+	if err != nil || blob == nil {
+		return emptyRoot, err
+	}
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(blob, serializer); err != nil {
+		return emptyRoot, err
+	}
+	if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil {
+		return pa.GetStorageRoot(), nil
+	}
+	return emptyRoot, nil
+}
@@ -137,6 +137,8 @@ func (pm *ProtocolManager) syncer() {
 	// Start and ensure cleanup of sync mechanisms
 	pm.fetcher.Start()
 	defer pm.fetcher.Stop()
+	pm.txFetcher.Start()
+	defer pm.txFetcher.Stop()
 	defer pm.downloader.Terminate()
 
 	// Wait for different events to fire synchronisation operations
@@ -166,6 +168,9 @@ func (pm *ProtocolManager) syncer() {
 func (pm *ProtocolManager) getSyncMode(currentBlock *types.Block) downloader.SyncMode {
 	if atomic.LoadUint32(&pm.fastSync) == 1 {
 		// Fast sync was explicitly requested, and explicitly granted
+		if atomic.LoadUint32(&pm.snapSync) == 1 {
+			return downloader.SnapSync
+		}
 		return downloader.FastSync
 	} else if currentBlock.NumberU64() == 0 && pm.blockchain.CurrentFastBlock().NumberU64() > 0 {
 		// The database seems empty as the current block is the genesis. Yet the fast
@@ -195,7 +200,7 @@ func (pm *ProtocolManager) synchronise(peer Peer) {
 	}
 	// Otherwise try to sync with the downloader
 	mode := pm.getSyncMode(currentBlock)
-	if mode == downloader.FastSync {
+	if mode == downloader.FastSync || mode == downloader.SnapSync {
 		// Make sure the peer's total blockscore we are synchronizing is higher.
 		if pm.blockchain.GetTdByHash(pm.blockchain.CurrentFastBlock().Hash()).Cmp(pTd) >= 0 {
 			return
@@ -209,6 +214,7 @@ func (pm *ProtocolManager) synchronise(peer Peer) {
 	if atomic.LoadUint32(&pm.fastSync) == 1 {
 		logger.Info("Fast sync complete, auto disabling")
 		atomic.StoreUint32(&pm.fastSync, 0)
+		atomic.StoreUint32(&pm.snapSync, 0)
 	}
 	atomic.StoreUint32(&pm.acceptTxs, 1) // Mark initial sync done
 	if head := pm.blockchain.CurrentBlock(); head.NumberU64() > 0 {
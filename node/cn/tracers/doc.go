@@ -16,11 +16,16 @@
 
 /*
 Package tracers provides implementation of Tracer that evaluates a Javascript
-function for each VM execution step.
+function for each VM execution step, and native implementations of the
+commonly used JavaScript tracers.
 
 Source Files
 
-  - tracer.go  : implementation of Tracer
-  - tracers.go : provides managing functions of tracers
+  - native.go                 : provides NativeTracer and the helpers of the native tracers
+  - native_4byte_tracer.go    : native implementation of 4byte_tracer.js
+  - native_call_tracer.go     : native implementation of call_tracer.js
+  - native_prestate_tracer.go : native implementation of prestate_tracer.js with a diff mode
+  - tracer.go                 : implementation of Tracer
+  - tracers.go                : provides managing functions of tracers
 */
 package tracers
@@ -0,0 +1,152 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// NativeTracer is a transaction tracer implemented in Go. A native tracer
+// produces the same result as the JavaScript tracer of the same name without
+// running a JavaScript VM for each execution step.
+type NativeTracer interface {
+	vm.Tracer
+
+	// GetResult returns the JSON encoded result of the tracing.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates execution of the tracer at the first opportune moment.
+	Stop(err error)
+}
+
+// nativeTracers contains the constructors of the native tracers by name.
+// The configuration is the JSON encoded TracerConfig given by the user.
+var nativeTracers = map[string]func(config json.RawMessage) (NativeTracer, error){
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+	"4byteTracer":    newFourByteTracer,
+}
+
+// NewNativeTracer creates the native tracer of the given name. It returns false
+// if there is no native tracer of the name.
+func NewNativeTracer(name string, config json.RawMessage) (NativeTracer, bool, error) {
+	constructor, ok := nativeTracers[name]
+	if !ok {
+		return nil, false, nil
+	}
+	tracer, err := constructor(config)
+	return tracer, true, err
+}
+
+// interruptible implements the interruption of the native tracers, which works
+// the same as the one of the JavaScript tracers.
+type interruptible struct {
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+	err       error  // Error to be returned with the result
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *interruptible) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// interrupted returns true if the tracer should not trace anymore.
+func (t *interruptible) interrupted() bool {
+	if t.err != nil {
+		return true
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.err = t.reason
+		return true
+	}
+	return false
+}
+
+// encodeResult encodes the result of a native tracer in the same way as the
+// JSON encoder of the JavaScript VM, which does not escape HTML characters.
+func encodeResult(result interface{}) (json.RawMessage, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(result); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// peekStack returns the nth-from-the-top element of the stack, or zero if the
+// stack does not have enough elements.
+func peekStack(stack *vm.Stack, idx int) *big.Int {
+	data := stack.Data()
+	if len(data) <= idx || idx < 0 {
+		logger.Warn("Tracer accessed out of bound stack", "size", len(data), "index", idx)
+		return new(big.Int)
+	}
+	return data[len(data)-idx-1]
+}
+
+// sliceMemory returns a copy of the memory from offset to offset+size, or nil
+// if the range is out of bound.
+func sliceMemory(memory *vm.Memory, offset, size *big.Int) []byte {
+	if size.Sign() == 0 {
+		return []byte{}
+	}
+	end := new(big.Int).Add(offset, size)
+	if offset.Sign() < 0 || size.Sign() < 0 || !end.IsInt64() || int64(memory.Len()) < end.Int64() {
+		logger.Warn("Tracer accessed out of bound memory", "available", memory.Len(), "offset", offset, "size", size)
+		return nil
+	}
+	return memory.GetCopy(offset.Int64(), size.Int64())
+}
+
+// toHex encodes the bytes into a hex string with 0x prefix.
+func toHex(b []byte) string {
+	return hexutil.Encode(b)
+}
+
+// addressToHex encodes the address into a lower-cased hex string with 0x prefix.
+func addressToHex(addr common.Address) string {
+	return hexutil.Encode(addr[:])
+}
+
+// intToHex encodes the signed integer into a hex string with 0x prefix, the
+// same as '0x' + bigInt(n).toString(16) in the JavaScript tracers.
+func intToHex(n int64) string {
+	if n < 0 {
+		return "0x-" + strconv.FormatUint(uint64(-n), 16)
+	}
+	return "0x" + strconv.FormatUint(uint64(n), 16)
+}
+
+// bigToHex encodes the big integer into a hex string with 0x prefix, the same
+// as '0x' + n.toString(16) in the JavaScript tracers.
+func bigToHex(n *big.Int) string {
+	if n == nil {
+		return "0x0"
+	}
+	return "0x" + n.Text(16)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+)
+
+// fourByteTracer is the native implementation of 4byte_tracer.js, which
+// searches for 4byte-identifiers, and collects them for post-processing.
+// It collects the methods identifiers along with the size of the supplied data,
+// so a reversed signature can be matched against the size of the data.
+type fourByteTracer struct {
+	interruptible
+
+	ids   map[string]int // ids aggregates the 4byte ids found
+	input []byte         // input of the outer call
+}
+
+func newFourByteTracer(config json.RawMessage) (NativeTracer, error) {
+	return &fourByteTracer{ids: make(map[string]int)}, nil
+}
+
+// store saves the given identifier and data size.
+func (t *fourByteTracer) store(id []byte, size int64) {
+	t.ids[toHex(id)+"-"+strconv.FormatInt(size, 10)]++
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *fourByteTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.input = common.CopyBytes(input)
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *fourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Skip any opcodes that are not internal calls, and find the stack index
+	// of the input offset
+	var inOffIdx int
+	switch op {
+	case vm.CALL, vm.CALLCODE:
+		// gas, addr, val, memin, meminsz, memout, memoutsz
+		inOffIdx = 3
+	case vm.DELEGATECALL, vm.STATICCALL:
+		// gas, addr, memin, meminsz, memout, memoutsz
+		inOffIdx = 2
+	default:
+		return nil
+	}
+	// Skip any pre-compile invocations, those are just fancy opcodes
+	if _, ok := vm.PrecompiledContractsCypress[common.BigToAddress(peekStack(stack, 1))]; ok {
+		return nil
+	}
+	// Gather internal call details
+	inSz := peekStack(stack, inOffIdx+1)
+	if inSz.Cmp(big.NewInt(4)) >= 0 && inSz.IsInt64() {
+		inOff := peekStack(stack, inOffIdx)
+		t.store(sliceMemory(memory, inOff, big.NewInt(4)), inSz.Int64()-4)
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *fourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *fourByteTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the JSON encoded 4byte-identifiers with their counts.
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	// Save the outer calldata also. The compiled 4byte_tracer.js only saves the
+	// calldata longer than 4 bytes, so it is followed here.
+	if len(t.input) > 4 {
+		t.store(t.input[:4], int64(len(t.input)-4))
+	}
+	res, err := encodeResult(t.ids)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.err
+}
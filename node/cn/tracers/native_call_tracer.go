@@ -0,0 +1,357 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// callFrame is a call reported by the call tracer. The exported fields are
+// ordered as the result of call_tracer.js, and the unexported ones are used
+// while the call is in the call stack.
+type callFrame struct {
+	Type     interface{}   `json:"type"`
+	From     string        `json:"from,omitempty"`
+	To       string        `json:"to,omitempty"`
+	Value    string        `json:"value,omitempty"`
+	Gas      string        `json:"gas,omitempty"`
+	GasUsed  string        `json:"gasUsed,omitempty"`
+	Input    string        `json:"input,omitempty"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Time     interface{}   `json:"time,omitempty"`
+	Calls    []*callFrame  `json:"calls,omitempty"`
+	Reverted *callReverted `json:"reverted,omitempty"`
+
+	gasIn   uint64
+	gasCost uint64
+	gas     uint64
+	hasGas  bool
+	outOff  *big.Int
+	outLen  *big.Int
+}
+
+// callReverted is the information of the reverted transaction.
+type callReverted struct {
+	Contract string  `json:"contract"`
+	Message  *string `json:"message,omitempty"`
+}
+
+// callTracer is the native implementation of call_tracer.js, which extracts
+// and reports all the internal calls made by a transaction.
+type callTracer struct {
+	interruptible
+
+	callstack        []*callFrame
+	revertedContract string
+
+	// descended tracks whether we've just descended from an outer transaction
+	// into an inner call.
+	descended bool
+
+	// Transaction context given by CaptureStart and CaptureEnd
+	ctxType    string
+	ctxFrom    common.Address
+	ctxTo      common.Address
+	ctxInput   []byte
+	ctxGas     uint64
+	ctxValue   *big.Int
+	ctxOutput  []byte
+	ctxGasUsed uint64
+	ctxTime    string
+	ctxError   string
+	started    bool
+	ended      bool
+}
+
+func newCallTracer(config json.RawMessage) (NativeTracer, error) {
+	return &callTracer{callstack: []*callFrame{{}}}, nil
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *callTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.ctxType = "CALL"
+	if create {
+		t.ctxType = "CREATE"
+	}
+	t.ctxFrom, t.ctxTo = from, to
+	t.ctxInput = common.CopyBytes(input)
+	t.ctxGas = gas
+	t.ctxValue = new(big.Int)
+	if value != nil {
+		t.ctxValue.Set(value)
+	}
+	t.started = true
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Capture any errors immediately
+	if err != nil {
+		t.fault(err)
+		return nil
+	}
+	// We only care about system opcodes, faster if we pre-check once
+	syscall := op&0xf0 == 0xf0
+
+	// If a new contract is being created, add to the call stack
+	if syscall && (op == vm.CREATE || op == vm.CREATE2) {
+		t.callstack = append(t.callstack, &callFrame{
+			Type:    op.String(),
+			From:    addressToHex(contract.Address()),
+			Input:   toHex(sliceMemory(memory, peekStack(stack, 1), peekStack(stack, 2))),
+			Value:   bigToHex(peekStack(stack, 0)),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		t.descended = true
+		return nil
+	}
+	// If a contract is being self destructed, gather that as a subcall too
+	if syscall && op == vm.SELFDESTRUCT {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, &callFrame{Type: op.String()})
+		return nil
+	}
+	// If a new method invocation is being done, add to the call stack
+	if syscall && (op == vm.CALL || op == vm.CALLCODE || op == vm.DELEGATECALL || op == vm.STATICCALL) {
+		// Skip any pre-compile invocations, those are just fancy opcodes
+		to := common.BigToAddress(peekStack(stack, 1))
+		if _, ok := vm.PrecompiledContractsCypress[to]; ok {
+			return nil
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		call := &callFrame{
+			Type:    op.String(),
+			From:    addressToHex(contract.Address()),
+			To:      addressToHex(to),
+			Input:   toHex(sliceMemory(memory, peekStack(stack, 2+off), peekStack(stack, 3+off))),
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  new(big.Int).Set(peekStack(stack, 4+off)),
+			outLen:  new(big.Int).Set(peekStack(stack, 5+off)),
+		}
+		if op != vm.DELEGATECALL && op != vm.STATICCALL {
+			call.Value = bigToHex(peekStack(stack, 2))
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return nil
+	}
+	// If we've just descended into an inner call, retrieve it's true allowance. We
+	// need to extract if from within the call as there may be funky gas dynamics
+	// with regard to requested and actually given gas (2300 stipend, 63/64 rule).
+	if t.descended {
+		if depth >= len(t.callstack) {
+			t.callstack[len(t.callstack)-1].gas = gas
+			t.callstack[len(t.callstack)-1].hasGas = true
+		}
+		t.descended = false
+	}
+	// If an existing call is returning, pop off the call stack
+	if syscall && op == vm.REVERT {
+		top := t.callstack[len(t.callstack)-1]
+		top.Error = "execution reverted"
+		if t.revertedContract == "" {
+			if top.To == "" {
+				t.revertedContract = addressToHex(contract.Address())
+			} else {
+				t.revertedContract = top.To
+			}
+		}
+		return nil
+	}
+	if depth == len(t.callstack)-1 {
+		// Pop off the last call and get the execution results
+		call := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			// If the call was a CREATE, retrieve the contract address and output code
+			call.GasUsed = intToHex(int64(call.gasIn) - int64(call.gasCost) - int64(gas))
+
+			if ret := peekStack(stack, 0); ret.Sign() != 0 {
+				addr := common.BigToAddress(ret)
+				call.To = addressToHex(addr)
+				call.Output = toHex(env.StateDB.GetCode(addr))
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		} else if call.hasGas {
+			// If the call was a contract call, retrieve the gas usage and output
+			call.GasUsed = intToHex(int64(call.gasIn) - int64(call.gasCost) + int64(call.gas) - int64(gas))
+
+			if ret := peekStack(stack, 0); ret.Sign() != 0 {
+				call.Output = toHex(sliceMemory(memory, call.outOff, call.outLen))
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		}
+		if call.hasGas {
+			call.Gas = intToHex(int64(call.gas))
+		}
+		// Inject the call into the previous one
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, call)
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.err == nil {
+		t.fault(err)
+	}
+	return nil
+}
+
+// fault is invoked when the actual execution of an opcode fails.
+func (t *callTracer) fault(err error) {
+	// If the topmost call already reverted, don't handle the additional fault again
+	if t.callstack[len(t.callstack)-1].Error != "" {
+		return
+	}
+	// Pop off the just failed call
+	call := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	call.Error = err.Error()
+
+	// Consume all available gas
+	if call.hasGas {
+		call.Gas = intToHex(int64(call.gas))
+		call.GasUsed = call.Gas
+	}
+	// Flatten the failed call into its parent
+	if len(t.callstack) > 0 {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, call)
+		return
+	}
+	// Last call failed too, leave it in the stack
+	t.callstack = append(t.callstack, call)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.ctxOutput = common.CopyBytes(output)
+	t.ctxGasUsed = gasUsed
+	t.ctxTime = d.String()
+	if err != nil {
+		t.ctxError = err.Error()
+	}
+	t.ended = true
+	return nil
+}
+
+// GetResult returns the JSON encoded call tree of the transaction.
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	result := &callFrame{
+		Type:    0,
+		From:    "0x",
+		To:      "0x",
+		Value:   "0x0",
+		Gas:     "0x0",
+		GasUsed: "0x0",
+		Input:   "0x",
+		Output:  "0x",
+		Time:    0,
+	}
+	if t.started {
+		result.Type = t.ctxType
+		result.From = addressToHex(t.ctxFrom)
+		result.To = addressToHex(t.ctxTo)
+		result.Value = bigToHex(t.ctxValue)
+		result.Gas = intToHex(int64(t.ctxGas))
+		result.Input = toHex(t.ctxInput)
+	}
+	if t.ended {
+		result.GasUsed = intToHex(int64(t.ctxGasUsed))
+		result.Output = toHex(t.ctxOutput)
+		result.Time = t.ctxTime
+	}
+	root := t.callstack[0]
+	result.Calls = root.Calls
+
+	if root.Error != "" {
+		result.Error = root.Error
+	} else {
+		result.Error = t.ctxError
+	}
+	if result.Error != "" {
+		result.Output = ""
+	}
+	if t.ctxError == vm.ErrExecutionReverted.Error() {
+		result.Reverted = &callReverted{Contract: t.revertedContract, Message: unpackRevertString(t.ctxOutput)}
+	}
+	res, err := encodeResult(result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.err
+}
+
+// unpackRevertString returns the reason string of Error(string) in the output
+// of the reverted transaction, or nil if the output is not Error(string).
+func unpackRevertString(output []byte) *string {
+	outputHex := hexutil.Encode(output)
+	if len(outputHex) < 10 || outputHex[2:10] != "08c379a0" {
+		return nil
+	}
+	const defaultOffset = 10
+	if len(outputHex) < defaultOffset+32*2+32*2 {
+		return nil
+	}
+	stringOffset, ok := new(big.Int).SetString(outputHex[defaultOffset:defaultOffset+32*2], 16)
+	if !ok || !stringOffset.IsInt64() {
+		return nil
+	}
+	stringLength, ok := new(big.Int).SetString(outputHex[defaultOffset+32*2:defaultOffset+32*2+32*2], 16)
+	if !ok || !stringLength.IsInt64() {
+		return nil
+	}
+	start := defaultOffset + 32*2 + int(stringOffset.Int64()*2)
+	end := start + int(stringLength.Int64()*2)
+	if start < 0 || end < start || end > len(outputHex) {
+		return nil
+	}
+	// Each byte is converted to a character as String.fromCharCode does
+	var message []rune
+	for i := start; i+2 <= end; i += 2 {
+		code, err := strconv.ParseUint(outputHex[i:i+2], 16, 8)
+		if err != nil {
+			return nil
+		}
+		message = append(message, rune(code))
+	}
+	str := string(message)
+	return &str
+}
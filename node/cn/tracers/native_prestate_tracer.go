@@ -0,0 +1,280 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+)
+
+// prestateAccount is an account in the result of the prestate tracer.
+type prestateAccount struct {
+	Balance string            `json:"balance"`
+	Nonce   uint64            `json:"nonce"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage"`
+
+	balance *big.Int
+	code    []byte
+}
+
+// poststateAccount is an account in the post state of the prestate tracer in
+// the diff mode, which only has the modified fields.
+type poststateAccount struct {
+	Balance string            `json:"balance,omitempty"`
+	Nonce   *uint64           `json:"nonce,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// prestateTracerConfig is the configuration of the prestate tracer.
+type prestateTracerConfig struct {
+	// DiffMode makes the tracer return the states of the touched accounts before
+	// and after the transaction, which only include the modified accounts.
+	DiffMode bool `json:"diffMode"`
+}
+
+// prestateTracer is the native implementation of prestate_tracer.js, which
+// outputs sufficient information to create a local execution of the transaction
+// from a custom assembled genesis block.
+type prestateTracer struct {
+	interruptible
+
+	config   prestateTracerConfig
+	prestate map[common.Address]*prestateAccount
+	storage  map[common.Address]map[common.Hash]common.Hash
+	db       vm.StateDB
+
+	// Transaction context given by CaptureStart
+	create bool
+	from   common.Address
+	to     common.Address
+	value  *big.Int
+}
+
+func newPrestateTracer(config json.RawMessage) (NativeTracer, error) {
+	t := &prestateTracer{}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &t.config); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.create = create
+	t.from, t.to = from, to
+	t.value = new(big.Int)
+	if value != nil {
+		t.value.Set(value)
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Add the current account if we just started tracing
+	if t.prestate == nil {
+		t.prestate = make(map[common.Address]*prestateAccount)
+		t.storage = make(map[common.Address]map[common.Hash]common.Hash)
+		t.db = env.StateDB
+
+		// Balance will potentially be wrong here, since this will include the value
+		// sent along with the message. We fix that in GetResult.
+		t.lookupAccount(contract.Address())
+	}
+	// Whenever new state is accessed, add it to the prestate
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.BALANCE:
+		t.lookupAccount(common.BigToAddress(peekStack(stack, 0)))
+	case vm.CREATE:
+		from := contract.Address()
+		t.lookupAccount(crypto.CreateAddress(from, env.StateDB.GetNonce(from)))
+	case vm.CREATE2:
+		// stack: salt, size, offset, endowment
+		from := contract.Address()
+		code := sliceMemory(memory, peekStack(stack, 1), peekStack(stack, 2))
+		salt := common.BigToHash(peekStack(stack, 3))
+		t.lookupAccount(crypto.CreateAddress2(from, salt, crypto.Keccak256(code)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.BigToAddress(peekStack(stack, 1)))
+	case vm.SSTORE, vm.SLOAD:
+		t.lookupStorage(contract.Address(), common.BigToHash(peekStack(stack, 0)))
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// lookupAccount injects the specified account into the prestate.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.prestate[addr]; ok {
+		return
+	}
+	t.prestate[addr] = &prestateAccount{
+		Nonce:   t.db.GetNonce(addr),
+		Storage: make(map[string]string),
+		balance: new(big.Int).Set(t.db.GetBalance(addr)),
+		code:    common.CopyBytes(t.db.GetCode(addr)),
+	}
+	t.storage[addr] = make(map[common.Hash]common.Hash)
+}
+
+// lookupStorage injects the specified storage entry of the given account into
+// the prestate.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	if _, ok := t.storage[addr][key]; ok {
+		return
+	}
+	t.storage[addr][key] = t.db.GetState(addr, key)
+}
+
+// GetResult returns the JSON encoded prestate of the accounts touched by the
+// transaction, or the pre and post states of the modified ones in the diff mode.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.prestate == nil {
+		// No opcode was executed, so no state was touched by the transaction
+		res, err := encodeResult(map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+		return res, t.err
+	}
+	// At this point, we need to deduct the 'value' from the
+	// outer transaction, and move it back to the origin
+	t.lookupAccount(t.from)
+	t.lookupAccount(t.to)
+
+	t.prestate[t.to].balance = new(big.Int).Sub(t.prestate[t.to].balance, t.value)
+	t.prestate[t.from].balance = new(big.Int).Add(t.prestate[t.from].balance, t.value)
+
+	// Decrement the caller's nonce, and remove empty create targets
+	if t.prestate[t.from].Nonce > 0 {
+		t.prestate[t.from].Nonce--
+	}
+	var created *common.Address
+	if t.create {
+		// We can blindly delete the contract prestate, as any existing state would
+		// have caused the transaction to be rejected as invalid in the first place.
+		delete(t.prestate, t.to)
+		created = &t.to
+	}
+	for addr, account := range t.prestate {
+		account.Balance = bigToHex(account.balance)
+		account.Code = toHex(account.code)
+		for key, val := range t.storage[addr] {
+			account.Storage[toHex(key[:])] = toHex(val[:])
+		}
+	}
+	var result interface{}
+	if t.config.DiffMode {
+		pre, post := t.diff(created)
+		result = map[string]interface{}{"pre": pre, "post": post}
+	} else {
+		result = t.encodedPrestate(t.prestate)
+	}
+	res, err := encodeResult(result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.err
+}
+
+// diff returns the pre and post states of the accounts modified by the
+// transaction. The post states only have the modified fields.
+func (t *prestateTracer) diff(created *common.Address) (map[string]*prestateAccount, map[string]*poststateAccount) {
+	pre := make(map[common.Address]*prestateAccount)
+	post := make(map[string]*poststateAccount)
+
+	for addr, account := range t.prestate {
+		modified := false
+		postAccount := &poststateAccount{}
+
+		if balance := t.db.GetBalance(addr); balance.Cmp(account.balance) != 0 {
+			postAccount.Balance = bigToHex(balance)
+			modified = true
+		}
+		if nonce := t.db.GetNonce(addr); nonce != account.Nonce {
+			postAccount.Nonce = &nonce
+			modified = true
+		}
+		if code := t.db.GetCode(addr); string(code) != string(account.code) {
+			postAccount.Code = toHex(code)
+			modified = true
+		}
+		for key, val := range t.storage[addr] {
+			if newVal := t.db.GetState(addr, key); newVal != val {
+				if postAccount.Storage == nil {
+					postAccount.Storage = make(map[string]string)
+				}
+				postAccount.Storage[toHex(key[:])] = toHex(newVal[:])
+				modified = true
+			}
+		}
+		if modified {
+			pre[addr] = account
+			post[addressToHex(addr)] = postAccount
+		}
+	}
+	// The contract created by the transaction only exists in the post state
+	if created != nil && t.db.Exist(*created) {
+		nonce := t.db.GetNonce(*created)
+		postAccount := &poststateAccount{
+			Balance: bigToHex(t.db.GetBalance(*created)),
+			Nonce:   &nonce,
+			Code:    toHex(t.db.GetCode(*created)),
+		}
+		for key := range t.storage[*created] {
+			if postAccount.Storage == nil {
+				postAccount.Storage = make(map[string]string)
+			}
+			val := t.db.GetState(*created, key)
+			postAccount.Storage[toHex(key[:])] = toHex(val[:])
+		}
+		post[addressToHex(*created)] = postAccount
+	}
+	return t.encodedPrestate(pre), post
+}
+
+// encodedPrestate returns the prestate keyed by the hex encoded addresses.
+func (t *prestateTracer) encodedPrestate(prestate map[common.Address]*prestateAccount) map[string]*prestateAccount {
+	encoded := make(map[string]*prestateAccount, len(prestate))
+	for addr, account := range prestate {
+		encoded[addressToHex(addr)] = account
+	}
+	return encoded
+}
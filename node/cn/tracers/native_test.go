@@ -0,0 +1,240 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeField matches the execution time in the result of the call tracer,
+// which differs for every execution.
+var timeField = regexp.MustCompile(`"time":"[^"]*"`)
+
+// loadCallTracerTests reads all the call tracer tests in the testdata directory.
+func loadCallTracerTests(t testing.TB) map[string]*callTracerTest {
+	files, err := ioutil.ReadDir("testdata")
+	require.NoError(t, err)
+
+	testcases := make(map[string]*callTracerTest)
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), "call_tracer_") {
+			continue
+		}
+		blob, err := ioutil.ReadFile(filepath.Join("testdata", file.Name()))
+		require.NoError(t, err)
+
+		test := new(callTracerTest)
+		require.NoError(t, json.Unmarshal(blob, test))
+		testcases[camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "call_tracer_"), ".json"))] = test
+	}
+	return testcases
+}
+
+// runTracer executes the transaction of the test with the tracer, and returns
+// the result of the tracer.
+func runTracer(t testing.TB, test *callTracerTest, tracer interface {
+	vm.Tracer
+	GetResult() (json.RawMessage, error)
+}) (json.RawMessage, error) {
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	tx := new(types.Transaction)
+	if test.Input != "" {
+		require.NoError(t, rlp.DecodeBytes(common.FromHex(test.Input), tx))
+	} else {
+		value := new(big.Int)
+		gasPrice := new(big.Int)
+		require.NoError(t, value.UnmarshalJSON([]byte(test.Transaction["value"])))
+		require.NoError(t, gasPrice.UnmarshalJSON([]byte(test.Transaction["gasPrice"])))
+		nonce, ok := math.ParseUint64(test.Transaction["nonce"])
+		require.True(t, ok)
+		gas, ok := math.ParseUint64(test.Transaction["gas"])
+		require.True(t, ok)
+
+		tx = types.NewTransaction(nonce, common.HexToAddress(test.Transaction["to"]), value, gas, gasPrice, common.FromHex(test.Transaction["input"]))
+
+		testKey, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		require.NoError(t, err)
+		require.NoError(t, tx.Sign(signer, testKey))
+	}
+	origin, _ := signer.Sender(tx)
+
+	context := vm.Context{
+		CanTransfer: blockchain.CanTransfer,
+		Transfer:    blockchain.Transfer,
+		Origin:      origin,
+		BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
+		Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
+		BlockScore:  (*big.Int)(test.Context.BlockScore),
+		GasLimit:    uint64(test.Context.GasLimit),
+		GasPrice:    tx.GasPrice(),
+	}
+	statedb := tests.MakePreState(database.NewMemoryDBManager(), test.Genesis.Alloc)
+	evm := vm.NewEVM(context, statedb, test.Genesis.Config, &vm.Config{Debug: true, Tracer: tracer})
+
+	msg, err := tx.AsMessageWithAccountKeyPicker(signer, statedb, context.BlockNumber.Uint64())
+	require.NoError(t, err)
+	st := blockchain.NewStateTransition(evm, msg)
+	if _, _, kerr := st.TransitionDb(); kerr.ErrTxInvalid != nil {
+		t.Fatalf("failed to execute transaction: %v", kerr.ErrTxInvalid)
+	}
+	return tracer.GetResult()
+}
+
+// Tests that the native tracers produce the same results as the JavaScript
+// tracers for all the call tracer tests.
+func TestNativeTracers(t *testing.T) {
+	for name, test := range loadCallTracerTests(t) {
+		test := test // capture range variable
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, tracerName := range []string{"callTracer", "prestateTracer", "4byteTracer"} {
+				jsTracer, err := New(tracerName)
+				require.NoError(t, err)
+				nativeTracer, ok, err := NewNativeTracer(tracerName, nil)
+				require.True(t, ok)
+				require.NoError(t, err)
+
+				jsResult, err := runTracer(t, test, jsTracer)
+				require.NoError(t, err)
+				nativeResult, err := runTracer(t, test, nativeTracer)
+				require.NoError(t, err)
+
+				if tracerName == "callTracer" {
+					// The results should be identical including the order of the fields
+					assert.Equal(t, timeField.ReplaceAllString(string(jsResult), ""), timeField.ReplaceAllString(string(nativeResult), ""))
+
+					ret := new(callTrace)
+					require.NoError(t, json.Unmarshal(nativeResult, ret))
+					assert.Equal(t, test.Result, ret)
+				} else {
+					// The JavaScript tracers keep the insertion order of the keys
+					assert.JSONEq(t, string(jsResult), string(nativeResult), tracerName)
+				}
+			}
+		})
+	}
+}
+
+// Tests that the prestate tracer in the diff mode returns the modified fields
+// of the touched accounts only.
+func TestNativePrestateTracerDiffMode(t *testing.T) {
+	test := loadCallTracerTests(t)["simple"]
+
+	tracer, _, err := NewNativeTracer("prestateTracer", json.RawMessage(`{"diffMode": true}`))
+	require.NoError(t, err)
+
+	res, err := runTracer(t, test, tracer)
+	require.NoError(t, err)
+	result := make(map[string]map[string]map[string]interface{})
+	require.NoError(t, json.Unmarshal(res, &result))
+
+	pre, post := result["pre"], result["post"]
+	require.NotEmpty(t, pre)
+	assert.Equal(t, len(pre), len(post))
+	for addr, account := range post {
+		assert.Contains(t, pre, addr)
+		for field, value := range account {
+			assert.NotEqual(t, pre[addr][field], value, "%s of %s is not modified", field, addr)
+		}
+	}
+	// The nonce of the sender is increased by the transaction
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	tx := new(types.Transaction)
+	require.NoError(t, rlp.DecodeBytes(common.FromHex(test.Input), tx))
+	from, err := signer.Sender(tx)
+	require.NoError(t, err)
+	sender := strings.ToLower(from.Hex())
+	assert.Equal(t, pre[sender]["nonce"].(float64)+1, post[sender]["nonce"])
+}
+
+// Tests that the native tracers are interrupted by Stop.
+func TestNativeTracerStop(t *testing.T) {
+	test := loadCallTracerTests(t)["deepCalls"]
+	for name := range nativeTracers {
+		tracer, _, err := NewNativeTracer(name, nil)
+		require.NoError(t, err)
+
+		stopErr := errors.New("stopped")
+		tracer.Stop(stopErr)
+
+		_, err = runTracer(t, test, tracer)
+		assert.Equal(t, stopErr, err, name)
+	}
+}
+
+// Tests that unknown names are not resolved as native tracers.
+func TestNewNativeTracerUnknown(t *testing.T) {
+	_, ok, err := NewNativeTracer("unigramTracer", nil)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	_, ok, err = NewNativeTracer("prestateTracer", json.RawMessage(`{"diffMode": 1}`))
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
+
+func benchmarkTracer(b *testing.B, name string, native bool) {
+	test := loadCallTracerTests(b)["deepCalls"]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var tracer interface {
+			vm.Tracer
+			GetResult() (json.RawMessage, error)
+		}
+		var err error
+		if native {
+			tracer, _, err = NewNativeTracer(name, nil)
+		} else {
+			tracer, err = New(name)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := runTracer(b, test, tracer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCallTracerJS(b *testing.B)         { benchmarkTracer(b, "callTracer", false) }
+func BenchmarkCallTracerNative(b *testing.B)     { benchmarkTracer(b, "callTracer", true) }
+func BenchmarkPrestateTracerJS(b *testing.B)     { benchmarkTracer(b, "prestateTracer", false) }
+func BenchmarkPrestateTracerNative(b *testing.B) { benchmarkTracer(b, "prestateTracer", true) }
+func Benchmark4byteTracerJS(b *testing.B)        { benchmarkTracer(b, "4byteTracer", false) }
+func Benchmark4byteTracerNative(b *testing.B)    { benchmarkTracer(b, "4byteTracer", true) }
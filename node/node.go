@@ -31,12 +31,14 @@ import (
 	"sync"
 
 	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/api/debug"
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/networks/grpc"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/node/cn/filters"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/prometheus/prometheus/util/flock"
 )
@@ -351,7 +353,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		}
 	}
 	// start gRPC server
-	if err := n.startgRPC(apis); err != nil {
+	if err := n.startgRPC(apis, services); err != nil {
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
@@ -414,8 +416,10 @@ func (n *Node) stopIPC() {
 	}
 }
 
-// startgRPC initializes and starts the gRPC endpoint.
-func (n *Node) startgRPC(apis []rpc.API) error {
+// startgRPC initializes and starts the gRPC endpoint. The typed API is served
+// with the api.Backend provided by the services as a component, and its log
+// queries are served by the filter API of the services.
+func (n *Node) startgRPC(apis []rpc.API, services map[reflect.Type]Service) error {
 	if n.grpcEndpoint == "" {
 		return nil
 	}
@@ -434,6 +438,18 @@ func (n *Node) startgRPC(apis []rpc.API) error {
 	n.grpcHandler = handler
 	n.grpcListener = listener
 	listener.SetRPCServer(handler)
+	for _, service := range services {
+		for _, component := range service.Components() {
+			if backend, ok := component.(api.Backend); ok {
+				listener.SetAPIBackend(backend)
+			}
+		}
+	}
+	for _, api := range apis {
+		if filterAPI, ok := api.Service.(*filters.PublicFilterAPI); ok {
+			listener.SetFilterAPI(filterAPI)
+		}
+	}
 
 	go listener.Start()
 	n.logger.Info("gRPC endpoint opened", "url", n.grpcEndpoint)
@@ -22,6 +22,9 @@ package node_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/rpc"
@@ -46,8 +49,15 @@ func (s *SampleService) Components() []interface{}              { return nil }
 func (s *SampleService) SetComponents(components []interface{}) {}
 
 func ExampleService() {
-	// Create a network node to run protocols with the default values.
-	stack, err := node.New(&node.Config{})
+	// Create a network node to run protocols with the default values, keeping
+	// its node key in a temporary data directory.
+	datadir, err := ioutil.TempDir("", "node-example")
+	if err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+	defer os.RemoveAll(datadir)
+
+	stack, err := node.New(&node.Config{DataDir: datadir})
 	if err != nil {
 		log.Fatalf("Failed to create network node: %v", err)
 	}
@@ -332,6 +332,10 @@ func (fb *filterLocalBackend) BloomStatus() (uint64, uint64) {
 	return 4096, 0
 }
 
+func (fb *filterLocalBackend) LogIndexStatus() (uint64, uint64) {
+	return 4096, 0
+}
+
 func (fb *filterLocalBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	// TODO-Klaytn this method should implmentation to support indexed tag in solidity
 	//for i := 0; i < bloomFilterThreads; i++ {
@@ -75,6 +75,8 @@ const (
 	NumberComputationCost         = 202
 	DifficultyComputationCost     = 180
 	GasLimitComputationCost       = 166
+	ChainIDComputationCost        = 120
+	SelfBalanceComputationCost    = 374
 	PopComputationCost            = 140
 	MloadComputationCost          = 376
 	MstoreComputationCost         = 288
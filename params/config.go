@@ -163,6 +163,9 @@ type ChainConfig struct {
 
 	Incompatible1Block *big.Int `json:"incompatible1Block"` // incompatible1 switch block (nil = no fork, 0 = already incompatible1)
 
+	IstanbulCompatibleBlock *big.Int `json:"istanbulCompatibleBlock,omitempty"` // IstanbulCompatible switch block (nil = no fork, 0 = already on istanbulCompatible)
+	BerlinCompatibleBlock   *big.Int `json:"berlinCompatibleBlock,omitempty"`   // BerlinCompatible switch block (nil = no fork, 0 = already on berlinCompatible)
+
 	// Various consensus engines
 	Gxhash   *GxhashConfig   `json:"gxhash,omitempty"` // (deprecated) not supported engine
 	Clique   *CliqueConfig   `json:"clique,omitempty"`
@@ -178,6 +181,7 @@ type GovernanceConfig struct {
 	GoverningNode  common.Address `json:"governingNode"`
 	GovernanceMode string         `json:"governanceMode"`
 	Reward         *RewardConfig  `json:"reward,omitempty"`
+	Ballot         *BallotConfig  `json:"ballot,omitempty"`
 }
 
 func (g *GovernanceConfig) DeferredTxFee() bool {
@@ -195,6 +199,12 @@ type RewardConfig struct {
 	MinimumStake           *big.Int `json:"minimumStake"`           // Minimum amount of peb to join CCO
 }
 
+// BallotConfig stores the rules of the proposals voted in the ballot governance mode
+type BallotConfig struct {
+	VotingWindow uint64 `json:"votingWindow"` // Number of epochs a proposal stays open before its votes expire
+	Quorum       uint64 `json:"quorum"`       // Percentage of the total voting power to be exceeded to pass a proposal
+}
+
 // IstanbulConfig is the consensus engine configs for Istanbul based sealing.
 type IstanbulConfig struct {
 	Epoch          uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
@@ -241,18 +251,22 @@ func (c *ChainConfig) String() string {
 		engine = "unknown"
 	}
 	if c.Istanbul != nil {
-		return fmt.Sprintf("{ChainID: %v Incompatible1Block: %v SubGroupSize: %d UnitPrice: %d DeriveShaImpl: %d Engine: %v}",
+		return fmt.Sprintf("{ChainID: %v Incompatible1Block: %v IstanbulCompatibleBlock: %v BerlinCompatibleBlock: %v SubGroupSize: %d UnitPrice: %d DeriveShaImpl: %d Engine: %v}",
 			c.ChainID,
 			c.Incompatible1Block,
+			c.IstanbulCompatibleBlock,
+			c.BerlinCompatibleBlock,
 			c.Istanbul.SubGroupSize,
 			c.UnitPrice,
 			c.DeriveShaImpl,
 			engine,
 		)
 	} else {
-		return fmt.Sprintf("{ChainID: %v Incompatible1Block: %v UnitPrice: %d DeriveShaImpl: %d Engine: %v }",
+		return fmt.Sprintf("{ChainID: %v Incompatible1Block: %v IstanbulCompatibleBlock: %v BerlinCompatibleBlock: %v UnitPrice: %d DeriveShaImpl: %d Engine: %v }",
 			c.ChainID,
 			c.Incompatible1Block,
+			c.IstanbulCompatibleBlock,
+			c.BerlinCompatibleBlock,
 			c.UnitPrice,
 			c.DeriveShaImpl,
 			engine,
@@ -265,11 +279,55 @@ func (c *ChainConfig) IsIncompatible1(num *big.Int) bool {
 	return isForked(c.Incompatible1Block, num)
 }
 
+// IsIstanbul returns whether num is either equal to the istanbul compatible block or greater.
+func (c *ChainConfig) IsIstanbul(num *big.Int) bool {
+	return isForked(c.IstanbulCompatibleBlock, num)
+}
+
+// IsBerlin returns whether num is either equal to the berlin compatible block or greater.
+func (c *ChainConfig) IsBerlin(num *big.Int) bool {
+	return isForked(c.BerlinCompatibleBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase.
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
 func (c *ChainConfig) GasTable(num *big.Int) GasTable {
-	return GasTableCypress
+	switch {
+	case c.IsBerlin(num):
+		return GasTableBerlin
+	case c.IsIstanbul(num):
+		return GasTableIstanbul
+	default:
+		return GasTableCypress
+	}
+}
+
+// CheckConfigForkOrder checks that the forks are scheduled in the order they were
+// introduced. A fork can be left unscheduled, but no later fork can be scheduled then.
+func (c *ChainConfig) CheckConfigForkOrder() error {
+	type fork struct {
+		name  string
+		block *big.Int
+	}
+	var lastFork fork
+	for _, cur := range []fork{
+		{"istanbulCompatibleBlock", c.IstanbulCompatibleBlock},
+		{"berlinCompatibleBlock", c.BerlinCompatibleBlock},
+	} {
+		if lastFork.name != "" {
+			switch {
+			case lastFork.block == nil && cur.block != nil:
+				return fmt.Errorf("unsupported fork ordering: %v not enabled, but %v enabled at %v",
+					lastFork.name, cur.name, cur.block)
+			case lastFork.block != nil && cur.block != nil && lastFork.block.Cmp(cur.block) > 0:
+				return fmt.Errorf("unsupported fork ordering: %v enabled at %v, but %v enabled at %v",
+					lastFork.name, lastFork.block, cur.name, cur.block)
+			}
+		}
+		lastFork = cur
+	}
+	return nil
 }
 
 // CheckCompatible checks whether scheduled fork transitions have been imported
@@ -294,6 +352,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.Incompatible1Block, newcfg.Incompatible1Block, head) {
 		return newCompatError("Incompatible1 Block", c.Incompatible1Block, newcfg.Incompatible1Block)
 	}
+	if isForkIncompatible(c.IstanbulCompatibleBlock, newcfg.IstanbulCompatibleBlock, head) {
+		return newCompatError("IstanbulCompatible Block", c.IstanbulCompatibleBlock, newcfg.IstanbulCompatibleBlock)
+	}
+	if isForkIncompatible(c.BerlinCompatibleBlock, newcfg.BerlinCompatibleBlock, head) {
+		return newCompatError("BerlinCompatible Block", c.BerlinCompatibleBlock, newcfg.BerlinCompatibleBlock)
+	}
 	return nil
 }
 
@@ -407,6 +471,8 @@ func (err *ConfigCompatError) Error() string {
 type Rules struct {
 	ChainID         *big.Int
 	IsInCompatible1 bool
+	IsIstanbul      bool
+	IsBerlin        bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -418,6 +484,8 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	return Rules{
 		ChainID:         new(big.Int).Set(chainID),
 		IsInCompatible1: c.IsIncompatible1(num),
+		IsIstanbul:      c.IsIstanbul(num),
+		IsBerlin:        c.IsBerlin(num),
 	}
 }
 
@@ -432,6 +500,12 @@ func (g *GovernanceConfig) Copy() *GovernanceConfig {
 	newConfig.Reward.UseGiniCoeff = g.Reward.UseGiniCoeff
 	newConfig.Reward.DeferredTxFee = g.Reward.DeferredTxFee
 	newConfig.GoverningNode = g.GoverningNode
+	if g.Ballot != nil {
+		newConfig.Ballot = &BallotConfig{
+			VotingWindow: g.Ballot.VotingWindow,
+			Quorum:       g.Ballot.Quorum,
+		}
+	}
 
 	return newConfig
 }
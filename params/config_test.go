@@ -0,0 +1,127 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestIsIstanbul(t *testing.T) {
+	config := &ChainConfig{IstanbulCompatibleBlock: big.NewInt(10)}
+
+	for _, tc := range []struct {
+		num      int64
+		expected bool
+	}{
+		{0, false},
+		{9, false},
+		{10, true},
+		{11, true},
+	} {
+		num := big.NewInt(tc.num)
+		if config.IsIstanbul(num) != tc.expected || config.Rules(num).IsIstanbul != tc.expected {
+			t.Errorf("IsIstanbul mismatch at %d: want %v", tc.num, tc.expected)
+		}
+	}
+	if (&ChainConfig{}).IsIstanbul(big.NewInt(1 << 40)) {
+		t.Error("IsIstanbul should be false if the fork is not scheduled")
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	type test struct {
+		stored, new *ChainConfig
+		head        uint64
+		wantErr     *ConfigCompatError
+	}
+	tests := []test{
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 0, wantErr: nil},
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 100, wantErr: nil},
+		{
+			stored:  &ChainConfig{IstanbulCompatibleBlock: big.NewInt(10)},
+			new:     &ChainConfig{IstanbulCompatibleBlock: big.NewInt(20)},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{IstanbulCompatibleBlock: big.NewInt(20)},
+			head:    19,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{IstanbulCompatibleBlock: big.NewInt(10)},
+			new:    &ChainConfig{IstanbulCompatibleBlock: big.NewInt(20)},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "IstanbulCompatible Block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{IstanbulCompatibleBlock: big.NewInt(20)},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "IstanbulCompatible Block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
+		{
+			stored: &ChainConfig{IstanbulCompatibleBlock: big.NewInt(0), BerlinCompatibleBlock: big.NewInt(30)},
+			new:    &ChainConfig{IstanbulCompatibleBlock: big.NewInt(0), BerlinCompatibleBlock: big.NewInt(40)},
+			head:   35,
+			wantErr: &ConfigCompatError{
+				What:         "BerlinCompatible Block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    big.NewInt(40),
+				RewindTo:     29,
+			},
+		},
+	}
+
+	for i, tc := range tests {
+		err := tc.stored.CheckCompatible(tc.new, tc.head)
+		if !reflect.DeepEqual(err, tc.wantErr) {
+			t.Errorf("test %d: error mismatch:\nstored: %v\nnew: %v\nhead: %v\nerr: %v\nwant: %v", i, tc.stored, tc.new, tc.head, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCheckConfigForkOrder(t *testing.T) {
+	for i, tc := range []struct {
+		config  *ChainConfig
+		wantErr bool
+	}{
+		{&ChainConfig{}, false},
+		{&ChainConfig{IstanbulCompatibleBlock: big.NewInt(10)}, false},
+		{&ChainConfig{IstanbulCompatibleBlock: big.NewInt(10), BerlinCompatibleBlock: big.NewInt(10)}, false},
+		{&ChainConfig{IstanbulCompatibleBlock: big.NewInt(10), BerlinCompatibleBlock: big.NewInt(20)}, false},
+		{&ChainConfig{IstanbulCompatibleBlock: big.NewInt(20), BerlinCompatibleBlock: big.NewInt(10)}, true},
+		{&ChainConfig{BerlinCompatibleBlock: big.NewInt(10)}, true},
+	} {
+		if err := tc.config.CheckConfigForkOrder(); (err != nil) != tc.wantErr {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+	}
+}
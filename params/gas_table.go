@@ -56,4 +56,35 @@ var (
 
 		CreateBySuicide: 25000, // G_newaccount
 	}
+
+	// GasTableIstanbul contains the gas prices after the istanbul compatible block.
+	// The prices of the state access opcodes are raised as in EIP-1884.
+	GasTableIstanbul = GasTable{
+		ExtcodeSize: 700,
+		ExtcodeCopy: 700,
+		ExtcodeHash: 700,
+		Balance:     700,
+		SLoad:       800,
+		Calls:       700,
+		Suicide:     5000,
+		ExpByte:     50,
+
+		CreateBySuicide: 25000,
+	}
+
+	// GasTableBerlin contains the gas prices after the berlin compatible block.
+	// The state access opcodes are charged the warm access cost of EIP-2929, and
+	// the difference to the cold access cost is added on the first access in a transaction.
+	GasTableBerlin = GasTable{
+		ExtcodeSize: WarmStorageReadCostEIP2929,
+		ExtcodeCopy: WarmStorageReadCostEIP2929,
+		ExtcodeHash: WarmStorageReadCostEIP2929,
+		Balance:     WarmStorageReadCostEIP2929,
+		SLoad:       WarmStorageReadCostEIP2929,
+		Calls:       WarmStorageReadCostEIP2929,
+		Suicide:     5000,
+		ExpByte:     50,
+
+		CreateBySuicide: 25000,
+	}
 )
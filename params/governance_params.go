@@ -104,6 +104,7 @@ const (
 	RoundRobin = iota
 	Sticky
 	WeightedRandom
+	StakeCappedWeightedRandom
 )
 
 const (
@@ -119,8 +120,27 @@ const (
 	DefaultDefferedTxFee  = false
 	DefaultUnitPrice      = uint64(250000000000)
 	DefaultPeriod         = 1
+	DefaultVotingWindow   = uint64(1)
+	DefaultQuorum         = uint64(50)
 )
 
+// weightedRandomPolicies has the proposer policies selecting proposers randomly
+// with the weights calculated from staking information. They are registered by
+// the proposer policy registry of the validator package.
+var weightedRandomPolicies = make(map[uint64]bool)
+
+// RegisterWeightedRandomPolicy marks the proposer policy as weighted random.
+// It is supposed to be called in init functions.
+func RegisterWeightedRandomPolicy(policy uint64) {
+	weightedRandomPolicies[policy] = true
+}
+
+// IsWeightedRandomPolicy returns true if the proposer policy selects proposers
+// randomly with the weights calculated from staking information.
+func IsWeightedRandomPolicy(policy uint64) bool {
+	return weightedRandomPolicies[policy]
+}
+
 func IsStakingUpdateInterval(blockNum uint64) bool {
 	return (blockNum % StakingUpdateInterval()) == 0
 }
@@ -83,6 +83,15 @@ const (
 	FeePayerGas             uint64 = 300    // Gas needed for calculating the fee payer of the transaction in a smart contract.
 	ValidateSenderGas       uint64 = 5000   // Gas needed for validating the signature of a message.
 
+	Bn256AddGasIstanbul             uint64 = 150   // Gas needed for an elliptic curve addition after the istanbul compatible block
+	Bn256ScalarMulGasIstanbul       uint64 = 6000  // Gas needed for an elliptic curve scalar multiplication after the istanbul compatible block
+	Bn256PairingBaseGasIstanbul     uint64 = 45000 // Base price for an elliptic curve pairing check after the istanbul compatible block
+	Bn256PairingPerPointGasIstanbul uint64 = 34000 // Per-point price for an elliptic curve pairing check after the istanbul compatible block
+
+	ColdAccountAccessCostEIP2929 uint64 = 2600 // Cost of the first access to an account in a transaction after the berlin compatible block
+	ColdSloadCostEIP2929         uint64 = 2100 // Cost of the first access to a storage slot in a transaction after the berlin compatible block
+	WarmStorageReadCostEIP2929   uint64 = 100  // Cost of the access to an account or a storage slot already accessed in a transaction
+
 	GasLimitBoundDivisor uint64 = 1024    // The bound divisor of the gas limit, used in update calculations.
 	MinGasLimit          uint64 = 5000    // Minimum the gas limit may ever be.
 	GenesisGasLimit      uint64 = 4712388 // Gas limit of the Genesis block.
@@ -17,6 +17,7 @@
 package reward
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -38,6 +39,56 @@ type governanceHelper interface {
 	StakingUpdateInterval() uint64
 }
 
+// RewardDetail is the details of the block reward distributed to the recipients.
+type RewardDetail struct {
+	BlockNumber   uint64   `json:"blockNumber"`
+	Minted        *big.Int `json:"minted"`
+	TotalFee      *big.Int `json:"totalFee"`
+	DeferredTxFee bool     `json:"deferredTxFee"`
+
+	// Ratio is the ratio of the CN reward, the PoC incentive and the KIR incentive.
+	// It is empty if the block reward is given to the proposer only.
+	Ratio string `json:"ratio"`
+
+	// Staking information used to determine the recipients
+	UseGini            bool    `json:"useGini"`
+	Gini               float64 `json:"gini"`
+	StakingBlockNumber uint64  `json:"stakingBlockNumber"`
+
+	Proposer common.Address              `json:"proposer"`
+	PoC      common.Address              `json:"poc"`
+	KIR      common.Address              `json:"kir"`
+	Rewards  map[common.Address]*big.Int `json:"rewards"`
+}
+
+func newRewardDetail(header *types.Header, rewardConfig *rewardConfig, totalTxFee *big.Int) *RewardDetail {
+	return &RewardDetail{
+		BlockNumber: header.Number.Uint64(),
+		Minted:      new(big.Int).Set(rewardConfig.mintingAmount),
+		TotalFee:    new(big.Int).Set(totalTxFee),
+		Proposer:    header.Rewardbase,
+		Rewards:     make(map[common.Address]*big.Int),
+	}
+}
+
+func (d *RewardDetail) addReward(addr common.Address, amount *big.Int) {
+	if r, ok := d.Rewards[addr]; ok {
+		r.Add(r, amount)
+	} else {
+		d.Rewards[addr] = new(big.Int).Set(amount)
+	}
+}
+
+// SetStakingInfo records the staking information used to determine the recipients.
+func (d *RewardDetail) SetStakingInfo(stakingInfo *StakingInfo) {
+	if stakingInfo == nil {
+		return
+	}
+	d.UseGini = stakingInfo.UseGini
+	d.Gini = stakingInfo.Gini
+	d.StakingBlockNumber = stakingInfo.BlockNum
+}
+
 type RewardDistributor struct {
 	rcc *rewardConfigCache
 	gh  governanceHelper
@@ -58,24 +109,28 @@ func (rd *RewardDistributor) getTotalTxFee(header *types.Header, rewardConfig *r
 }
 
 // MintKLAY mints KLAY and gives the KLAY and the total transaction gas fee to the block proposer.
-func (rd *RewardDistributor) MintKLAY(b BalanceAdder, header *types.Header) error {
+// It returns the details of the distributed reward.
+func (rd *RewardDistributor) MintKLAY(b BalanceAdder, header *types.Header) (*RewardDetail, error) {
 	rewardConfig, err := rd.rcc.get(header.Number.Uint64())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	totalTxFee := rd.getTotalTxFee(header, rewardConfig)
+	detail := newRewardDetail(header, rewardConfig, totalTxFee)
 	blockReward := totalTxFee.Add(rewardConfig.mintingAmount, totalTxFee)
 
 	b.AddBalance(header.Rewardbase, blockReward)
-	return nil
+	detail.addReward(header.Rewardbase, blockReward)
+	return detail, nil
 }
 
 // DistributeBlockReward distributes block reward to proposer, kirAddr and pocAddr.
-func (rd *RewardDistributor) DistributeBlockReward(b BalanceAdder, header *types.Header, pocAddr common.Address, kirAddr common.Address) error {
+// It returns the details of the distributed reward.
+func (rd *RewardDistributor) DistributeBlockReward(b BalanceAdder, header *types.Header, pocAddr common.Address, kirAddr common.Address) (*RewardDetail, error) {
 	rewardConfig, err := rd.rcc.get(header.Number.Uint64())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Calculate total tx fee
@@ -84,12 +139,15 @@ func (rd *RewardDistributor) DistributeBlockReward(b BalanceAdder, header *types
 		totalTxFee = rd.getTotalTxFee(header, rewardConfig)
 	}
 
-	rd.distributeBlockReward(b, header, totalTxFee, rewardConfig, pocAddr, kirAddr)
-	return nil
+	return rd.distributeBlockReward(b, header, totalTxFee, rewardConfig, pocAddr, kirAddr), nil
 }
 
 // distributeBlockReward mints KLAY and distributes newly minted KLAY and transaction fee to proposer, kirAddr and pocAddr.
-func (rd *RewardDistributor) distributeBlockReward(b BalanceAdder, header *types.Header, totalTxFee *big.Int, rewardConfig *rewardConfig, pocAddr common.Address, kirAddr common.Address) {
+func (rd *RewardDistributor) distributeBlockReward(b BalanceAdder, header *types.Header, totalTxFee *big.Int, rewardConfig *rewardConfig, pocAddr common.Address, kirAddr common.Address) *RewardDetail {
+	detail := newRewardDetail(header, rewardConfig, totalTxFee)
+	detail.DeferredTxFee = rd.gh.DeferredTxFee()
+	detail.Ratio = fmt.Sprintf("%v/%v/%v", rewardConfig.cnRatio, rewardConfig.pocRatio, rewardConfig.kirRatio)
+
 	proposer := header.Rewardbase
 	// Block reward
 	blockReward := big.NewInt(0).Add(rewardConfig.mintingAmount, totalTxFee)
@@ -112,6 +170,7 @@ func (rd *RewardDistributor) distributeBlockReward(b BalanceAdder, header *types
 
 	// CN reward
 	b.AddBalance(proposer, cnReward)
+	detail.addReward(proposer, cnReward)
 
 	// Proposer gets PoC incentive and KIR incentive, if there is no PoC/KIR address.
 	// PoC
@@ -119,15 +178,20 @@ func (rd *RewardDistributor) distributeBlockReward(b BalanceAdder, header *types
 		pocAddr = proposer
 	}
 	b.AddBalance(pocAddr, pocIncentive)
+	detail.addReward(pocAddr, pocIncentive)
+	detail.PoC = pocAddr
 
 	// KIR
 	if common.EmptyAddress(kirAddr) {
 		kirAddr = proposer
 	}
 	b.AddBalance(kirAddr, kirIncentive)
+	detail.addReward(kirAddr, kirIncentive)
+	detail.KIR = kirAddr
 
 	logger.Debug("Block reward", "blockNumber", header.Number.Uint64(),
 		"Reward address of a proposer", proposer, "CN reward amount", cnReward,
 		"PoC address", pocAddr, "Poc incentive", pocIncentive,
 		"KIR address", kirAddr, "KIR incentive", kirIncentive)
+	return detail
 }
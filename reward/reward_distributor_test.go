@@ -116,13 +116,17 @@ func TestRewardDistributor_MintKLAY(t *testing.T) {
 	governance := newDefaultTestGovernance()
 	rewardDistributor := NewRewardDistributor(governance)
 
-	err := rewardDistributor.MintKLAY(BalanceAdder, header)
+	detail, err := rewardDistributor.MintKLAY(BalanceAdder, header)
 	if !assert.NoError(t, err) {
 		t.FailNow()
 	}
 
 	assert.NotNil(t, BalanceAdder.GetBalance(header.Rewardbase).Int64())
 	assert.Equal(t, governance.mintingAmount, BalanceAdder.GetBalance(header.Rewardbase).String())
+
+	assert.Equal(t, governance.mintingAmount, detail.Minted.String())
+	assert.Equal(t, "", detail.Ratio)
+	assert.Equal(t, map[common.Address]*big.Int{header.Rewardbase: BalanceAdder.GetBalance(header.Rewardbase)}, detail.Rewards)
 }
 
 func TestRewardDistributor_distributeBlockReward(t *testing.T) {
@@ -247,11 +251,23 @@ func TestRewardDistributor_DistributeBlockReward(t *testing.T) {
 		header.GasUsed = testCase.gasUsed
 		rewardDistributor := NewRewardDistributor(governance)
 
-		err := rewardDistributor.DistributeBlockReward(BalanceAdder, header, pocAddress, kirAddress)
+		detail, err := rewardDistributor.DistributeBlockReward(BalanceAdder, header, pocAddress, kirAddress)
 		if !assert.NoError(t, err) {
 			t.FailNow()
 		}
 
+		// The details match the balance changes
+		assert.Equal(t, testCase.mintingAmount, detail.Minted.String())
+		assert.Equal(t, testCase.ratio, detail.Ratio)
+		assert.Equal(t, pocAddress, detail.PoC)
+		assert.Equal(t, kirAddress, detail.KIR)
+		total := new(big.Int).Add(detail.Minted, detail.TotalFee)
+		for addr, amount := range detail.Rewards {
+			assert.Equal(t, BalanceAdder.GetBalance(addr), amount)
+			total.Sub(total, amount)
+		}
+		assert.Equal(t, 0, total.Sign())
+
 		assert.NotNil(t, BalanceAdder.GetBalance(header.Rewardbase).Int64())
 		assert.Equal(t, testCase.expectedCnBalance.Uint64(), BalanceAdder.GetBalance(header.Rewardbase).Uint64())
 		assert.Equal(t, testCase.expectedPocBalance.Uint64(), BalanceAdder.GetBalance(pocAddress).Uint64())
@@ -208,7 +208,7 @@ func handleChainHeadEvent() {
 		select {
 		// Handle ChainHeadEvent
 		case ev := <-stakingManager.chainHeadChan:
-			if stakingManager.governanceHelper.ProposerPolicy() == params.WeightedRandom {
+			if params.IsWeightedRandomPolicy(stakingManager.governanceHelper.ProposerPolicy()) {
 				// check and update if staking info is not valid before for the next update interval blocks
 				stakingInfo := GetStakingInfo(ev.Block.NumberU64() + params.StakingUpdateInterval())
 				if stakingInfo == nil {
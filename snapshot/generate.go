@@ -0,0 +1,329 @@
+// Modifications Copyright 2020 The klaytn Authors
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file is derived from core/state/snapshot/generate.go (2020/12/14).
+// Modified and improved for the klaytn development.
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+)
+
+const (
+	// accountSnapshotKeyLength is the length of an account snapshot key:
+	// SnapshotAccountPrefix + account hash.
+	accountSnapshotKeyLength = 1 + common.HashLength
+
+	// storageSnapshotKeyLength is the length of a storage snapshot key:
+	// SnapshotStoragePrefix + account hash + storage hash.
+	storageSnapshotKeyLength = 1 + 2*common.HashLength
+)
+
+// emptyRoot is the known root hash of an empty trie.
+var emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// generatorStats is a collection of statistics gathered by the snapshot generator
+// for logging purposes.
+type generatorStats struct {
+	origin   uint64             // Origin prefix where generation started
+	start    time.Time          // Timestamp when generation started
+	accounts uint64             // Number of accounts indexed
+	slots    uint64             // Number of storage slots indexed
+	storage  common.StorageSize // Account and storage slot size
+}
+
+// Log creates an contextual log with the given message and the context pulled
+// from the internally maintained statistics.
+func (gs *generatorStats) Log(msg string, root common.Hash, marker []byte) {
+	var ctx []interface{}
+	if root != (common.Hash{}) {
+		ctx = append(ctx, []interface{}{"root", root}...)
+	}
+	// Figure out whether we're after or within an account
+	switch len(marker) {
+	case common.HashLength:
+		ctx = append(ctx, []interface{}{"at", common.BytesToHash(marker)}...)
+	case 2 * common.HashLength:
+		ctx = append(ctx, []interface{}{
+			"in", common.BytesToHash(marker[:common.HashLength]),
+			"at", common.BytesToHash(marker[common.HashLength:]),
+		}...)
+	}
+	// Add the usual measurements
+	ctx = append(ctx, []interface{}{
+		"accounts", gs.accounts,
+		"slots", gs.slots,
+		"storage", gs.storage,
+		"elapsed", common.PrettyDuration(time.Since(gs.start)),
+	}...)
+	// Calculate the estimated indexing time based on current stats
+	if len(marker) > 0 {
+		if done := binary.BigEndian.Uint64(marker[:8]) - gs.origin; done > 0 {
+			left := ^uint64(0) - binary.BigEndian.Uint64(marker[:8])
+
+			speed := done/uint64(time.Since(gs.start)/time.Millisecond+1) + 1 // +1s to avoid division by zero
+			ctx = append(ctx, []interface{}{
+				"eta", common.PrettyDuration(time.Duration(left/speed) * time.Millisecond),
+			}...)
+		}
+	}
+	logger.Info(msg, ctx...)
+}
+
+// copy returns a copy of the statistics to share it with other goroutines.
+func (gs *generatorStats) copy() *generatorStats {
+	cpy := *gs
+	return &cpy
+}
+
+// generateSnapshot regenerates a brand new snapshot based on an existing state
+// database and head block asynchronously. The snapshot is returned immediately
+// and generation is continued in the background until done.
+func generateSnapshot(diskdb database.DBManager, triedb *statedb.Database, cache int, root common.Hash) *diskLayer {
+	// Create a new disk layer with an initialized state marker at zero
+	var (
+		stats     = &generatorStats{start: time.Now()}
+		batch     = diskdb.NewBatch(database.SnapshotDB)
+		genMarker = []byte{} // Initialized but empty!
+	)
+	batch.Put(database.SnapshotRootKey, root[:])
+	journalProgress(batch, genMarker, stats)
+	if err := batch.Write(); err != nil {
+		logger.Crit("Failed to write initialized state marker", "err", err)
+	}
+	base := &diskLayer{
+		diskdb:     diskdb,
+		triedb:     triedb,
+		root:       root,
+		cache:      fastcache.New(cache * 1024 * 1024),
+		genMarker:  genMarker,
+		genPending: make(chan struct{}),
+		genAbort:   make(chan chan *generatorStats),
+	}
+	go base.generate(stats)
+	logger.Debug("Start snapshot generation", "root", root)
+	return base
+}
+
+// wipe removes the leftovers of a previous snapshot. It returns the abort
+// request channel if the wiping was interrupted before it finished.
+func (dl *diskLayer) wipe(stats *generatorStats) chan *generatorStats {
+	for _, prefix := range [][]byte{database.SnapshotAccountPrefix, database.SnapshotStoragePrefix} {
+		keylen := accountSnapshotKeyLength
+		if bytes.Equal(prefix, database.SnapshotStoragePrefix) {
+			keylen = storageSnapshotKeyLength
+		}
+		var (
+			batch = dl.diskdb.NewBatch(database.SnapshotDB)
+			it    = dl.diskdb.NewSnapshotDBIterator(prefix, nil)
+		)
+		for it.Next() {
+			// Skip any keys with the correct prefix but wrong length, as they
+			// can belong to other data sharing the same database.
+			if key := it.Key(); len(key) == keylen {
+				batch.Delete(key)
+			}
+			if batch.ValueSize() > database.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					logger.Crit("Failed to wipe snapshot", "err", err)
+				}
+				batch.Reset()
+
+				select {
+				case abort := <-dl.genAbort:
+					it.Release()
+					return abort
+				default:
+				}
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			logger.Crit("Failed to wipe snapshot", "err", err)
+		}
+	}
+	stats.Log("Wiped previous snapshot", dl.root, nil)
+	return nil
+}
+
+// generate is a background thread that iterates over the state and storage tries,
+// constructing the state snapshot. All the arguments are purely for statistics
+// gathering and logging, since the method surfs the blocks as they arrive, often
+// being restarted.
+func (dl *diskLayer) generate(stats *generatorStats) {
+	snapshotGenerationRunningGauge.Update(1)
+	defer snapshotGenerationRunningGauge.Update(0)
+
+	// A fresh generation can't trust any leftover of a previous snapshot, so
+	// delete it before starting. Keys beyond the marker are invisible to the
+	// readers, so it's safe to do in the background.
+	dl.lock.RLock()
+	fresh := len(dl.genMarker) == 0
+	dl.lock.RUnlock()
+	if fresh {
+		if abort := dl.wipe(stats); abort != nil {
+			stats.Log("Aborting state snapshot wiping", dl.root, nil)
+			abort <- stats
+			return
+		}
+	}
+	// Create an account and state iterator pointing to the current generator marker
+	accTrie, err := statedb.NewSecureTrie(dl.root, dl.triedb)
+	if err != nil {
+		// The account trie is missing (GC), surf the chain until one becomes available
+		stats.Log("Trie missing, state snapshotting paused", dl.root, dl.genMarker)
+
+		abort := <-dl.genAbort
+		abort <- stats
+		return
+	}
+	stats.Log("Resuming state snapshot generation", dl.root, dl.genMarker)
+
+	var accMarker []byte
+	if len(dl.genMarker) > 0 { // []byte{} is the start, use nil for that
+		accMarker = dl.genMarker[:common.HashLength]
+	}
+	var (
+		accIt  = statedb.NewIterator(accTrie.NodeIterator(accMarker))
+		batch  = dl.diskdb.NewBatch(database.SnapshotDB)
+		logged = time.Now()
+	)
+	// flush writes the batch along with the new marker and reports whether the
+	// generation has to stop.
+	flush := func(marker []byte) chan *generatorStats {
+		var abort chan *generatorStats
+		select {
+		case abort = <-dl.genAbort:
+		default:
+		}
+		if batch.ValueSize() > database.IdealBatchSize || abort != nil {
+			// Only write and set the marker if we actually did something useful
+			if batch.ValueSize() > 0 {
+				// Ensure the generator entry is in sync with the data
+				journalProgress(batch, marker, stats)
+
+				if err := batch.Write(); err != nil {
+					logger.Crit("Failed to write snapshot", "err", err)
+				}
+				batch.Reset()
+
+				dl.lock.Lock()
+				dl.genMarker = marker
+				dl.genStats = stats.copy()
+				dl.lock.Unlock()
+			}
+			if abort != nil {
+				stats.Log("Aborting state snapshot generation", dl.root, marker)
+			}
+		}
+		return abort
+	}
+	for accIt.Next() {
+		accountHash := common.BytesToHash(accIt.Key)
+
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(accIt.Value, serializer); err != nil {
+			logger.Crit("Invalid account encountered during snapshot creation", "err", err)
+		}
+		// If the account is not yet in-progress, write it out
+		if accMarker == nil || !bytes.Equal(accountHash[:], accMarker) {
+			batch.Put(database.AccountSnapshotKey(accountHash), accIt.Value)
+			stats.storage += common.StorageSize(accountSnapshotKeyLength + len(accIt.Value))
+			stats.accounts++
+			snapshotGeneratedAccountMeter.Mark(1)
+		}
+		// If we've exceeded our batch allowance or termination was requested, flush to disk
+		if abort := flush(accountHash[:]); abort != nil {
+			abort <- stats
+			return
+		}
+		// If the account is in-progress, continue where we left off (otherwise iterate all)
+		if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil && pa.GetStorageRoot() != emptyRoot {
+			storeTrie, err := statedb.NewSecureTrie(pa.GetStorageRoot(), dl.triedb)
+			if err != nil {
+				logger.Error("Generator failed to access storage trie", "root", dl.root, "account", accountHash, "stroot", pa.GetStorageRoot(), "err", err)
+				abort := <-dl.genAbort
+				abort <- stats
+				return
+			}
+			var storeMarker []byte
+			if accMarker != nil && bytes.Equal(accountHash[:], accMarker) && len(dl.genMarker) > common.HashLength {
+				storeMarker = dl.genMarker[common.HashLength:]
+			}
+			storeIt := statedb.NewIterator(storeTrie.NodeIterator(storeMarker))
+			for storeIt.Next() {
+				batch.Put(database.StorageSnapshotKey(accountHash, common.BytesToHash(storeIt.Key)), storeIt.Value)
+				stats.storage += common.StorageSize(storageSnapshotKeyLength + len(storeIt.Value))
+				stats.slots++
+				snapshotGeneratedStorageMeter.Mark(1)
+
+				// If we've exceeded our batch allowance or termination was requested, flush to disk
+				if abort := flush(append(accountHash[:], storeIt.Key...)); abort != nil {
+					abort <- stats
+					return
+				}
+			}
+			if storeIt.Err != nil {
+				logger.Error("Generator failed to iterate storage trie", "accroot", dl.root, "acchash", accountHash, "stroot", pa.GetStorageRoot(), "err", storeIt.Err)
+				abort := <-dl.genAbort
+				abort <- stats
+				return
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			stats.Log("Generating state snapshot", dl.root, accIt.Key)
+			logged = time.Now()
+		}
+		// Some account processed, unmark the marker
+		accMarker = nil
+	}
+	if accIt.Err != nil {
+		logger.Error("Generator failed to iterate account trie", "root", dl.root, "err", accIt.Err)
+		abort := <-dl.genAbort
+		abort <- stats
+		return
+	}
+	// Snapshot fully generated, set the marker to nil.
+	// Note even there is nothing to commit, persist the
+	// generator anyway to mark the snapshot is complete.
+	journalProgress(batch, nil, stats)
+	if err := batch.Write(); err != nil {
+		logger.Crit("Failed to write snapshot", "err", err)
+	}
+	logger.Info("Generated state snapshot", "accounts", stats.accounts, "slots", stats.slots,
+		"storage", stats.storage, "elapsed", common.PrettyDuration(time.Since(stats.start)))
+
+	dl.lock.Lock()
+	dl.genMarker = nil
+	dl.genStats = stats.copy()
+	close(dl.genPending)
+	dl.lock.Unlock()
+
+	// Someone will be looking for us, wait it out
+	abort := <-dl.genAbort
+	abort <- nil
+}
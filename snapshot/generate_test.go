@@ -0,0 +1,203 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+// testState is a small state trie used to verify the snapshot generation.
+type testState struct {
+	diskdb   database.DBManager
+	triedb   *statedb.Database
+	root     common.Hash
+	accounts map[common.Hash][]byte
+	storage  map[common.Hash]map[common.Hash][]byte
+}
+
+func encodeAccount(t *testing.T, acc account.Account) []byte {
+	serializer := account.NewAccountSerializerWithAccount(acc)
+	blob, err := rlp.EncodeToBytes(serializer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blob
+}
+
+// newTestState creates a state trie with two externally owned accounts and
+// a smart contract account having three storage slots.
+func newTestState(t *testing.T) *testState {
+	var (
+		diskdb = database.NewMemoryDBManager()
+		triedb = statedb.NewDatabase(diskdb)
+		ts     = &testState{
+			diskdb:   diskdb,
+			triedb:   triedb,
+			accounts: make(map[common.Hash][]byte),
+			storage:  make(map[common.Hash]map[common.Hash][]byte),
+		}
+	)
+	// Build the storage trie of the contract
+	storageTrie, _ := statedb.NewSecureTrie(common.Hash{}, triedb)
+	contractHash := crypto.Keccak256Hash([]byte("contract"))
+	ts.storage[contractHash] = make(map[common.Hash][]byte)
+	for i := byte(1); i <= 3; i++ {
+		key := common.BytesToHash([]byte{i})
+		val, _ := rlp.EncodeToBytes([]byte{i, i})
+		storageTrie.Update(key[:], val)
+		ts.storage[contractHash][crypto.Keccak256Hash(key[:])] = val
+	}
+	storageRoot, err := storageTrie.Commit(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Build the account trie
+	accTrie, _ := statedb.NewSecureTrie(common.Hash{}, triedb)
+	for i, name := range []string{"alice", "bob"} {
+		eoa, _ := account.NewAccountWithMap(account.ExternallyOwnedAccountType, map[account.AccountValueKeyType]interface{}{
+			account.AccountValueKeyNonce:         uint64(i),
+			account.AccountValueKeyBalance:       big.NewInt(int64(1000 * (i + 1))),
+			account.AccountValueKeyHumanReadable: false,
+			account.AccountValueKeyAccountKey:    accountkey.NewAccountKeyLegacy(),
+		})
+		blob := encodeAccount(t, eoa)
+		accTrie.Update([]byte(name), blob)
+		ts.accounts[crypto.Keccak256Hash([]byte(name))] = blob
+	}
+	sca, _ := account.NewAccountWithMap(account.SmartContractAccountType, map[account.AccountValueKeyType]interface{}{
+		account.AccountValueKeyNonce:         uint64(1),
+		account.AccountValueKeyBalance:       big.NewInt(0),
+		account.AccountValueKeyHumanReadable: false,
+		account.AccountValueKeyAccountKey:    accountkey.NewAccountKeyFail(),
+		account.AccountValueKeyStorageRoot:   storageRoot,
+		account.AccountValueKeyCodeHash:      crypto.Keccak256([]byte("code")),
+	})
+	blob := encodeAccount(t, sca)
+	accTrie.Update([]byte("contract"), blob)
+	ts.accounts[contractHash] = blob
+
+	if ts.root, err = accTrie.Commit(nil); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// stopGeneration aborts the generator of the given disk layer, which waits for
+// the abort request even after the generation is done.
+func stopGeneration(dl *diskLayer) {
+	abort := make(chan *generatorStats)
+	dl.genAbort <- abort
+	<-abort
+}
+
+// Tests that the snapshot generated from a state trie contains all the accounts
+// and storage slots of the trie.
+func TestGeneration(t *testing.T) {
+	ts := newTestState(t)
+
+	snap := generateSnapshot(ts.diskdb, ts.triedb, 16, ts.root)
+	<-snap.genPending
+	defer stopGeneration(snap)
+
+	assert.Nil(t, snap.genMarker)
+	assert.Equal(t, ts.root, ts.diskdb.ReadSnapshotRoot())
+
+	for hash, blob := range ts.accounts {
+		data, err := snap.AccountRLP(hash)
+		assert.NoError(t, err)
+		assert.Equal(t, blob, data)
+		assert.Equal(t, blob, ts.diskdb.ReadAccountSnapshot(hash))
+	}
+	for accHash, slots := range ts.storage {
+		for slotHash, val := range slots {
+			data, err := snap.Storage(accHash, slotHash)
+			assert.NoError(t, err)
+			assert.Equal(t, val, data)
+		}
+	}
+	// Missing entries should be reported as empty, not as an error
+	data, err := snap.AccountRLP(crypto.Keccak256Hash([]byte("nobody")))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	// The finished generation should be journalled as done
+	var generator journalGenerator
+	assert.NoError(t, rlp.DecodeBytes(ts.diskdb.ReadSnapshotGenerator(), &generator))
+	assert.True(t, generator.Done)
+	assert.Equal(t, uint64(len(ts.accounts)), generator.Accounts)
+	assert.Equal(t, uint64(3), generator.Slots)
+}
+
+// Tests that the leftovers of a previous snapshot are wiped before generating
+// a brand new snapshot.
+func TestGenerationWipesLeftovers(t *testing.T) {
+	ts := newTestState(t)
+
+	stale := crypto.Keccak256Hash([]byte("stale"))
+	ts.diskdb.WriteAccountSnapshot(stale, []byte{0x01})
+	ts.diskdb.WriteStorageSnapshot(stale, stale, []byte{0x02})
+
+	snap := generateSnapshot(ts.diskdb, ts.triedb, 16, ts.root)
+	<-snap.genPending
+	defer stopGeneration(snap)
+
+	assert.Nil(t, ts.diskdb.ReadAccountSnapshot(stale))
+	assert.Nil(t, ts.diskdb.ReadStorageSnapshot(stale, stale))
+
+	it := ts.diskdb.NewSnapshotDBIterator(database.SnapshotAccountPrefix, nil)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		if len(it.Key()) == accountSnapshotKeyLength {
+			count++
+		}
+	}
+	assert.Equal(t, len(ts.accounts), count)
+}
+
+// Tests that the snapshot generation of a missing state trie is paused instead
+// of producing a broken snapshot.
+func TestGenerationMissingTrie(t *testing.T) {
+	ts := newTestState(t)
+
+	missing := crypto.Keccak256Hash([]byte("missing"))
+	snap := generateSnapshot(ts.diskdb, ts.triedb, 16, missing)
+
+	abort := make(chan *generatorStats)
+	snap.genAbort <- abort
+	<-abort
+
+	// The generation should not be finished
+	select {
+	case <-snap.genPending:
+		t.Fatal("generation finished without the state trie")
+	default:
+	}
+	_, err := snap.AccountRLP(crypto.Keccak256Hash([]byte("alice")))
+	assert.Equal(t, ErrNotCoveredYet, err)
+}
@@ -0,0 +1,224 @@
+// Modifications Copyright 2020 The klaytn Authors
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file is derived from core/state/snapshot/iterator_binary.go (2020/12/14).
+// Modified and improved for the klaytn development.
+
+package snapshot
+
+import (
+	"bytes"
+
+	"github.com/klaytn/klaytn/common"
+)
+
+// binaryIterator is a simplistic iterator to step over the accounts or storage
+// in a snapshot, which may or may not be composed of multiple layers. Every
+// layer is merged with the ones below it pairwise, which is slow for deep
+// hierarchies but simple to verify.
+type binaryIterator struct {
+	a               Iterator
+	b               Iterator
+	aDone           bool
+	bDone           bool
+	accountIterator bool
+	layer           snapshot
+	k               common.Hash
+	account         common.Hash
+	value           []byte
+	fail            error
+}
+
+// emptyIterator is an iterator over nothing, used below a layer which destructed
+// the storage of an account.
+type emptyIterator struct{}
+
+func (it emptyIterator) Next() bool        { return false }
+func (it emptyIterator) Error() error      { return nil }
+func (it emptyIterator) Hash() common.Hash { return common.Hash{} }
+func (it emptyIterator) Release()          {}
+func (it emptyIterator) Slot() []byte      { return nil }
+
+// newLayerAccountIterator creates an account iterator over all the layers from
+// the given one downwards, hiding the deleted accounts.
+func newLayerAccountIterator(layer snapshot, seek common.Hash) AccountIterator {
+	return &binaryIterator{
+		a:               newBinaryAccountIterator(layer, seek),
+		accountIterator: true,
+		layer:           layer,
+	}
+}
+
+// newLayerStorageIterator creates a storage iterator over all the layers from
+// the given one downwards, hiding the deleted slots.
+func newLayerStorageIterator(layer snapshot, accountHash common.Hash, seek common.Hash) StorageIterator {
+	return &binaryIterator{
+		a:       newBinaryStorageIterator(layer, accountHash, seek),
+		layer:   layer,
+		account: accountHash,
+	}
+}
+
+// newBinaryAccountIterator creates a simplistic account iterator to step over
+// all the accounts in a slow, but easily verifiable way. Deleted accounts are
+// still reported.
+func newBinaryAccountIterator(layer snapshot, seek common.Hash) Iterator {
+	dl, ok := layer.(*diffLayer)
+	if !ok {
+		return layer.AccountIterator(seek)
+	}
+	l := &binaryIterator{
+		a:               dl.AccountIterator(seek),
+		b:               newBinaryAccountIterator(dl.Parent(), seek),
+		accountIterator: true,
+		layer:           dl,
+	}
+	l.aDone = !l.a.Next()
+	l.bDone = !l.b.Next()
+	return l
+}
+
+// newBinaryStorageIterator creates a simplistic storage iterator to step over
+// all the storage slots in a slow, but easily verifiable way. Deleted slots
+// are still reported.
+func newBinaryStorageIterator(layer snapshot, accountHash common.Hash, seek common.Hash) Iterator {
+	dl, ok := layer.(*diffLayer)
+	if !ok {
+		it, _ := layer.StorageIterator(accountHash, seek)
+		return it
+	}
+	// If the storage in this layer is already destructed, discard all
+	// deeper layers but still return an valid single-branch iterator.
+	a, destructed := dl.StorageIterator(accountHash, seek)
+	if destructed {
+		l := &binaryIterator{
+			a:       a,
+			b:       emptyIterator{},
+			account: accountHash,
+			layer:   dl,
+		}
+		l.aDone = !l.a.Next()
+		l.bDone = true
+		return l
+	}
+	l := &binaryIterator{
+		a:       a,
+		b:       newBinaryStorageIterator(dl.Parent(), accountHash, seek),
+		account: accountHash,
+		layer:   dl,
+	}
+	l.aDone = !l.a.Next()
+	l.bDone = !l.b.Next()
+	return l
+}
+
+// Next steps the iterator forward one element, returning false if exhausted,
+// or an error if iteration failed for some reason (e.g. root being iterated
+// becomes stale and garbage collected).
+func (it *binaryIterator) Next() bool {
+	// The topmost iterator of newLayerXXXIterator only wraps the merged one,
+	// resolving the values and skipping the deleted entries.
+	if it.b == nil {
+		for it.a.Next() {
+			it.k = it.a.Hash()
+			if it.accountIterator {
+				it.value, it.fail = it.layer.AccountRLP(it.k)
+			} else {
+				it.value, it.fail = it.layer.Storage(it.account, it.k)
+			}
+			if it.fail != nil {
+				return false
+			}
+			if len(it.value) > 0 {
+				return true
+			}
+		}
+		it.fail = it.a.Error()
+		return false
+	}
+	if it.aDone && it.bDone {
+		return false
+	}
+first:
+	if it.aDone {
+		it.k = it.b.Hash()
+		it.bDone = !it.b.Next()
+		return true
+	}
+	if it.bDone {
+		it.k = it.a.Hash()
+		it.aDone = !it.a.Next()
+		return true
+	}
+	nextA, nextB := it.a.Hash(), it.b.Hash()
+	if diff := bytes.Compare(nextA[:], nextB[:]); diff < 0 {
+		it.aDone = !it.a.Next()
+		it.k = nextA
+		return true
+	} else if diff == 0 {
+		// Now we need to advance one of them
+		it.aDone = !it.a.Next()
+		goto first
+	}
+	it.bDone = !it.b.Next()
+	it.k = nextB
+	return true
+}
+
+// Error returns any failure that occurred during iteration, which might have
+// caused a premature iteration exit (e.g. snapshot stack becoming stale).
+func (it *binaryIterator) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	if err := it.a.Error(); err != nil {
+		return err
+	}
+	if it.b != nil {
+		return it.b.Error()
+	}
+	return nil
+}
+
+// Hash returns the hash of the account the iterator is currently at.
+func (it *binaryIterator) Hash() common.Hash {
+	return it.k
+}
+
+// Account returns the RLP encoded account the iterator is currently at.
+func (it *binaryIterator) Account() []byte {
+	if !it.accountIterator {
+		return nil
+	}
+	return it.value
+}
+
+// Slot returns the raw storage slot data the iterator is currently at.
+func (it *binaryIterator) Slot() []byte {
+	if it.accountIterator {
+		return nil
+	}
+	return it.value
+}
+
+// Release recursively releases all the iterators in the stack.
+func (it *binaryIterator) Release() {
+	it.a.Release()
+	if it.b != nil {
+		it.b.Release()
+	}
+}
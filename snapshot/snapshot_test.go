@@ -0,0 +1,218 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testAccA = common.HexToHash("0xa1")
+	testAccB = common.HexToHash("0xb1")
+	testSlot = common.HexToHash("0x01")
+)
+
+// newTestTree creates a snapshot tree with a fully generated disk layer which
+// contains two accounts and a storage slot.
+func newTestTree() *Tree {
+	var (
+		diskdb = database.NewMemoryDBManager()
+		base   = common.HexToHash("0x01")
+	)
+	diskdb.WriteSnapshotRoot(base)
+	diskdb.WriteAccountSnapshot(testAccA, []byte("a-0"))
+	diskdb.WriteAccountSnapshot(testAccB, []byte("b-0"))
+	diskdb.WriteStorageSnapshot(testAccA, testSlot, []byte("s-0"))
+
+	blob, _ := rlp.EncodeToBytes(journalGenerator{Done: true})
+	diskdb.WriteSnapshotGenerator(blob)
+
+	return &Tree{
+		diskdb: diskdb,
+		triedb: statedb.NewDatabase(diskdb),
+		cache:  16,
+		layers: map[common.Hash]snapshot{
+			base: &diskLayer{
+				diskdb: diskdb,
+				root:   base,
+				cache:  fastcache.New(16 * 1024 * 1024),
+			},
+		},
+	}
+}
+
+// updateTestTree stacks the given number of diff layers on top of the disk
+// layer, each updating the account A and the storage slot.
+func updateTestTree(t *testing.T, tree *Tree, layers int) []common.Hash {
+	roots := []common.Hash{tree.DiskRoot()}
+	for i := 1; i <= layers; i++ {
+		root := common.BytesToHash([]byte{0x10, byte(i)})
+		accounts := map[common.Hash][]byte{testAccA: []byte{'a', '-', byte('0' + i)}}
+		storage := map[common.Hash]map[common.Hash][]byte{testAccA: {testSlot: []byte{'s', '-', byte('0' + i)}}}
+		if err := tree.Update(root, roots[len(roots)-1], nil, accounts, storage); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// Tests that the reads on a diff layer fall through to the parent layers.
+func TestDiffLayerAccess(t *testing.T) {
+	tree := newTestTree()
+	roots := updateTestTree(t, tree, 2)
+
+	head := tree.Snapshot(roots[2])
+	data, err := head.AccountRLP(testAccA)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("a-2"), data)
+
+	data, err = head.AccountRLP(testAccB)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("b-0"), data)
+
+	data, err = tree.Snapshot(roots[1]).Storage(testAccA, testSlot)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("s-1"), data)
+
+	// Destructing an account hides its storage of the lower layers
+	root := common.HexToHash("0x20")
+	destructs := map[common.Hash]struct{}{testAccA: {}}
+	assert.NoError(t, tree.Update(root, roots[2], destructs, nil, nil))
+
+	data, err = tree.Snapshot(root).AccountRLP(testAccA)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	data, err = tree.Snapshot(root).Storage(testAccA, testSlot)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	// Self-referencing layers are rejected
+	assert.Equal(t, errSnapshotCycle, tree.Update(root, root, nil, nil, nil))
+}
+
+// Tests that capping the tree flattens the bottom layers into the disk layer
+// and marks the flattened layers stale.
+func TestCapFlattensToDisk(t *testing.T) {
+	tree := newTestTree()
+	roots := updateTestTree(t, tree, 3)
+
+	bottom := tree.Snapshot(roots[1])
+	assert.NoError(t, tree.Cap(roots[3], 0))
+
+	assert.Equal(t, roots[3], tree.DiskRoot())
+	assert.Equal(t, roots[3], tree.diskdb.ReadSnapshotRoot())
+	assert.Equal(t, []byte("a-3"), tree.diskdb.ReadAccountSnapshot(testAccA))
+	assert.Equal(t, []byte("s-3"), tree.diskdb.ReadStorageSnapshot(testAccA, testSlot))
+	assert.Len(t, tree.layers, 1)
+
+	_, err := bottom.AccountRLP(testAccA)
+	assert.Equal(t, ErrSnapshotStale, err)
+
+	// A disk layer can't be capped anymore
+	assert.Error(t, tree.Cap(roots[3], 0))
+}
+
+// Tests that the diff layers are kept in the memory until the memory limit of
+// the accumulator layer is reached.
+func TestCapKeepsLayers(t *testing.T) {
+	tree := newTestTree()
+	roots := updateTestTree(t, tree, 4)
+
+	assert.NoError(t, tree.Cap(roots[4], 2))
+
+	// Two diff layers and the accumulator layer should be left on the disk layer
+	assert.Equal(t, roots[0], tree.DiskRoot())
+	assert.Len(t, tree.layers, 4)
+	assert.Nil(t, tree.Snapshot(roots[1]))
+
+	data, err := tree.Snapshot(roots[2]).AccountRLP(testAccA)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("a-2"), data)
+}
+
+// Tests that the diff layers survive a restart through the journal.
+func TestJournalRoundTrip(t *testing.T) {
+	tree := newTestTree()
+	roots := updateTestTree(t, tree, 3)
+
+	base, err := tree.Journal(roots[3])
+	assert.NoError(t, err)
+	assert.Equal(t, roots[0], base)
+
+	loaded, err := New(tree.diskdb, tree.triedb, 16, roots[3], false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, loaded.layers, 4)
+	for i := 1; i <= 3; i++ {
+		data, err := loaded.Snapshot(roots[i]).Storage(testAccA, testSlot)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{'s', '-', byte('0' + i)}, data)
+	}
+	// Loading with a mismatched head should fail without rebuilding
+	_, err = New(tree.diskdb, tree.triedb, 16, roots[2], false, false)
+	assert.Error(t, err)
+}
+
+// Tests that iterators merge all the layers hiding the deleted entries.
+func TestAccountIterator(t *testing.T) {
+	tree := newTestTree()
+	roots := updateTestTree(t, tree, 2)
+
+	root := common.HexToHash("0x20")
+	destructs := map[common.Hash]struct{}{testAccB: {}}
+	accounts := map[common.Hash][]byte{common.HexToHash("0xc1"): []byte("c-3")}
+	assert.NoError(t, tree.Update(root, roots[2], destructs, accounts, nil))
+
+	it, err := tree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Release()
+
+	var (
+		hashes []common.Hash
+		blobs  []string
+	)
+	for it.Next() {
+		hashes = append(hashes, it.Hash())
+		blobs = append(blobs, string(it.Account()))
+	}
+	assert.NoError(t, it.Error())
+	assert.Equal(t, []common.Hash{testAccA, common.HexToHash("0xc1")}, hashes)
+	assert.Equal(t, []string{"a-2", "c-3"}, blobs)
+
+	sit, err := tree.StorageIterator(root, testAccA, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sit.Release()
+
+	assert.True(t, sit.Next())
+	assert.Equal(t, testSlot, sit.Hash())
+	assert.Equal(t, []byte("s-2"), sit.Slot())
+	assert.False(t, sit.Next())
+}
@@ -275,3 +275,30 @@ func TestDBEntryLengthCheck(t *testing.T) {
 		t.Fatalf("Sum of database configuration ratio should be 100! actual: %v", dbRatioSum)
 	}
 }
+
+// TestShardedDB_Iterator checks if the iterator of a sharded database returns
+// the entries of all shards in binary-alphabetical order.
+func TestShardedDB_Iterator(t *testing.T) {
+	db, err := newShardedDB(&DBConfig{DBType: MemoryDB}, StateTrieDB, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The first byte of a key decides its shard.
+	keys := []string{"\x04d", "\x01a", "\x03c", "\x02b", "\x05e", "\x06f", "\x07g"}
+	for _, k := range keys {
+		assert.NoError(t, db.Put([]byte(k), []byte("v"+k)))
+	}
+
+	it := db.NewIterator(nil, []byte("\x02"))
+	defer it.Release()
+
+	var found []string
+	for it.Next() {
+		assert.Equal(t, "v"+string(it.Key()), string(it.Value()))
+		found = append(found, string(it.Key()))
+	}
+	assert.NoError(t, it.Error())
+	assert.Equal(t, []string{"\x02b", "\x03c", "\x04d", "\x05e", "\x06f", "\x07g"}, found)
+}
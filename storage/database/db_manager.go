@@ -117,6 +117,17 @@ type DBManager interface {
 	ReadIstanbulSnapshot(hash common.Hash) ([]byte, error)
 	WriteIstanbulSnapshot(hash common.Hash, blob []byte) error
 
+	// Validator stats related functions
+	WriteValidatorStats(start uint64, b []byte) error
+	ReadValidatorStats(start uint64) ([]byte, error)
+	ReadValidatorStatsRange(from, to uint64) [][]byte
+	WriteValidatorStatsHead(num uint64) error
+	ReadValidatorStatsHead() (uint64, error)
+
+	// Reward detail related functions
+	WriteRewardDetail(hash common.Hash, b []byte) error
+	ReadRewardDetail(hash common.Hash) ([]byte, error)
+
 	WriteMerkleProof(key, value []byte)
 
 	// State Trie Database related operations
@@ -165,6 +176,15 @@ type DBManager interface {
 	WriteSectionHead(encodedSection []byte, hash common.Hash)
 	DeleteSectionHead(encodedSection []byte)
 
+	ReadLogIndex(logIndexKey []byte) ([]byte, error)
+
+	ReadLogIndexValidSections() ([]byte, error)
+	WriteLogIndexValidSections(encodedSections []byte)
+
+	ReadLogIndexSectionHead(encodedSection []byte) ([]byte, error)
+	WriteLogIndexSectionHead(encodedSection []byte, hash common.Hash)
+	DeleteLogIndexSectionHead(encodedSection []byte)
+
 	// from accessors_metadata.go
 	ReadDatabaseVersion() *uint64
 	WriteDatabaseVersion(version uint64)
@@ -213,11 +233,39 @@ type DBManager interface {
 	ReadGovernanceAtNumber(num uint64, epoch uint64) (uint64, map[string]interface{}, error)
 	WriteGovernanceState(b []byte) error
 	ReadGovernanceState() ([]byte, error)
+	WriteGovernanceProposal(id uint64, b []byte) error
+	ReadGovernanceProposal(id uint64) ([]byte, error)
+	ReadGovernanceProposals() [][]byte
+	WriteGovernanceVoteHistory(num uint64, b []byte) error
+	ReadGovernanceVoteHistory(from, to uint64) [][]byte
 
 	// StakingInfo related functions
 	ReadStakingInfo(blockNum uint64) ([]byte, error)
 	WriteStakingInfo(blockNum uint64, stakingInfo []byte) error
 
+	// Snapshot related functions
+	ReadSnapshotRoot() common.Hash
+	WriteSnapshotRoot(root common.Hash)
+	DeleteSnapshotRoot()
+	ReadSnapshotJournal() []byte
+	WriteSnapshotJournal(journal []byte)
+	DeleteSnapshotJournal()
+	ReadSnapshotGenerator() []byte
+	WriteSnapshotGenerator(generator []byte)
+	DeleteSnapshotGenerator()
+	ReadAccountSnapshot(hash common.Hash) []byte
+	WriteAccountSnapshot(hash common.Hash, entry []byte)
+	DeleteAccountSnapshot(hash common.Hash)
+	ReadStorageSnapshot(accountHash, storageHash common.Hash) []byte
+	WriteStorageSnapshot(accountHash, storageHash common.Hash, entry []byte)
+	DeleteStorageSnapshot(accountHash, storageHash common.Hash)
+	NewSnapshotDBIterator(prefix []byte, start []byte) Iterator
+
+	// State pruning related functions
+	ReadStatePruningProgress() []byte
+	WriteStatePruningProgress(progress []byte)
+	NewStateTrieDBIterator(start []byte) Iterator
+
 	// DB migration related function
 	StartDBMigration(DBManager) error
 
@@ -237,6 +285,7 @@ const (
 	StateTrieMigrationDB
 	TxLookUpEntryDB
 	bridgeServiceDB
+	SnapshotDB
 	// databaseEntryTypeSize should be the last item in this list!!
 	databaseEntryTypeSize
 )
@@ -257,6 +306,7 @@ var dbBaseDirs = [databaseEntryTypeSize]string{
 	"statetrie_migrated", // "statetrie_migrated_#N" path will be used. (#N is a migrated block number.)
 	"txlookup",
 	"bridgeservice",
+	"snapshot",
 }
 
 // Sum of dbConfigRatio should be 100.
@@ -266,10 +316,11 @@ var dbConfigRatio = [databaseEntryTypeSize]int{
 	5,  // headerDB
 	5,  // BodyDB
 	5,  // ReceiptsDB
-	40, // StateTrieDB
-	40, // StateTrieMigrationDB
+	37, // StateTrieDB
+	37, // StateTrieMigrationDB
 	2,  // TXLookUpEntryDB
 	1,  // bridgeServiceDB
+	6,  // SnapshotDB
 }
 
 // checkDBEntryConfigRatio checks if sum of dbConfigRatio is 100.
@@ -1409,6 +1460,76 @@ func (dbm *databaseManager) WriteIstanbulSnapshot(hash common.Hash, blob []byte)
 	return db.Put(snapshotKey(hash), blob)
 }
 
+// WriteRewardDetail stores the serialized reward details of the block of the given hash.
+func (dbm *databaseManager) WriteRewardDetail(hash common.Hash, b []byte) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(rewardDetailKey(hash), b)
+}
+
+// ReadRewardDetail retrieves the serialized reward details of the block of the given hash.
+func (dbm *databaseManager) ReadRewardDetail(hash common.Hash) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(rewardDetailKey(hash))
+}
+
+// WriteValidatorStats stores the serialized validator stats of the epoch starting at the given block number.
+func (dbm *databaseManager) WriteValidatorStats(start uint64, b []byte) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(validatorStatsKey(start), b)
+}
+
+// ReadValidatorStats retrieves the serialized validator stats of the epoch starting at the given block number.
+func (dbm *databaseManager) ReadValidatorStats(start uint64) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(validatorStatsKey(start))
+}
+
+// ReadValidatorStatsRange retrieves the serialized validator stats of the epochs
+// starting from `from` to `to`, both inclusive, in ascending order of block numbers.
+func (dbm *databaseManager) ReadValidatorStatsRange(from, to uint64) [][]byte {
+	db := dbm.getDatabase(MiscDB)
+	it := db.NewIterator(validatorStatsPrefix, common.Int64ToByteBigEndian(from))
+	defer it.Release()
+
+	var stats [][]byte
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(validatorStatsPrefix)+8 {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(validatorStatsPrefix):]) > to {
+			break
+		}
+		stats = append(stats, common.CopyBytes(it.Value()))
+	}
+	return stats
+}
+
+// WriteValidatorStatsHead stores the number of the last block counted in the validator stats.
+func (dbm *databaseManager) WriteValidatorStatsHead(num uint64) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(validatorStatsHeadKey, common.Int64ToByteBigEndian(num))
+}
+
+// ReadValidatorStatsHead retrieves the number of the last block counted in the validator stats.
+// 0 is returned if no block has been counted.
+func (dbm *databaseManager) ReadValidatorStatsHead() (uint64, error) {
+	db := dbm.getDatabase(MiscDB)
+	data, err := db.Get(validatorStatsHeadKey)
+	if err != nil {
+		if err == leveldb.ErrNotFound || err == badger.ErrKeyNotFound ||
+			strings.Contains(err.Error(), "not found") { // memoryDB
+			return 0, nil
+		}
+		return 0, err
+	}
+	if len(data) != 8 {
+		logger.Warn("the returned error is nil, but the data is wrong", "len(data)", len(data))
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
 // Merkle Proof operation.
 func (dbm *databaseManager) WriteMerkleProof(key, value []byte) {
 	db := dbm.getDatabase(MiscDB)
@@ -1733,6 +1854,44 @@ func (dbm *databaseManager) DeleteSectionHead(encodedSection []byte) {
 	db.Delete(sectionHeadKey(encodedSection))
 }
 
+// LogIndex operations.
+// ReadLogIndex retrieves the compressed bitset of the blocks containing the logs
+// of an address or a topic in the given log index section.
+func (dbm *databaseManager) ReadLogIndex(logIndexKey []byte) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(logIndexKey)
+}
+
+// ReadLogIndexValidSections retrieves the number of valid sections of the log index.
+func (dbm *databaseManager) ReadLogIndexValidSections() ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(logIndexValidSectionKey)
+}
+
+// WriteLogIndexValidSections stores the number of valid sections of the log index.
+func (dbm *databaseManager) WriteLogIndexValidSections(encodedSections []byte) {
+	db := dbm.getDatabase(MiscDB)
+	db.Put(logIndexValidSectionKey, encodedSections)
+}
+
+// ReadLogIndexSectionHead retrieves the last block hash of a log index section.
+func (dbm *databaseManager) ReadLogIndexSectionHead(encodedSection []byte) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(logIndexSectionHeadKey(encodedSection))
+}
+
+// WriteLogIndexSectionHead stores the last block hash of a log index section.
+func (dbm *databaseManager) WriteLogIndexSectionHead(encodedSection []byte, hash common.Hash) {
+	db := dbm.getDatabase(MiscDB)
+	db.Put(logIndexSectionHeadKey(encodedSection), hash.Bytes())
+}
+
+// DeleteLogIndexSectionHead removes the last block hash of a log index section.
+func (dbm *databaseManager) DeleteLogIndexSectionHead(encodedSection []byte) {
+	db := dbm.getDatabase(MiscDB)
+	db.Delete(logIndexSectionHeadKey(encodedSection))
+}
+
 // ReadDatabaseVersion retrieves the version number of the database.
 func (dbm *databaseManager) ReadDatabaseVersion() *uint64 {
 	db := dbm.getDatabase(MiscDB)
@@ -2078,6 +2237,61 @@ func (dbm *databaseManager) ReadGovernanceState() ([]byte, error) {
 	return db.Get(governanceStateKey)
 }
 
+// WriteGovernanceProposal stores a serialized governance proposal of the given id.
+func (dbm *databaseManager) WriteGovernanceProposal(id uint64, b []byte) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(governanceProposalKey(id), b)
+}
+
+// ReadGovernanceProposal retrieves a serialized governance proposal of the given id.
+func (dbm *databaseManager) ReadGovernanceProposal(id uint64) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(governanceProposalKey(id))
+}
+
+// ReadGovernanceProposals retrieves all serialized governance proposals in ascending order of their ids.
+func (dbm *databaseManager) ReadGovernanceProposals() [][]byte {
+	db := dbm.getDatabase(MiscDB)
+	it := db.NewIterator(governanceProposalPrefix, nil)
+	defer it.Release()
+
+	var proposals [][]byte
+	for it.Next() {
+		if len(it.Key()) != len(governanceProposalPrefix)+8 {
+			continue
+		}
+		proposals = append(proposals, common.CopyBytes(it.Value()))
+	}
+	return proposals
+}
+
+// WriteGovernanceVoteHistory stores a serialized governance vote included in the block of the given number.
+func (dbm *databaseManager) WriteGovernanceVoteHistory(num uint64, b []byte) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(governanceVoteHistoryKey(num), b)
+}
+
+// ReadGovernanceVoteHistory retrieves the serialized governance votes included
+// in the blocks from `from` to `to`, both inclusive, in ascending order of block numbers.
+func (dbm *databaseManager) ReadGovernanceVoteHistory(from, to uint64) [][]byte {
+	db := dbm.getDatabase(MiscDB)
+	it := db.NewIterator(governanceVoteHistoryPrefix, common.Int64ToByteBigEndian(from))
+	defer it.Release()
+
+	var votes [][]byte
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(governanceVoteHistoryPrefix)+8 {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(governanceVoteHistoryPrefix):]) > to {
+			break
+		}
+		votes = append(votes, common.CopyBytes(it.Value()))
+	}
+	return votes
+}
+
 func (dbm *databaseManager) WriteChainDataFetcherCheckpoint(checkpoint uint64) error {
 	db := dbm.getDatabase(MiscDB)
 	return db.Put(chaindatafetcherCheckpointKey, common.Int64ToByteBigEndian(checkpoint))
@@ -0,0 +1,43 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+// ReadStatePruningProgress retrieves the serialized progress of the state
+// pruning. Nil is returned if the state has never been pruned.
+func (dbm *databaseManager) ReadStatePruningProgress() []byte {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(StatePruningProgressKey)
+	return data
+}
+
+// WriteStatePruningProgress stores the serialized progress of the state pruning
+// to resume it after a restart.
+func (dbm *databaseManager) WriteStatePruningProgress(progress []byte) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(StatePruningProgressKey, progress); err != nil {
+		logger.Crit("Failed to store state pruning progress", "err", err)
+	}
+}
+
+// NewStateTrieDBIterator returns an iterator over the state trie database
+// starting at the given key.
+func (dbm *databaseManager) NewStateTrieDBIterator(start []byte) Iterator {
+	dbm.lockInMigration.RLock()
+	defer dbm.lockInMigration.RUnlock()
+
+	return dbm.getDatabase(StateTrieDB).NewIterator(nil, start)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import "github.com/klaytn/klaytn/common"
+
+// ReadSnapshotRoot retrieves the root of the block whose state is contained in
+// the persisted snapshot. An empty hash is returned if there is no snapshot.
+func (dbm *databaseManager) ReadSnapshotRoot() common.Hash {
+	db := dbm.getDatabase(SnapshotDB)
+	data, _ := db.Get(SnapshotRootKey)
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteSnapshotRoot stores the root of the block whose state is contained in
+// the persisted snapshot.
+func (dbm *databaseManager) WriteSnapshotRoot(root common.Hash) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Put(SnapshotRootKey, root[:]); err != nil {
+		logger.Crit("Failed to store snapshot root", "err", err)
+	}
+}
+
+// DeleteSnapshotRoot deletes the hash of the block whose state is contained in
+// the persisted snapshot. Since snapshots are not immutable, this method can
+// be used during updates, so a crash or failure will mark the entire snapshot
+// invalid.
+func (dbm *databaseManager) DeleteSnapshotRoot() {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(SnapshotRootKey); err != nil {
+		logger.Crit("Failed to remove snapshot root", "err", err)
+	}
+}
+
+// ReadSnapshotJournal retrieves the serialized in-memory diff layers saved at
+// the last shutdown. The blob is expected to be max a few 10s of megabytes.
+func (dbm *databaseManager) ReadSnapshotJournal() []byte {
+	db := dbm.getDatabase(SnapshotDB)
+	data, _ := db.Get(SnapshotJournalKey)
+	return data
+}
+
+// WriteSnapshotJournal stores the serialized in-memory diff layers to save at
+// shutdown. The blob is expected to be max a few 10s of megabytes.
+func (dbm *databaseManager) WriteSnapshotJournal(journal []byte) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Put(SnapshotJournalKey, journal); err != nil {
+		logger.Crit("Failed to store snapshot journal", "err", err)
+	}
+}
+
+// DeleteSnapshotJournal deletes the serialized in-memory diff layers saved at
+// the last shutdown.
+func (dbm *databaseManager) DeleteSnapshotJournal() {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(SnapshotJournalKey); err != nil {
+		logger.Crit("Failed to remove snapshot journal", "err", err)
+	}
+}
+
+// ReadSnapshotGenerator retrieves the serialized snapshot generator saved at
+// the last shutdown.
+func (dbm *databaseManager) ReadSnapshotGenerator() []byte {
+	db := dbm.getDatabase(SnapshotDB)
+	data, _ := db.Get(SnapshotGeneratorKey)
+	return data
+}
+
+// WriteSnapshotGenerator stores the serialized snapshot generator to save at
+// shutdown.
+func (dbm *databaseManager) WriteSnapshotGenerator(generator []byte) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Put(SnapshotGeneratorKey, generator); err != nil {
+		logger.Crit("Failed to store snapshot generator", "err", err)
+	}
+}
+
+// DeleteSnapshotGenerator deletes the serialized snapshot generator saved at
+// the last shutdown.
+func (dbm *databaseManager) DeleteSnapshotGenerator() {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(SnapshotGeneratorKey); err != nil {
+		logger.Crit("Failed to remove snapshot generator", "err", err)
+	}
+}
+
+// ReadAccountSnapshot retrieves the snapshot entry of an account trie leaf.
+func (dbm *databaseManager) ReadAccountSnapshot(hash common.Hash) []byte {
+	db := dbm.getDatabase(SnapshotDB)
+	data, _ := db.Get(AccountSnapshotKey(hash))
+	return data
+}
+
+// WriteAccountSnapshot stores the snapshot entry of an account trie leaf.
+func (dbm *databaseManager) WriteAccountSnapshot(hash common.Hash, entry []byte) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Put(AccountSnapshotKey(hash), entry); err != nil {
+		logger.Crit("Failed to store account snapshot", "err", err)
+	}
+}
+
+// DeleteAccountSnapshot removes the snapshot entry of an account trie leaf.
+func (dbm *databaseManager) DeleteAccountSnapshot(hash common.Hash) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(AccountSnapshotKey(hash)); err != nil {
+		logger.Crit("Failed to delete account snapshot", "err", err)
+	}
+}
+
+// ReadStorageSnapshot retrieves the snapshot entry of a storage trie leaf.
+func (dbm *databaseManager) ReadStorageSnapshot(accountHash, storageHash common.Hash) []byte {
+	db := dbm.getDatabase(SnapshotDB)
+	data, _ := db.Get(StorageSnapshotKey(accountHash, storageHash))
+	return data
+}
+
+// WriteStorageSnapshot stores the snapshot entry of a storage trie leaf.
+func (dbm *databaseManager) WriteStorageSnapshot(accountHash, storageHash common.Hash, entry []byte) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Put(StorageSnapshotKey(accountHash, storageHash), entry); err != nil {
+		logger.Crit("Failed to store storage snapshot", "err", err)
+	}
+}
+
+// DeleteStorageSnapshot removes the snapshot entry of a storage trie leaf.
+func (dbm *databaseManager) DeleteStorageSnapshot(accountHash, storageHash common.Hash) {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(StorageSnapshotKey(accountHash, storageHash)); err != nil {
+		logger.Crit("Failed to delete storage snapshot", "err", err)
+	}
+}
+
+// NewSnapshotDBIterator returns an iterator over the snapshot database
+// restricted to the given prefix and starting at the given key.
+func (dbm *databaseManager) NewSnapshotDBIterator(prefix []byte, start []byte) Iterator {
+	return dbm.getDatabase(SnapshotDB).NewIterator(prefix, start)
+}
@@ -46,6 +46,8 @@ var (
 
 	validSectionKey = []byte("count")
 
+	logIndexValidSectionKey = append(append([]byte{}, LogIndexIndexPrefix...), validSectionKey...)
+
 	sectionHeadKeyPrefix = []byte("shead")
 
 	snapshotKeyPrefix = []byte("snapshot")
@@ -66,6 +68,7 @@ var (
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	LogIndexIndexPrefix  = []byte("iL") // LogIndexIndexPrefix is the data table of the log index chain indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -83,18 +86,42 @@ var (
 	// bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 	bloomBitsPrefix = []byte("B")
 
+	// logIndexPrefix + section (uint64 big endian) + hash + address or topic -> compressed bitset of the blocks
+	logIndexPrefix = []byte("logIndex")
+
 	senderTxHashToTxHashPrefix = []byte("SenderTxHash")
 
 	governancePrefix     = []byte("governance")
 	governanceHistoryKey = []byte("governanceIdxHistory")
 	governanceStateKey   = []byte("governanceState")
 
+	governanceProposalPrefix    = []byte("governanceProposal")
+	governanceVoteHistoryPrefix = []byte("governanceVoteHistory")
+
 	databaseDirPrefix  = []byte("databaseDirectory")
 	migrationStatusKey = []byte("migrationStatus")
 
 	stakingInfoPrefix = []byte("stakingInfo")
 
+	validatorStatsPrefix  = []byte("validatorStats")
+	validatorStatsHeadKey = []byte("lastValidatorStatsBlock")
+
+	rewardDetailPrefix = []byte("rewardDetail")
+
 	chaindatafetcherCheckpointKey = []byte("chaindatafetcherCheckpoint")
+
+	// SnapshotRootKey tracks the hash of the last flat state snapshot persisted on disk.
+	SnapshotRootKey = []byte("SnapshotRoot")
+	// SnapshotJournalKey tracks the in-memory diff layers across restarts.
+	SnapshotJournalKey = []byte("SnapshotJournal")
+	// SnapshotGeneratorKey tracks the snapshot generation marker across restarts.
+	SnapshotGeneratorKey = []byte("SnapshotGenerator")
+
+	// StatePruningProgressKey tracks the progress of the state pruning across restarts.
+	StatePruningProgressKey = []byte("StatePruningProgress")
+
+	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
+	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
 )
 
 // TxLookupEntry is a positional metadata to help looking up the data content of
@@ -162,6 +189,21 @@ func snapshotKey(hash common.Hash) []byte {
 	return append(snapshotKeyPrefix, hash[:]...)
 }
 
+// AccountSnapshotKey = SnapshotAccountPrefix + hash
+func AccountSnapshotKey(hash common.Hash) []byte {
+	return append(append([]byte{}, SnapshotAccountPrefix...), hash[:]...)
+}
+
+// StorageSnapshotKey = SnapshotStoragePrefix + account hash + storage hash
+func StorageSnapshotKey(accountHash, storageHash common.Hash) []byte {
+	return append(append(append([]byte{}, SnapshotStoragePrefix...), accountHash[:]...), storageHash[:]...)
+}
+
+// StorageSnapshotsKey = SnapshotStoragePrefix + account hash
+func StorageSnapshotsKey(accountHash common.Hash) []byte {
+	return append(append([]byte{}, SnapshotStoragePrefix...), accountHash[:]...)
+}
+
 func childChainTxHashKey(ccBlockHash common.Hash) []byte {
 	return append(append(childChainTxHashPrefix, ccBlockHash.Bytes()...))
 }
@@ -184,11 +226,45 @@ func BloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// LogIndexKey returns the key of the log index of an address or a topic in a section.
+// LogIndexKey = logIndexPrefix + section (uint64 big endian) + hash + address or topic
+func LogIndexKey(key []byte, section uint64, hash common.Hash) []byte {
+	encodedSection := make([]byte, 8)
+	binary.BigEndian.PutUint64(encodedSection, section)
+
+	return append(append(append(append([]byte{}, logIndexPrefix...), encodedSection...), hash.Bytes()...), key...)
+}
+
+// logIndexSectionHeadKey = LogIndexIndexPrefix + sectionHeadKeyPrefix + section
+func logIndexSectionHeadKey(encodedSection []byte) []byte {
+	return append(append(append([]byte{}, LogIndexIndexPrefix...), sectionHeadKeyPrefix...), encodedSection...)
+}
+
 func makeKey(prefix []byte, num uint64) []byte {
 	byteKey := common.Int64ToByteLittleEndian(num)
 	return append(prefix, byteKey...)
 }
 
+// governanceProposalKey = governanceProposalPrefix + id (uint64 big endian)
+func governanceProposalKey(id uint64) []byte {
+	return append(governanceProposalPrefix, common.Int64ToByteBigEndian(id)...)
+}
+
+// governanceVoteHistoryKey = governanceVoteHistoryPrefix + num (uint64 big endian)
+func governanceVoteHistoryKey(num uint64) []byte {
+	return append(governanceVoteHistoryPrefix, common.Int64ToByteBigEndian(num)...)
+}
+
+// validatorStatsKey = validatorStatsPrefix + epoch start block number (uint64 big endian)
+func validatorStatsKey(start uint64) []byte {
+	return append(validatorStatsPrefix, common.Int64ToByteBigEndian(start)...)
+}
+
+// rewardDetailKey = rewardDetailPrefix + hash
+func rewardDetailKey(hash common.Hash) []byte {
+	return append(rewardDetailPrefix, hash.Bytes()...)
+}
+
 func databaseDirKey(dbEntryType uint64) []byte {
 	return append(databaseDirPrefix, common.Int64ToByteBigEndian(dbEntryType)...)
 }
@@ -17,9 +17,12 @@
 package database
 
 import (
+	"bytes"
 	"fmt"
 	"path"
 	"strconv"
+
+	"github.com/klaytn/klaytn/common"
 )
 
 var errKeyLengthZero = fmt.Errorf("database key for sharded database should be greater than 0")
@@ -155,75 +158,75 @@ func (db *shardedDB) Close() {
 	}
 }
 
+// shardedDBIterator merges the iterators of all shards into a single
+// binary-alphabetical iterator.
 type shardedDBIterator struct {
-	// TODO-Klaytn implement this later.
 	iterators []Iterator
+	hasNext   []bool // hasNext[i] is true if iterators[i] points to an unconsumed entry.
 	key       []byte
 	value     []byte
-
-	//numBatches uint
-	//
-	//taskCh   chan pdbBatchTask
-	//resultCh chan pdbBatchResult
 }
 
 // NewIterator creates a binary-alphabetical iterator over a subset
 // of database content with a particular key prefix, starting at a particular
 // initial key (or after, if it does not exist).
 func (pdb *shardedDB) NewIterator(prefix []byte, start []byte) Iterator {
-	// TODO-Klaytn implement this later.
-	return nil
+	it := &shardedDBIterator{
+		iterators: make([]Iterator, pdb.numShards),
+		hasNext:   make([]bool, pdb.numShards),
+	}
+	for i, shard := range pdb.shards {
+		it.iterators[i] = shard.NewIterator(prefix, start)
+		it.hasNext[i] = it.iterators[i].Next()
+	}
+	return it
 }
 
+// Next moves the iterator to the smallest key among the shard iterators.
 func (pdi *shardedDBIterator) Next() bool {
-	// TODO-Klaytn implement this later.
-	//var minIter Iterator
-	//minIdx := -1
-	//minKey := []byte{0}
-	//minKeyValue := []byte{0}
-	//
-	//for idx, iter := range pdi.iterators {
-	//	if iter != nil {
-	//		if bytes.Compare(minKey, iter.Key()) >= 0 {
-	//			minIdx = idx
-	//			minIter = iter
-	//			minKey = iter.Key()
-	//			minKeyValue = iter.Value()
-	//		}
-	//	}
-	//}
-	//
-	//if minIter == nil {
-	//	return false
-	//}
-	//
-	//pdi.key = minKey
-	//pdi.value = minKeyValue
-	//
-	//if !minIter.Next() {
-	//	pdi.iterators[minIdx] = nil
-	//}
-	//
+	minIdx := -1
+	for idx, iter := range pdi.iterators {
+		if !pdi.hasNext[idx] {
+			continue
+		}
+		if minIdx == -1 || bytes.Compare(iter.Key(), pdi.iterators[minIdx].Key()) < 0 {
+			minIdx = idx
+		}
+	}
+	if minIdx == -1 {
+		pdi.key, pdi.value = nil, nil
+		return false
+	}
+
+	minIter := pdi.iterators[minIdx]
+	pdi.key = common.CopyBytes(minIter.Key())
+	pdi.value = common.CopyBytes(minIter.Value())
+	pdi.hasNext[minIdx] = minIter.Next()
 	return true
 }
 
+// Error returns the first error of the shard iterators.
 func (pdi *shardedDBIterator) Error() error {
-	// TODO-Klaytn implement this later.
+	for _, iter := range pdi.iterators {
+		if err := iter.Error(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (pdi *shardedDBIterator) Key() []byte {
-	// TODO-Klaytn implement this later.
-	return nil
+	return pdi.key
 }
 
 func (pdi *shardedDBIterator) Value() []byte {
-	// TODO-Klaytn implement this later.
-	return nil
+	return pdi.value
 }
 
 func (pdi *shardedDBIterator) Release() {
-	// TODO-Klaytn implement this later.
+	for _, iter := range pdi.iterators {
+		iter.Release()
+	}
 }
 
 func (db *shardedDB) NewBatch() Batch {
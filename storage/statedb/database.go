@@ -118,6 +118,8 @@ type Database struct {
 	trieNodeCache                TrieNodeCache        // GC friendly memory cache of trie node RLPs
 	trieNodeCacheConfig          *TrieNodeCacheConfig // Configuration of trieNodeCache
 	savingTrieNodeCacheTriggered bool                 // Whether saving trie node cache has been triggered or not
+
+	pruningMarker func(hash common.Hash) // Marks the nodes flushed to disk while the state is being pruned
 }
 
 // rawNode is a simple binary blob used to differentiate between collapsed trie
@@ -771,6 +773,9 @@ func (db *Database) Cap(limit common.StorageSize) error {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.nodes[oldest]
 		enc := node.rlp()
+		if db.pruningMarker != nil {
+			db.pruningMarker(oldest)
+		}
 		if err := database.PutAndWriteBatchesOverThreshold(batch, oldest[:], enc); err != nil {
 			db.lock.RUnlock()
 			return err
@@ -894,6 +899,9 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 			continue
 		}
 
+		if db.pruningMarker != nil {
+			db.pruningMarker(common.BytesToHash(result.key))
+		}
 		if err := batch.Put(result.key, result.val); err != nil {
 			return err
 		}
@@ -906,6 +914,9 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 	}
 
 	enc := rootNode.rlp()
+	if db.pruningMarker != nil {
+		db.pruningMarker(node)
+	}
 	if err := batch.Put(node[:], enc); err != nil {
 		return err
 	}
@@ -1021,6 +1032,16 @@ func (db *Database) uncache(hash common.Hash) {
 	db.nodesSize -= common.StorageSize(common.HashLength + int(node.size))
 }
 
+// SetPruningMarker sets the function called with the hash of every node flushed
+// to disk. The state pruner uses it to keep the nodes written while the stale
+// nodes are being deleted. A nil marker unsets the previous one.
+func (db *Database) SetPruningMarker(marker func(hash common.Hash)) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.pruningMarker = marker
+}
+
 // Size returns the current database size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (common.StorageSize, common.StorageSize) {
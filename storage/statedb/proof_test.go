@@ -24,6 +24,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	mrand "math/rand"
+	"sort"
 	"testing"
 	"time"
 
@@ -94,6 +95,164 @@ func TestVerifyBadProof(t *testing.T) {
 	}
 }
 
+// sortedEntries returns the key-value pairs of the trie sorted by the key.
+func sortedEntries(vals map[string]*kv) []*kv {
+	entries := make([]*kv, 0, len(vals))
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].k, entries[j].k) < 0 })
+	return entries
+}
+
+// rangeProof proves the first and the last key of the range into a new proof database.
+func rangeProof(t *testing.T, trie *Trie, first, last []byte) database.DBManager {
+	proof := database.NewMemoryDBManager()
+	if err := trie.Prove(first, 0, proof); err != nil {
+		t.Fatalf("Failed to prove the first node %v", err)
+	}
+	if err := trie.Prove(last, 0, proof); err != nil {
+		t.Fatalf("Failed to prove the last node %v", err)
+	}
+	return proof
+}
+
+// Tests that the random ranges with the existent edge proofs are verified.
+func TestRangeProof(t *testing.T) {
+	trie, vals := randomTrie(1024)
+	entries := sortedEntries(vals)
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+
+		var keys, values [][]byte
+		for _, entry := range entries[start:end] {
+			keys = append(keys, entry.k)
+			values = append(values, entry.v)
+		}
+		proof := rangeProof(t, trie, keys[0], keys[len(keys)-1])
+		hasMore, err := VerifyRangeProof(trie.Hash(), keys[0], keys[len(keys)-1], keys, values, proof)
+		if err != nil {
+			t.Fatalf("Case %d(%d->%d) expect no error, got %v", i, start, end-1, err)
+		}
+		if hasMore != (end < len(entries)) {
+			t.Fatalf("Case %d(%d->%d) wrong more flag, got %v", i, start, end-1, hasMore)
+		}
+	}
+}
+
+// Tests that the ranges with the non-existent edge proofs are verified.
+func TestRangeProofWithNonExistentProof(t *testing.T) {
+	trie, vals := randomTrie(1024)
+	entries := sortedEntries(vals)
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries)-1) + 1
+		end := mrand.Intn(len(entries)-start-1) + start + 1
+
+		// The edge keys are located right next to the range, without being in the trie
+		first := common.CopyBytes(entries[start].k)
+		first[len(first)-1]--
+		if bytes.Equal(first, entries[start-1].k) {
+			continue
+		}
+		last := common.CopyBytes(entries[end-1].k)
+		last[len(last)-1]++
+		if bytes.Equal(last, entries[end].k) {
+			continue
+		}
+		var keys, values [][]byte
+		for _, entry := range entries[start:end] {
+			keys = append(keys, entry.k)
+			values = append(values, entry.v)
+		}
+		proof := rangeProof(t, trie, first, last)
+		if _, err := VerifyRangeProof(trie.Hash(), first, last, keys, values, proof); err != nil {
+			t.Fatalf("Case %d(%d->%d) expect no error, got %v", i, start, end-1, err)
+		}
+	}
+	// Zero element proof should succeed only if there's no leaf on the right side
+	last := entries[len(entries)-1].k
+	beyond := common.CopyBytes(last)
+	beyond[len(beyond)-1]++
+	proof := database.NewMemoryDBManager()
+	trie.Prove(beyond, 0, proof)
+	if _, err := VerifyRangeProof(trie.Hash(), beyond, nil, nil, nil, proof); err != nil {
+		t.Fatalf("Expected no error for the empty range, got %v", err)
+	}
+	proof = database.NewMemoryDBManager()
+	trie.Prove(entries[0].k, 0, proof)
+	if _, err := VerifyRangeProof(trie.Hash(), entries[0].k, nil, nil, nil, proof); err == nil {
+		t.Fatal("Expected error for the empty range with more entries")
+	}
+}
+
+// Tests that the whole leaf set is verified without any proof.
+func TestAllElementsProof(t *testing.T) {
+	trie, vals := randomTrie(1024)
+	entries := sortedEntries(vals)
+
+	var keys, values [][]byte
+	for _, entry := range entries {
+		keys = append(keys, entry.k)
+		values = append(values, entry.v)
+	}
+	hasMore, err := VerifyRangeProof(trie.Hash(), nil, nil, keys, values, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if hasMore {
+		t.Fatal("Expected no more elements")
+	}
+	// A missing leaf must fail the verification
+	if _, err := VerifyRangeProof(trie.Hash(), nil, nil, keys[1:], values[1:], nil); err == nil {
+		t.Fatal("Expected error for the incomplete leaf set")
+	}
+}
+
+// Tests that the tampered ranges are rejected.
+func TestBadRangeProof(t *testing.T) {
+	trie, vals := randomTrie(1024)
+	entries := sortedEntries(vals)
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+		if end-start < 3 {
+			continue
+		}
+		var keys, values [][]byte
+		for _, entry := range entries[start:end] {
+			keys = append(keys, entry.k)
+			values = append(values, common.CopyBytes(entry.v))
+		}
+		first, last := keys[0], keys[len(keys)-1]
+		proof := rangeProof(t, trie, first, last)
+
+		index := mrand.Intn(len(keys))
+		switch mrand.Intn(3) {
+		case 0:
+			// Modified value
+			values[index] = randBytes(20)
+		case 1:
+			// Gapped entry
+			if index == 0 || index == len(keys)-1 {
+				continue
+			}
+			keys = append(keys[:index], keys[index+1:]...)
+			values = append(values[:index], values[index+1:]...)
+		case 2:
+			// Out of order
+			if index == len(keys)-1 {
+				continue
+			}
+			keys[index], keys[index+1] = keys[index+1], keys[index]
+			values[index], values[index+1] = values[index+1], values[index]
+		}
+		if _, err := VerifyRangeProof(trie.Hash(), first, last, keys, values, proof); err == nil {
+			t.Fatalf("Case %d(%d->%d) expected error, got nil", i, start, end-1)
+		}
+	}
+}
+
 // mutateByte changes one byte in b.
 func mutateByte(b []byte) {
 	for r := mrand.Intn(len(b)); ; {
@@ -0,0 +1,181 @@
+// Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	forkTestCaller   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	forkTestContract = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	forkTestOther    = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// runForkTest executes the given code of forkTestContract with the chain config of
+// the given fork, and returns the output and the gas used by the execution.
+func runForkTest(t *testing.T, fork string, code []byte, prepare func(*state.StateDB)) ([]byte, uint64, error) {
+	config, ok := Forks[fork]
+	if !ok {
+		t.Fatal(UnsupportedForkError{fork})
+	}
+	statedb := MakePreState(database.NewMemoryDBManager(), blockchain.GenesisAlloc{
+		forkTestCaller:   {Balance: big.NewInt(params.KLAY)},
+		forkTestContract: {Balance: big.NewInt(1234), Code: code},
+		forkTestOther:    {Balance: big.NewInt(1), Code: hexutil.MustDecode("0x00")},
+	})
+	if prepare != nil {
+		prepare(statedb)
+	}
+	context := vm.Context{
+		CanTransfer: blockchain.CanTransfer,
+		Transfer:    blockchain.Transfer,
+		GetHash:     vmTestBlockHash,
+		Origin:      forkTestCaller,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		BlockScore:  big.NewInt(1),
+		GasPrice:    big.NewInt(1),
+	}
+	evm := vm.NewEVM(context, statedb, config, &vm.Config{})
+
+	gas := uint64(100000)
+	ret, leftOverGas, err := evm.Call(vm.AccountRef(forkTestCaller), forkTestContract, nil, gas, new(big.Int))
+	return ret, gas - leftOverGas, err
+}
+
+// TestEVMFork_NewOpcodes tests that CHAINID and SELFBALANCE are available
+// from the istanbul compatible block.
+func TestEVMFork_NewOpcodes(t *testing.T) {
+	var (
+		// CHAINID PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+		chainIDCode = hexutil.MustDecode("0x4660005260206000f3")
+		// SELFBALANCE PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+		selfBalanceCode = hexutil.MustDecode("0x4760005260206000f3")
+	)
+
+	for _, fork := range []string{"Istanbul", "Berlin"} {
+		ret, gas, err := runForkTest(t, fork, chainIDCode, nil)
+		assert.NoError(t, err, fork)
+		assert.Equal(t, common.BigToHash(Forks[fork].ChainID).Bytes(), ret, fork)
+		assert.Equal(t, uint64(2+3+3+3+3+3), gas, fork)
+
+		ret, gas, err = runForkTest(t, fork, selfBalanceCode, nil)
+		assert.NoError(t, err, fork)
+		assert.Equal(t, common.BigToHash(big.NewInt(1234)).Bytes(), ret, fork)
+		assert.Equal(t, uint64(5+3+3+3+3+3), gas, fork)
+	}
+
+	// The opcodes are invalid before the istanbul compatible block
+	for _, code := range [][]byte{chainIDCode, selfBalanceCode} {
+		_, _, err := runForkTest(t, "Constantinople", code, nil)
+		assert.Error(t, err)
+	}
+}
+
+// TestEVMFork_GasSchedule tests the gas of the state access opcodes repriced
+// by EIP-1884 and the warm/cold access gas of EIP-2929.
+func TestEVMFork_GasSchedule(t *testing.T) {
+	var (
+		other = forkTestOther.Bytes()
+		// PUSH20 other EXTCODEHASH POP, twice
+		extCodeHashCode = append(append(append(append([]byte{byte(vm.PUSH20)}, other...), byte(vm.EXTCODEHASH), byte(vm.POP), byte(vm.PUSH20)), other...), byte(vm.EXTCODEHASH), byte(vm.POP))
+		// PUSH20 other BALANCE POP, twice
+		balanceCode = append(append(append(append([]byte{byte(vm.PUSH20)}, other...), byte(vm.BALANCE), byte(vm.POP), byte(vm.PUSH20)), other...), byte(vm.BALANCE), byte(vm.POP))
+		// PUSH1 0 SLOAD POP, twice
+		sloadCode = hexutil.MustDecode("0x6000545060005450")
+		// PUSH1 1 PUSH1 0 SSTORE PUSH1 0 SLOAD POP
+		sstoreCode = hexutil.MustDecode("0x600160005560005450")
+	)
+
+	tests := []struct {
+		name string
+		code []byte
+		gas  map[string]uint64
+	}{
+		{"EXTCODEHASH", extCodeHashCode, map[string]uint64{
+			"Constantinople": 2 * (3 + 400 + 2),
+			"Istanbul":       2 * (3 + 700 + 2),
+			"Berlin":         (3 + 2600 + 2) + (3 + 100 + 2),
+		}},
+		{"BALANCE", balanceCode, map[string]uint64{
+			"Constantinople": 2 * (3 + 400 + 2),
+			"Istanbul":       2 * (3 + 700 + 2),
+			"Berlin":         (3 + 2600 + 2) + (3 + 100 + 2),
+		}},
+		{"SLOAD", sloadCode, map[string]uint64{
+			"Constantinople": 2 * (3 + 200 + 2),
+			"Istanbul":       2 * (3 + 800 + 2),
+			"Berlin":         (3 + 2100 + 2) + (3 + 100 + 2),
+		}},
+		{"SSTORE", sstoreCode, map[string]uint64{
+			"Constantinople": (3 + 3 + 20000) + (3 + 200 + 2),
+			"Istanbul":       (3 + 3 + 20000) + (3 + 800 + 2),
+			"Berlin":         (3 + 3 + 2100 + 20000) + (3 + 100 + 2),
+		}},
+	}
+	for _, tc := range tests {
+		for fork, expected := range tc.gas {
+			_, gas, err := runForkTest(t, fork, tc.code, nil)
+			assert.NoError(t, err, tc.name+"/"+fork)
+			assert.Equal(t, expected, gas, tc.name+"/"+fork)
+		}
+	}
+
+	// The accounts in the access list prepared by the state transition are warm from the start
+	_, gas, err := runForkTest(t, "Berlin", balanceCode, func(statedb *state.StateDB) {
+		statedb.PrepareAccessList(forkTestCaller, &forkTestOther, nil)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2*(3+100+2)), gas)
+}
+
+// TestEVMFork_AccessListRevert tests that the slots accessed in a reverted
+// call become cold again, while the callee accessed by the caller stays warm.
+func TestEVMFork_AccessListRevert(t *testing.T) {
+	var (
+		// The callee loads slot 0 and reverts:
+		// PUSH1 0 SLOAD POP PUSH1 0 DUP1 REVERT
+		calleeCode = hexutil.MustDecode("0x60005450600080fd")
+		// PUSH1 0 DUP1 DUP1 DUP1 DUP1 PUSH20 other GAS CALL POP
+		callCode = append(append(hexutil.MustDecode("0x60008080808073"), forkTestOther.Bytes()...), byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+		// The caller calls the callee twice
+		callerCode = append(append([]byte{}, callCode...), callCode...)
+	)
+	_, gas, err := runForkTest(t, "Berlin", callerCode, func(statedb *state.StateDB) {
+		statedb.SetCode(forkTestOther, calleeCode)
+	})
+	assert.NoError(t, err)
+
+	var (
+		push   = uint64(3 + 4*3 + 3 + 2)
+		callee = uint64(3 + 2100 + 2 + 3 + 3)
+	)
+	first := push + (100 + 2500) + 2 + callee
+	second := push + 100 + 2 + callee
+	assert.Equal(t, first+second, gas)
+}
@@ -306,7 +306,7 @@ func (bcdata *BCData) GenABlockWithTxpool(accountMap *AccountMap, txpool *blockc
 
 	// Apply reward
 	start = time.Now()
-	if err := bcdata.rewardDistributor.MintKLAY(accountMap, header); err != nil {
+	if _, err := bcdata.rewardDistributor.MintKLAY(accountMap, header); err != nil {
 		return err
 	}
 	prof.Profile("main_apply_reward", time.Now().Sub(start))
@@ -359,7 +359,7 @@ func (bcdata *BCData) GenABlockWithTransactions(accountMap *AccountMap, transact
 
 	// Apply reward
 	start = time.Now()
-	if err := bcdata.rewardDistributor.MintKLAY(accountMap, b.Header()); err != nil {
+	if _, err := bcdata.rewardDistributor.MintKLAY(accountMap, b.Header()); err != nil {
 		return err
 	}
 	prof.Profile("main_apply_reward", time.Now().Sub(start))
@@ -19,6 +19,7 @@ import (
 	event "github.com/klaytn/klaytn/event"
 	params "github.com/klaytn/klaytn/params"
 	rlp "github.com/klaytn/klaytn/rlp"
+	snapshot "github.com/klaytn/klaytn/snapshot"
 )
 
 // MockBlockChain is a mock of BlockChain interface
@@ -634,6 +635,20 @@ func (mr *MockBlockChainMockRecorder) Processor() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Processor", reflect.TypeOf((*MockBlockChain)(nil).Processor))
 }
 
+// RebuildSnapshot mocks base method
+func (m *MockBlockChain) RebuildSnapshot() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildSnapshot")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildSnapshot indicates an expected call of RebuildSnapshot
+func (mr *MockBlockChainMockRecorder) RebuildSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildSnapshot", reflect.TypeOf((*MockBlockChain)(nil).RebuildSnapshot))
+}
+
 // ResetWithGenesisBlock mocks base method
 func (m *MockBlockChain) ResetWithGenesisBlock(arg0 *types.Block) error {
 	m.ctrl.T.Helper()
@@ -712,6 +727,35 @@ func (mr *MockBlockChainMockRecorder) SetUseGiniCoeff(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUseGiniCoeff", reflect.TypeOf((*MockBlockChain)(nil).SetUseGiniCoeff), arg0)
 }
 
+// Snapshots mocks base method
+func (m *MockBlockChain) Snapshots() *snapshot.Tree {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshots")
+	ret0, _ := ret[0].(*snapshot.Tree)
+	return ret0
+}
+
+// Snapshots indicates an expected call of Snapshots
+func (mr *MockBlockChainMockRecorder) Snapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshots", reflect.TypeOf((*MockBlockChain)(nil).Snapshots))
+}
+
+// SnapshotStatus mocks base method
+func (m *MockBlockChain) SnapshotStatus() (*snapshot.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotStatus")
+	ret0, _ := ret[0].(*snapshot.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotStatus indicates an expected call of SnapshotStatus
+func (mr *MockBlockChainMockRecorder) SnapshotStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotStatus", reflect.TypeOf((*MockBlockChain)(nil).SnapshotStatus))
+}
+
 // StartCollectingTrieStats mocks base method
 func (m *MockBlockChain) StartCollectingTrieStats(arg0 common.Address) error {
 	m.ctrl.T.Helper()
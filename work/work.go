@@ -38,6 +38,7 @@ import (
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/database"
 )
 
@@ -305,6 +306,11 @@ type BlockChain interface {
 	// Save trie node cache to this
 	SaveTrieNodeCacheToDisk() error
 
+	// Snapshot
+	Snapshots() *snapshot.Tree
+	SnapshotStatus() (*snapshot.Status, error)
+	RebuildSnapshot() error
+
 	// KES
 	BlockSubscriptionLoop(pool *blockchain.TxPool)
 	CloseBlockSubscriptionLoop()